@@ -18,7 +18,10 @@ import (
 	"pulseguard/internal/service"
 	"pulseguard/pkg/auth"
 	"pulseguard/pkg/logger"
+	"pulseguard/pkg/mailer"
+	"pulseguard/pkg/notifier"
 	"pulseguard/pkg/otel"
+	"pulseguard/pkg/storage"
 
 	"github.com/joho/godotenv"
 )
@@ -57,12 +60,46 @@ func main() {
 
 	prometheusURL := getEnvOrDefault("PROMETHEUS_URL", "http://prometheus:9090")
 	tempoURL := getEnvOrDefault("TEMPO_URL", "http://tempo:3200")
+	// OTLP received from projects is forwarded to the collector, which ships it to Tempo, Loki and Prometheus
+	otlpForwardURL := getEnvOrDefault("OTLP_FORWARD_URL", "http://"+otlpEndpoint)
 	portStr := getEnvOrDefault("PORT", "8081")
 	port, err := strconv.Atoi(portStr)
 	if err != nil {
 		appLogger.Error(context.Background(), "Invalid PORT", err)
 		os.Exit(1)
 	}
+	alertEvalInterval, err := time.ParseDuration(getEnvOrDefault("ALERT_EVAL_INTERVAL", "1m"))
+	if err != nil || alertEvalInterval <= 0 {
+		appLogger.Error(context.Background(), "Invalid ALERT_EVAL_INTERVAL", err)
+		os.Exit(1)
+	}
+	ingestQueueSize, err := strconv.Atoi(getEnvOrDefault("INGEST_QUEUE_SIZE", strconv.Itoa(service.DefaultIngestQueueConfig.Capacity)))
+	if err != nil || ingestQueueSize <= 0 {
+		appLogger.Error(context.Background(), "Invalid INGEST_QUEUE_SIZE", err)
+		os.Exit(1)
+	}
+	ingestWorkers, err := strconv.Atoi(getEnvOrDefault("INGEST_WORKERS", strconv.Itoa(service.DefaultIngestQueueConfig.Workers)))
+	if err != nil || ingestWorkers <= 0 {
+		appLogger.Error(context.Background(), "Invalid INGEST_WORKERS", err)
+		os.Exit(1)
+	}
+	ingestFlushInterval, err := time.ParseDuration(getEnvOrDefault("INGEST_FLUSH_INTERVAL", service.DefaultIngestQueueConfig.FlushInterval.String()))
+	if err != nil || ingestFlushInterval <= 0 {
+		appLogger.Error(context.Background(), "Invalid INGEST_FLUSH_INTERVAL", err)
+		os.Exit(1)
+	}
+	// How long shutdown waits for the queued errors to be stored
+	ingestDrainTimeout, err := time.ParseDuration(getEnvOrDefault("INGEST_DRAIN_TIMEOUT", "30s"))
+	if err != nil || ingestDrainTimeout <= 0 {
+		appLogger.Error(context.Background(), "Invalid INGEST_DRAIN_TIMEOUT", err)
+		os.Exit(1)
+	}
+	// Events per minute a project may send unless its own limit is set; 0 is unlimited
+	ingestRateLimit, err := strconv.Atoi(getEnvOrDefault("INGEST_RATE_LIMIT", "6000"))
+	if err != nil || ingestRateLimit < 0 {
+		appLogger.Error(context.Background(), "Invalid INGEST_RATE_LIMIT", err)
+		os.Exit(1)
+	}
 
 	// Initialize OTEL tracing + metrics
 	otelClient, err := otel.InitClient(otlpEndpoint, appLogger)
@@ -98,36 +135,110 @@ func main() {
 	// Init repositories
 	userRepo := postgres.NewUserRepository(conn)
 	errorRepo := postgres.NewErrorRepository(conn)
+	groupingRuleRepo := postgres.NewGroupingRuleRepository(conn)
+	artifactRepo := postgres.NewArtifactRepository(conn)
+	releaseRepo := postgres.NewReleaseRepository(conn)
+	savedViewRepo := postgres.NewSavedViewRepository(conn)
 	alertRepo := postgres.NewAlertRepository(conn)
+	alertRuleRepo := postgres.NewAlertRuleRepository(conn)
+	notificationRepo := postgres.NewNotificationRepository(conn)
+	silenceRepo := postgres.NewSilenceRepository(conn)
 	lokiRepo := telemetry.NewLokiRepository(lokiURL)
 	projectRepo := postgres.NewProjectRepository(conn)
+	projectKeyRepo := postgres.NewProjectKeyRepository(conn)
+	usageRepo := postgres.NewUsageRepository(conn)
+	samplingRepo := postgres.NewSamplingRepository(conn)
+	membershipRepo := postgres.NewMembershipRepository(conn)
+	organizationRepo := postgres.NewOrganizationRepository(conn)
+	invitationRepo := postgres.NewInvitationRepository(conn)
 	tempoRepo := telemetry.NewTempoRepository(tempoURL)
 	sessionRepo := telemetry.NewSessionRepository(conn)
 	prometheusRepo := telemetry.NewPrometheusRepository(prometheusURL)
+	otlpRepo := telemetry.NewOTLPRepository(otlpForwardURL)
 
 	// Init services
 	tokenService := auth.NewTokenService(jwtSecret)
 	logsService := service.NewLogsService(lokiRepo)
 	userService := service.NewUserService(userRepo)
-	errorService := service.NewErrorService(errorRepo)
-	alertService := service.NewAlertService(alertRepo)
+	groupingService := service.NewGroupingService(groupingRuleRepo)
+	artifactStore, err := storage.NewStoreFromEnv()
+	if err != nil {
+		appLogger.Error(context.Background(), "Failed to initialize artifact storage", err)
+		os.Exit(1)
+	}
+	artifactService := service.NewArtifactService(artifactRepo, artifactStore, appLogger)
+	savedViewService := service.NewSavedViewService(savedViewRepo)
+	errorService := service.NewErrorService(errorRepo, groupingService, artifactService, savedViewService)
+	releaseService := service.NewReleaseService(releaseRepo)
+	// Email channels send through Resend and need RESEND_API_KEY and EMAIL_FROM;
+	// without them they fail with a clear delivery error
+	var emailSender notifier.Sender
+	if m, err := mailer.NewMailer(); err != nil {
+		appLogger.Info(context.Background(), "Email notifications disabled: "+err.Error())
+	} else {
+		emailSender = m
+	}
+	silenceService := service.NewSilenceService(silenceRepo)
+	notificationService := service.NewNotificationService(notificationRepo, nil, emailSender, silenceService, appLogger)
+	alertService := service.NewAlertService(alertRepo, alertRuleRepo, projectRepo, notificationService)
 	tracesService := service.NewTracesService(tempoRepo)
-	projectService := service.NewProjectService(projectRepo)
+	projectService := service.NewProjectService(projectRepo, membershipRepo, organizationRepo)
+	projectKeyService := service.NewProjectKeyService(projectKeyRepo)
+	organizationService := service.NewOrganizationService(organizationRepo, membershipRepo)
+	invitationService := service.NewInvitationService(invitationRepo, membershipRepo, userRepo)
 	sessionService := service.NewSessionService(sessionRepo)
 	metricsService := service.NewMetricsService(prometheusRepo)
+	samplingService := service.NewSamplingService(samplingRepo, appLogger)
+	// Rate limits, quotas and spike protection of the events sent with a project key
+	ingestLimiter := service.NewIngestLimiter(usageRepo, ingestRateLimit, appLogger)
+	ingestLimiter.Start(context.Background())
+	// Errors sent with a project key are sampled and stored in the background
+	ingestQueue := service.NewIngestQueue(errorService, samplingService, ingestLimiter, metrics, service.IngestQueueConfig{
+		Capacity:      ingestQueueSize,
+		Workers:       ingestWorkers,
+		FlushInterval: ingestFlushInterval,
+	}, appLogger)
+	ingestQueue.Start()
+	otlpService := service.NewOTLPService(otlpRepo, ingestQueue, ingestLimiter, appLogger)
 	dashboardService := service.NewDashboardService(alertService, metricsService, errorService, sessionService)
 
+	// Start evaluating alert rules in the background
+	alertEvaluator := service.NewAlertEvaluator(alertRepo, alertRuleRepo, errorService, sessionService, metricsService, notificationService, appLogger, alertEvalInterval)
+	alertEvaluator.Start(context.Background())
+
+	// Send queued alert notifications in the background
+	notificationDispatcher := service.NewNotificationDispatcher(notificationRepo, notificationService, appLogger, 5*time.Second)
+	notificationDispatcher.Start(context.Background())
+
+	// Send the emails of issue assignments and mentions in the background
+	issueMailer := service.NewIssueMailer(emailSender, appLogger)
+	issueMailer.Start()
+
 	// Start HTTP server
 	server := api.NewServer(
 		userService,
 		projectService,
+		projectKeyService,
+		organizationService,
+		invitationService,
 		errorService,
+		ingestQueue,
+		ingestLimiter,
+		samplingService,
+		groupingService,
+		artifactService,
+		releaseService,
+		savedViewService,
 		alertService,
+		notificationService,
+		issueMailer,
+		silenceService,
 		metricsService,
 		logsService,
 		tracesService,
 		dashboardService,
 		sessionService,
+		otlpService,
 		port,
 		appLogger,
 		metrics,
@@ -156,19 +267,34 @@ func main() {
 
 	appLogger.Info(context.Background(), "Shutting down server...")
 
-	// Shutdown with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Shutdown OTEL client
-	if err := otelClient.Shutdown(ctx, appLogger); err != nil {
-		appLogger.Error(ctx, "Failed to shutdown OTEL client", err)
+	// Stop accepting requests first, so that nothing is queued after the drain
+	serverCtx, cancelServer := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelServer()
+	if err := srv.Shutdown(serverCtx); err != nil {
+		appLogger.Error(serverCtx, "Server forced to shutdown", err)
+	} else {
+		appLogger.Info(serverCtx, "Server stopped gracefully")
 	}
 
-	// Shutdown HTTP server
-	if err := srv.Shutdown(ctx); err != nil {
-		appLogger.Error(ctx, "Server forced to shutdown", err)
+	// Stop alert evaluation
+	alertEvaluator.Stop()
+	notificationDispatcher.Stop()
+	issueMailer.Stop()
+
+	// Store the errors still queued, with a deadline of its own
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), ingestDrainTimeout)
+	defer cancelDrain()
+	if err := ingestQueue.Shutdown(drainCtx); err != nil {
+		appLogger.Error(drainCtx, "Failed to drain ingest queue", err)
 	} else {
-		appLogger.Info(ctx, "Server stopped gracefully")
+		appLogger.Info(drainCtx, "Ingest queue drained")
+	}
+	ingestLimiter.Stop()
+
+	// Shutdown OTEL client last, so that the spans and metrics of the drain are exported
+	otelCtx, cancelOtel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelOtel()
+	if err := otelClient.Shutdown(otelCtx, appLogger); err != nil {
+		appLogger.Error(otelCtx, "Failed to shutdown OTEL client", err)
 	}
 }
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type alertTransitionRequest struct {
+	Note string `json:"note"`
+}
+
+type alertTransition func(ctx context.Context, projectID, alertID, userID, note string) (*models.Alert, error)
+
+// Acknowledge marks a firing alert as being handled by the caller
+func (h *AlertHandler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "acknowledge", h.alertService.AcknowledgeAlert)
+}
+
+// Resolve closes an open alert on behalf of the caller
+func (h *AlertHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "resolve", h.alertService.ResolveAlert)
+}
+
+// Reopen puts a resolved alert back to firing
+func (h *AlertHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "reopen", h.alertService.ReopenAlert)
+}
+
+// ListEvents returns the transition history of an alert
+func (h *AlertHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	events, err := h.alertService.ListAlertEvents(ctx, projectID, chi.URLParam(r, "alert_id"))
+	if err != nil {
+		h.writeAlertError(w, r, err, "list_alert_events_failed", "Failed to fetch alert history")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, events)
+}
+
+func (h *AlertHandler) transition(w http.ResponseWriter, r *http.Request, action string, apply alertTransition) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	// The note is optional, so an empty body is fine
+	var req alertTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	alert, err := apply(ctx, projectID, chi.URLParam(r, "alert_id"), userID, req.Note)
+	if err != nil {
+		h.writeAlertError(w, r, err, action+"_alert_failed", "Failed to "+action+" alert")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", action+"_alert"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, alert)
+}
+
+func (h *AlertHandler) writeAlertError(w http.ResponseWriter, r *http.Request, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, service.ErrAlertNotFound):
+		util.WriteError(w, http.StatusNotFound, "Alert not found")
+	case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrAlertConflict):
+		util.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", errorType)))
+		util.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/promql"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type alertRuleRequest struct {
+	Name          string  `json:"name"`
+	ConditionType string  `json:"condition_type"`
+	Threshold     float64 `json:"threshold"`
+	WindowSeconds int     `json:"window_seconds"`
+	Query         string  `json:"query"`
+	Environment   string  `json:"environment"`
+	Severity      string  `json:"severity"`
+	Enabled       *bool   `json:"enabled"`
+}
+
+func (req alertRuleRequest) apply(rule *models.AlertRule) {
+	rule.Name = req.Name
+	rule.ConditionType = req.ConditionType
+	rule.Threshold = req.Threshold
+	rule.WindowSeconds = req.WindowSeconds
+	rule.Query = req.Query
+	rule.Environment = req.Environment
+	rule.Severity = req.Severity
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+}
+
+// ListRules returns the project's alert rules
+func (h *AlertHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	rules, err := h.alertService.ListRules(ctx, projectID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_alert_rules_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch alert rules")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, rules)
+}
+
+// CreateRule adds an alert rule to the project
+func (h *AlertHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req alertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	rule := &models.AlertRule{ProjectID: projectID, Enabled: true, CreatedBy: userID}
+	req.apply(rule)
+
+	created, err := h.alertService.CreateRule(ctx, rule)
+	if err != nil {
+		h.writeRuleError(w, r, err, "create")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_alert_rule"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, created)
+}
+
+// UpdateRule changes an alert rule of the project
+func (h *AlertHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req alertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.alertService.GetRule(ctx, projectID, chi.URLParam(r, "rule_id"))
+	if err != nil {
+		h.writeRuleError(w, r, err, "update")
+		return
+	}
+	req.apply(rule)
+
+	updated, err := h.alertService.UpdateRule(ctx, rule)
+	if err != nil {
+		h.writeRuleError(w, r, err, "update")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, updated)
+}
+
+// DeleteRule removes an alert rule of the project
+func (h *AlertHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	if err := h.alertService.DeleteRule(ctx, projectID, chi.URLParam(r, "rule_id")); err != nil {
+		h.writeRuleError(w, r, err, "delete")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Alert rule deleted"})
+}
+
+func (h *AlertHandler) writeRuleError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrAlertRuleNotFound):
+		util.WriteError(w, http.StatusNotFound, "Alert rule not found")
+	case errors.Is(err, promql.ErrInvalidQuery), errors.Is(err, promql.ErrUnscoped):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_alert_rule")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrInvalidAlertRule):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_alert_rule")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid alert rule: name and a known condition_type are required; promql rules need a query and session_error_rate thresholds are between 0 and 1")
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_alert_rule_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action+" alert rule")
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// maxAlertmanagerBody bounds the size of a webhook notification
+const maxAlertmanagerBody = 5 << 20
+
+// AlertmanagerHandler receives Prometheus Alertmanager webhook notifications.
+// Alertmanager authenticates with the bearer token in ALERTMANAGER_WEBHOOK_TOKEN,
+// or in the file named by ALERTMANAGER_WEBHOOK_TOKEN_FILE, which lets the
+// backend and Alertmanager share one secret; the endpoint is disabled when
+// neither is set.
+type AlertmanagerHandler struct {
+	alertService *service.AlertService
+	metrics      *otel.Metrics
+	logger       *logger.Logger
+	token        string
+}
+
+func NewAlertmanagerHandler(alertService *service.AlertService, metrics *otel.Metrics, logger *logger.Logger) *AlertmanagerHandler {
+	return &AlertmanagerHandler{
+		alertService: alertService,
+		metrics:      metrics,
+		logger:       logger,
+		token:        alertmanagerToken(logger),
+	}
+}
+
+func alertmanagerToken(log *logger.Logger) string {
+	if token := os.Getenv("ALERTMANAGER_WEBHOOK_TOKEN"); token != "" {
+		return token
+	}
+	path := os.Getenv("ALERTMANAGER_WEBHOOK_TOKEN_FILE")
+	if path == "" {
+		return ""
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		log.Error(context.Background(), "Failed to read ALERTMANAGER_WEBHOOK_TOKEN_FILE, Alertmanager webhook is disabled", err)
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// Webhook imports the alerts of an Alertmanager notification. The optional
+// project query parameter (ID or slug) applies to alerts without a project label.
+func (h *AlertmanagerHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.token == "" {
+		util.WriteError(w, http.StatusServiceUnavailable, "Alertmanager webhook is not configured")
+		return
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_alertmanager_token")))
+		util.WriteError(w, http.StatusUnauthorized, "Invalid or missing token")
+		return
+	}
+
+	var payload service.AlertmanagerPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAlertmanagerBody)).Decode(&payload); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.alertService.ImportAlertmanager(ctx, &payload, r.URL.Query().Get("project"))
+	if err != nil {
+		h.logger.Error(ctx, "Failed to import Alertmanager alerts", err, "group_key", payload.GroupKey)
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "alertmanager_import_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to import alerts")
+		return
+	}
+
+	if result.Skipped > 0 {
+		h.logger.Info(ctx, "Skipped Alertmanager alerts without a known project", "group_key", payload.GroupKey, "skipped", result.Skipped)
+	}
+
+	util.WriteJSON(w, http.StatusOK, result)
+}
@@ -34,6 +34,14 @@ func (h *AlertHandler) Create(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    projectID, err := scopedProjectID(r.Context(), req.ProjectID)
+    if err != nil {
+        h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "project_mismatch")))
+        http.Error(w, "project_id does not match the authorized project", http.StatusForbidden)
+        return
+    }
+    req.ProjectID = projectID
+
     if req.ProjectID == "" || req.Message == "" || req.Severity == "" {
         h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "missing_fields")))
         http.Error(w, "Missing required fields", http.StatusBadRequest)
@@ -57,8 +65,8 @@ func (h *AlertHandler) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AlertHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
-    projectID := r.URL.Query().Get("project_id")
-    if projectID == "" {
+    projectID, ok := authorizedProjectID(r.Context())
+    if !ok {
         h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "missing_project_id")))
         http.Error(w, "Missing project_id", http.StatusBadRequest)
         return
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// maxArtifactSize caps uploaded source maps; bundles of large apps stay well below it
+const maxArtifactSize = 64 << 20
+
+type ArtifactHandler struct {
+	artifactService *service.ArtifactService
+	metrics         *otel.Metrics
+}
+
+func NewArtifactHandler(artifactService *service.ArtifactService, metrics *otel.Metrics) *ArtifactHandler {
+	return &ArtifactHandler{artifactService: artifactService, metrics: metrics}
+}
+
+// Upload stores a source map for a release. The map is sent as the "file"
+// field of a multipart form; the "name" field is the URL of the minified
+// file plus ".map" (or "~/path.map" for any origin) and defaults to the
+// uploaded file name.
+func (h *ArtifactHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxArtifactSize+1<<20)
+	if err := r.ParseMultipartForm(8 << 20); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid multipart form")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		util.WriteError(w, http.StatusBadRequest, "Missing file")
+		return
+	}
+	defer file.Close()
+	if header.Size > maxArtifactSize {
+		util.WriteError(w, http.StatusRequestEntityTooLarge, "Artifact is too large")
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		util.WriteError(w, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+
+	name := r.FormValue("name")
+	if name == "" {
+		name = header.Filename
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	artifact, err := h.artifactService.UploadArtifact(ctx, projectID, chi.URLParam(r, "release"), name, data, userID)
+	if err != nil {
+		h.writeArtifactError(w, r, err, "upload")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "upload_artifact"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, artifact)
+}
+
+// List returns the artifacts uploaded for a release
+func (h *ArtifactHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	artifacts, err := h.artifactService.ListArtifacts(ctx, projectID, chi.URLParam(r, "release"))
+	if err != nil {
+		h.writeArtifactError(w, r, err, "list")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, artifacts)
+}
+
+// Delete removes an artifact of a release
+func (h *ArtifactHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	err := h.artifactService.DeleteArtifact(ctx, projectID, chi.URLParam(r, "release"), chi.URLParam(r, "artifact_id"))
+	if err != nil {
+		h.writeArtifactError(w, r, err, "delete")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Artifact deleted"})
+}
+
+func (h *ArtifactHandler) writeArtifactError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrArtifactNotFound):
+		util.WriteError(w, http.StatusNotFound, "Artifact not found")
+	case errors.Is(err, service.ErrInvalidArtifact):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_artifact")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_artifact_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action+" artifact")
+	}
+}
@@ -34,8 +34,8 @@ func (h *DashboardHandler) GetDashboardData(w http.ResponseWriter, r *http.Reque
     _, span := h.tracer.Start(ctx, "GetDashboardData")
     defer span.End()
 
-    projectID := r.URL.Query().Get("project_id")
-    if projectID == "" {
+    projectID, ok := authorizedProjectID(ctx)
+    if !ok {
         h.logger.Error(ctx, "Missing project_id in dashboard data request", nil)
         span.SetStatus(codes.Error, "Missing project_id")
         util.WriteError(w, http.StatusBadRequest, "Missing project_id")
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+)
+
+type assignErrorRequest struct {
+	UserID string `json:"userId"`
+	TeamID string `json:"teamId"`
+}
+
+type createCommentRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parentId"`
+}
+
+// GetErrorActivity returns the assignee, comment threads and activity log of an error
+func (h *ErrorHandler) GetErrorActivity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "GetErrorActivity")
+	defer span.End()
+
+	id := chi.URLParam(r, "id")
+	projectID, _ := authorizedProjectID(ctx)
+	timeline, err := h.errorService.GetErrorActivity(ctx, projectID, id)
+	if err != nil {
+		h.writeActivityError(w, r, span, err, "get_activity_failed", "Failed to fetch activity")
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Activity fetched successfully")
+	span.SetAttributes(attribute.String("error_id", id))
+
+	util.WriteJSON(w, http.StatusOK, timeline)
+}
+
+// AssignError assigns an error to a user or a team, or unassigns it when
+// neither is given, and emails the new assignee
+func (h *ErrorHandler) AssignError(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "AssignError")
+	defer span.End()
+
+	var req assignErrorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_body"),
+		))
+		span.SetStatus(codes.Error, "Invalid request body")
+		span.RecordError(err)
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		span.SetStatus(codes.Error, "Unauthorized")
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	projectID, _ := authorizedProjectID(ctx)
+	assignee, notify, err := h.errorService.AssignError(ctx, projectID, id, req.UserID, req.TeamID, userID)
+	if err != nil {
+		h.writeActivityError(w, r, span, err, "assign_failed", "Failed to assign error")
+		return
+	}
+
+	if assignee != nil {
+		h.notifyIssueUsers(ctx, id, notify, func(title, link string) (string, string) {
+			return util.IssueAssignedEmail(assignee.Name, title, link)
+		})
+	}
+
+	h.logger.Info(ctx, "Error assigned",
+		"error_id", id,
+		"assigned", assignee != nil,
+	)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "assign_error"),
+		attribute.String("user_id", userID),
+		attribute.String("error_id", id),
+	))
+
+	span.SetStatus(codes.Ok, "Error assigned successfully")
+	span.SetAttributes(
+		attribute.String("error_id", id),
+		attribute.String("user_id", userID),
+	)
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"assignee": assignee,
+	})
+}
+
+// CreateErrorComment adds a comment or a reply to an error and emails the
+// users it mentions
+func (h *ErrorHandler) CreateErrorComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "CreateErrorComment")
+	defer span.End()
+
+	var req createCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_body"),
+		))
+		span.SetStatus(codes.Error, "Invalid request body")
+		span.RecordError(err)
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		span.SetStatus(codes.Error, "Unauthorized")
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	projectID, _ := authorizedProjectID(ctx)
+	comment, notify, err := h.errorService.AddComment(ctx, projectID, id, req.ParentID, req.Body, userID)
+	if err != nil {
+		h.writeActivityError(w, r, span, err, "comment_failed", "Failed to add comment")
+		return
+	}
+
+	author := comment.AuthorName
+	if author == "" {
+		author = "Someone"
+	}
+	h.notifyIssueUsers(ctx, id, notify, func(title, link string) (string, string) {
+		return util.MentionEmail(author, title, comment.Body, link)
+	})
+
+	h.logger.Info(ctx, "Comment added",
+		"error_id", id,
+		"comment_id", comment.ID,
+		"mentions", len(comment.Mentions),
+	)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "comment_error"),
+		attribute.String("user_id", userID),
+		attribute.String("error_id", id),
+	))
+
+	span.SetStatus(codes.Ok, "Comment added successfully")
+	span.SetAttributes(
+		attribute.String("error_id", id),
+		attribute.String("comment_id", comment.ID),
+		attribute.String("user_id", userID),
+	)
+
+	util.WriteJSON(w, http.StatusCreated, comment)
+}
+
+// notifyIssueUsers queues an email about the error to each user, written
+// by compose. Emails are sent in the background; those that cannot be
+// queued are logged, as the change that triggered them has been saved.
+func (h *ErrorHandler) notifyIssueUsers(ctx context.Context, errorID string, users []models.UserSummary, compose func(title, link string) (subject, body string)) {
+	if len(users) == 0 {
+		return
+	}
+	project, _ := util.GetProjectFromContext(ctx)
+	e, err := h.errorService.GetErrorByID(ctx, project.ID, errorID)
+	if err != nil || e == nil {
+		h.logger.Error(ctx, "Failed to load error for notification", err, "error_id", errorID)
+		return
+	}
+
+	title := e.Message
+	if e.Type != "" {
+		title = e.Type + ": " + e.Message
+	}
+	link := os.Getenv("FRONTEND_URL") + "/projects/" + project.Slug + "?error=" + errorID
+
+	subject, body := compose(title, link)
+	for _, u := range users {
+		if err := h.issueMailer.Send(u.Email, subject, body); err != nil {
+			h.logger.Error(ctx, "Failed to queue issue notification", err,
+				"error_id", errorID,
+				"user_id", u.ID,
+			)
+		}
+	}
+}
+
+func (h *ErrorHandler) writeActivityError(w http.ResponseWriter, r *http.Request, span trace.Span, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, service.ErrErrorNotFound):
+		span.SetStatus(codes.Error, "Error not found")
+		util.WriteError(w, http.StatusNotFound, "Error not found")
+	case errors.Is(err, service.ErrInvalidAssignment), errors.Is(err, service.ErrInvalidComment):
+		span.SetStatus(codes.Error, "Invalid request")
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(
+			attribute.String("error_type", errorType),
+		))
+		span.SetStatus(codes.Error, message)
+		span.RecordError(err)
+		h.logger.Error(r.Context(), message, err)
+		util.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/search"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+)
+
+type bulkErrorFilter struct {
+	Search      string `json:"search"`
+	ViewID      string `json:"viewId"`
+	Environment string `json:"environment"`
+	Status      string `json:"status"`
+}
+
+type bulkErrorsRequest struct {
+	// Either the ids of the errors or a filter selecting them
+	IDs    []string         `json:"ids"`
+	Filter *bulkErrorFilter `json:"filter"`
+	Action string           `json:"action"`
+	// Optional conditions of resolve and ignore, as for UpdateErrorStatus
+	InNextRelease   bool       `json:"inNextRelease"`
+	IgnoreDuration  int        `json:"ignoreDuration"`
+	IgnoreUntil     *time.Time `json:"ignoreUntil"`
+	IgnoreCount     int        `json:"ignoreCount"`
+	IgnoreUserCount int        `json:"ignoreUserCount"`
+	// The assignee of assign; neither unassigns
+	UserID string `json:"userId"`
+	TeamID string `json:"teamId"`
+	// The tags added by tag
+	Tags []models.ErrorTag `json:"tags"`
+}
+
+// BulkUpdateErrors resolves, ignores, assigns, tags or deletes many errors
+// at once, selected by id or by a filter, and reports how many it changed.
+// Deleting takes the admin role.
+func (h *ErrorHandler) BulkUpdateErrors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "BulkUpdateErrors")
+	defer span.End()
+
+	var req bulkErrorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_body"),
+		))
+		span.SetStatus(codes.Error, "Invalid request body")
+		span.RecordError(err)
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if (len(req.IDs) == 0) == (req.Filter == nil) {
+		span.SetStatus(codes.Error, "Invalid selection")
+		util.WriteError(w, http.StatusBadRequest, "Select errors with either ids or a filter")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		span.SetStatus(codes.Error, "Unauthorized")
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	project, _ := util.GetProjectFromContext(ctx)
+	if req.Action == models.BulkDelete && !project.Role.AtLeast(models.RoleAdmin) {
+		span.SetStatus(codes.Error, "Forbidden")
+		util.WriteError(w, http.StatusForbidden, "Deleting errors requires the admin role")
+		return
+	}
+
+	action := &models.BulkAction{
+		Type: req.Action,
+		StatusDetails: &models.StatusDetails{
+			InNextRelease:   req.InNextRelease,
+			IgnoreUntil:     req.IgnoreUntil,
+			IgnoreCount:     req.IgnoreCount,
+			IgnoreUserCount: req.IgnoreUserCount,
+		},
+		AssigneeUserID: req.UserID,
+		AssigneeTeamID: req.TeamID,
+		Tags:           req.Tags,
+	}
+	if req.IgnoreDuration > 0 {
+		until := time.Now().Add(time.Duration(req.IgnoreDuration) * time.Minute)
+		action.StatusDetails.IgnoreUntil = &until
+	}
+
+	filters := service.ErrorFilters{ProjectID: project.ID, ViewerID: userID}
+	if req.Filter != nil {
+		filters.Search = req.Filter.Search
+		filters.ViewID = req.Filter.ViewID
+		filters.Environment = req.Filter.Environment
+		filters.Status = req.Filter.Status
+	}
+
+	affected, err := h.errorService.BulkUpdate(ctx, filters, req.IDs, action, userID)
+	var parseErr *search.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		span.SetStatus(codes.Error, "Invalid search")
+		util.WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":    parseErr.Msg,
+			"position": parseErr.Pos,
+		})
+		return
+	case errors.Is(err, service.ErrSavedViewNotFound):
+		span.SetStatus(codes.Error, "Saved view not found")
+		util.WriteError(w, http.StatusNotFound, "Saved view not found")
+		return
+	case errors.Is(err, service.ErrInvalidBulkAction), errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, service.ErrInvalidAssignment):
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_bulk_action"),
+		))
+		span.SetStatus(codes.Error, "Invalid bulk action")
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "bulk_update_failed"),
+		))
+		span.SetStatus(codes.Error, "Failed to update errors")
+		span.RecordError(err)
+		h.logger.Error(ctx, "Failed to apply bulk action", err, "action", req.Action)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to update errors")
+		return
+	}
+
+	h.logger.Info(ctx, "Bulk action applied",
+		"project_id", project.ID,
+		"action", req.Action,
+		"affected", affected,
+	)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "bulk_"+req.Action),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", project.ID),
+	))
+
+	span.SetStatus(codes.Ok, "Bulk action applied successfully")
+	span.SetAttributes(
+		attribute.String("action", req.Action),
+		attribute.Int("affected", affected),
+	)
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"action":   req.Action,
+		"affected": affected,
+	})
+}
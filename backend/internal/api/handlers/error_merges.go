@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+)
+
+type mergeErrorsRequest struct {
+	TargetID  string   `json:"targetId"`
+	SourceIDs []string `json:"sourceIds"`
+}
+
+type unmergeErrorRequest struct {
+	MergeID string `json:"mergeId"`
+}
+
+// MergeErrors folds one or more error groups into a target group
+func (h *ErrorHandler) MergeErrors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "MergeErrors")
+	defer span.End()
+
+	var req mergeErrorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_body"),
+		))
+		span.SetStatus(codes.Error, "Invalid request body")
+		span.RecordError(err)
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.TargetID == "" || len(req.SourceIDs) == 0 {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "missing_fields"),
+		))
+		span.SetStatus(codes.Error, "Missing required fields")
+		util.WriteError(w, http.StatusBadRequest, "targetId and sourceIds are required")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		span.SetStatus(codes.Error, "Unauthorized")
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, _ := authorizedProjectID(ctx)
+	target, merges, err := h.errorService.MergeErrors(ctx, projectID, req.TargetID, req.SourceIDs, userID)
+	if err != nil {
+		h.writeMergeError(w, r, span, err, "merge_failed", "Failed to merge errors")
+		return
+	}
+
+	h.logger.Info(ctx, "Errors merged",
+		"error_id", target.ID,
+		"merged", len(merges),
+	)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "merge_errors"),
+		attribute.String("user_id", userID),
+		attribute.String("error_id", target.ID),
+	))
+
+	span.SetStatus(codes.Ok, "Errors merged successfully")
+	span.SetAttributes(
+		attribute.String("error_id", target.ID),
+		attribute.Int("merged", len(merges)),
+		attribute.String("user_id", userID),
+	)
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"error":  target,
+		"merges": merges,
+	})
+}
+
+// UnmergeError splits a previously merged group out of its target again
+func (h *ErrorHandler) UnmergeError(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "UnmergeError")
+	defer span.End()
+
+	var req unmergeErrorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_body"),
+		))
+		span.SetStatus(codes.Error, "Invalid request body")
+		span.RecordError(err)
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.MergeID == "" {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "missing_fields"),
+		))
+		span.SetStatus(codes.Error, "Missing required fields")
+		util.WriteError(w, http.StatusBadRequest, "mergeId is required")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		span.SetStatus(codes.Error, "Unauthorized")
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID, _ := authorizedProjectID(ctx)
+	restored, err := h.errorService.UnmergeError(ctx, projectID, req.MergeID, userID)
+	if err != nil {
+		h.writeMergeError(w, r, span, err, "unmerge_failed", "Failed to unmerge error")
+		return
+	}
+
+	h.logger.Info(ctx, "Error unmerged",
+		"error_id", restored.ID,
+		"merge_id", req.MergeID,
+	)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "unmerge_error"),
+		attribute.String("user_id", userID),
+		attribute.String("error_id", restored.ID),
+	))
+
+	span.SetStatus(codes.Ok, "Error unmerged successfully")
+	span.SetAttributes(
+		attribute.String("error_id", restored.ID),
+		attribute.String("merge_id", req.MergeID),
+		attribute.String("user_id", userID),
+	)
+
+	util.WriteJSON(w, http.StatusOK, restored)
+}
+
+// ListErrorMerges returns the groups merged into an error, so they can be unmerged
+func (h *ErrorHandler) ListErrorMerges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "ListErrorMerges")
+	defer span.End()
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "missing_id"),
+		))
+		span.SetStatus(codes.Error, "Missing error id")
+		util.WriteError(w, http.StatusBadRequest, "Missing error id")
+		return
+	}
+
+	projectID, _ := authorizedProjectID(ctx)
+	merges, err := h.errorService.ListErrorMerges(ctx, projectID, id)
+	if err != nil {
+		h.writeMergeError(w, r, span, err, "list_merges_failed", "Failed to fetch merges")
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Merges fetched successfully")
+	span.SetAttributes(attribute.String("error_id", id))
+
+	util.WriteJSON(w, http.StatusOK, merges)
+}
+
+func (h *ErrorHandler) writeMergeError(w http.ResponseWriter, r *http.Request, span trace.Span, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, service.ErrErrorNotFound):
+		span.SetStatus(codes.Error, "Error not found")
+		util.WriteError(w, http.StatusNotFound, "Error not found")
+	case errors.Is(err, service.ErrMergeNotFound):
+		span.SetStatus(codes.Error, "Merge not found")
+		util.WriteError(w, http.StatusNotFound, "Merge not found")
+	case errors.Is(err, service.ErrInvalidMerge):
+		span.SetStatus(codes.Error, "Invalid merge")
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(
+			attribute.String("error_type", errorType),
+		))
+		span.SetStatus(codes.Error, message)
+		span.RecordError(err)
+		h.logger.Error(r.Context(), message, err)
+		util.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+)
+
+const (
+	defaultOccurrenceLimit = 50
+	maxOccurrenceLimit     = 100
+)
+
+// ListErrorOccurrences returns a page of an error's occurrences, newest
+// first. The next page is requested with the returned nextCursor.
+func (h *ErrorHandler) ListErrorOccurrences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "ListErrorOccurrences")
+	defer span.End()
+
+	projectID, _ := authorizedProjectID(ctx)
+	query := r.URL.Query()
+	filters := service.OccurrenceFilters{
+		ProjectID:   projectID,
+		ErrorID:     chi.URLParam(r, "id"),
+		UserID:      query.Get("user_id"),
+		SessionID:   query.Get("session_id"),
+		Release:     query.Get("release"),
+		Environment: query.Get("environment"),
+		Cursor:      query.Get("cursor"),
+		Limit:       defaultOccurrenceLimit,
+	}
+	for param, dst := range map[string]*time.Time{"start_date": &filters.StartDate, "end_date": &filters.EndDate} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			span.SetStatus(codes.Error, "Invalid "+param)
+			util.WriteError(w, http.StatusBadRequest, param+" must be an RFC 3339 time")
+			return
+		}
+		*dst = t
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if l, err := strconv.Atoi(limit); err == nil && l > 0 {
+			filters.Limit = min(l, maxOccurrenceLimit)
+		}
+	}
+
+	occurrences, next, err := h.errorService.ListOccurrences(ctx, filters)
+	if err != nil {
+		h.writeOccurrenceError(w, r, span, err, "list_occurrences_failed", "Failed to fetch occurrences")
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Occurrences fetched successfully")
+	span.SetAttributes(
+		attribute.String("error_id", filters.ErrorID),
+		attribute.Int("occurrence_count", len(occurrences)),
+	)
+
+	util.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"occurrences": occurrences,
+		"nextCursor":  next,
+	})
+}
+
+// GetErrorOccurrence returns one occurrence of an error with its frames and breadcrumbs
+func (h *ErrorHandler) GetErrorOccurrence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "GetErrorOccurrence")
+	defer span.End()
+
+	id := chi.URLParam(r, "id")
+	occurrenceID := chi.URLParam(r, "occurrence_id")
+	projectID, _ := authorizedProjectID(ctx)
+	occurrence, err := h.errorService.GetOccurrence(ctx, projectID, id, occurrenceID)
+	if err != nil {
+		h.writeOccurrenceError(w, r, span, err, "get_occurrence_failed", "Failed to fetch occurrence")
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Occurrence fetched successfully")
+	span.SetAttributes(
+		attribute.String("error_id", id),
+		attribute.String("occurrence_id", occurrenceID),
+	)
+
+	util.WriteJSON(w, http.StatusOK, occurrence)
+}
+
+func (h *ErrorHandler) writeOccurrenceError(w http.ResponseWriter, r *http.Request, span trace.Span, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, service.ErrErrorNotFound):
+		span.SetStatus(codes.Error, "Error not found")
+		util.WriteError(w, http.StatusNotFound, "Error not found")
+	case errors.Is(err, service.ErrOccurrenceNotFound):
+		span.SetStatus(codes.Error, "Occurrence not found")
+		util.WriteError(w, http.StatusNotFound, "Occurrence not found")
+	case errors.Is(err, service.ErrInvalidCursor):
+		span.SetStatus(codes.Error, "Invalid cursor")
+		util.WriteError(w, http.StatusBadRequest, "Invalid cursor")
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(
+			attribute.String("error_type", errorType),
+		))
+		span.SetStatus(codes.Error, message)
+		span.RecordError(err)
+		h.logger.Error(r.Context(), message, err)
+		util.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
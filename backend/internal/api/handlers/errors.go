@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"pulseguard/internal/models"
+	"pulseguard/internal/search"
 	"pulseguard/internal/service"
 	"pulseguard/internal/util"
 	"pulseguard/pkg/logger"
@@ -22,16 +25,22 @@ import (
 type ErrorHandler struct {
 	metrics        *otel.Metrics
     errorService   *service.ErrorService
+    ingestQueue    *service.IngestQueue
+    ingestLimiter  *service.IngestLimiter
     sessionService *service.SessionService
+    issueMailer    *service.IssueMailer
     logger         *logger.Logger
     tracer         trace.Tracer
 }
 
-func NewErrorHandler(errorService *service.ErrorService, sessionService *service.SessionService, metrics *otel.Metrics, logger *logger.Logger, tracer trace.Tracer) *ErrorHandler {
+func NewErrorHandler(errorService *service.ErrorService, ingestQueue *service.IngestQueue, ingestLimiter *service.IngestLimiter, sessionService *service.SessionService, issueMailer *service.IssueMailer, metrics *otel.Metrics, logger *logger.Logger, tracer trace.Tracer) *ErrorHandler {
     return &ErrorHandler{
         metrics:        metrics,
         errorService:   errorService,
+        ingestQueue:    ingestQueue,
+        ingestLimiter:  ingestLimiter,
         sessionService: sessionService,
+        issueMailer:    issueMailer,
         logger:         logger,
         tracer:         tracer,
     }
@@ -49,7 +58,13 @@ type trackErrorRequest struct {
 	SessionID      string                 `json:"sessionId"`
 	ProjectID      string                 `json:"projectId"`
 	Environment    string                 `json:"environment"`
+	Release        string                 `json:"release"`
+	Fingerprint    []string               `json:"fingerprint"`
+	Breadcrumbs    []models.Breadcrumb    `json:"breadcrumbs"`
 	Metadata       map[string]interface{} `json:"metadata"`
+	// SampleRate is the rate the client sampled the event at, as served by
+	// /api/ingest/sampling; unset when it did not sample
+	SampleRate float64 `json:"sampleRate"`
 }
 
 func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
@@ -58,7 +73,15 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 
 	var req trackErrorRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeIngestBody(w, r, &req); err != nil {
+		if isBodyTooLarge(err) {
+			h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("error_type", "payload_too_large"),
+			))
+			span.SetStatus(codes.Error, "Request body too large")
+			util.WriteError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "invalid_body"),
 		))
@@ -69,6 +92,18 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	projectID, err := scopedProjectID(ctx, req.ProjectID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "project_mismatch"),
+		))
+		span.SetStatus(codes.Error, "Project mismatch")
+		h.logger.Error(ctx, "Track error request does not match the authorized project", err)
+		util.WriteError(w, http.StatusForbidden, "project_id does not match the authorized project")
+		return
+	}
+	req.ProjectID = projectID
+
 	if req.ProjectID == "" || req.Message == "" {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "missing_fields"),
@@ -79,6 +114,15 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.SampleRate < 0 || req.SampleRate > 1 {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_sample_rate"),
+		))
+		span.SetStatus(codes.Error, "Invalid sampleRate")
+		util.WriteError(w, http.StatusBadRequest, "sampleRate must be between 0 and 1")
+		return
+	}
+
 	projectUUID, err := uuid.Parse(req.ProjectID)
 	if err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
@@ -91,7 +135,7 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	userID, ok := ingestActor(ctx, h.metrics)
 	if !ok {
 		span.SetStatus(codes.Error, "Unauthorized")
 		h.logger.Error(ctx, "Unauthorized access to track error", nil)
@@ -99,33 +143,6 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle session
-    if req.SessionID != "" {
-        err := h.sessionService.IncrementErrorCount(ctx, req.SessionID)
-        if err != nil {
-            // Session doesn't exist, create it
-            session := &models.Session{
-                SessionID:     req.SessionID,
-                ProjectID:     req.ProjectID,
-                UserID:        req.UserID,
-                StartTime:     time.Now(),
-                ErrorCount:    1,
-                EventCount:    0,
-                PageviewCount: 0,
-                CreatedAt:     time.Now(),
-            }
-            if err := h.sessionService.CreateSession(ctx, session); err != nil {
-                h.logger.Error(ctx, "Failed to create session", err)
-            } else {
-                h.metrics.ActiveSessions.Add(ctx, 1, metric.WithAttributes(
-                    attribute.String("user_id", req.UserID),
-                    attribute.String("project_id", req.ProjectID),
-                    attribute.String("session_id", req.SessionID),
-                ))
-            }
-        }
-    }
-
 	errorData := &models.Error{
 		ProjectID:      projectUUID.String(),
 		Message:        req.Message,
@@ -138,11 +155,45 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 		UserID:         req.UserID,
 		SessionID:      req.SessionID,
 		Environment:    req.Environment,
+		Release:        req.Release,
+		Breadcrumbs:    req.Breadcrumbs,
 		OccurredAt:     time.Now(),
 		Status:         "ACTIVE",
 	}
 
-	errEntry, err := h.errorService.Track(ctx, errorData, req.Metadata)
+	// Events sent with a project key are queued and stored in the
+	// background; tracking from the dashboard waits for the group it returns
+	if key, ok := util.GetProjectKeyFromContext(ctx); ok {
+		if decision := admitEvent(ctx, h.ingestLimiter, h.metrics); !decision.Accepted() {
+			span.SetStatus(codes.Error, "Event rejected: "+decision.Outcome)
+			writeIngestRejected(w, decision)
+			return
+		}
+		if err := h.ingestQueue.EnqueueSampled(key, errorData, req.Fingerprint, req.Metadata, req.SampleRate); err != nil {
+			h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("error_type", "ingest_queue_rejected"),
+			))
+			refundEvent(ctx, h.ingestLimiter, service.IngestQueueOutcome(err))
+			recordIngestOutcome(ctx, h.metrics, errorData.ProjectID, service.IngestQueueOutcome(err), 1)
+			span.SetStatus(codes.Error, err.Error())
+			writeIngestQueueError(w, h.ingestQueue, err)
+			return
+		}
+		recordIngestOutcome(ctx, h.metrics, errorData.ProjectID, models.IngestAccepted, 1)
+		h.recordSessionError(ctx, req)
+
+		h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("activity_type", "error_queued"),
+			attribute.String("user_id", userID),
+			attribute.String("project_id", projectUUID.String()),
+		))
+		span.SetStatus(codes.Ok, "Error queued successfully")
+		span.SetAttributes(attribute.String("project_id", projectUUID.String()))
+		util.WriteJSON(w, http.StatusAccepted, map[string]string{"status": "queued"})
+		return
+	}
+
+	errEntry, err := h.errorService.Track(ctx, errorData, req.Fingerprint, req.Metadata)
 	if err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "track_failed"),
@@ -154,6 +205,8 @@ func (h *ErrorHandler) Track(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordSessionError(ctx, req)
+
 	h.logger.ErrorWithFields(ctx, "Error tracked", map[string]interface{}{
 		"project_id":  projectUUID.String(),
 		"message":     req.Message,
@@ -186,8 +239,8 @@ func (h *ErrorHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 	_, span := h.tracer.Start(ctx, "ListErrorsByProject")
 	defer span.End()
 
-	projectID := r.URL.Query().Get("project_id")
-	if projectID == "" {
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "missing_project_id"),
 		))
@@ -197,18 +250,6 @@ func (h *ErrorHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := uuid.Parse(projectID)
-	if err != nil {
-		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
-			attribute.String("error_type", "invalid_project_id"),
-		))
-		span.SetStatus(codes.Error, "Invalid project_id")
-		span.RecordError(err)
-		h.logger.Error(ctx, "Invalid project_id format", err)
-		util.WriteError(w, http.StatusBadRequest, "Invalid project_id")
-		return
-	}
-
 	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
 	if !ok {
 		span.SetStatus(codes.Error, "Unauthorized")
@@ -219,6 +260,7 @@ func (h *ErrorHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 
 	filters := service.ErrorFilters{
 		ProjectID: projectID,
+		ViewerID:  userID,
 		Page:      1,
 		Limit:     20,
 	}
@@ -231,6 +273,9 @@ func (h *ErrorHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 	if search := r.URL.Query().Get("search"); search != "" {
 		filters.Search = search
 	}
+	if viewID := r.URL.Query().Get("view_id"); viewID != "" {
+		filters.ViewID = viewID
+	}
 	if userID := r.URL.Query().Get("user_id"); userID != "" {
 		filters.UserID = userID
 	}
@@ -247,6 +292,9 @@ func (h *ErrorHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 			filters.EndDate = t
 		}
 	}
+	if sort := r.URL.Query().Get("sort"); sort != "" {
+		filters.Sort = sort
+	}
 	if page := r.URL.Query().Get("page"); page != "" {
 		if p, err := strconv.Atoi(page); err == nil && p > 0 {
 			filters.Page = p
@@ -259,6 +307,23 @@ func (h *ErrorHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	list, total, err := h.errorService.GetErrors(ctx, filters)
+	var parseErr *search.ParseError
+	if errors.As(err, &parseErr) {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_search"),
+		))
+		span.SetStatus(codes.Error, "Invalid search")
+		util.WriteJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":    parseErr.Msg,
+			"position": parseErr.Pos,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrSavedViewNotFound) {
+		span.SetStatus(codes.Error, "Saved view not found")
+		util.WriteError(w, http.StatusNotFound, "Saved view not found")
+		return
+	}
 	if err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "list_failed"),
@@ -318,7 +383,8 @@ func (h *ErrorHandler) GetErrorByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	errorData, err := h.errorService.GetErrorByID(ctx, id)
+	projectID, _ := authorizedProjectID(ctx)
+	errorData, err := h.errorService.GetErrorByID(ctx, projectID, id)
 	if err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "get_failed"),
@@ -365,6 +431,12 @@ func (h *ErrorHandler) UpdateErrorStatus(w http.ResponseWriter, r *http.Request)
 	var req struct {
 		ID     string `json:"id"`
 		Status string `json:"status"`
+		// Optional conditions, see models.StatusDetails; ignoreDuration is in minutes
+		InNextRelease   bool       `json:"inNextRelease"`
+		IgnoreDuration  int        `json:"ignoreDuration"`
+		IgnoreUntil     *time.Time `json:"ignoreUntil"`
+		IgnoreCount     int        `json:"ignoreCount"`
+		IgnoreUserCount int        `json:"ignoreUserCount"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
@@ -405,7 +477,27 @@ func (h *ErrorHandler) UpdateErrorStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	errorData, err := h.errorService.UpdateErrorStatus(ctx, req.ID, req.Status)
+	details := &models.StatusDetails{
+		InNextRelease:   req.InNextRelease,
+		IgnoreUntil:     req.IgnoreUntil,
+		IgnoreCount:     req.IgnoreCount,
+		IgnoreUserCount: req.IgnoreUserCount,
+	}
+	if req.IgnoreDuration > 0 {
+		until := time.Now().Add(time.Duration(req.IgnoreDuration) * time.Minute)
+		details.IgnoreUntil = &until
+	}
+
+	projectID, _ := authorizedProjectID(ctx)
+	errorData, err := h.errorService.UpdateErrorStatus(ctx, projectID, req.ID, req.Status, details, userID)
+	if errors.Is(err, service.ErrInvalidStatus) {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "invalid_status"),
+		))
+		span.SetStatus(codes.Error, "Invalid status")
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "update_failed"),
@@ -416,6 +508,11 @@ func (h *ErrorHandler) UpdateErrorStatus(w http.ResponseWriter, r *http.Request)
 		util.WriteError(w, http.StatusInternalServerError, "Failed to update error status")
 		return
 	}
+	if errorData == nil {
+		span.SetStatus(codes.Error, "Error not found")
+		util.WriteError(w, http.StatusNotFound, "Error not found")
+		return
+	}
 
 	// h.logger.Info(ctx, "Error status updated",
 	// 	"error_id", req.ID,
@@ -439,5 +536,38 @@ func (h *ErrorHandler) UpdateErrorStatus(w http.ResponseWriter, r *http.Request)
 }
 
 func isValidStatus(status string) bool {
-	return status == "ACTIVE" || status == "RESOLVED" || status == "IGNORED" || status == "INVESTIGATING"
+	return status == "ACTIVE" || status == "RESOLVED" || status == "IGNORED" || status == "INVESTIGATING" || status == "REGRESSED"
+}
+
+// recordSessionError counts a stored or queued error against its session,
+// opening the session if it is new. It runs only once the event was
+// admitted, so that rejected events never reach the database.
+func (h *ErrorHandler) recordSessionError(ctx context.Context, req trackErrorRequest) {
+	if req.SessionID == "" {
+		return
+	}
+	if err := h.sessionService.IncrementErrorCount(ctx, req.ProjectID, req.SessionID); err == nil {
+		return
+	}
+
+	// Session doesn't exist, create it
+	session := &models.Session{
+		SessionID:     req.SessionID,
+		ProjectID:     req.ProjectID,
+		UserID:        req.UserID,
+		StartTime:     time.Now(),
+		ErrorCount:    1,
+		EventCount:    0,
+		PageviewCount: 0,
+		CreatedAt:     time.Now(),
+	}
+	if err := h.sessionService.CreateSession(ctx, session); err != nil {
+		h.logger.Error(ctx, "Failed to create session", err)
+		return
+	}
+	h.metrics.ActiveSessions.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("user_id", req.UserID),
+		attribute.String("project_id", req.ProjectID),
+		attribute.String("session_id", req.SessionID),
+	))
 }
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type GroupingRuleHandler struct {
+	groupingService *service.GroupingService
+	metrics         *otel.Metrics
+}
+
+func NewGroupingRuleHandler(groupingService *service.GroupingService, metrics *otel.Metrics) *GroupingRuleHandler {
+	return &GroupingRuleHandler{groupingService: groupingService, metrics: metrics}
+}
+
+type groupingRuleRequest struct {
+	Name         string   `json:"name"`
+	MatchType    string   `json:"match_type"`
+	MatchMessage string   `json:"match_message"`
+	MatchFrame   string   `json:"match_frame"`
+	Fingerprint  []string `json:"fingerprint"`
+	Priority     int      `json:"priority"`
+	Enabled      *bool    `json:"enabled"`
+}
+
+func (req groupingRuleRequest) apply(rule *models.GroupingRule) {
+	rule.Name = req.Name
+	rule.MatchType = req.MatchType
+	rule.MatchMessage = req.MatchMessage
+	rule.MatchFrame = req.MatchFrame
+	rule.Fingerprint = req.Fingerprint
+	rule.Priority = req.Priority
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+}
+
+// List returns the project's grouping rules in the order they are applied
+func (h *GroupingRuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	rules, err := h.groupingService.ListRules(ctx, projectID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_grouping_rules_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch grouping rules")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, rules)
+}
+
+// Create adds a grouping rule to the project
+func (h *GroupingRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req groupingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	rule := &models.GroupingRule{ProjectID: projectID, Enabled: true, CreatedBy: userID}
+	req.apply(rule)
+
+	created, err := h.groupingService.CreateRule(ctx, rule)
+	if err != nil {
+		h.writeRuleError(w, r, err, "create")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_grouping_rule"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, created)
+}
+
+// Update changes a grouping rule of the project. Errors already grouped
+// keep their group; the rule applies to events tracked from now on.
+func (h *GroupingRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req groupingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.groupingService.GetRule(ctx, projectID, chi.URLParam(r, "rule_id"))
+	if err != nil {
+		h.writeRuleError(w, r, err, "update")
+		return
+	}
+	req.apply(rule)
+
+	updated, err := h.groupingService.UpdateRule(ctx, rule)
+	if err != nil {
+		h.writeRuleError(w, r, err, "update")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, updated)
+}
+
+// Delete removes a grouping rule of the project
+func (h *GroupingRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	if err := h.groupingService.DeleteRule(ctx, projectID, chi.URLParam(r, "rule_id")); err != nil {
+		h.writeRuleError(w, r, err, "delete")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Grouping rule deleted"})
+}
+
+func (h *GroupingRuleHandler) writeRuleError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrGroupingRuleNotFound):
+		util.WriteError(w, http.StatusNotFound, "Grouping rule not found")
+	case errors.Is(err, service.ErrInvalidGroupingRule):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_grouping_rule")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_grouping_rule_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action+" grouping rule")
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+)
+
+// maxIngestBody caps the JSON body of an event or session sent with an
+// ingest key. Keys ship inside client apps, so anyone can send one.
+const maxIngestBody = 1 << 20
+
+// decodeIngestBody decodes the JSON body of a public ingest request, reading
+// at most maxIngestBody bytes of it
+func decodeIngestBody(w http.ResponseWriter, r *http.Request, v any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIngestBody)).Decode(v)
+}
+
+// isBodyTooLarge reports whether decoding failed because the body went
+// over its cap
+func isBodyTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+// writeIngestQueueError answers a request the ingest queue turned away,
+// telling the client when to retry
+func writeIngestQueueError(w http.ResponseWriter, queue *service.IngestQueue, err error) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(queue.RetryAfter().Seconds())))
+	if errors.Is(err, service.ErrIngestQueueFull) {
+		util.WriteError(w, http.StatusTooManyRequests, "Too many events, retry later")
+		return
+	}
+	util.WriteError(w, http.StatusServiceUnavailable, "Server is shutting down, retry later")
+}
+
+// admitEvent checks one event against the ingest limits of the key that
+// authenticated the request. Rejected events are recorded; accepted ones
+// are recorded by the caller once queued.
+func admitEvent(ctx context.Context, limiter *service.IngestLimiter, metrics *otel.Metrics) service.IngestDecision {
+	key, ok := util.GetProjectKeyFromContext(ctx)
+	if !ok {
+		return service.IngestDecision{Outcome: models.IngestAccepted}
+	}
+	decision := limiter.Admit(ctx, key)
+	if !decision.Accepted() {
+		recordIngestOutcome(ctx, metrics, key.ProjectID, decision.Outcome, 1)
+	}
+	return decision
+}
+
+// refundEvent takes back an event admitEvent accepted but the ingest queue
+// turned away, so that it does not count towards the key's limits
+func refundEvent(ctx context.Context, limiter *service.IngestLimiter, outcome string) {
+	if key, ok := util.GetProjectKeyFromContext(ctx); ok {
+		limiter.Refund(key, outcome)
+	}
+}
+
+// recordIngestOutcome counts n events of a project in the ingest metrics.
+// Outcomes other than accepted and rate limited count as dropped, with the
+// outcome as the reason.
+func recordIngestOutcome(ctx context.Context, metrics *otel.Metrics, projectID, outcome string, n int) {
+	if n <= 0 {
+		return
+	}
+	project := attribute.String("project_id", projectID)
+	switch outcome {
+	case models.IngestAccepted:
+		metrics.IngestAcceptedTotal.Add(ctx, int64(n), metric.WithAttributes(project))
+	case models.IngestRateLimited:
+		metrics.IngestRateLimitedTotal.Add(ctx, int64(n), metric.WithAttributes(project))
+	default:
+		metrics.IngestDroppedTotal.Add(ctx, int64(n), metric.WithAttributes(project, attribute.String("reason", outcome)))
+	}
+}
+
+// writeIngestRejected answers a request whose event the ingest limits
+// turned away, telling the client when to retry
+func writeIngestRejected(w http.ResponseWriter, decision service.IngestDecision) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(decision.RetryAfter)))
+	switch decision.Outcome {
+	case models.IngestOverQuota:
+		util.WriteError(w, http.StatusTooManyRequests, "Monthly event quota exceeded")
+	case models.IngestSpike:
+		util.WriteError(w, http.StatusTooManyRequests, "Event spike detected, retry later")
+	default:
+		util.WriteError(w, http.StatusTooManyRequests, "Rate limit exceeded, retry later")
+	}
+}
+
+// retryAfterSeconds rounds a wait up to whole seconds, at least one
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
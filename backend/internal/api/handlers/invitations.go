@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+	"pulseguard/pkg/validator"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type InvitationHandler struct {
+	metrics           *otel.Metrics
+	invitationService *service.InvitationService
+	logger            *logger.Logger
+}
+
+func NewInvitationHandler(invitationService *service.InvitationService, metrics *otel.Metrics, logger *logger.Logger) *InvitationHandler {
+	return &InvitationHandler{
+		metrics:           metrics,
+		invitationService: invitationService,
+		logger:            logger,
+	}
+}
+
+type createInvitationRequest struct {
+	Email string      `json:"email"`
+	Role  models.Role `json:"role"`
+}
+
+type acceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+// InviteToOrganization emails an invitation to join the organization
+func (h *InvitationHandler) InviteToOrganization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	h.invite(w, r, org.Name, func(req createInvitationRequest, userID string) (*models.Invitation, error) {
+		return h.invitationService.InviteToOrganization(ctx, org.ID, req.Email, req.Role, userID)
+	})
+}
+
+// InviteToProject emails an invitation to join the project
+func (h *InvitationHandler) InviteToProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	h.invite(w, r, project.Name, func(req createInvitationRequest, userID string) (*models.Invitation, error) {
+		return h.invitationService.InviteToProject(ctx, project.ID, req.Email, req.Role, userID)
+	})
+}
+
+func (h *InvitationHandler) invite(w http.ResponseWriter, r *http.Request, target string, create func(createInvitationRequest, string) (*models.Invitation, error)) {
+	ctx := r.Context()
+
+	var req createInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !validator.IsValidEmail(req.Email) {
+		util.WriteError(w, http.StatusBadRequest, "Invalid email")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	inv, err := create(req, userID)
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "create_invitation")
+		return
+	}
+
+	// Compose invite URL (frontend will handle token from query param)
+	inviteURL := os.Getenv("FRONTEND_URL") + "/invitations/accept?token=" + inv.Token
+
+	if err := util.SendInvitationEmail(inv.Email, target, inviteURL); err != nil {
+		h.logger.Error(ctx, "Failed to send invitation email", err)
+		// The invitation is withdrawn so that inviting again starts afresh
+		if err := h.invitationService.Withdraw(ctx, inv.ID); err != nil {
+			h.logger.Error(ctx, "Failed to withdraw unsent invitation", err, "invitation_id", inv.ID)
+		}
+		util.WriteError(w, http.StatusInternalServerError, "Failed to send invitation email")
+		return
+	}
+
+	h.logger.Info(ctx, "Invitation sent", "invitation_id", inv.ID)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "send_invitation"),
+		attribute.String("user_id", userID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, inv)
+}
+
+// ListOrganizationInvitations returns the organization's pending invitations
+func (h *InvitationHandler) ListOrganizationInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	invitations, err := h.invitationService.ListPendingForOrganization(ctx, org.ID)
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "list_invitations")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, invitations)
+}
+
+// ListProjectInvitations returns the project's pending invitations
+func (h *InvitationHandler) ListProjectInvitations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	invitations, err := h.invitationService.ListPendingForProject(ctx, project.ID)
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "list_invitations")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, invitations)
+}
+
+// Accept joins the caller to the invitation's organization or project
+func (h *InvitationHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req acceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	inv, err := h.invitationService.Accept(ctx, req.Token, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvitationNotFound):
+			util.WriteError(w, http.StatusNotFound, "Invitation not found")
+		case errors.Is(err, service.ErrInvitationExpired):
+			util.WriteError(w, http.StatusGone, "Invitation expired or already accepted")
+		case errors.Is(err, service.ErrInvitationEmailMismatch):
+			util.WriteError(w, http.StatusForbidden, "This invitation was sent to a different email address")
+		default:
+			h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "accept_invitation_failed")))
+			h.logger.Error(ctx, "Failed to accept invitation", err)
+			util.WriteError(w, http.StatusInternalServerError, "Failed to accept invitation")
+		}
+		return
+	}
+
+	h.logger.Info(ctx, "Invitation accepted", "invitation_id", inv.ID, "user_id", userID)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "accept_invitation"),
+		attribute.String("user_id", userID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, inv)
+}
@@ -26,8 +26,8 @@ func (h *MetricsHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
     ctx := r.Context()
 
     // Extract project ID from context
-    projectID := r.URL.Query().Get("project_id")
-    if projectID == "" {
+    projectID, ok := authorizedProjectID(ctx)
+    if !ok {
         h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
             attribute.String("error_type", "missing_project_id"),
         ))
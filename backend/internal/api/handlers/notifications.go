@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/notifier"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+	metrics             *otel.Metrics
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService, metrics *otel.Metrics) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService, metrics: metrics}
+}
+
+type channelRequest struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	Recipients []string `json:"recipients"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+func (req channelRequest) apply(ch *models.NotificationChannel) {
+	ch.Name = req.Name
+	ch.URL = strings.TrimSpace(req.URL)
+	ch.Recipients = req.Recipients
+	if req.Secret != "" {
+		ch.Secret = req.Secret
+	}
+	if req.Enabled != nil {
+		ch.Enabled = *req.Enabled
+	}
+}
+
+// ListChannels returns the project's notification channels
+func (h *NotificationHandler) ListChannels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	channels, err := h.notificationService.ListChannels(ctx, projectID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_channels_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch notification channels")
+		return
+	}
+
+	// Signing secrets are only shown to those who can manage channels
+	if project, ok := util.GetProjectFromContext(ctx); !ok || !project.Role.AtLeast(models.RoleAdmin) {
+		for _, ch := range channels {
+			ch.Secret = ""
+		}
+	}
+
+	util.WriteJSON(w, http.StatusOK, channels)
+}
+
+// CreateChannel adds a notification channel to the project
+func (h *NotificationHandler) CreateChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req channelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	ch := &models.NotificationChannel{ProjectID: projectID, Type: req.Type, Enabled: true, CreatedBy: userID}
+	req.apply(ch)
+
+	created, err := h.notificationService.CreateChannel(ctx, ch)
+	if err != nil {
+		h.writeChannelError(w, r, err, "create")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_notification_channel"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, created)
+}
+
+// UpdateChannel changes a notification channel of the project
+func (h *NotificationHandler) UpdateChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req channelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	ch, err := h.notificationService.GetChannel(ctx, projectID, chi.URLParam(r, "channel_id"))
+	if err != nil {
+		h.writeChannelError(w, r, err, "update")
+		return
+	}
+	req.apply(ch)
+
+	updated, err := h.notificationService.UpdateChannel(ctx, ch)
+	if err != nil {
+		h.writeChannelError(w, r, err, "update")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, updated)
+}
+
+// DeleteChannel removes a notification channel of the project
+func (h *NotificationHandler) DeleteChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	if err := h.notificationService.DeleteChannel(ctx, projectID, chi.URLParam(r, "channel_id")); err != nil {
+		h.writeChannelError(w, r, err, "delete")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Notification channel deleted"})
+}
+
+// TestChannel sends a test notification through a channel right away
+func (h *NotificationHandler) TestChannel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	ch, err := h.notificationService.GetChannel(ctx, projectID, chi.URLParam(r, "channel_id"))
+	if err != nil {
+		h.writeChannelError(w, r, err, "test")
+		return
+	}
+
+	if err := h.notificationService.TestChannel(ctx, ch); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "test_notification_failed")))
+		util.WriteError(w, http.StatusBadGateway, "Test notification failed: "+notifier.Describe(err))
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Test notification sent"})
+}
+
+// ListDeliveries returns the project's notification delivery log
+func (h *NotificationHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	query := r.URL.Query()
+	filters := postgres.DeliveryFilters{
+		ProjectID: projectID,
+		ChannelID: query.Get("channel_id"),
+		AlertID:   query.Get("alert_id"),
+		Status:    query.Get("status"),
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filters.Limit = limit
+	}
+
+	deliveries, err := h.notificationService.ListDeliveries(ctx, filters)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_deliveries_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch notification deliveries")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, deliveries)
+}
+
+func (h *NotificationHandler) writeChannelError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrChannelNotFound):
+		util.WriteError(w, http.StatusNotFound, "Notification channel not found")
+	case errors.Is(err, service.ErrInvalidChannel):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_notification_channel")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_notification_channel_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action+" notification channel")
+	}
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type OrganizationHandler struct {
+	metrics    *otel.Metrics
+	orgService *service.OrganizationService
+	logger     *logger.Logger
+}
+
+func NewOrganizationHandler(orgService *service.OrganizationService, metrics *otel.Metrics, logger *logger.Logger) *OrganizationHandler {
+	return &OrganizationHandler{
+		metrics:    metrics,
+		orgService: orgService,
+		logger:     logger,
+	}
+}
+
+type createOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+type createTeamRequest struct {
+	Name string `json:"name"`
+}
+
+type updateMemberRoleRequest struct {
+	Role models.Role `json:"role"`
+}
+
+type addTeamMemberRequest struct {
+	UserID string `json:"userId"`
+}
+
+// Create creates an organization owned by the caller
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req createOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Name == "" {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "missing_name")))
+		util.WriteError(w, http.StatusBadRequest, "Missing organization name")
+		return
+	}
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	org, err := h.orgService.Create(ctx, req.Name, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrDuplicateOrganization) {
+			util.WriteErrorFields(w, "Organization name already exists", []string{"name"})
+			return
+		}
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "create_organization_failed")))
+		h.logger.Error(ctx, "Failed to create organization", err)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to create organization")
+		return
+	}
+
+	h.logger.Info(ctx, "Organization created", "organization_id", org.ID)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_organization"),
+		attribute.String("user_id", userID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, org)
+}
+
+// List returns the organizations the caller belongs to
+func (h *OrganizationHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	orgs, err := h.orgService.ListForUser(ctx, userID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_organizations_failed")))
+		h.logger.Error(ctx, "Failed to list organizations", err)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch organizations")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, orgs)
+}
+
+// Get returns the organization authorized by the organization access middleware
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	org, ok := util.GetOrganizationFromContext(r.Context())
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+	util.WriteJSON(w, http.StatusOK, org)
+}
+
+// Delete removes the organization
+func (h *OrganizationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	if err := h.orgService.Delete(ctx, org.ID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "delete_organization")
+		return
+	}
+
+	h.logger.Info(ctx, "Organization deleted", "organization_id", org.ID)
+	util.WriteJSON(w, http.StatusOK, org)
+}
+
+// ListMembers returns the organization's members
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	members, err := h.orgService.ListMembers(ctx, org.ID)
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "list_organization_members")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, members)
+}
+
+// UpdateMemberRole changes a member's role in the organization
+func (h *OrganizationHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	var req updateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, ok := memberIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.UpdateMemberRole(ctx, org, userID, req.Role); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "update_organization_member")
+		return
+	}
+
+	h.logger.Info(ctx, "Organization member role updated", "organization_id", org.ID, "member_id", userID, "role", string(req.Role))
+	util.WriteJSON(w, http.StatusOK, map[string]string{"userId": userID, "role": string(req.Role)})
+}
+
+// RemoveMember removes a member from the organization
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	userID, ok := memberIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.RemoveMember(ctx, org, userID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "remove_organization_member")
+		return
+	}
+
+	h.logger.Info(ctx, "Organization member removed", "organization_id", org.ID, "member_id", userID)
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Member removed"})
+}
+
+// CreateTeam creates a team in the organization
+func (h *OrganizationHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	var req createTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Name == "" {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "missing_name")))
+		util.WriteError(w, http.StatusBadRequest, "Missing team name")
+		return
+	}
+
+	team, err := h.orgService.CreateTeam(ctx, org.ID, req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrDuplicateTeam) {
+			util.WriteErrorFields(w, "Team name already exists", []string{"name"})
+			return
+		}
+		writeMembershipError(w, r, h.metrics, h.logger, err, "create_team")
+		return
+	}
+
+	h.logger.Info(ctx, "Team created", "organization_id", org.ID, "team_id", team.ID)
+	util.WriteJSON(w, http.StatusCreated, team)
+}
+
+// ListTeams returns the organization's teams
+func (h *OrganizationHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return
+	}
+
+	teams, err := h.orgService.ListTeams(ctx, org.ID)
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "list_teams")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, teams)
+}
+
+// DeleteTeam removes a team from the organization
+func (h *OrganizationHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	team, ok := h.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.DeleteTeam(ctx, team.ID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "delete_team")
+		return
+	}
+
+	h.logger.Info(ctx, "Team deleted", "team_id", team.ID)
+	util.WriteJSON(w, http.StatusOK, team)
+}
+
+// AddTeamMember adds an organization member to a team
+func (h *OrganizationHandler) AddTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req addTeamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		util.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	team, ok := h.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.AddTeamMember(ctx, team, req.UserID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "add_team_member")
+		return
+	}
+
+	h.logger.Info(ctx, "Team member added", "team_id", team.ID, "member_id", req.UserID)
+	util.WriteJSON(w, http.StatusOK, map[string]string{"teamId": team.ID, "userId": req.UserID})
+}
+
+// RemoveTeamMember removes a user from a team
+func (h *OrganizationHandler) RemoveTeamMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := memberIDParam(w, r)
+	if !ok {
+		return
+	}
+	team, ok := h.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.RemoveTeamMember(ctx, team, userID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "remove_team_member")
+		return
+	}
+
+	h.logger.Info(ctx, "Team member removed", "team_id", team.ID, "member_id", userID)
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Member removed"})
+}
+
+// team resolves the {team_slug} route param within the authorized organization
+func (h *OrganizationHandler) team(w http.ResponseWriter, r *http.Request) (*models.Team, bool) {
+	ctx := r.Context()
+	org, ok := util.GetOrganizationFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+		return nil, false
+	}
+
+	team, err := h.orgService.GetTeam(ctx, org.ID, chi.URLParam(r, "team_slug"))
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "get_team")
+		return nil, false
+	}
+	return team, true
+}
+
+// memberIDParam reads and validates the {user_id} route param
+func memberIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := chi.URLParam(r, "user_id")
+	if _, err := uuid.Parse(userID); err != nil {
+		util.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return "", false
+	}
+	return userID, true
+}
+
+// writeMembershipError maps organization, team and membership errors to responses
+func writeMembershipError(w http.ResponseWriter, r *http.Request, metrics *otel.Metrics, log *logger.Logger, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrOrganizationNotFound):
+		util.WriteError(w, http.StatusNotFound, "Organization not found")
+	case errors.Is(err, service.ErrTeamNotFound):
+		util.WriteError(w, http.StatusNotFound, "Team not found")
+	case errors.Is(err, service.ErrMemberNotFound):
+		util.WriteError(w, http.StatusNotFound, "Member not found")
+	case errors.Is(err, service.ErrInvalidRole):
+		util.WriteError(w, http.StatusBadRequest, "Invalid role")
+	case errors.Is(err, service.ErrInsufficientRole):
+		util.WriteError(w, http.StatusForbidden, "Only owners can grant or revoke the owner role")
+	case errors.Is(err, service.ErrLastOwner):
+		util.WriteError(w, http.StatusConflict, "Cannot remove or demote the last owner")
+	default:
+		metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_failed")))
+		log.Error(r.Context(), "Failed to "+action, err)
+		util.WriteError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/otlp"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+)
+
+// maxOTLPRequestSize caps decompressed OTLP requests, as the collector does
+const maxOTLPRequestSize = 20 << 20
+
+// OTLPHandler receives OTLP/HTTP exports from OpenTelemetry SDKs and
+// collectors, authenticated by project key
+type OTLPHandler struct {
+	otlpService *service.OTLPService
+	metrics     *otel.Metrics
+	logger      *logger.Logger
+	tracer      trace.Tracer
+}
+
+func NewOTLPHandler(otlpService *service.OTLPService, metrics *otel.Metrics, logger *logger.Logger, tracer trace.Tracer) *OTLPHandler {
+	return &OTLPHandler{
+		otlpService: otlpService,
+		metrics:     metrics,
+		logger:      logger,
+		tracer:      tracer,
+	}
+}
+
+// Traces receives spans; exceptions recorded on them are queued as errors
+func (h *OTLPHandler) Traces(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, otlp.Traces)
+}
+
+// Logs receives log records
+func (h *OTLPHandler) Logs(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, otlp.Logs)
+}
+
+// Metrics receives metrics
+func (h *OTLPHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	h.export(w, r, otlp.Metrics)
+}
+
+func (h *OTLPHandler) export(w http.ResponseWriter, r *http.Request, signal otlp.Signal) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "OTLPExport")
+	defer span.End()
+	span.SetAttributes(attribute.String("signal", string(signal)))
+
+	key, ok := util.GetProjectKeyFromContext(ctx)
+	if !ok {
+		h.writeOTLPError(w, r, span, errors.New("missing project key"), http.StatusUnauthorized, "missing_project_key")
+		return
+	}
+	projectID := key.ProjectID
+
+	mediaType, err := otlp.MediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		h.writeOTLPError(w, r, span, err, http.StatusUnsupportedMediaType, "unsupported_content_type")
+		return
+	}
+
+	body, err := util.ReadBody(r.Body, r.Header.Get("Content-Encoding"), maxOTLPRequestSize)
+	switch {
+	case errors.Is(err, util.ErrBodyTooLarge):
+		h.writeOTLPError(w, r, span, err, http.StatusRequestEntityTooLarge, "otlp_payload_too_large")
+		return
+	case errors.Is(err, util.ErrUnsupportedEncoding):
+		h.writeOTLPError(w, r, span, err, http.StatusUnsupportedMediaType, "unsupported_encoding")
+		return
+	case err != nil:
+		h.writeOTLPError(w, r, span, err, http.StatusBadRequest, "invalid_body")
+		return
+	}
+
+	outcomes, err := h.otlpService.Export(ctx, key, signal, mediaType, body)
+	switch {
+	case errors.Is(err, otlp.ErrInvalidRequest):
+		h.writeOTLPError(w, r, span, err, http.StatusBadRequest, "invalid_otlp_request")
+		return
+	case errors.Is(err, service.ErrTelemetryUnavailable):
+		// 503 tells exporters to retry later
+		h.logger.Error(ctx, "Failed to forward OTLP export", err, "signal", string(signal))
+		h.writeOTLPError(w, r, span, err, http.StatusServiceUnavailable, "otlp_forward_failed")
+		return
+	case err != nil:
+		h.logger.Error(ctx, "Failed to export OTLP telemetry", err, "signal", string(signal))
+		h.writeOTLPError(w, r, span, err, http.StatusInternalServerError, "otlp_export_failed")
+		return
+	}
+	for outcome, n := range outcomes {
+		recordIngestOutcome(ctx, h.metrics, projectID, outcome, n)
+	}
+
+	resp, err := otlp.Marshal(mediaType, otlp.NewResponse(signal))
+	if err != nil {
+		h.logger.Error(ctx, "Failed to encode OTLP response", err)
+		h.writeOTLPError(w, r, span, err, http.StatusInternalServerError, "otlp_response_failed")
+		return
+	}
+
+	actor, _ := ingestActor(ctx, h.metrics)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "telemetry_"+string(signal)+"_exported"),
+		attribute.String("user_id", actor),
+		attribute.String("project_id", projectID),
+	))
+	span.SetStatus(codes.Ok, "OTLP export forwarded successfully")
+	span.SetAttributes(
+		attribute.String("project_id", projectID),
+		attribute.Int("exceptions_queued", outcomes[models.IngestAccepted]),
+	)
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
+
+func (h *OTLPHandler) writeOTLPError(w http.ResponseWriter, r *http.Request, span trace.Span, err error, status int, errorType string) {
+	h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(
+		attribute.String("error_type", errorType),
+	))
+	span.SetStatus(codes.Error, err.Error())
+	span.RecordError(err)
+	switch status {
+	case http.StatusInternalServerError:
+		util.WriteError(w, status, "Failed to export telemetry")
+	case http.StatusServiceUnavailable:
+		w.Header().Set("Retry-After", "5")
+		util.WriteError(w, status, "Telemetry backend unavailable")
+	default:
+		util.WriteError(w, status, err.Error())
+	}
+}
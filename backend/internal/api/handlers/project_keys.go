@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type ProjectKeyHandler struct {
+	metrics           *otel.Metrics
+	projectKeyService *service.ProjectKeyService
+	logger            *logger.Logger
+}
+
+func NewProjectKeyHandler(projectKeyService *service.ProjectKeyService, metrics *otel.Metrics, logger *logger.Logger) *ProjectKeyHandler {
+	return &ProjectKeyHandler{
+		metrics:           metrics,
+		projectKeyService: projectKeyService,
+		logger:            logger,
+	}
+}
+
+type createProjectKeyRequest struct {
+	Name string `json:"name"`
+}
+
+// Create issues a new ingest key for the project
+func (h *ProjectKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req createProjectKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, project, ok := h.ownedProject(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.projectKeyService.Create(ctx, project.ID, req.Name, userID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "create_project_key_failed")))
+		h.logger.Error(ctx, "Failed to create project key", err)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to create project key")
+		return
+	}
+	key.DSN = service.BuildDSN(publicBaseURL(r), key)
+	key.SentryDSN = service.BuildSentryDSN(publicBaseURL(r), key)
+
+	ctx = logger.WithProjectID(ctx, project.ID)
+	h.logger.Info(ctx, "Project key created", "key_id", key.ID)
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_project_key"),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", project.ID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, key)
+}
+
+type updateProjectKeyRequest struct {
+	RateLimit *int `json:"rateLimit"`
+}
+
+// Update sets the rate limit of an ingest key
+func (h *ProjectKeyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req updateProjectKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RateLimit == nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "rateLimit is required")
+		return
+	}
+
+	userID, project, ok := h.ownedProject(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.projectKeyService.UpdateRateLimit(ctx, project.ID, chi.URLParam(r, "key_id"), *req.RateLimit)
+	if err != nil {
+		h.writeKeyError(w, r, err, "update")
+		return
+	}
+	if key.IsActive() {
+		key.DSN = service.BuildDSN(publicBaseURL(r), key)
+		key.SentryDSN = service.BuildSentryDSN(publicBaseURL(r), key)
+	}
+
+	ctx = logger.WithProjectID(ctx, project.ID)
+	h.logger.Info(ctx, "Project key rate limit updated", "key_id", key.ID, "rate_limit", key.RateLimit)
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "update_project_key"),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", project.ID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, key)
+}
+
+// List returns every ingest key of the project
+func (h *ProjectKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	_, project, ok := h.ownedProject(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.projectKeyService.ListByProject(ctx, project.ID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_project_keys_failed")))
+		h.logger.Error(ctx, "Failed to list project keys", err)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch project keys")
+		return
+	}
+
+	baseURL := publicBaseURL(r)
+	for _, key := range keys {
+		if key.IsActive() {
+			key.DSN = service.BuildDSN(baseURL, key)
+			key.SentryDSN = service.BuildSentryDSN(baseURL, key)
+		}
+	}
+
+	util.WriteJSON(w, http.StatusOK, keys)
+}
+
+// Rotate replaces the secret of an ingest key
+func (h *ProjectKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, project, ok := h.ownedProject(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.projectKeyService.Rotate(ctx, project.ID, chi.URLParam(r, "key_id"))
+	if err != nil {
+		h.writeKeyError(w, r, err, "rotate")
+		return
+	}
+	key.DSN = service.BuildDSN(publicBaseURL(r), key)
+	key.SentryDSN = service.BuildSentryDSN(publicBaseURL(r), key)
+
+	ctx = logger.WithProjectID(ctx, project.ID)
+	h.logger.Info(ctx, "Project key rotated", "key_id", key.ID)
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "rotate_project_key"),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", project.ID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, key)
+}
+
+// Revoke disables an ingest key
+func (h *ProjectKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, project, ok := h.ownedProject(w, r)
+	if !ok {
+		return
+	}
+
+	key, err := h.projectKeyService.Revoke(ctx, project.ID, chi.URLParam(r, "key_id"))
+	if err != nil {
+		h.writeKeyError(w, r, err, "revoke")
+		return
+	}
+
+	ctx = logger.WithProjectID(ctx, project.ID)
+	h.logger.Info(ctx, "Project key revoked", "key_id", key.ID)
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "revoke_project_key"),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", project.ID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, key)
+}
+
+// ownedProject returns the caller and the project authorized by the project access middleware
+func (h *ProjectKeyHandler) ownedProject(w http.ResponseWriter, r *http.Request) (string, *models.Project, bool) {
+	ctx := r.Context()
+
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return "", nil, false
+	}
+
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return "", nil, false
+	}
+
+	return userID, project, true
+}
+
+func (h *ProjectKeyHandler) writeKeyError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	if errors.Is(err, service.ErrProjectKeyNotFound) {
+		util.WriteError(w, http.StatusNotFound, "Project key not found")
+		return
+	}
+	if errors.Is(err, service.ErrInvalidRateLimit) {
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_project_key_failed")))
+	h.logger.Error(r.Context(), "Failed to "+action+" project key", err)
+	util.WriteError(w, http.StatusInternalServerError, "Failed to "+action+" project key")
+}
+
+// publicBaseURL is the externally reachable API address embedded in DSNs
+func publicBaseURL(r *http.Request) string {
+	if base := os.Getenv("PUBLIC_API_URL"); base != "" {
+		return base
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/util"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type grantTeamRequest struct {
+	TeamID string      `json:"teamId"`
+	Role   models.Role `json:"role"`
+}
+
+type projectMembersResponse struct {
+	Members []models.Member      `json:"members"`
+	Teams   []models.ProjectTeam `json:"teams"`
+}
+
+// ListMembers returns the project's members and the teams with access to it
+func (h *ProjectHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	members, teams, err := h.projectService.ListMembers(ctx, project.ID)
+	if err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "list_project_members")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, projectMembersResponse{Members: members, Teams: teams})
+}
+
+// UpdateMemberRole changes a member's role on the project
+func (h *ProjectHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	var req updateMemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, ok := memberIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.projectService.UpdateMemberRole(ctx, project, userID, req.Role); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "update_project_member")
+		return
+	}
+
+	h.logger.Info(ctx, "Project member role updated", "member_id", userID, "role", string(req.Role))
+	util.WriteJSON(w, http.StatusOK, map[string]string{"userId": userID, "role": string(req.Role)})
+}
+
+// RemoveMember removes a member from the project
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	userID, ok := memberIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.projectService.RemoveMember(ctx, project, userID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "remove_project_member")
+		return
+	}
+
+	h.logger.Info(ctx, "Project member removed", "member_id", userID)
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Member removed"})
+}
+
+// GrantTeam gives a team of the project's organization access to the project
+func (h *ProjectHandler) GrantTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	var req grantTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.projectService.GrantTeam(ctx, project, req.TeamID, req.Role); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "grant_project_team")
+		return
+	}
+
+	h.logger.Info(ctx, "Project team granted", "team_id", req.TeamID, "role", string(req.Role))
+	util.WriteJSON(w, http.StatusOK, map[string]string{"teamId": req.TeamID, "role": string(req.Role)})
+}
+
+// RevokeTeam removes a team's access to the project
+func (h *ProjectHandler) RevokeTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	teamID := chi.URLParam(r, "team_id")
+	if err := h.projectService.RevokeTeam(ctx, project.ID, teamID); err != nil {
+		writeMembershipError(w, r, h.metrics, h.logger, err, "revoke_project_team")
+		return
+	}
+
+	h.logger.Info(ctx, "Project team revoked", "team_id", teamID)
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Team access revoked"})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+)
+
+var errProjectScopeMismatch = errors.New("project_id does not match the authorized project")
+
+// scopedProjectID returns the project a request body refers to. Requests
+// authenticated by a project key are pinned to that key's project, and
+// requests that went through the project access middleware are pinned to the
+// authorized project; a conflicting project_id in the body is rejected.
+func scopedProjectID(ctx context.Context, requested string) (string, error) {
+	if key, ok := util.GetProjectKeyFromContext(ctx); ok {
+		if requested != "" && requested != key.ProjectID {
+			return "", errProjectScopeMismatch
+		}
+		return key.ProjectID, nil
+	}
+	if project, ok := util.GetProjectFromContext(ctx); ok {
+		if requested != "" && requested != project.ID {
+			return "", errProjectScopeMismatch
+		}
+		return project.ID, nil
+	}
+	return requested, nil
+}
+
+// authorizedProjectID returns the project set by the project access middleware
+func authorizedProjectID(ctx context.Context) (string, bool) {
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return project.ID, true
+}
+
+// ingestActor identifies who sent an ingest request, for activity metrics
+func ingestActor(ctx context.Context, metrics *otel.Metrics) (string, bool) {
+	if key, ok := util.GetProjectKeyFromContext(ctx); ok {
+		return "key:" + key.ID, true
+	}
+	return util.GetUserIDFromContext(ctx, metrics)
+}
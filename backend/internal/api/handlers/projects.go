@@ -12,7 +12,6 @@ import (
 	"pulseguard/pkg/logger"
 	"pulseguard/pkg/otel"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -33,8 +32,9 @@ func NewProjectHandler(projectService *service.ProjectService, metrics *otel.Met
 }
 
 type createProjectRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	OrganizationID string `json:"organizationId"`
 }
 
 type updateProjectRequest struct {
@@ -73,12 +73,17 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
 		return
 	}
-	project, err := h.projectService.Create(ctx, req.Name, req.Description, userID)
+	project, err := h.projectService.Create(ctx, req.Name, req.Description, userID, req.OrganizationID)
 	if err != nil {
 		if errors.Is(err, service.ErrDuplicateSlug) {
 			util.WriteErrorFields(w, "Project name already exists", []string{"name"})
 			return
 		}
+		if errors.Is(err, service.ErrOrganizationNotFound) || errors.Is(err, service.ErrOrganizationAccessDenied) {
+			h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "organization_access_denied")))
+			util.WriteError(w, http.StatusForbidden, "Only organization admins can create projects in it")
+			return
+		}
 
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "create_project_failed")))
 		util.WriteError(w, http.StatusInternalServerError, "Failed to create project")
@@ -99,8 +104,8 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSON(w, http.StatusCreated, project)
 }
 
-// ListByOwner handles fetches all projects owned by a specific user
-func (h *ProjectHandler) ListByOwner(w http.ResponseWriter, r *http.Request) {
+// List fetches all projects the user is a member of
+func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
 	if !ok {
@@ -109,7 +114,7 @@ func (h *ProjectHandler) ListByOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	projects, err := h.projectService.ListByOwner(ctx, userID)
+	projects, err := h.projectService.ListForUser(ctx, userID)
 	if err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_projects_failed")))
 		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch projects")
@@ -122,17 +127,20 @@ func (h *ProjectHandler) ListByOwner(w http.ResponseWriter, r *http.Request) {
 // GetBySlug fetches a project by its slug
 func (h *ProjectHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	slug := chi.URLParam(r, "slug")
+	authorized, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
 
-	project, err := h.projectService.GetBySlug(ctx, slug)
+	errorCount, err := h.projectService.ErrorCount(ctx, authorized.ID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			util.WriteError(w, http.StatusNotFound, "Project not found")
-			return
-		}
-		util.WriteError(w, http.StatusInternalServerError, "Failed to retrieve project")
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "get_project_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch project")
 		return
 	}
+	project := *authorized
+	project.ErrorCount = errorCount
 
 	// Set project ID in cookie
 	http.SetCookie(w, &http.Cookie{
@@ -150,15 +158,19 @@ func (h *ProjectHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
 // DeleteBySlug deletes a project by its slug
 func (h *ProjectHandler) DeleteBySlug(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	slug := chi.URLParam(r, "slug")
+	current, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
 
-	project, err := h.projectService.DeleteBySlug(ctx, slug)
+	project, err := h.projectService.Delete(ctx, current.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			util.WriteError(w, http.StatusNotFound, "Project not found")
 			return
 		}
-		util.WriteError(w, http.StatusInternalServerError, "Failed to retrieve project")
+		util.WriteError(w, http.StatusInternalServerError, "Failed to delete project")
 		return
 	}
 
@@ -171,7 +183,11 @@ func (h *ProjectHandler) DeleteBySlug(w http.ResponseWriter, r *http.Request) {
 // UpdateProject updates an existing project
 func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	oldSlug := chi.URLParam(r, "slug")
+	current, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
 
 	var req updateProjectRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -191,7 +207,7 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	project, err := h.projectService.UpdateProject(ctx, oldSlug, req.Name, req.Description, req.Slug)
+	project, err := h.projectService.UpdateProject(ctx, current, req.Name, req.Description, req.Slug)
 	if err != nil {
 		var pqe *pq.Error
 		// if project name already exists for user
@@ -215,7 +231,7 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSON(w, http.StatusOK, project)
 }
 
-// DeleteAllByOwner deletes all projects owned by a specific user
+// DeleteAllByOwner deletes all personal projects owned by a specific user
 func (h *ProjectHandler) DeleteAllByOwner(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type ReleaseHandler struct {
+	releaseService *service.ReleaseService
+	metrics        *otel.Metrics
+}
+
+func NewReleaseHandler(releaseService *service.ReleaseService, metrics *otel.Metrics) *ReleaseHandler {
+	return &ReleaseHandler{releaseService: releaseService, metrics: metrics}
+}
+
+type createReleaseRequest struct {
+	Version      string     `json:"version"`
+	Ref          string     `json:"ref"`
+	URL          string     `json:"url"`
+	DateReleased *time.Time `json:"date_released"`
+}
+
+type createDeployRequest struct {
+	Environment  string     `json:"environment"`
+	Name         string     `json:"name"`
+	URL          string     `json:"url"`
+	DateStarted  *time.Time `json:"date_started"`
+	DateFinished *time.Time `json:"date_finished"`
+}
+
+// List returns the project's most recent releases
+func (h *ReleaseHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	releases, err := h.releaseService.ListReleases(ctx, projectID)
+	if err != nil {
+		h.writeReleaseError(w, r, err, "list_releases_failed", "Failed to fetch releases")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, releases)
+}
+
+// Create adds a release to the project. Creating a release that already
+// exists fills in its details and responds 200 instead of 201.
+func (h *ReleaseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req createReleaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	release, created, err := h.releaseService.CreateRelease(ctx, &models.Release{
+		ProjectID:    projectID,
+		Version:      req.Version,
+		Ref:          req.Ref,
+		URL:          req.URL,
+		DateReleased: req.DateReleased,
+		CreatedBy:    userID,
+	})
+	if err != nil {
+		h.writeReleaseError(w, r, err, "create_release_failed", "Failed to create release")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_release"),
+		attribute.String("project_id", projectID),
+	))
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	util.WriteJSON(w, status, release)
+}
+
+// Get returns a release with its new issue count and latest deploy
+func (h *ReleaseHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	release, err := h.releaseService.GetRelease(ctx, projectID, chi.URLParam(r, "release"))
+	if err != nil {
+		h.writeReleaseError(w, r, err, "get_release_failed", "Failed to fetch release")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, release)
+}
+
+// ListDeploys returns the deploys of a release
+func (h *ReleaseHandler) ListDeploys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	deploys, err := h.releaseService.ListDeploys(ctx, projectID, chi.URLParam(r, "release"))
+	if err != nil {
+		h.writeReleaseError(w, r, err, "list_deploys_failed", "Failed to fetch deploys")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, deploys)
+}
+
+// CreateDeploy records a deploy of a release to an environment
+func (h *ReleaseHandler) CreateDeploy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req createDeployRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	deploy := &models.Deploy{
+		Environment: req.Environment,
+		Name:        req.Name,
+		URL:         req.URL,
+		DateStarted: req.DateStarted,
+		CreatedBy:   userID,
+	}
+	if req.DateFinished != nil {
+		deploy.DateFinished = *req.DateFinished
+	}
+
+	created, err := h.releaseService.CreateDeploy(ctx, projectID, chi.URLParam(r, "release"), deploy)
+	if err != nil {
+		h.writeReleaseError(w, r, err, "create_deploy_failed", "Failed to create deploy")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_deploy"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, created)
+}
+
+func (h *ReleaseHandler) writeReleaseError(w http.ResponseWriter, r *http.Request, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, service.ErrReleaseNotFound):
+		util.WriteError(w, http.StatusNotFound, "Release not found")
+	case errors.Is(err, service.ErrInvalidRelease), errors.Is(err, service.ErrInvalidDeploy):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_release")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", errorType)))
+		util.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// SamplingHandler manages the sampling configuration of projects and serves
+// it to SDKs that sample on the client
+type SamplingHandler struct {
+	samplingService *service.SamplingService
+	metrics         *otel.Metrics
+	logger          *logger.Logger
+}
+
+func NewSamplingHandler(samplingService *service.SamplingService, metrics *otel.Metrics, logger *logger.Logger) *SamplingHandler {
+	return &SamplingHandler{samplingService: samplingService, metrics: metrics, logger: logger}
+}
+
+type updateSamplingRequest struct {
+	SampleRate       float64            `json:"sample_rate"`
+	EnvironmentRates map[string]float64 `json:"environment_rates"`
+	TypeRates        map[string]float64 `json:"type_rates"`
+	KeepFirst        int                `json:"keep_first"`
+}
+
+// Get returns the project's sampling configuration
+func (h *SamplingHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	cfg, err := h.samplingService.Config(ctx, projectID)
+	if err != nil {
+		h.writeSamplingError(w, r, err, "get sampling configuration")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, cfg)
+}
+
+// ClientConfig returns the sampling configuration of the key's project, for
+// SDKs to sample with before sending events
+func (h *SamplingHandler) ClientConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key, ok := util.GetProjectKeyFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Missing project key")
+		return
+	}
+
+	cfg, err := h.samplingService.Config(ctx, key.ProjectID)
+	if err != nil {
+		h.writeSamplingError(w, r, err, "get sampling configuration")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, cfg)
+}
+
+// Update replaces the project's sampling configuration
+func (h *SamplingHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req updateSamplingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cfg := &models.SamplingConfig{
+		ProjectID:        projectID,
+		SampleRate:       req.SampleRate,
+		EnvironmentRates: req.EnvironmentRates,
+		TypeRates:        req.TypeRates,
+		KeepFirst:        req.KeepFirst,
+	}
+	if err := h.samplingService.UpdateConfig(ctx, cfg); err != nil {
+		h.writeSamplingError(w, r, err, "update sampling configuration")
+		return
+	}
+
+	ctx = logger.WithProjectID(ctx, projectID)
+	h.logger.Info(ctx, "Sampling configuration updated", "sample_rate", cfg.SampleRate, "keep_first", cfg.KeepFirst)
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "update_sampling"),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, cfg)
+}
+
+func (h *SamplingHandler) writeSamplingError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, service.ErrInvalidSamplingConfig):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_sampling_config")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", strings.ReplaceAll(action, " ", "_")+"_failed")))
+		h.logger.Error(r.Context(), "Failed to "+action, err)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action)
+	}
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type SavedViewHandler struct {
+	savedViewService *service.SavedViewService
+	metrics          *otel.Metrics
+}
+
+func NewSavedViewHandler(savedViewService *service.SavedViewService, metrics *otel.Metrics) *SavedViewHandler {
+	return &SavedViewHandler{savedViewService: savedViewService, metrics: metrics}
+}
+
+type savedViewRequest struct {
+	Name        string `json:"name"`
+	Query       string `json:"query"`
+	Environment string `json:"environment"`
+	Status      string `json:"status"`
+	Sort        string `json:"sort"`
+	Shared      bool   `json:"shared"`
+}
+
+func (req savedViewRequest) apply(view *models.SavedView) {
+	view.Name = req.Name
+	view.Query = req.Query
+	view.Environment = req.Environment
+	view.Status = req.Status
+	view.Sort = req.Sort
+	view.Shared = req.Shared
+}
+
+// List returns the caller's saved views and those shared with the project
+func (h *SavedViewHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	views, err := h.savedViewService.ListViews(ctx, projectID, userID)
+	if err != nil {
+		h.writeViewError(w, r, err, "list")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, views)
+}
+
+// Get returns a saved view the caller owns or that is shared
+func (h *SavedViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	view, err := h.savedViewService.GetView(ctx, projectID, chi.URLParam(r, "view_id"), userID)
+	if err != nil {
+		h.writeViewError(w, r, err, "get")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, view)
+}
+
+// Create saves a search as a view owned by the caller
+func (h *SavedViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req savedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	view := &models.SavedView{ProjectID: project.ID, OwnerID: userID}
+	req.apply(view)
+
+	created, err := h.savedViewService.CreateView(ctx, view, project.Role)
+	if err != nil {
+		h.writeViewError(w, r, err, "create")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_saved_view"),
+		attribute.String("project_id", project.ID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, created)
+}
+
+// Update replaces the name, search and sharing of a saved view
+func (h *SavedViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req savedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	view := &models.SavedView{ID: chi.URLParam(r, "view_id"), ProjectID: project.ID}
+	req.apply(view)
+
+	updated, err := h.savedViewService.UpdateView(ctx, view, userID, project.Role)
+	if err != nil {
+		h.writeViewError(w, r, err, "update")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, updated)
+}
+
+// Delete removes a saved view
+func (h *SavedViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, ok := util.GetProjectFromContext(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.savedViewService.DeleteView(ctx, project.ID, chi.URLParam(r, "view_id"), userID, project.Role); err != nil {
+		h.writeViewError(w, r, err, "delete")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Saved view deleted"})
+}
+
+func (h *SavedViewHandler) writeViewError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrSavedViewNotFound):
+		util.WriteError(w, http.StatusNotFound, "Saved view not found")
+	case errors.Is(err, service.ErrSavedViewForbidden):
+		util.WriteError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, service.ErrInvalidSavedView):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_saved_view")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", action+"_saved_view_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action+" saved view")
+	}
+}
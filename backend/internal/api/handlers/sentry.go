@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/sentry"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+)
+
+// Caps on the decompressed size of Sentry requests, as Sentry applies them
+const (
+	maxSentryEventSize    = 1 << 20
+	maxSentryEnvelopeSize = 20 << 20
+)
+
+// SentryStore receives an event sent by a Sentry SDK to the store endpoint
+func (h *ErrorHandler) SentryStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "SentryStore")
+	defer span.End()
+
+	projectID, body, ok := h.readSentryRequest(w, r, span, maxSentryEventSize)
+	if !ok {
+		return
+	}
+	event, err := sentry.ParseEvent(body)
+	if err != nil {
+		h.writeSentryError(w, r, span, err, http.StatusBadRequest, "invalid_sentry_event")
+		return
+	}
+
+	decision, err := h.queueSentryEvent(ctx, projectID, event)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		writeIngestQueueError(w, h.ingestQueue, err)
+		return
+	}
+	if !decision.Accepted() {
+		span.SetStatus(codes.Error, "Event rejected: "+decision.Outcome)
+		writeIngestRejected(w, decision)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Sentry event queued successfully")
+	span.SetAttributes(
+		attribute.String("project_id", projectID),
+		attribute.String("event_id", event.EventID),
+	)
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"id": event.EventID})
+}
+
+// SentryEnvelope receives an envelope sent by a Sentry SDK. Its error
+// events are tracked; other items, such as transactions and sessions, are
+// accepted and dropped. The envelope is turned away only when none of its
+// events was queued, since SDKs send it again whole; otherwise the events
+// the ingest limits or the queue turned away are dropped and reported in
+// the X-Sentry-Rate-Limits header.
+func (h *ErrorHandler) SentryEnvelope(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, span := h.tracer.Start(ctx, "SentryEnvelope")
+	defer span.End()
+
+	projectID, body, ok := h.readSentryRequest(w, r, span, maxSentryEnvelopeSize)
+	if !ok {
+		return
+	}
+	envelope, err := sentry.ParseEnvelope(body)
+	if err != nil {
+		h.writeSentryError(w, r, span, err, http.StatusBadRequest, "invalid_sentry_envelope")
+		return
+	}
+
+	var rejected []service.IngestDecision
+	queued := 0
+	for i, event := range envelope.Events {
+		decision, err := h.queueSentryEvent(ctx, projectID, event)
+		if err != nil && queued == 0 {
+			span.SetStatus(codes.Error, err.Error())
+			writeIngestQueueError(w, h.ingestQueue, err)
+			return
+		}
+		if err != nil {
+			// The queue takes no more for now; the events after this one are dropped too
+			recordIngestOutcome(ctx, h.metrics, projectID, service.IngestQueueOutcome(err), len(envelope.Events)-i-1)
+			rejected = append(rejected, service.IngestDecision{Outcome: service.IngestQueueOutcome(err), RetryAfter: h.ingestQueue.RetryAfter()})
+			break
+		}
+		if !decision.Accepted() {
+			rejected = append(rejected, decision)
+			continue
+		}
+		queued++
+	}
+	if queued == 0 && len(rejected) > 0 {
+		span.SetStatus(codes.Error, "Event rejected: "+rejected[0].Outcome)
+		writeIngestRejected(w, rejected[0])
+		return
+	}
+	if len(rejected) > 0 {
+		w.Header().Set("X-Sentry-Rate-Limits", sentryRateLimits(rejected))
+	}
+
+	span.SetStatus(codes.Ok, "Sentry envelope queued successfully")
+	span.SetAttributes(
+		attribute.String("project_id", projectID),
+		attribute.String("event_id", envelope.EventID),
+		attribute.Int("event_count", len(envelope.Events)),
+		attribute.Int("rejected_events", len(rejected)),
+		attribute.Int("skipped_items", envelope.Skipped),
+	)
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"id": envelope.EventID})
+}
+
+// readSentryRequest checks the project of a Sentry request and reads its
+// body. Sentry DSNs carry a numeric placeholder for the project, which the
+// key identifies; a project ID in the URL must match the key's project.
+func (h *ErrorHandler) readSentryRequest(w http.ResponseWriter, r *http.Request, span trace.Span, limit int64) (string, []byte, bool) {
+	requested := chi.URLParam(r, "project_id")
+	if _, err := uuid.Parse(requested); err != nil {
+		requested = ""
+	}
+	projectID, err := scopedProjectID(r.Context(), requested)
+	if err != nil || projectID == "" {
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(
+			attribute.String("error_type", "project_mismatch"),
+		))
+		span.SetStatus(codes.Error, "Project mismatch")
+		util.WriteError(w, http.StatusForbidden, "project_id does not match the project key")
+		return "", nil, false
+	}
+
+	body, err := util.ReadBody(r.Body, r.Header.Get("Content-Encoding"), limit)
+	switch {
+	case errors.Is(err, util.ErrBodyTooLarge):
+		h.writeSentryError(w, r, span, err, http.StatusRequestEntityTooLarge, "sentry_payload_too_large")
+		return "", nil, false
+	case errors.Is(err, util.ErrUnsupportedEncoding):
+		h.writeSentryError(w, r, span, err, http.StatusUnsupportedMediaType, "unsupported_encoding")
+		return "", nil, false
+	case err != nil:
+		h.writeSentryError(w, r, span, err, http.StatusBadRequest, "invalid_body")
+		return "", nil, false
+	}
+	return projectID, body, true
+}
+
+// queueSentryEvent checks an event against the ingest limits and queues
+// it. It returns the decision of the limits, and the error of the queue
+// when it turned away an admitted event.
+func (h *ErrorHandler) queueSentryEvent(ctx context.Context, projectID string, event *sentry.Event) (service.IngestDecision, error) {
+	decision := admitEvent(ctx, h.ingestLimiter, h.metrics)
+	if !decision.Accepted() {
+		return decision, nil
+	}
+
+	key, _ := util.GetProjectKeyFromContext(ctx)
+	errorData := event.ToError(projectID, time.Now())
+	if err := h.ingestQueue.Enqueue(key, errorData, event.Fingerprint, event.Metadata()); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "ingest_queue_rejected"),
+		))
+		refundEvent(ctx, h.ingestLimiter, service.IngestQueueOutcome(err))
+		recordIngestOutcome(ctx, h.metrics, projectID, service.IngestQueueOutcome(err), 1)
+		return decision, err
+	}
+	recordIngestOutcome(ctx, h.metrics, projectID, models.IngestAccepted, 1)
+
+	actor, _ := ingestActor(ctx, h.metrics)
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "error_queued"),
+		attribute.String("user_id", actor),
+		attribute.String("project_id", projectID),
+	))
+	return decision, nil
+}
+
+// sentryRateLimits formats the X-Sentry-Rate-Limits header for events of
+// an envelope that were turned away, asking the SDK to hold back its error
+// events for the longest of their waits
+func sentryRateLimits(rejected []service.IngestDecision) string {
+	var wait time.Duration
+	for _, decision := range rejected {
+		wait = max(wait, decision.RetryAfter)
+	}
+	return strconv.Itoa(retryAfterSeconds(wait)) + ":error:key"
+}
+
+func (h *ErrorHandler) writeSentryError(w http.ResponseWriter, r *http.Request, span trace.Span, err error, status int, errorType string) {
+	h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(
+		attribute.String("error_type", errorType),
+	))
+	span.SetStatus(codes.Error, err.Error())
+	span.RecordError(err)
+	util.WriteError(w, status, err.Error())
+}
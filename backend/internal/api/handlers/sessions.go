@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
@@ -46,7 +45,15 @@ func (h *SessionHandler) StartSession(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 
 	var req startSessionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeIngestBody(w, r, &req); err != nil {
+		if isBodyTooLarge(err) {
+			h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("error_type", "payload_too_large"),
+			))
+			span.SetStatus(codes.Error, "Request body too large")
+			util.WriteError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "invalid_body"),
 		))
@@ -57,6 +64,18 @@ func (h *SessionHandler) StartSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	projectID, err := scopedProjectID(ctx, req.ProjectID)
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("error_type", "project_mismatch"),
+		))
+		span.SetStatus(codes.Error, "Project mismatch")
+		h.logger.Error(ctx, "Start session request does not match the authorized project", err)
+		util.WriteError(w, http.StatusForbidden, "project_id does not match the authorized project")
+		return
+	}
+	req.ProjectID = projectID
+
 	if req.ProjectID == "" || req.SessionID == "" {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "missing_fields"),
@@ -67,8 +86,7 @@ func (h *SessionHandler) StartSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := uuid.Parse(req.ProjectID)
-	if err != nil {
+	if _, err := uuid.Parse(req.ProjectID); err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "invalid_project_id"),
 		))
@@ -132,7 +150,15 @@ func (h *SessionHandler) EndSession(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 
 	var req endSessionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeIngestBody(w, r, &req); err != nil {
+		if isBodyTooLarge(err) {
+			h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("error_type", "payload_too_large"),
+			))
+			span.SetStatus(codes.Error, "Request body too large")
+			util.WriteError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+			return
+		}
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "invalid_body"),
 		))
@@ -153,8 +179,11 @@ func (h *SessionHandler) EndSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Sessions may only be ended within the key or authorized project
+	projectID, _ := scopedProjectID(ctx, "")
+
 	endTime := time.Now()
-	if err := h.sessionService.EndSession(ctx, req.SessionID, endTime); err != nil {
+	if err := h.sessionService.EndSession(ctx, projectID, req.SessionID, endTime); err != nil {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "end_session_failed"),
 		))
@@ -187,8 +216,8 @@ func (h *SessionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
 	_, span := h.tracer.Start(ctx, "GetSessions")
 	defer span.End()
 
-	projectID := r.URL.Query().Get("project_id")
-	if projectID == "" {
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "missing_project_id"),
 		))
@@ -198,18 +227,6 @@ func (h *SessionHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := uuid.Parse(projectID)
-	if err != nil {
-		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
-			attribute.String("error_type", "invalid_project_id"),
-		))
-		span.SetStatus(codes.Error, "Invalid project_id")
-		span.RecordError(err)
-		h.logger.Error(ctx, "Invalid project_id format", err)
-		util.WriteError(w, http.StatusBadRequest, "Invalid project_id")
-		return
-	}
-
 	startStr := r.URL.Query().Get("start")
 	endStr := r.URL.Query().Get("end")
 	start, err := time.Parse(time.RFC3339, startStr)
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type SilenceHandler struct {
+	silenceService *service.SilenceService
+	metrics        *otel.Metrics
+}
+
+func NewSilenceHandler(silenceService *service.SilenceService, metrics *otel.Metrics) *SilenceHandler {
+	return &SilenceHandler{silenceService: silenceService, metrics: metrics}
+}
+
+type silenceRequest struct {
+	Matchers []models.Matcher `json:"matchers"`
+	Comment  string           `json:"comment"`
+	StartsAt time.Time        `json:"starts_at"`
+	EndsAt   time.Time        `json:"ends_at"`
+}
+
+type maintenanceWindowRequest struct {
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// ListSilences returns the project's current and upcoming silences, or all
+// of them with include_expired=true
+func (h *SilenceHandler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	silences, err := h.silenceService.ListSilences(ctx, projectID, r.URL.Query().Get("include_expired") == "true")
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_silences_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch silences")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, silences)
+}
+
+// CreateSilence silences the project's alerts matching the given labels
+func (h *SilenceHandler) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req silenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	silence, err := h.silenceService.CreateSilence(ctx, &models.Silence{
+		ProjectID: projectID,
+		Matchers:  req.Matchers,
+		Comment:   req.Comment,
+		CreatedBy: userID,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	})
+	if err != nil {
+		h.writeSilenceError(w, r, err, "create_silence_failed", "Failed to create silence")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_silence"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, silence)
+}
+
+// ExpireSilence ends a silence now
+func (h *SilenceHandler) ExpireSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	if err := h.silenceService.ExpireSilence(ctx, projectID, chi.URLParam(r, "silence_id")); err != nil {
+		h.writeSilenceError(w, r, err, "expire_silence_failed", "Failed to expire silence")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Silence expired"})
+}
+
+// ListMaintenanceWindows returns the project's current and upcoming
+// maintenance windows, or all of them with include_past=true
+func (h *SilenceHandler) ListMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	windows, err := h.silenceService.ListMaintenanceWindows(ctx, projectID, r.URL.Query().Get("include_past") == "true")
+	if err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "list_maintenance_windows_failed")))
+		util.WriteError(w, http.StatusInternalServerError, "Failed to fetch maintenance windows")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, windows)
+}
+
+// CreateMaintenanceWindow schedules a window during which the project sends no notifications
+func (h *SilenceHandler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	var req maintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := util.GetUserIDFromContext(ctx, h.metrics)
+	window, err := h.silenceService.CreateMaintenanceWindow(ctx, &models.MaintenanceWindow{
+		ProjectID: projectID,
+		Name:      req.Name,
+		CreatedBy: userID,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	})
+	if err != nil {
+		h.writeSilenceError(w, r, err, "create_maintenance_window_failed", "Failed to create maintenance window")
+		return
+	}
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "create_maintenance_window"),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusCreated, window)
+}
+
+// DeleteMaintenanceWindow removes a maintenance window
+func (h *SilenceHandler) DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+		return
+	}
+
+	if err := h.silenceService.DeleteMaintenanceWindow(ctx, projectID, chi.URLParam(r, "window_id")); err != nil {
+		h.writeSilenceError(w, r, err, "delete_maintenance_window_failed", "Failed to delete maintenance window")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, map[string]string{"message": "Maintenance window deleted"})
+}
+
+func (h *SilenceHandler) writeSilenceError(w http.ResponseWriter, r *http.Request, err error, errorType, message string) {
+	switch {
+	case errors.Is(err, service.ErrSilenceNotFound):
+		util.WriteError(w, http.StatusNotFound, "Silence not found")
+	case errors.Is(err, service.ErrMaintenanceWindowNotFound):
+		util.WriteError(w, http.StatusNotFound, "Maintenance window not found")
+	case errors.Is(err, service.ErrInvalidSilence), errors.Is(err, service.ErrInvalidMaintenanceWindow):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_silence")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", errorType)))
+		util.WriteError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -34,8 +35,8 @@ func (h *TracesHandler) ListTracesByProject(w http.ResponseWriter, r *http.Reque
 	ctx, span := spanutil.StartSpanFromRequest(h.tracer, r, "ListTracesByProject")
 	defer span.End()
 
-	projectID := r.URL.Query().Get("project_id")
-	if projectID == "" {
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
 		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(
 			attribute.String("error_type", "missing_project_id"),
 		))
@@ -96,7 +97,13 @@ func (h *TracesHandler) GetTraceByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	traceData, err := h.tracesService.GetTrace(ctx, traceID)
+	projectID, _ := authorizedProjectID(ctx)
+	traceData, err := h.tracesService.GetProjectTrace(ctx, projectID, traceID)
+	if errors.Is(err, service.ErrTraceNotFound) {
+		span.SetStatus(codes.Error, "Trace not found")
+		util.WriteError(w, http.StatusNotFound, "Trace not found")
+		return
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to fetch errors")
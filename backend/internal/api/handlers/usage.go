@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// UsageHandler reports the events a project sent and manages its ingest
+// limits
+type UsageHandler struct {
+	ingestLimiter *service.IngestLimiter
+	metrics       *otel.Metrics
+	logger        *logger.Logger
+}
+
+func NewUsageHandler(ingestLimiter *service.IngestLimiter, metrics *otel.Metrics, logger *logger.Logger) *UsageHandler {
+	return &UsageHandler{ingestLimiter: ingestLimiter, metrics: metrics, logger: logger}
+}
+
+type updateLimitsRequest struct {
+	RateLimit       int   `json:"rate_limit"`
+	MonthlyQuota    int64 `json:"monthly_quota"`
+	SpikeProtection *bool `json:"spike_protection"`
+}
+
+// GetUsage returns the project's accepted, rate limited and dropped events
+// in the current month, per day
+func (h *UsageHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	usage, err := h.ingestLimiter.Usage(ctx, projectID)
+	if err != nil {
+		h.writeUsageError(w, r, err, "get usage")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, usage)
+}
+
+// GetLimits returns the project's ingest limits
+func (h *UsageHandler) GetLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+
+	limits, err := h.ingestLimiter.Limits(ctx, projectID)
+	if err != nil {
+		h.writeUsageError(w, r, err, "get limits")
+		return
+	}
+
+	util.WriteJSON(w, http.StatusOK, limits)
+}
+
+// UpdateLimits replaces the project's ingest limits
+func (h *UsageHandler) UpdateLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID, ok := authorizedProjectID(ctx)
+	if !ok {
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	userID, ok := util.GetUserIDFromContext(ctx, h.metrics)
+	if !ok {
+		util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req updateLimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_body")))
+		util.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	limits := &models.IngestLimits{
+		ProjectID:       projectID,
+		RateLimit:       req.RateLimit,
+		MonthlyQuota:    req.MonthlyQuota,
+		SpikeProtection: req.SpikeProtection == nil || *req.SpikeProtection,
+	}
+	if err := h.ingestLimiter.UpdateLimits(ctx, limits); err != nil {
+		h.writeUsageError(w, r, err, "update limits")
+		return
+	}
+
+	ctx = logger.WithProjectID(ctx, projectID)
+	h.logger.Info(ctx, "Ingest limits updated",
+		"rate_limit", limits.RateLimit, "monthly_quota", limits.MonthlyQuota, "spike_protection", limits.SpikeProtection)
+
+	h.metrics.UserActivityTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("activity_type", "update_ingest_limits"),
+		attribute.String("user_id", userID),
+		attribute.String("project_id", projectID),
+	))
+
+	util.WriteJSON(w, http.StatusOK, limits)
+}
+
+func (h *UsageHandler) writeUsageError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		util.WriteError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, service.ErrInvalidIngestLimits):
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "invalid_ingest_limits")))
+		util.WriteError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", strings.ReplaceAll(action, " ", "_")+"_failed")))
+		h.logger.Error(r.Context(), "Failed to "+action, err)
+		util.WriteError(w, http.StatusInternalServerError, "Failed to "+action)
+	}
+}
@@ -270,7 +270,7 @@ func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		SessionID string `json:"sessionId"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err == nil && req.SessionID != "" {
-		if err := h.sessionService.EndSession(ctx, req.SessionID, time.Now()); err != nil {
+		if err := h.sessionService.EndSession(ctx, "", req.SessionID, time.Now()); err != nil {
 			h.logger.Error(ctx, "Failed to end session during logout", err)
 		} else {
 			h.metrics.ActiveSessions.Add(ctx, -1, metric.WithAttributes(
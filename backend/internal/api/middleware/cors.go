@@ -2,17 +2,55 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/cors"
 )
 
+var allowedOrigins = []string{"http://localhost:3000"}
+
+// publicPrefixes are the routes SDKs send events to from any origin. They
+// are authenticated by project key, never by cookie, so they are served
+// without credentials.
+var publicPrefixes = []string{"/api/ingest/", "/api/sentry/", "/api/otlp/"}
+
+// CORS lets any origin reach the public ingest routes and restricts
+// everything else, with credentials, to the dashboard.
 func CORS() func(http.Handler) http.Handler {
-	return cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:3000"},
+	dashboard := cors.Handler(cors.Options{
+		AllowedOrigins:   allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
+	public := cors.Handler(cors.Options{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Encoding", IngestKeyHeader, IngestDSNHeader, SentryAuthHeader},
+		ExposedHeaders:   []string{"Retry-After", "X-Sentry-Rate-Limits"},
+		AllowCredentials: false,
+		MaxAge:           300,
+	})
+
+	return func(next http.Handler) http.Handler {
+		dashboardNext, publicNext := dashboard(next), public(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path) {
+				publicNext.ServeHTTP(w, r)
+				return
+			}
+			dashboardNext.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isPublicPath(path string) bool {
+	for _, prefix := range publicPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
 }
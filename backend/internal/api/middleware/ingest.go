@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/sentry"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	IngestKeyHeader  = "X-PulseGuard-Key"
+	IngestDSNHeader  = "X-PulseGuard-DSN"
+	SentryAuthHeader = "X-Sentry-Auth"
+)
+
+// KeyAuthenticator resolves the public key of an ingest request to its
+// project key, failing with service.ErrInvalidProjectKey for unknown or
+// revoked keys. It is implemented by service.ProjectKeyService.
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, publicKey string) (*models.ProjectKey, error)
+}
+
+// IngestKey authenticates public ingestion routes with a project key instead
+// of a user session. The key is read from the X-PulseGuard-Key header, or
+// from a DSN passed in the X-PulseGuard-DSN header or the dsn query param.
+// Sentry SDKs send it as sentry_key in the X-Sentry-Auth header or the query.
+func IngestKey(keyService KeyAuthenticator, log *logger.Logger, metrics *otel.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			publicKey, dsnProjectID, err := extractIngestKey(r)
+			if err != nil {
+				metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_dsn")))
+				util.WriteError(w, http.StatusUnauthorized, "Invalid DSN")
+				return
+			}
+
+			key, err := keyService.Authenticate(ctx, publicKey)
+			if err != nil {
+				if !errors.Is(err, service.ErrInvalidProjectKey) {
+					log.Error(ctx, "Failed to authenticate project key", err)
+				}
+				metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "invalid_project_key")))
+				util.WriteError(w, http.StatusUnauthorized, "Invalid or missing project key")
+				return
+			}
+
+			if dsnProjectID != "" && dsnProjectID != key.ProjectID {
+				metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "dsn_project_mismatch")))
+				util.WriteError(w, http.StatusUnauthorized, "DSN does not match project key")
+				return
+			}
+
+			ctx = util.WithProjectKey(ctx, key)
+			ctx = logger.WithProjectID(ctx, key.ProjectID)
+			labelMetricsProject(ctx, key.ProjectID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func extractIngestKey(r *http.Request) (publicKey, projectID string, err error) {
+	if key := r.Header.Get(IngestKeyHeader); key != "" {
+		return key, "", nil
+	}
+	if key := sentry.AuthKey(r.Header.Get(SentryAuthHeader)); key != "" {
+		return key, "", nil
+	}
+	if key := r.URL.Query().Get("sentry_key"); key != "" {
+		return key, "", nil
+	}
+
+	dsn := r.Header.Get(IngestDSNHeader)
+	if dsn == "" {
+		dsn = r.URL.Query().Get("dsn")
+	}
+	if dsn == "" {
+		return "", "", nil
+	}
+	return service.ParseDSN(dsn)
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+func testMetrics(t *testing.T) *otel.Metrics {
+	t.Helper()
+	meter := noop.NewMeterProvider().Meter("test")
+	appErrors, err := meter.Int64Counter("app_errors_total")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	return &otel.Metrics{AppErrorsTotal: appErrors}
+}
+
+// servedBy runs a request through middleware and reports the status and the
+// context the next handler saw, which is nil when it was not called
+func servedBy(mw func(http.Handler) http.Handler, r *http.Request) (int, context.Context) {
+	var seen context.Context
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Context()
+		w.WriteHeader(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	mw(next).ServeHTTP(rec, r)
+	return rec.Code, seen
+}
+
+// fakeKeys authenticates the public keys it holds, like ProjectKeyService
+// does for active keys
+type fakeKeys map[string]*models.ProjectKey
+
+func (k fakeKeys) Authenticate(_ context.Context, publicKey string) (*models.ProjectKey, error) {
+	if publicKey == "broken" {
+		return nil, errors.New("database is down")
+	}
+	key, ok := k[publicKey]
+	if !ok {
+		return nil, service.ErrInvalidProjectKey
+	}
+	return key, nil
+}
+
+func TestIngestKey(t *testing.T) {
+	keys := fakeKeys{
+		"key1": {ID: "k1", ProjectID: "project-1", PublicKey: "key1"},
+		"key2": {ID: "k2", ProjectID: "project-2", PublicKey: "key2"},
+	}
+	mw := IngestKey(keys, logger.NewLogger(), testMetrics(t))
+
+	tests := []struct {
+		name        string
+		target      string
+		header      map[string]string
+		wantStatus  int
+		wantProject string
+	}{
+		{
+			name:        "key header",
+			target:      "/api/errors",
+			header:      map[string]string{IngestKeyHeader: "key1"},
+			wantStatus:  http.StatusOK,
+			wantProject: "project-1",
+		},
+		{
+			name:        "DSN header",
+			target:      "/api/errors",
+			header:      map[string]string{IngestDSNHeader: "https://key2@pulseguard.example.com/project-2"},
+			wantStatus:  http.StatusOK,
+			wantProject: "project-2",
+		},
+		{
+			name:        "DSN query",
+			target:      "/api/errors?dsn=https://key1@pulseguard.example.com/project-1",
+			wantStatus:  http.StatusOK,
+			wantProject: "project-1",
+		},
+		{
+			name:        "Sentry auth header",
+			target:      "/api/1/envelope/",
+			header:      map[string]string{SentryAuthHeader: "Sentry sentry_version=7, sentry_key=key2, sentry_client=sentry.javascript/8"},
+			wantStatus:  http.StatusOK,
+			wantProject: "project-2",
+		},
+		{
+			name:        "Sentry key query",
+			target:      "/api/1/store/?sentry_key=key1&sentry_version=7",
+			wantStatus:  http.StatusOK,
+			wantProject: "project-1",
+		},
+		{
+			name:       "DSN of another project",
+			target:     "/api/errors",
+			header:     map[string]string{IngestDSNHeader: "https://key1@pulseguard.example.com/project-2"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unknown key",
+			target:     "/api/errors",
+			header:     map[string]string{IngestKeyHeader: "revoked"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "unknown Sentry key",
+			target:     "/api/1/envelope/",
+			header:     map[string]string{SentryAuthHeader: "Sentry sentry_key=revoked"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no key",
+			target:     "/api/errors",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "DSN without a key",
+			target:     "/api/errors",
+			header:     map[string]string{IngestDSNHeader: "https://pulseguard.example.com/project-1"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "DSN without a project",
+			target:     "/api/errors",
+			header:     map[string]string{IngestDSNHeader: "https://key1@pulseguard.example.com/"},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "key lookup fails",
+			target:     "/api/errors",
+			header:     map[string]string{IngestKeyHeader: "broken"},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+
+			status, ctx := servedBy(mw, r)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if ctx != nil {
+					t.Error("rejected request reached the handler")
+				}
+				return
+			}
+
+			key, ok := util.GetProjectKeyFromContext(ctx)
+			if !ok || key.ProjectID != tt.wantProject {
+				t.Errorf("project key = %+v, want project %s", key, tt.wantProject)
+			}
+			if id, _ := logger.GetProjectIDFromContext(ctx); id != tt.wantProject {
+				t.Errorf("logger project_id = %q, want %q", id, tt.wantProject)
+			}
+		})
+	}
+}
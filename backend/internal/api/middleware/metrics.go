@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -30,12 +31,27 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return rw.ResponseWriter.Write(b)
 }
 
+// metricsProjectKey holds the *string that ProjectAccess and IngestKey fill
+// with the project they authorized, so requests are labelled after the fact
+type metricsProjectKey struct{}
+
+// labelMetricsProject labels the request's HTTP metrics with the project
+func labelMetricsProject(ctx context.Context, projectID string) {
+	if project, ok := ctx.Value(metricsProjectKey{}).(*string); ok {
+		*project = projectID
+	}
+}
+
+// Metrics records the HTTP metrics of every request. Requests to a project
+// that ProjectAccess or IngestKey authorized carry its project_id, which
+// the per-project dashboard filters on.
 func Metrics(metrics *otel.Metrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
 			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK} // Default status code
-			next.ServeHTTP(rw, r)
+			var projectID string
+			next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), metricsProjectKey{}, &projectID)))
 
 			durationMs := float64(time.Since(start).Milliseconds())
 			attrs := []attribute.KeyValue{
@@ -43,6 +59,9 @@ func Metrics(metrics *otel.Metrics) func(http.Handler) http.Handler {
 				attribute.String("http_method", r.Method),
 				attribute.String("status_code", fmt.Sprintf("%d", rw.statusCode)),
 			}
+			if projectID != "" {
+				attrs = append(attrs, attribute.String("project_id", projectID))
+			}
 
 			metrics.HTTPRequestsTotal.Add(r.Context(), 1, metric.WithAttributes(attrs...))
 			metrics.HTTPRequestDurationMs.Record(r.Context(), durationMs, metric.WithAttributes(attrs...))
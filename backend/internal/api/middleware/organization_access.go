@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// OrganizationAuthorizer resolves an organization for a user, failing with
+// service.ErrOrganizationNotFound or service.ErrOrganizationAccessDenied. It
+// is implemented by service.OrganizationService.
+type OrganizationAuthorizer interface {
+	Authorize(ctx context.Context, userID, slug string) (*models.Organization, error)
+}
+
+// OrganizationAccess authorizes the authenticated user for the organization
+// named by the {org_slug} route param. Unknown organizations get a 404 and
+// non-members get a 403. The organization, with the caller's role, is stored
+// in the request context for handlers.
+func OrganizationAccess(orgService OrganizationAuthorizer, log *logger.Logger, metrics *otel.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			userID, ok := util.GetUserIDFromContext(ctx, metrics)
+			if !ok {
+				util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			org, err := orgService.Authorize(ctx, userID, chi.URLParam(r, "org_slug"))
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrOrganizationNotFound):
+					metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "organization_not_found")))
+					util.WriteError(w, http.StatusNotFound, "Organization not found")
+				case errors.Is(err, service.ErrOrganizationAccessDenied):
+					metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "organization_access_denied")))
+					log.Error(ctx, "Organization access denied", nil, "user_id", userID)
+					util.WriteError(w, http.StatusForbidden, "You do not have access to this organization")
+				default:
+					log.Error(ctx, "Failed to authorize organization access", err)
+					util.WriteError(w, http.StatusInternalServerError, "Failed to authorize organization access")
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(util.WithOrganization(ctx, org)))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ProjectAuthorizer resolves a project for a user, failing with
+// service.ErrProjectNotFound or service.ErrProjectAccessDenied. It is
+// implemented by service.ProjectService.
+type ProjectAuthorizer interface {
+	Authorize(ctx context.Context, userID, projectID string) (*models.Project, error)
+	AuthorizeSlug(ctx context.Context, userID, slug string) (*models.Project, error)
+}
+
+// ProjectAccess authorizes the authenticated user for the project a request
+// is scoped to. The project is taken from the {slug} or {project_id} route
+// param, falling back to the project_id query param, X-Project-ID header or
+// pulseguard_project_id cookie. Unknown projects get a 404 and projects the
+// user may not access get a 403. The authorized project is stored in the
+// request context for handlers.
+func ProjectAccess(projectService ProjectAuthorizer, log *logger.Logger, metrics *otel.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			userID, ok := util.GetUserIDFromContext(ctx, metrics)
+			if !ok {
+				util.WriteError(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			var project *models.Project
+			var err error
+			if slug := chi.URLParam(r, "slug"); slug != "" {
+				project, err = projectService.AuthorizeSlug(ctx, userID, slug)
+			} else {
+				projectID := chi.URLParam(r, "project_id")
+				if projectID == "" {
+					projectID, _ = logger.GetProjectIDFromContext(ctx)
+				}
+				if projectID == "" {
+					metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "missing_project_id")))
+					util.WriteError(w, http.StatusBadRequest, "Missing project_id")
+					return
+				}
+				project, err = projectService.Authorize(ctx, userID, projectID)
+			}
+
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrProjectNotFound):
+					metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "project_not_found")))
+					util.WriteError(w, http.StatusNotFound, "Project not found")
+				case errors.Is(err, service.ErrProjectAccessDenied):
+					metrics.AppErrorsTotal.Add(ctx, 1, metric.WithAttributes(attribute.String("error_type", "project_access_denied")))
+					log.Error(ctx, "Project access denied", nil, "user_id", userID)
+					util.WriteError(w, http.StatusForbidden, "You do not have access to this project")
+				default:
+					log.Error(ctx, "Failed to authorize project access", err)
+					util.WriteError(w, http.StatusInternalServerError, "Failed to authorize project access")
+				}
+				return
+			}
+
+			ctx = util.WithProject(ctx, project)
+			ctx = logger.WithProjectID(ctx, project.ID)
+			labelMetricsProject(ctx, project.ID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// fakeProjects authorizes users by the roles it holds per project, like
+// ProjectService does with the roles stored in the database
+type fakeProjects struct {
+	projects map[string]*models.Project
+	roles    map[string]map[string]models.Role // project ID -> user ID -> role
+}
+
+func (f *fakeProjects) Authorize(_ context.Context, userID, projectID string) (*models.Project, error) {
+	if projectID == "broken" {
+		return nil, errors.New("database is down")
+	}
+	project, ok := f.projects[projectID]
+	if !ok {
+		return nil, service.ErrProjectNotFound
+	}
+	role := f.roles[projectID][userID]
+	if role == "" {
+		return nil, service.ErrProjectAccessDenied
+	}
+	authorized := *project
+	authorized.Role = role
+	return &authorized, nil
+}
+
+func (f *fakeProjects) AuthorizeSlug(ctx context.Context, userID, slug string) (*models.Project, error) {
+	for id, project := range f.projects {
+		if project.Slug == slug {
+			return f.Authorize(ctx, userID, id)
+		}
+	}
+	return nil, service.ErrProjectNotFound
+}
+
+func withUser(r *http.Request, userID string) *http.Request {
+	if userID == "" {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), "user_id", userID))
+}
+
+// routed serves a request through a chi router, so that the middleware sees
+// the route params of the pattern
+func routed(mw func(http.Handler) http.Handler, pattern string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		router := chi.NewRouter()
+		router.With(mw).Handle(pattern, next)
+		return router
+	}
+}
+
+func TestProjectAccess(t *testing.T) {
+	projects := &fakeProjects{
+		projects: map[string]*models.Project{
+			"project-1": {ID: "project-1", Slug: "shop"},
+			"project-2": {ID: "project-2", Slug: "blog"},
+		},
+		roles: map[string]map[string]models.Role{
+			"project-1": {"alice": models.RoleOwner, "bob": models.RoleViewer},
+			"project-2": {"bob": models.RoleAdmin},
+		},
+	}
+	mw := ProjectAccess(projects, logger.NewLogger(), testMetrics(t))
+
+	tests := []struct {
+		name        string
+		pattern     string
+		target      string
+		header      map[string]string
+		userID      string
+		wantStatus  int
+		wantProject string
+		wantRole    models.Role
+	}{
+		{
+			name:        "slug route",
+			pattern:     "/projects/{slug}",
+			target:      "/projects/shop",
+			userID:      "alice",
+			wantStatus:  http.StatusOK,
+			wantProject: "project-1",
+			wantRole:    models.RoleOwner,
+		},
+		{
+			name:        "project_id route",
+			pattern:     "/projects/id/{project_id}/errors",
+			target:      "/projects/id/project-1/errors",
+			userID:      "bob",
+			wantStatus:  http.StatusOK,
+			wantProject: "project-1",
+			wantRole:    models.RoleViewer,
+		},
+		{
+			name:        "project_id query",
+			pattern:     "/errors",
+			target:      "/errors?project_id=project-2",
+			userID:      "bob",
+			wantStatus:  http.StatusOK,
+			wantProject: "project-2",
+			wantRole:    models.RoleAdmin,
+		},
+		{
+			name:        "X-Project-ID header",
+			pattern:     "/errors",
+			target:      "/errors",
+			header:      map[string]string{"X-Project-ID": "project-2"},
+			userID:      "bob",
+			wantStatus:  http.StatusOK,
+			wantProject: "project-2",
+			wantRole:    models.RoleAdmin,
+		},
+		{
+			name:       "route param wins over query",
+			pattern:    "/projects/id/{project_id}/errors",
+			target:     "/projects/id/project-2/errors?project_id=project-1",
+			userID:     "alice",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "non-member",
+			pattern:    "/projects/id/{project_id}/errors",
+			target:     "/projects/id/project-2/errors",
+			userID:     "alice",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "non-member by slug",
+			pattern:    "/projects/{slug}",
+			target:     "/projects/blog",
+			userID:     "alice",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "unknown project",
+			pattern:    "/projects/id/{project_id}/errors",
+			target:     "/projects/id/project-3/errors",
+			userID:     "alice",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown slug",
+			pattern:    "/projects/{slug}",
+			target:     "/projects/wiki",
+			userID:     "alice",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "no project",
+			pattern:    "/errors",
+			target:     "/errors",
+			userID:     "alice",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "no user",
+			pattern:    "/projects/{slug}",
+			target:     "/projects/shop",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "lookup fails",
+			pattern:    "/projects/id/{project_id}/errors",
+			target:     "/projects/id/broken/errors",
+			userID:     "alice",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			// The router resolves the project_id fallbacks before ProjectAccess runs
+			stack := func(next http.Handler) http.Handler { return ProjectIDMiddleware(mw(next)) }
+
+			status, ctx := servedBy(routed(stack, tt.pattern), withUser(r, tt.userID))
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if ctx != nil {
+					t.Error("rejected request reached the handler")
+				}
+				return
+			}
+
+			project, ok := util.GetProjectFromContext(ctx)
+			if !ok || project.ID != tt.wantProject || project.Role != tt.wantRole {
+				t.Errorf("project = %+v, want %s as %s", project, tt.wantProject, tt.wantRole)
+			}
+		})
+	}
+}
@@ -14,7 +14,12 @@ func ProjectIDMiddleware(next http.Handler) http.Handler {
 			ctx = logger.WithProjectID(ctx, cookie.Value)
 		}
 
-		// 2. Fallback to query param (if set)
+		// 2. Explicit header sent by server-side callers
+		if projectID := r.Header.Get("X-Project-ID"); projectID != "" {
+			ctx = logger.WithProjectID(ctx, projectID)
+		}
+
+		// 3. Fallback to query param (if set)
 		if projectID := r.URL.Query().Get("project_id"); projectID != "" {
 			ctx = logger.WithProjectID(ctx, projectID)
 		}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RequireProjectRole rejects callers whose role on the project authorized by
+// ProjectAccess is weaker than min. It must run after ProjectAccess.
+func RequireProjectRole(min models.Role, metrics *otel.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			project, ok := util.GetProjectFromContext(r.Context())
+			if !ok {
+				util.WriteError(w, http.StatusNotFound, "Project not found")
+				return
+			}
+			if !project.Role.AtLeast(min) {
+				metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "insufficient_project_role")))
+				util.WriteError(w, http.StatusForbidden, "This action requires the "+string(min)+" role on the project")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireOrganizationRole rejects callers whose role in the organization
+// authorized by OrganizationAccess is weaker than min. It must run after
+// OrganizationAccess.
+func RequireOrganizationRole(min models.Role, metrics *otel.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			org, ok := util.GetOrganizationFromContext(r.Context())
+			if !ok {
+				util.WriteError(w, http.StatusNotFound, "Organization not found")
+				return
+			}
+			if !org.Role.AtLeast(min) {
+				metrics.AppErrorsTotal.Add(r.Context(), 1, metric.WithAttributes(attribute.String("error_type", "insufficient_organization_role")))
+				util.WriteError(w, http.StatusForbidden, "This action requires the "+string(min)+" role in the organization")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
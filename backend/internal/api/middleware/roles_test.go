@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/service"
+	"pulseguard/internal/util"
+	"pulseguard/pkg/logger"
+)
+
+func TestRequireProjectRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       models.Role
+		min        models.Role
+		wantStatus int
+	}{
+		{"viewer reads", models.RoleViewer, models.RoleViewer, http.StatusOK},
+		{"viewer writes", models.RoleViewer, models.RoleMember, http.StatusForbidden},
+		{"member writes", models.RoleMember, models.RoleMember, http.StatusOK},
+		{"member manages", models.RoleMember, models.RoleAdmin, http.StatusForbidden},
+		{"admin manages", models.RoleAdmin, models.RoleAdmin, http.StatusOK},
+		{"admin deletes", models.RoleAdmin, models.RoleOwner, http.StatusForbidden},
+		{"owner deletes", models.RoleOwner, models.RoleOwner, http.StatusOK},
+		{"unknown role", models.Role("guest"), models.RoleViewer, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r = r.WithContext(util.WithProject(r.Context(), &models.Project{ID: "project-1", Role: tt.role}))
+
+			status, ctx := servedBy(RequireProjectRole(tt.min, testMetrics(t)), r)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK && ctx != nil {
+				t.Error("rejected request reached the handler")
+			}
+		})
+	}
+
+	t.Run("without ProjectAccess", func(t *testing.T) {
+		status, _ := servedBy(RequireProjectRole(models.RoleViewer, testMetrics(t)), httptest.NewRequest(http.MethodGet, "/", nil))
+		if status != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", status, http.StatusNotFound)
+		}
+	})
+}
+
+func TestRequireOrganizationRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       models.Role
+		min        models.Role
+		wantStatus int
+	}{
+		{"member writes", models.RoleMember, models.RoleMember, http.StatusOK},
+		{"viewer writes", models.RoleViewer, models.RoleMember, http.StatusForbidden},
+		{"member invites", models.RoleMember, models.RoleAdmin, http.StatusForbidden},
+		{"admin invites", models.RoleAdmin, models.RoleAdmin, http.StatusOK},
+		{"admin deletes", models.RoleAdmin, models.RoleOwner, http.StatusForbidden},
+		{"owner deletes", models.RoleOwner, models.RoleOwner, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r = r.WithContext(util.WithOrganization(r.Context(), &models.Organization{ID: "org-1", Role: tt.role}))
+
+			status, ctx := servedBy(RequireOrganizationRole(tt.min, testMetrics(t)), r)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK && ctx != nil {
+				t.Error("rejected request reached the handler")
+			}
+		})
+	}
+
+	t.Run("without OrganizationAccess", func(t *testing.T) {
+		status, _ := servedBy(RequireOrganizationRole(models.RoleViewer, testMetrics(t)), httptest.NewRequest(http.MethodGet, "/", nil))
+		if status != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", status, http.StatusNotFound)
+		}
+	})
+}
+
+// fakeOrganizations authorizes members by their role per organization slug
+type fakeOrganizations map[string]map[string]models.Role // slug -> user ID -> role
+
+func (f fakeOrganizations) Authorize(_ context.Context, userID, slug string) (*models.Organization, error) {
+	members, ok := f[slug]
+	if !ok {
+		return nil, service.ErrOrganizationNotFound
+	}
+	role := members[userID]
+	if role == "" {
+		return nil, service.ErrOrganizationAccessDenied
+	}
+	return &models.Organization{ID: "org-" + slug, Slug: slug, Role: role}, nil
+}
+
+func TestOrganizationAccess(t *testing.T) {
+	orgs := fakeOrganizations{
+		"acme":   {"alice": models.RoleOwner, "bob": models.RoleViewer},
+		"globex": {"bob": models.RoleAdmin},
+	}
+	// Organization settings need admins, as the organization routes do
+	stack := func(next http.Handler) http.Handler {
+		return OrganizationAccess(orgs, logger.NewLogger(), testMetrics(t))(
+			RequireOrganizationRole(models.RoleAdmin, testMetrics(t))(next))
+	}
+
+	tests := []struct {
+		name       string
+		slug       string
+		userID     string
+		wantStatus int
+	}{
+		{"owner", "acme", "alice", http.StatusOK},
+		{"admin", "globex", "bob", http.StatusOK},
+		{"viewer", "acme", "bob", http.StatusForbidden},
+		{"non-member", "globex", "alice", http.StatusForbidden},
+		{"unknown organization", "initech", "alice", http.StatusNotFound},
+		{"no user", "acme", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := withUser(httptest.NewRequest(http.MethodPatch, "/organizations/"+tt.slug, nil), tt.userID)
+
+			status, ctx := servedBy(routed(stack, "/organizations/{org_slug}"), r)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if ctx != nil {
+					t.Error("rejected request reached the handler")
+				}
+				return
+			}
+			if org, ok := util.GetOrganizationFromContext(ctx); !ok || org.Slug != tt.slug {
+				t.Errorf("organization = %+v, want %s", org, tt.slug)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"pulseguard/internal/api/handlers"
 	"pulseguard/internal/api/middleware"
+	"pulseguard/internal/models"
 	"pulseguard/internal/service"
 	"pulseguard/pkg/auth"
 	"pulseguard/pkg/logger"
@@ -23,9 +24,23 @@ func NewRouter(
 	tracesSvc *service.TracesService,
 	dashboardSvc *service.DashboardService,
 	alertSvc *service.AlertService,
+	notificationSvc *service.NotificationService,
+	issueMailer *service.IssueMailer,
+	silenceSvc *service.SilenceService,
 	projectSvc *service.ProjectService,
+	projectKeySvc *service.ProjectKeyService,
+	organizationSvc *service.OrganizationService,
+	invitationSvc *service.InvitationService,
 	errorSvc *service.ErrorService,
+	ingestQueue *service.IngestQueue,
+	ingestLimiter *service.IngestLimiter,
+	samplingSvc *service.SamplingService,
+	groupingSvc *service.GroupingService,
+	artifactSvc *service.ArtifactService,
+	releaseSvc *service.ReleaseService,
+	savedViewSvc *service.SavedViewService,
 	sessionSvc *service.SessionService,
+	otlpSvc *service.OTLPService,
 	metrics *otel.Metrics,
 	tokenSvc *auth.TokenService,
 	logger *logger.Logger,
@@ -48,16 +63,29 @@ func NewRouter(
 	// Handlers
 	userHandler := handlers.NewUserHandler(userSvc, sessionSvc, metrics, tokenSvc, logger, tracer)
 	projectHandler := handlers.NewProjectHandler(projectSvc, metrics, logger)
+	projectKeyHandler := handlers.NewProjectKeyHandler(projectKeySvc, metrics, logger)
+	usageHandler := handlers.NewUsageHandler(ingestLimiter, metrics, logger)
+	samplingHandler := handlers.NewSamplingHandler(samplingSvc, metrics, logger)
+	organizationHandler := handlers.NewOrganizationHandler(organizationSvc, metrics, logger)
+	invitationHandler := handlers.NewInvitationHandler(invitationSvc, metrics, logger)
 	oauthHandler := handlers.NewOAuthHandler(userSvc, sessionSvc, metrics, tokenSvc, logger, tracer)
 
 	dashboardHandler := handlers.NewDashboardHandler(dashboardSvc, logger, tracer)
-	errorHandler := handlers.NewErrorHandler(errorSvc, sessionSvc, metrics, logger, tracer)
+	errorHandler := handlers.NewErrorHandler(errorSvc, ingestQueue, ingestLimiter, sessionSvc, issueMailer, metrics, logger, tracer)
+	groupingRuleHandler := handlers.NewGroupingRuleHandler(groupingSvc, metrics)
+	artifactHandler := handlers.NewArtifactHandler(artifactSvc, metrics)
+	releaseHandler := handlers.NewReleaseHandler(releaseSvc, metrics)
+	savedViewHandler := handlers.NewSavedViewHandler(savedViewSvc, metrics)
 	tracesHandler := handlers.NewTracesHandler(tracesSvc, logger, metrics, tracer)
 	logsHandler := handlers.NewLogsHandler(logsSvc, logger, metrics, tracer)
 	sessionHandler := handlers.NewSessionHandler(sessionSvc, metrics, logger, tracer)
+	otlpHandler := handlers.NewOTLPHandler(otlpSvc, metrics, logger, tracer)
 
 	metricsHandler := handlers.NewMetricsHandler(metricsSvc, metrics)
 	alertHandler := handlers.NewAlertHandler(alertSvc, metrics)
+	notificationHandler := handlers.NewNotificationHandler(notificationSvc, metrics)
+	silenceHandler := handlers.NewSilenceHandler(silenceSvc, metrics)
+	alertmanagerHandler := handlers.NewAlertmanagerHandler(alertSvc, metrics, logger)
 
 	// user routes
 	r.Post("/api/users/register", userHandler.Register)
@@ -70,6 +98,29 @@ func NewRouter(
 	r.Get("/api/auth/{provider}", oauthHandler.BeginAuth)
 	r.Get("/api/auth/{provider}/callback", oauthHandler.CompleteAuth)
 
+	// Public ingest routes, authenticated by project key (X-PulseGuard-Key, DSN or Sentry auth)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.IngestKey(projectKeySvc, logger, metrics))
+
+		r.Post("/api/ingest/errors", errorHandler.Track)
+		r.Post("/api/ingest/sessions/start", sessionHandler.StartSession)
+		r.Post("/api/ingest/sessions/end", sessionHandler.EndSession)
+		r.Get("/api/ingest/sampling", samplingHandler.ClientConfig)
+
+		// Sentry SDKs, with a DSN of the form https://<public_key>@<host>/api/sentry/0
+		r.Post("/api/sentry/api/{project_id}/store", errorHandler.SentryStore)
+		r.Post("/api/sentry/api/{project_id}/envelope", errorHandler.SentryEnvelope)
+
+		// OTLP/HTTP exporters, with OTEL_EXPORTER_OTLP_ENDPOINT=https://<host>/api/otlp
+		// and the key in OTEL_EXPORTER_OTLP_HEADERS=X-PulseGuard-Key=<public_key>
+		r.Post("/api/otlp/v1/traces", otlpHandler.Traces)
+		r.Post("/api/otlp/v1/logs", otlpHandler.Logs)
+		r.Post("/api/otlp/v1/metrics", otlpHandler.Metrics)
+	})
+
+	// Alertmanager webhook receiver, authenticated by ALERTMANAGER_WEBHOOK_TOKEN
+	r.Post("/api/alertmanager/webhook", alertmanagerHandler.Webhook)
+
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.CookieTokenParser(tokenSvc.GetTokenAuth()))
@@ -83,31 +134,146 @@ func NewRouter(
 
 		// project routes
 		r.Post("/api/projects", projectHandler.Create)
-		r.Get("/api/projects", projectHandler.ListByOwner)
-		r.Get("/api/projects/{slug}", projectHandler.GetBySlug)
+		r.Get("/api/projects", projectHandler.List)
 		r.Delete("/api/projects", projectHandler.DeleteAllByOwner)
-		r.Put("/api/projects/{slug}", projectHandler.UpdateProject)
-		r.Delete("/api/projects/{slug}", projectHandler.DeleteBySlug)
-
-		// Error tracking routes
-		r.Post("/api/errors/track", errorHandler.Track)
-		r.Get("/api/errors", errorHandler.ListByProject)
-		r.Get("/api/errors/get", errorHandler.GetErrorByID)
-		r.Put("/api/errors/status", errorHandler.UpdateErrorStatus)
-
-		// alert routes
-		r.Post("/api/alerts", alertHandler.Create)
-		r.Get("/api/alerts/{project_id}", alertHandler.ListByProject)
-
-		// otlp
-		r.Get("/api/sessions", sessionHandler.GetSessions)
-		r.Post("/api/sessions/start", sessionHandler.StartSession)
-		r.Post("/api/sessions/end", sessionHandler.EndSession)
-		r.Get("/api/metrics", metricsHandler.GetMetrics)
-		r.Get("/api/logs", logsHandler.GetLogsByProjectID)
-		r.Get("/api/traces", tracesHandler.ListTracesByProject)
-		r.Get("/api/traces/{trace_id}", tracesHandler.GetTraceByID)
-		r.Get("/api/dashboard", dashboardHandler.GetDashboardData)
+
+		// organization routes
+		r.Post("/api/organizations", organizationHandler.Create)
+		r.Get("/api/organizations", organizationHandler.List)
+		r.Post("/api/invitations/accept", invitationHandler.Accept)
+
+		// Organization-scoped routes, authorized against the caller's memberships
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.OrganizationAccess(organizationSvc, logger, metrics))
+			admin := middleware.RequireOrganizationRole(models.RoleAdmin, metrics)
+
+			r.Get("/api/organizations/{org_slug}", organizationHandler.Get)
+			r.With(middleware.RequireOrganizationRole(models.RoleOwner, metrics)).Delete("/api/organizations/{org_slug}", organizationHandler.Delete)
+
+			r.Get("/api/organizations/{org_slug}/members", organizationHandler.ListMembers)
+			r.With(admin).Put("/api/organizations/{org_slug}/members/{user_id}", organizationHandler.UpdateMemberRole)
+			r.With(admin).Delete("/api/organizations/{org_slug}/members/{user_id}", organizationHandler.RemoveMember)
+
+			r.With(admin).Get("/api/organizations/{org_slug}/invitations", invitationHandler.ListOrganizationInvitations)
+			r.With(admin).Post("/api/organizations/{org_slug}/invitations", invitationHandler.InviteToOrganization)
+
+			r.Get("/api/organizations/{org_slug}/teams", organizationHandler.ListTeams)
+			r.With(admin).Post("/api/organizations/{org_slug}/teams", organizationHandler.CreateTeam)
+			r.With(admin).Delete("/api/organizations/{org_slug}/teams/{team_slug}", organizationHandler.DeleteTeam)
+			r.With(admin).Post("/api/organizations/{org_slug}/teams/{team_slug}/members", organizationHandler.AddTeamMember)
+			r.With(admin).Delete("/api/organizations/{org_slug}/teams/{team_slug}/members/{user_id}", organizationHandler.RemoveTeamMember)
+		})
+
+		// Project-scoped routes, authorized against the caller's project roles.
+		// Reads are open to every role; writes need member, admin or owner.
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.ProjectAccess(projectSvc, logger, metrics))
+			member := middleware.RequireProjectRole(models.RoleMember, metrics)
+			admin := middleware.RequireProjectRole(models.RoleAdmin, metrics)
+			owner := middleware.RequireProjectRole(models.RoleOwner, metrics)
+
+			r.Get("/api/projects/{slug}", projectHandler.GetBySlug)
+			r.With(admin).Put("/api/projects/{slug}", projectHandler.UpdateProject)
+			r.With(owner).Delete("/api/projects/{slug}", projectHandler.DeleteBySlug)
+
+			// project members, teams and invitations
+			r.Get("/api/projects/{slug}/members", projectHandler.ListMembers)
+			r.With(admin).Put("/api/projects/{slug}/members/{user_id}", projectHandler.UpdateMemberRole)
+			r.With(admin).Delete("/api/projects/{slug}/members/{user_id}", projectHandler.RemoveMember)
+			r.With(admin).Post("/api/projects/{slug}/teams", projectHandler.GrantTeam)
+			r.With(admin).Delete("/api/projects/{slug}/teams/{team_id}", projectHandler.RevokeTeam)
+			r.With(admin).Get("/api/projects/{slug}/invitations", invitationHandler.ListProjectInvitations)
+			r.With(admin).Post("/api/projects/{slug}/invitations", invitationHandler.InviteToProject)
+
+			// project ingest keys
+			r.With(admin).Post("/api/projects/{slug}/keys", projectKeyHandler.Create)
+			r.With(admin).Get("/api/projects/{slug}/keys", projectKeyHandler.List)
+			r.With(admin).Post("/api/projects/{slug}/keys/{key_id}/rotate", projectKeyHandler.Rotate)
+			r.With(admin).Delete("/api/projects/{slug}/keys/{key_id}", projectKeyHandler.Revoke)
+			r.With(admin).Put("/api/projects/{slug}/keys/{key_id}", projectKeyHandler.Update)
+
+			// ingest limits, usage and sampling
+			r.Get("/api/projects/{slug}/usage", usageHandler.GetUsage)
+			r.Get("/api/projects/{slug}/limits", usageHandler.GetLimits)
+			r.With(admin).Put("/api/projects/{slug}/limits", usageHandler.UpdateLimits)
+			r.Get("/api/projects/{slug}/sampling", samplingHandler.Get)
+			r.With(admin).Put("/api/projects/{slug}/sampling", samplingHandler.Update)
+
+			// Error tracking routes
+			r.With(member).Post("/api/errors/track", errorHandler.Track)
+			r.Get("/api/errors", errorHandler.ListByProject)
+			r.Get("/api/errors/get", errorHandler.GetErrorByID)
+			r.With(member).Put("/api/errors/status", errorHandler.UpdateErrorStatus)
+			r.With(member).Post("/api/errors/bulk", errorHandler.BulkUpdateErrors)
+			r.With(member).Post("/api/errors/merge", errorHandler.MergeErrors)
+			r.With(member).Post("/api/errors/unmerge", errorHandler.UnmergeError)
+			r.Get("/api/errors/merges", errorHandler.ListErrorMerges)
+			r.Get("/api/errors/{id}/occurrences", errorHandler.ListErrorOccurrences)
+			r.Get("/api/errors/{id}/occurrences/{occurrence_id}", errorHandler.GetErrorOccurrence)
+			r.Get("/api/errors/{id}/activity", errorHandler.GetErrorActivity)
+			r.With(member).Put("/api/errors/{id}/assignee", errorHandler.AssignError)
+			r.With(member).Post("/api/errors/{id}/comments", errorHandler.CreateErrorComment)
+
+			// saved error views; who may share or change a view is checked per view
+			r.Get("/api/views", savedViewHandler.List)
+			r.Post("/api/views", savedViewHandler.Create)
+			r.Get("/api/views/{view_id}", savedViewHandler.Get)
+			r.Put("/api/views/{view_id}", savedViewHandler.Update)
+			r.Delete("/api/views/{view_id}", savedViewHandler.Delete)
+
+			r.Get("/api/grouping-rules", groupingRuleHandler.List)
+			r.With(admin).Post("/api/grouping-rules", groupingRuleHandler.Create)
+			r.With(admin).Put("/api/grouping-rules/{rule_id}", groupingRuleHandler.Update)
+			r.With(admin).Delete("/api/grouping-rules/{rule_id}", groupingRuleHandler.Delete)
+
+			// releases, deploys and release artifacts (source maps)
+			r.Get("/api/releases", releaseHandler.List)
+			r.With(member).Post("/api/releases", releaseHandler.Create)
+			r.Get("/api/releases/{release}", releaseHandler.Get)
+			r.Get("/api/releases/{release}/deploys", releaseHandler.ListDeploys)
+			r.With(member).Post("/api/releases/{release}/deploys", releaseHandler.CreateDeploy)
+			r.Get("/api/releases/{release}/artifacts", artifactHandler.List)
+			r.With(member).Post("/api/releases/{release}/artifacts", artifactHandler.Upload)
+			r.With(member).Delete("/api/releases/{release}/artifacts/{artifact_id}", artifactHandler.Delete)
+
+			// alert routes
+			r.With(member).Post("/api/alerts", alertHandler.Create)
+			r.Get("/api/alerts/{project_id}", alertHandler.ListByProject)
+			r.With(member).Post("/api/alerts/{alert_id}/acknowledge", alertHandler.Acknowledge)
+			r.With(member).Post("/api/alerts/{alert_id}/resolve", alertHandler.Resolve)
+			r.With(member).Post("/api/alerts/{alert_id}/reopen", alertHandler.Reopen)
+			r.Get("/api/alerts/{alert_id}/events", alertHandler.ListEvents)
+			r.Get("/api/alert-rules", alertHandler.ListRules)
+			r.With(admin).Post("/api/alert-rules", alertHandler.CreateRule)
+			r.With(admin).Put("/api/alert-rules/{rule_id}", alertHandler.UpdateRule)
+			r.With(admin).Delete("/api/alert-rules/{rule_id}", alertHandler.DeleteRule)
+
+			// silences and maintenance windows
+			r.Get("/api/silences", silenceHandler.ListSilences)
+			r.With(member).Post("/api/silences", silenceHandler.CreateSilence)
+			r.With(member).Delete("/api/silences/{silence_id}", silenceHandler.ExpireSilence)
+			r.Get("/api/maintenance-windows", silenceHandler.ListMaintenanceWindows)
+			r.With(admin).Post("/api/maintenance-windows", silenceHandler.CreateMaintenanceWindow)
+			r.With(admin).Delete("/api/maintenance-windows/{window_id}", silenceHandler.DeleteMaintenanceWindow)
+
+			// notification channel routes
+			r.Get("/api/notification-channels", notificationHandler.ListChannels)
+			r.With(admin).Post("/api/notification-channels", notificationHandler.CreateChannel)
+			r.With(admin).Put("/api/notification-channels/{channel_id}", notificationHandler.UpdateChannel)
+			r.With(admin).Delete("/api/notification-channels/{channel_id}", notificationHandler.DeleteChannel)
+			r.With(admin).Post("/api/notification-channels/{channel_id}/test", notificationHandler.TestChannel)
+			r.Get("/api/notification-deliveries", notificationHandler.ListDeliveries)
+
+			// otlp
+			r.Get("/api/sessions", sessionHandler.GetSessions)
+			r.With(member).Post("/api/sessions/start", sessionHandler.StartSession)
+			r.With(member).Post("/api/sessions/end", sessionHandler.EndSession)
+			r.Get("/api/metrics", metricsHandler.GetMetrics)
+			r.Get("/api/logs", logsHandler.GetLogsByProjectID)
+			r.Get("/api/traces", tracesHandler.ListTracesByProject)
+			r.Get("/api/traces/{trace_id}", tracesHandler.GetTraceByID)
+			r.Get("/api/dashboard", dashboardHandler.GetDashboardData)
+		})
 	})
 
 	return r
@@ -25,13 +25,27 @@ type Server struct {
 func NewServer(
 	userService *service.UserService,
 	projectService *service.ProjectService,
+	projectKeyService *service.ProjectKeyService,
+	organizationService *service.OrganizationService,
+	invitationService *service.InvitationService,
 	errorService *service.ErrorService,
+	ingestQueue *service.IngestQueue,
+	ingestLimiter *service.IngestLimiter,
+	samplingService *service.SamplingService,
+	groupingService *service.GroupingService,
+	artifactService *service.ArtifactService,
+	releaseService *service.ReleaseService,
+	savedViewService *service.SavedViewService,
 	alertService *service.AlertService,
+	notificationService *service.NotificationService,
+	issueMailer *service.IssueMailer,
+	silenceService *service.SilenceService,
 	metricsService *service.MetricsService,
 	logsService *service.LogsService,
 	tracesService *service.TracesService,
 	dashboardService *service.DashboardService,
 	sessionService *service.SessionService,
+	otlpService *service.OTLPService,
 	port int,
 	logger *logger.Logger,
 	metrics *pulseguardOtel.Metrics,
@@ -47,9 +61,23 @@ func NewServer(
 		tracesService,
 		dashboardService,
 		alertService,
+		notificationService,
+		issueMailer,
+		silenceService,
 		projectService,
+		projectKeyService,
+		organizationService,
+		invitationService,
 		errorService,
+		ingestQueue,
+		ingestLimiter,
+		samplingService,
+		groupingService,
+		artifactService,
+		releaseService,
+		savedViewService,
 		sessionService,
+		otlpService,
 		metrics,
 		tokenService,
 		logger,
@@ -0,0 +1,104 @@
+// Package breadcrumbs validates the breadcrumbs sent with an error.
+package breadcrumbs
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pulseguard/internal/models"
+)
+
+// Size caps of the breadcrumbs stored with an occurrence
+const (
+	MaxBreadcrumbs   = 100       // most recent breadcrumbs kept
+	MaxMessageLength = 1024      // bytes of a message
+	MaxDataSize      = 4 * 1024  // bytes of the encoded data of a breadcrumb
+	MaxTotalSize     = 64 * 1024 // bytes of all encoded breadcrumbs
+)
+
+var categories = map[string]string{
+	models.BreadcrumbNavigation: models.BreadcrumbNavigation,
+	models.BreadcrumbClick:      models.BreadcrumbClick,
+	"ui.click":                  models.BreadcrumbClick,
+	models.BreadcrumbConsole:    models.BreadcrumbConsole,
+	models.BreadcrumbXHR:        models.BreadcrumbXHR,
+	models.BreadcrumbFetch:      models.BreadcrumbFetch,
+	models.BreadcrumbCustom:     models.BreadcrumbCustom,
+}
+
+var levels = map[string]string{
+	"debug":   "debug",
+	"log":     "info",
+	"info":    "info",
+	"warn":    "warning",
+	"warning": "warning",
+	"error":   "error",
+	"fatal":   "fatal",
+}
+
+// Normalize orders breadcrumbs oldest first and fits them into the size
+// caps, dropping the oldest ones first. Unknown categories become custom and
+// unknown levels info; breadcrumbs without a timestamp get occurredAt.
+// Messages are truncated and data too large to keep is dropped. It returns
+// nil when there are no breadcrumbs.
+func Normalize(crumbs []models.Breadcrumb, occurredAt time.Time) []models.Breadcrumb {
+	if len(crumbs) == 0 {
+		return nil
+	}
+
+	out := make([]models.Breadcrumb, 0, len(crumbs))
+	for _, b := range crumbs {
+		if b.Timestamp.IsZero() {
+			b.Timestamp = occurredAt
+		}
+		b.Timestamp = b.Timestamp.UTC()
+		if category, ok := categories[strings.ToLower(strings.TrimSpace(b.Category))]; ok {
+			b.Category = category
+		} else {
+			b.Category = models.BreadcrumbCustom
+		}
+		if level, ok := levels[strings.ToLower(strings.TrimSpace(b.Level))]; ok {
+			b.Level = level
+		} else {
+			b.Level = "info"
+		}
+		b.Message = truncate(b.Message, MaxMessageLength)
+		if len(b.Data) > 0 {
+			if encoded, err := json.Marshal(b.Data); err != nil || len(encoded) > MaxDataSize {
+				b.Data = nil
+			}
+		}
+		out = append(out, b)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	if len(out) > MaxBreadcrumbs {
+		out = out[len(out)-MaxBreadcrumbs:]
+	}
+
+	// Keep the most recent breadcrumbs that fit, each costs its encoding and a comma
+	total := 2
+	for i := len(out) - 1; i >= 0; i-- {
+		encoded, _ := json.Marshal(out[i])
+		total += len(encoded) + 1
+		if total > MaxTotalSize {
+			return out[i+1:]
+		}
+	}
+	return out
+}
+
+// truncate shortens s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
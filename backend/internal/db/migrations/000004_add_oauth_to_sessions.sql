@@ -1,9 +1,11 @@
 -- +goose Up
 -- +goose StatementBegin
-ALTER TABLE sessions
-ADD COLUMN oauth_data TEXT;
+-- sessions is only created by a later migration on a new database, which
+-- adds oauth_data then
+ALTER TABLE IF EXISTS sessions
+ADD COLUMN IF NOT EXISTS oauth_data TEXT;
 
-CREATE TABLE password_resets (
+CREATE TABLE IF NOT EXISTS password_resets (
     email TEXT NOT NULL,
     token TEXT PRIMARY KEY,
     expires_at TIMESTAMP NOT NULL,
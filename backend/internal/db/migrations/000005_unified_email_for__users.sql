@@ -1,3 +1,5 @@
+-- +goose Up
+-- +goose StatementBegin
 -- Enable uuid generation
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
@@ -71,3 +73,4 @@ BEGIN
     EXECUTE FUNCTION set_updated_at();
   END IF;
 END$$;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    condition_type VARCHAR(32) NOT NULL CHECK (condition_type IN ('new_fingerprint', 'error_count', 'session_error_rate', 'promql')),
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    window_seconds INTEGER NOT NULL DEFAULT 300,
+    query TEXT,
+    environment TEXT,
+    severity TEXT NOT NULL DEFAULT 'warning',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_evaluated_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_project_id ON alert_rules (project_id);
+
+-- Alerts become instances: one row per firing episode of a rule (or a manual alert)
+ALTER TABLE alerts DROP CONSTRAINT IF EXISTS alerts_project_id_key;
+
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS rule_id UUID REFERENCES alert_rules(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'firing',
+ADD COLUMN IF NOT EXISTS fingerprint TEXT NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'manual',
+ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+ADD COLUMN IF NOT EXISTS value DOUBLE PRECISION,
+ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE alerts SET started_at = created_at WHERE started_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_project_id_created_at ON alerts (project_id, created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_rule_fingerprint ON alerts (rule_id, fingerprint) WHERE status = 'firing';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_alerts_open_rule_fingerprint;
+DROP INDEX IF EXISTS idx_alerts_project_id_created_at;
+
+ALTER TABLE alerts
+DROP COLUMN IF EXISTS resolved_at,
+DROP COLUMN IF EXISTS started_at,
+DROP COLUMN IF EXISTS value,
+DROP COLUMN IF EXISTS labels,
+DROP COLUMN IF EXISTS source,
+DROP COLUMN IF EXISTS fingerprint,
+DROP COLUMN IF EXISTS status,
+DROP COLUMN IF EXISTS rule_id;
+
+DROP TABLE IF EXISTS alert_rules;
+-- +goose StatementEnd
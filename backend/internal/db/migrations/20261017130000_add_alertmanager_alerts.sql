@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Alerts imported from outside (e.g. Alertmanager) are upserted by fingerprint while firing
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_external_fingerprint
+ON alerts (project_id, source, fingerprint)
+WHERE status = 'firing' AND rule_id IS NULL AND fingerprint <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_alerts_open_external_fingerprint;
+-- +goose StatementEnd
@@ -0,0 +1,83 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE alerts
+ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN IF NOT EXISTS acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS resolved_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Acknowledged alerts are still open, so they keep their fingerprint reserved
+DROP INDEX IF EXISTS idx_alerts_open_rule_fingerprint;
+DROP INDEX IF EXISTS idx_alerts_open_external_fingerprint;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_rule_fingerprint
+ON alerts (rule_id, fingerprint)
+WHERE status IN ('firing', 'acknowledged');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_external_fingerprint
+ON alerts (project_id, source, fingerprint)
+WHERE status IN ('firing', 'acknowledged') AND rule_id IS NULL AND fingerprint <> '';
+
+CREATE TABLE IF NOT EXISTS alert_events (
+    id UUID PRIMARY KEY,
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('fired', 'acknowledged', 'resolved', 'reopened')),
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert_id ON alert_events (alert_id, created_at);
+
+-- Existing alerts get the event that opened them, and the one that closed them
+INSERT INTO alert_events (id, alert_id, project_id, type, created_at)
+SELECT gen_random_uuid(), id, project_id, 'fired', COALESCE(started_at, created_at) FROM alerts;
+INSERT INTO alert_events (id, alert_id, project_id, type, created_at)
+SELECT gen_random_uuid(), id, project_id, 'resolved', resolved_at FROM alerts WHERE resolved_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS alert_silences (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    matchers JSONB NOT NULL,
+    comment TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_silences_project_id_ends_at ON alert_silences (project_id, ends_at);
+
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_project_id_ends_at ON maintenance_windows (project_id, ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS maintenance_windows;
+DROP TABLE IF EXISTS alert_silences;
+DROP TABLE IF EXISTS alert_events;
+
+DROP INDEX IF EXISTS idx_alerts_open_external_fingerprint;
+DROP INDEX IF EXISTS idx_alerts_open_rule_fingerprint;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_rule_fingerprint
+ON alerts (rule_id, fingerprint)
+WHERE status = 'firing';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open_external_fingerprint
+ON alerts (project_id, source, fingerprint)
+WHERE status = 'firing' AND rule_id IS NULL AND fingerprint <> '';
+
+ALTER TABLE alerts
+DROP COLUMN IF EXISTS resolved_by,
+DROP COLUMN IF EXISTS acknowledged_by,
+DROP COLUMN IF EXISTS acknowledged_at;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The initial schema made these columns unique, which allowed a single error
+-- group per project and a single occurrence per group. Groups are unique per
+-- project, environment and fingerprint instead.
+ALTER TABLE errors DROP CONSTRAINT IF EXISTS errors_project_id_key;
+ALTER TABLE errors DROP CONSTRAINT IF EXISTS errors_fingerprint_key;
+ALTER TABLE error_occurrences DROP CONSTRAINT IF EXISTS error_occurrences_error_id_key;
+ALTER TABLE error_occurrences DROP CONSTRAINT IF EXISTS error_occurrences_session_id_key;
+ALTER TABLE error_occurrences DROP CONSTRAINT IF EXISTS error_occurrences_timestamp_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_errors_project_environment_fingerprint ON errors (project_id, environment, fingerprint);
+CREATE INDEX IF NOT EXISTS idx_error_occurrences_error_id_timestamp ON error_occurrences (error_id, timestamp DESC);
+
+CREATE TABLE IF NOT EXISTS grouping_rules (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    match_type TEXT,
+    match_message TEXT,
+    match_frame TEXT,
+    fingerprint TEXT[] NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    CHECK (match_type IS NOT NULL OR match_message IS NOT NULL OR match_frame IS NOT NULL),
+    CHECK (cardinality(fingerprint) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_grouping_rules_project_id ON grouping_rules (project_id, priority);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS grouping_rules;
+DROP INDEX IF EXISTS idx_error_occurrences_error_id_timestamp;
+DROP INDEX IF EXISTS idx_errors_project_environment_fingerprint;
+-- The original unique constraints are not restored: existing data would violate them
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every fingerprint that routes events into a group. A group owns its own
+-- fingerprint plus those of the groups merged into it.
+CREATE TABLE IF NOT EXISTS error_fingerprints (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    environment TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    error_id UUID NOT NULL REFERENCES errors(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (project_id, environment, fingerprint)
+);
+
+CREATE INDEX IF NOT EXISTS idx_error_fingerprints_error_id ON error_fingerprints (error_id);
+
+INSERT INTO error_fingerprints (project_id, environment, fingerprint, error_id)
+SELECT project_id, environment, fingerprint, id FROM errors
+ON CONFLICT DO NOTHING;
+
+-- The fingerprint an occurrence was grouped by, so unmerging can move it back
+ALTER TABLE error_occurrences ADD COLUMN IF NOT EXISTS fingerprint TEXT;
+
+UPDATE error_occurrences o SET fingerprint = e.fingerprint
+FROM errors e
+WHERE o.error_id = e.id AND o.fingerprint IS NULL;
+
+-- A group merged into another, with what is needed to split it out again.
+-- The source group is deleted on merge, so neither error id is a foreign key.
+CREATE TABLE IF NOT EXISTS error_merges (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    target_error_id UUID NOT NULL,
+    source_error_id UUID NOT NULL,
+    source JSONB NOT NULL,
+    fingerprints TEXT[] NOT NULL,
+    tags JSONB NOT NULL DEFAULT '[]'::jsonb,
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    merged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    unmerged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    unmerged_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_error_merges_target_error_id ON error_merges (target_error_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS error_merges;
+ALTER TABLE error_occurrences DROP COLUMN IF EXISTS fingerprint;
+DROP TABLE IF EXISTS error_fingerprints;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Parsed stack frames, of the group's first event and of every occurrence.
+-- Rows tracked before this are parsed from stack_trace when read.
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS frames JSONB;
+ALTER TABLE error_occurrences ADD COLUMN IF NOT EXISTS frames JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE error_occurrences DROP COLUMN IF EXISTS frames;
+ALTER TABLE errors DROP COLUMN IF EXISTS frames;
+-- +goose StatementEnd
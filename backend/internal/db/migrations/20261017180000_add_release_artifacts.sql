@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Files uploaded for a release, such as JavaScript source maps. The content
+-- lives in the artifact store under storage_key.
+CREATE TABLE IF NOT EXISTS release_artifacts (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    release TEXT NOT NULL,
+    name TEXT NOT NULL,
+    storage_key TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    checksum TEXT NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE (project_id, release, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS release_artifacts;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Releases are ordered by when they were created, which is also when
+-- ingestion first saw them if they were not created through the API
+CREATE TABLE IF NOT EXISTS releases (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    version TEXT NOT NULL,
+    ref TEXT,
+    url TEXT,
+    date_released TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    UNIQUE (project_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS deploys (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    release_id UUID NOT NULL REFERENCES releases(id) ON DELETE CASCADE,
+    environment TEXT NOT NULL,
+    name TEXT,
+    url TEXT,
+    date_started TIMESTAMP WITH TIME ZONE,
+    date_finished TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deploys_release_id ON deploys (release_id, date_finished DESC);
+
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS first_release TEXT NOT NULL DEFAULT '';
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS last_release TEXT NOT NULL DEFAULT '';
+ALTER TABLE error_occurrences ADD COLUMN IF NOT EXISTS release TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_errors_first_release ON errors (project_id, first_release);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_errors_first_release;
+ALTER TABLE error_occurrences DROP COLUMN IF EXISTS release;
+ALTER TABLE errors DROP COLUMN IF EXISTS last_release;
+ALTER TABLE errors DROP COLUMN IF EXISTS first_release;
+DROP TABLE IF EXISTS deploys;
+DROP TABLE IF EXISTS releases;
+-- +goose StatementEnd
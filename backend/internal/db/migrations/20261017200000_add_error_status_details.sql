@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Conditions of a RESOLVED or IGNORED status, see models.StatusDetails
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS status_details JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE errors DROP COLUMN IF EXISTS status_details;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+-- An issue is assigned to a user or a team, never both
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS assignee_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS assignee_team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE errors ADD CONSTRAINT errors_single_assignee
+    CHECK (assignee_user_id IS NULL OR assignee_team_id IS NULL);
+
+-- Comments and activity have no foreign key on the error: those of a merged
+-- group are kept and come back with it when it is unmerged
+CREATE TABLE IF NOT EXISTS error_comments (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    error_id UUID NOT NULL,
+    parent_id UUID REFERENCES error_comments(id) ON DELETE CASCADE,
+    author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    mentions UUID[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_error_comments_error ON error_comments(error_id, created_at);
+
+-- Append-only: rows are never updated; actor_id is NULL for changes made by ingestion
+CREATE TABLE IF NOT EXISTS error_activity (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    error_id UUID NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_error_activity_error ON error_activity(error_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS error_activity;
+DROP TABLE IF EXISTS error_comments;
+ALTER TABLE errors DROP CONSTRAINT IF EXISTS errors_single_assignee;
+ALTER TABLE errors DROP COLUMN IF EXISTS assignee_team_id;
+ALTER TABLE errors DROP COLUMN IF EXISTS assignee_user_id;
+-- +goose StatementEnd
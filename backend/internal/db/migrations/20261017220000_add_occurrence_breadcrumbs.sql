@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Breadcrumbs sent with the occurrence, oldest first, see models.Breadcrumb
+ALTER TABLE error_occurrences ADD COLUMN IF NOT EXISTS breadcrumbs JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE error_occurrences DROP COLUMN IF EXISTS breadcrumbs;
+-- +goose StatementEnd
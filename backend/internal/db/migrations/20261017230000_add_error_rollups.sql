@@ -0,0 +1,74 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Occurrences per error group and hour, for the 24 hour and 30 day histograms.
+-- Rollups are kept per fingerprint of the group, so that merging and
+-- unmerging groups moves them along with the fingerprints.
+CREATE TABLE IF NOT EXISTS error_hourly_counts (
+    error_id UUID NOT NULL REFERENCES errors(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (error_id, fingerprint, bucket)
+);
+
+-- Distinct users and sessions affected by each error group; the counters on
+-- errors are kept in step so that issues can be sorted by them
+CREATE TABLE IF NOT EXISTS error_users (
+    error_id UUID NOT NULL REFERENCES errors(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (error_id, fingerprint, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS error_sessions (
+    error_id UUID NOT NULL REFERENCES errors(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    session_id TEXT NOT NULL,
+    first_seen TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (error_id, fingerprint, session_id)
+);
+
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS user_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE errors ADD COLUMN IF NOT EXISTS session_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_errors_project_user_count ON errors(project_id, user_count DESC);
+
+-- Backfill from the occurrences tracked so far
+INSERT INTO error_hourly_counts (error_id, fingerprint, bucket, count)
+SELECT o.error_id, COALESCE(o.fingerprint, e.fingerprint), date_trunc('hour', o.timestamp), COUNT(*)
+FROM error_occurrences o
+JOIN errors e ON e.id = o.error_id
+GROUP BY 1, 2, 3
+ON CONFLICT DO NOTHING;
+
+INSERT INTO error_users (error_id, fingerprint, user_id, first_seen)
+SELECT o.error_id, COALESCE(o.fingerprint, e.fingerprint), o.user_id, MIN(o.timestamp)
+FROM error_occurrences o
+JOIN errors e ON e.id = o.error_id
+WHERE o.user_id <> ''
+GROUP BY 1, 2, 3
+ON CONFLICT DO NOTHING;
+
+INSERT INTO error_sessions (error_id, fingerprint, session_id, first_seen)
+SELECT o.error_id, COALESCE(o.fingerprint, e.fingerprint), o.session_id, MIN(o.timestamp)
+FROM error_occurrences o
+JOIN errors e ON e.id = o.error_id
+WHERE o.session_id <> ''
+GROUP BY 1, 2, 3
+ON CONFLICT DO NOTHING;
+
+UPDATE errors e SET
+    user_count = (SELECT COUNT(DISTINCT user_id) FROM error_users u WHERE u.error_id = e.id),
+    session_count = (SELECT COUNT(DISTINCT session_id) FROM error_sessions s WHERE s.error_id = e.id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_errors_project_user_count;
+ALTER TABLE errors DROP COLUMN IF EXISTS session_count;
+ALTER TABLE errors DROP COLUMN IF EXISTS user_count;
+DROP TABLE IF EXISTS error_sessions;
+DROP TABLE IF EXISTS error_users;
+DROP TABLE IF EXISTS error_hourly_counts;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ingest limits of a project: events per minute (0 uses the server default),
+-- events per calendar month (0 is unlimited) and spike protection.
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS ingest_rate_limit INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS monthly_event_quota BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS spike_protection BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Events per minute a single key may send, on top of the project's limit (0 is unlimited)
+ALTER TABLE project_keys
+    ADD COLUMN IF NOT EXISTS rate_limit INTEGER NOT NULL DEFAULT 0;
+
+-- Daily counts of the events a project sent, by outcome
+CREATE TABLE IF NOT EXISTS project_usage (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    accepted BIGINT NOT NULL DEFAULT 0,
+    rate_limited BIGINT NOT NULL DEFAULT 0,
+    dropped BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (project_id, day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS project_usage;
+ALTER TABLE project_keys DROP COLUMN IF EXISTS rate_limit;
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS ingest_rate_limit,
+    DROP COLUMN IF EXISTS monthly_event_quota,
+    DROP COLUMN IF EXISTS spike_protection;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Sampling of a project's events: the share of events stored as occurrences,
+-- overridden per environment and per error type, and how many occurrences a
+-- new group keeps before sampling applies. Sampled-out events still count
+-- towards their group.
+ALTER TABLE projects
+    ADD COLUMN IF NOT EXISTS sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1
+        CHECK (sample_rate > 0 AND sample_rate <= 1),
+    ADD COLUMN IF NOT EXISTS sample_environment_rates JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS sample_type_rates JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS sample_keep_first INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS sample_rate,
+    DROP COLUMN IF EXISTS sample_environment_rates,
+    DROP COLUMN IF EXISTS sample_type_rates,
+    DROP COLUMN IF EXISTS sample_keep_first;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- What the merged fingerprints had counted when they were merged, for
+-- unmerging to hand back what they counted since
+ALTER TABLE error_merges ADD COLUMN IF NOT EXISTS rollup_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE error_merges m SET rollup_count = (
+    SELECT COUNT(*) FROM error_occurrences o
+    WHERE o.error_id = m.target_error_id AND o.fingerprint = ANY(m.fingerprints) AND o.timestamp <= m.merged_at)
+WHERE m.unmerged_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE error_merges DROP COLUMN IF EXISTS rollup_count;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A database migrated from scratch ends up like the deployed ones: sessions
+-- is created after the migration that adds oauth_data to it, and the initial
+-- schema allowed a single project per owner and per slug
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS oauth_data TEXT;
+
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_owner_id_key;
+ALTER TABLE projects DROP CONSTRAINT IF EXISTS projects_slug_key;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The constraints are not restored: owners may have several projects, and
+-- projects may share slugs, by now
+-- +goose StatementEnd
@@ -0,0 +1,91 @@
+// Package grouping decides which error group (issue) an event belongs to.
+package grouping
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+
+	"pulseguard/internal/models"
+)
+
+// maxFrames is how many of the innermost in-app frames identify a crash
+const maxFrames = 3
+
+// Event is the part of a tracked error that grouping looks at
+type Event struct {
+	Type    string
+	Message string
+	Source  string
+	// Frames is the parsed stack trace, innermost frame first
+	Frames []models.StackFrame
+}
+
+// DefaultComponents returns the values the default fingerprint is made of.
+// Events with a stack trace are identified by their type and innermost
+// in-app frames; events without one by their type, normalized message and
+// source.
+func DefaultComponents(e Event) []string {
+	if frames := topFrames(GroupingFrames(e.Frames)); len(frames) > 0 {
+		components := []string{e.Type}
+		for _, f := range frames {
+			components = append(components, f.String())
+		}
+		return components
+	}
+	return []string{e.Type, NormalizeMessage(e.Message), e.Source}
+}
+
+// Fingerprint hashes fingerprint components into the stored fingerprint
+func Fingerprint(components []string) string {
+	hash := md5.Sum([]byte(strings.Join(components, "|")))
+	return hex.EncodeToString(hash[:])
+}
+
+// LegacyFingerprint is the fingerprint of groups opened before stack-aware
+// grouping: the hash of the raw message, source and type. Those groups are
+// still registered under it.
+func LegacyFingerprint(e Event) string {
+	return Fingerprint([]string{e.Message, e.Source, e.Type})
+}
+
+// Expand resolves the variables of a custom fingerprint: {{ default }} is
+// replaced by the default components, {{ type }} by the error type and
+// {{ message }} by the normalized message. Other values are kept as they are.
+func Expand(custom []string, e Event) []string {
+	var components []string
+	for _, part := range custom {
+		switch strings.ToLower(strings.Join(strings.Fields(part), "")) {
+		case "{{default}}":
+			components = append(components, DefaultComponents(e)...)
+		case "{{type}}":
+			components = append(components, e.Type)
+		case "{{message}}":
+			components = append(components, NormalizeMessage(e.Message))
+		default:
+			components = append(components, part)
+		}
+	}
+	return components
+}
+
+// topFrames keeps the innermost in-app frames, or the innermost frames when
+// none are in-app
+func topFrames(frames []Frame) []Frame {
+	var top []Frame
+	for _, f := range frames {
+		if f.InApp {
+			top = append(top, f)
+			if len(top) == maxFrames {
+				return top
+			}
+		}
+	}
+	if len(top) > 0 {
+		return top
+	}
+	if len(frames) > maxFrames {
+		return frames[:maxFrames]
+	}
+	return frames
+}
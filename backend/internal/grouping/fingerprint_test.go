@@ -0,0 +1,174 @@
+package grouping
+
+import (
+	"reflect"
+	"testing"
+
+	"pulseguard/internal/models"
+)
+
+func TestLegacyFingerprint(t *testing.T) {
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		// md5 of "message|source|type", as groups were fingerprinted before
+		// stack-aware grouping; the message is not normalized and frames are
+		// not looked at
+		{
+			Event{
+				Type:    "TypeError",
+				Message: "Request 42 failed",
+				Source:  "https://app.example.com/app.js",
+				Frames:  []models.StackFrame{{Function: "load", File: "app.js", InApp: true}},
+			},
+			"53d9cba8ef2d4217d4022ce10abe127c",
+		},
+		{Event{}, "7d010443693eec253a121e2aa2ba177c"},
+	}
+	for _, tt := range tests {
+		if got := LegacyFingerprint(tt.event); got != tt.want {
+			t.Errorf("LegacyFingerprint(%+v) = %s, want %s", tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestStacksOfOneMessage(t *testing.T) {
+	event := func(function string) Event {
+		return Event{
+			Type:    "TypeError",
+			Message: "x is undefined",
+			Source:  "app.js",
+			Frames:  []models.StackFrame{{Function: function, File: "src/app.js", Line: 1, InApp: true}},
+		}
+	}
+	render, save := event("render"), event("save")
+
+	// Different in-app stacks open different groups, though groups opened
+	// before stack-aware grouping held them both
+	if Fingerprint(DefaultComponents(render)) == Fingerprint(DefaultComponents(save)) {
+		t.Error("stacks of one message share a fingerprint")
+	}
+	if LegacyFingerprint(render) != LegacyFingerprint(save) {
+		t.Error("stacks of one message have different legacy fingerprints")
+	}
+}
+
+func TestDefaultComponents(t *testing.T) {
+	tests := []struct {
+		name  string
+		event Event
+		want  []string
+	}{
+		{
+			name: "innermost in-app frames",
+			event: Event{
+				Type:    "TypeError",
+				Message: "x is undefined",
+				Frames: []models.StackFrame{
+					{Function: "get", File: "https://app.example.com/node_modules/lodash/get.js", Line: 10},
+					{Function: "render", File: "https://app.example.com/static/main.3f9a1c2b.js", Line: 1, Column: 200, InApp: true},
+					{Function: "update", File: "src/view.js", Line: 5, InApp: true},
+					{Function: "", File: "src/anonymous.js", InApp: true},
+					{Function: "main", File: "src/main.js", InApp: true},
+				},
+			},
+			want: []string{"TypeError", "render static/main.js", "update src/view.js", "src/anonymous.js"},
+		},
+		{
+			name: "innermost frames without in-app ones",
+			event: Event{
+				Type: "Error",
+				Frames: []models.StackFrame{
+					{Function: "a", File: "lib/a.js"},
+					{Function: "b", File: "lib/b.js"},
+					{Function: "c", File: "lib/c.js"},
+					{Function: "d", File: "lib/d.js"},
+				},
+			},
+			want: []string{"Error", "a lib/a.js", "b lib/b.js", "c lib/c.js"},
+		},
+		{
+			name:  "message without frames",
+			event: Event{Type: "ValueError", Message: "invalid id 42", Source: "worker.py"},
+			want:  []string{"ValueError", "invalid id <num>", "worker.py"},
+		},
+		{
+			name:  "empty event",
+			event: Event{},
+			want:  []string{"", "", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultComponents(tt.event); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DefaultComponents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultComponentsIgnoreVariableParts(t *testing.T) {
+	withFrames := func(line int, file, message string) Event {
+		return Event{
+			Type:    "TypeError",
+			Message: message,
+			Frames:  []models.StackFrame{{Function: "render", File: file, Line: line, Column: line * 2, InApp: true}},
+		}
+	}
+	a := DefaultComponents(withFrames(10, "https://app.example.com/static/main.3f9a1c2b.js", "Order 1 failed"))
+	b := DefaultComponents(withFrames(12, "https://cdn.example.com/static/main.88aa99bb.js?v=2", "Order 2 failed for x"))
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("DefaultComponents() = %q and %q, want the same for another line, deploy and message", a, b)
+	}
+
+	c := DefaultComponents(withFrames(10, "https://app.example.com/static/other.js", "Order 1 failed"))
+	if reflect.DeepEqual(a, c) {
+		t.Errorf("DefaultComponents() = %q for another file, want it to differ", c)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	components := []string{"TypeError", "render static/main.js"}
+	// The stored fingerprints depend on this; it must never change
+	if got, want := Fingerprint(components), "b0712a41367e02aae443630d3ce9a825"; got != want {
+		t.Errorf("Fingerprint(%q) = %s, want %s", components, got, want)
+	}
+	if Fingerprint(components) != Fingerprint([]string{"TypeError", "render static/main.js"}) {
+		t.Error("Fingerprint() is not stable")
+	}
+	if Fingerprint(components) == Fingerprint([]string{"render static/main.js", "TypeError"}) {
+		t.Error("Fingerprint() does not depend on the order of components")
+	}
+	if Fingerprint(components) == Fingerprint(components[:1]) {
+		t.Error("Fingerprint() does not depend on every component")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	event := Event{Type: "TimeoutError", Message: "timed out after 30s", Source: "api.js"}
+
+	tests := []struct {
+		custom []string
+		want   []string
+	}{
+		{[]string{"{{ default }}"}, []string{"TimeoutError", "timed out after <num>", "api.js"}},
+		{[]string{"{{default}}", "checkout"}, []string{"TimeoutError", "timed out after <num>", "api.js", "checkout"}},
+		{[]string{"{{ type }}", "{{ MESSAGE }}"}, []string{"TimeoutError", "timed out after <num>"}},
+		{[]string{"checkout", "{{type}}"}, []string{"checkout", "TimeoutError"}},
+		{[]string{"{{ unknown }}", "{{type"}, []string{"{{ unknown }}", "{{type"}},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		if got := Expand(tt.custom, event); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expand(%q) = %q, want %q", tt.custom, got, tt.want)
+		}
+	}
+
+	// The order of a custom fingerprint is part of it
+	a := Fingerprint(Expand([]string{"checkout", "{{ type }}"}, event))
+	b := Fingerprint(Expand([]string{"{{ type }}", "checkout"}, event))
+	if a == b {
+		t.Error("Expand() lost the order of the custom fingerprint")
+	}
+}
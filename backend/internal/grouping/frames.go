@@ -0,0 +1,40 @@
+package grouping
+
+import (
+	"regexp"
+	"strings"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/stacktrace"
+)
+
+// Frame is the part of a stack frame that identifies code for grouping:
+// the function and the file it lives in, without line numbers
+type Frame struct {
+	Function string
+	File     string
+	InApp    bool
+}
+
+// String renders the frame as "function file", the form grouping rules match against
+func (f Frame) String() string {
+	return strings.TrimSpace(f.Function + " " + f.File)
+}
+
+// main.3f9a1c2b.js -> main.js; bundlers add content hashes that change every deploy
+var contentHash = regexp.MustCompile(`[.-][0-9a-fA-F]{8,}(\.\w+)$`)
+
+// GroupingFrames reduces parsed frames to the parts that identify code
+func GroupingFrames(parsed []models.StackFrame) []Frame {
+	frames := make([]Frame, 0, len(parsed))
+	for _, f := range parsed {
+		frames = append(frames, Frame{Function: f.Function, File: cleanFile(f.File), InApp: f.InApp})
+	}
+	return frames
+}
+
+// cleanFile keeps the path of a frame's file without the parts that vary
+// between deploys and users
+func cleanFile(file string) string {
+	return contentHash.ReplaceAllString(stacktrace.Path(file), "$1")
+}
@@ -0,0 +1,85 @@
+package grouping
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Variable tokens replaced by placeholders before a message is fingerprinted,
+// most specific first so that e.g. a UUID is not split into hex and numbers.
+var messagePatterns = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s'"<>]+`), "<url>"},
+	{regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`), "<email>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?)?\b`), "<timestamp>"},
+	{regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(?:\.\d+)?\b`), "<time>"},
+	{regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}(?::\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b`), "<hex>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]*[0-9][0-9a-f]*[a-f][0-9a-f]*\b|\b[0-9a-f]*[a-f][0-9a-f]*[0-9][0-9a-f]*\b`), "<hex>"},
+	// With a unit, such as 3.5s or 200ms, so that the fraction or unit is not left behind
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?(?:[a-zA-Z]{1,3})?\b`), "<num>"},
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// NormalizeMessage replaces the variable parts of an error message (quoted
+// values, IDs, timestamps, numbers, addresses, URLs) with placeholders, so
+// that messages differing only in those parts group together.
+func NormalizeMessage(message string) string {
+	message = replaceQuoted(message)
+	for _, p := range messagePatterns {
+		message = p.re.ReplaceAllStringFunc(message, func(match string) string {
+			// Short hex-looking words such as "cafe1" are left alone
+			if p.placeholder == "<hex>" && !strings.HasPrefix(strings.ToLower(match), "0x") && len(match) < 8 {
+				return match
+			}
+			return p.placeholder
+		})
+	}
+	return strings.TrimSpace(whitespace.ReplaceAllString(message, " "))
+}
+
+// replaceQuoted replaces values in single, double or back quotes with
+// <str>. A quote only opens a value at the start of a word and closes it at
+// the end of one, so apostrophes as in "can't" are left alone, and a value
+// does not span lines.
+func replaceQuoted(message string) string {
+	var b strings.Builder
+	for i := 0; i < len(message); i++ {
+		c := message[i]
+		if (c == '\'' || c == '"' || c == '`') && (i == 0 || !isWordByte(message[i-1])) {
+			if end := closingQuote(message, i); end > 0 {
+				b.WriteString("<str>")
+				i = end
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// closingQuote returns the index of the quote closing the one at open, or
+// -1 if there is none on the same line
+func closingQuote(message string, open int) int {
+	for j := open + 1; j < len(message); j++ {
+		switch message[j] {
+		case '\n':
+			return -1
+		case message[open]:
+			if j+1 == len(message) || !isWordByte(message[j+1]) {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// isWordByte reports whether c is part of a word: a letter, digit or
+// underscore, or a byte of a non-ASCII character
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c >= 0x80
+}
@@ -0,0 +1,54 @@
+package grouping
+
+import "testing"
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"Request 42 failed after 3.5s", "Request <num> failed after <num>"},
+		{"timeout after 200ms", "timeout after <num>"},
+		{"User 550e8400-e29b-41d4-a716-446655440000 not found", "User <uuid> not found"},
+		{"User 550E8400-E29B-41D4-A716-446655440000 not found", "User <uuid> not found"},
+		{"Segfault at 0x7ffde4a1 in worker", "Segfault at <hex> in worker"},
+		{"Object deadbeefcafe1234 expired", "Object <hex> expired"},
+		{"Cannot read property 'name' of undefined", "Cannot read property <str> of undefined"},
+		{`Unknown column "user_id" in 'field list'`, "Unknown column <str> in <str>"},
+		{"Missing file `config.yml`", "Missing file <str>"},
+		{"fetch https://api.example.com/users/42?x=1 failed", "fetch <url> failed"},
+		{"mail to bob@example.com bounced", "mail to <email> bounced"},
+		{"at 2026-10-17T12:00:00Z and 12:00:05.123", "at <timestamp> and <time>"},
+		{"connect 10.0.0.5:5432 refused", "connect <ip> refused"},
+		{"  many   spaces\there ", "many spaces here"},
+		{"", ""},
+
+		// Left alone: words with digits, short hex-looking words and apostrophes
+		{"v2 api_v3 x86_64 error", "v2 api_v3 x86_64 error"},
+		{"Error cafe1 in module", "Error cafe1 in module"},
+		{"Can't find user's file", "Can't find user's file"},
+		{"it's 'x' and 'y'", "it's <str> and <str>"},
+		{"unterminated 'quote here", "unterminated 'quote here"},
+		{"quote 'spans\nlines'", "quote 'spans lines'"},
+		{"résumé 'x'", "résumé <str>"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeMessage(tt.message); got != tt.want {
+			t.Errorf("NormalizeMessage(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeMessageGroupsVariants(t *testing.T) {
+	variants := [][2]string{
+		{"Order 1001 not found", "Order 987654 not found"},
+		{"User 550e8400-e29b-41d4-a716-446655440000 not found", "User 6ba7b810-9dad-11d1-80b4-00c04fd430c8 not found"},
+		{"Cannot read property 'id' of null", `Cannot read property "name" of null`},
+		{"Segfault at 0x1", "Segfault at 0xffffffffffff"},
+	}
+	for _, v := range variants {
+		if a, b := NormalizeMessage(v[0]), NormalizeMessage(v[1]); a != b {
+			t.Errorf("NormalizeMessage(%q) = %q and NormalizeMessage(%q) = %q, want the same", v[0], a, v[1], b)
+		}
+	}
+}
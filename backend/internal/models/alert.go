@@ -2,10 +2,57 @@ package models
 
 import "time"
 
+const (
+    AlertStatusFiring       = "firing"
+    AlertStatusAcknowledged = "acknowledged"
+    AlertStatusResolved     = "resolved"
+
+    AlertSourceManual = "manual"
+    AlertSourceRule   = "rule"
+    AlertSourceAlertmanager = "alertmanager"
+)
+
+// Alert is one firing episode of an alert rule, or a manually raised alert
 type Alert struct {
+    ID          string            `json:"id"`
+    ProjectID   string            `json:"project_id"`
+    RuleID      string            `json:"rule_id,omitempty"`
+    Message     string            `json:"message"`
+    Severity    string            `json:"severity"`
+    Status      string            `json:"status"`
+    Fingerprint string            `json:"fingerprint"`
+    Source      string            `json:"source"`
+    Labels      map[string]string `json:"labels"`
+    Value       *float64          `json:"value,omitempty"`
+    StartedAt   time.Time         `json:"started_at"`
+    ResolvedAt  *time.Time        `json:"resolved_at,omitempty"`
+    ResolvedBy  string            `json:"resolved_by,omitempty"`
+    AcknowledgedAt *time.Time     `json:"acknowledged_at,omitempty"`
+    AcknowledgedBy string         `json:"acknowledged_by,omitempty"`
+    CreatedAt   time.Time         `json:"created_at"`
+}
+
+// IsOpen reports whether the alert is still firing, acknowledged or not
+func (a *Alert) IsOpen() bool {
+    return a.Status == AlertStatusFiring || a.Status == AlertStatusAcknowledged
+}
+
+// Alert event types
+const (
+    AlertEventFired        = "fired"
+    AlertEventAcknowledged = "acknowledged"
+    AlertEventResolved     = "resolved"
+    AlertEventReopened     = "reopened"
+)
+
+// AlertEvent is one transition in an alert's history. ActorID is empty for
+// transitions made by PulseGuard itself, such as a rule recovering.
+type AlertEvent struct {
     ID        string    `json:"id"`
+    AlertID   string    `json:"alert_id"`
     ProjectID string    `json:"project_id"`
-    Message   string    `json:"message"`
-    Severity  string    `json:"severity"`
+    Type      string    `json:"type"`
+    ActorID   string    `json:"actor_id,omitempty"`
+    Note      string    `json:"note,omitempty"`
     CreatedAt time.Time `json:"created_at"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Alert rule condition types
+const (
+	// ConditionNewFingerprint fires for every error group first seen within the window.
+	ConditionNewFingerprint = "new_fingerprint"
+	// ConditionErrorCount fires when more than Threshold error occurrences happen within the window.
+	ConditionErrorCount = "error_count"
+	// ConditionSessionErrorRate fires when the share of sessions with errors, started within
+	// the window, is above Threshold (0-1).
+	ConditionSessionErrorRate = "session_error_rate"
+	// ConditionPromQL fires for every series returned by Query whose value is above Threshold.
+	ConditionPromQL = "promql"
+)
+
+type AlertRule struct {
+	ID              string     `json:"id"`
+	ProjectID       string     `json:"project_id"`
+	Name            string     `json:"name"`
+	ConditionType   string     `json:"condition_type"`
+	Threshold       float64    `json:"threshold"`
+	WindowSeconds   int        `json:"window_seconds"`
+	Query           string     `json:"query,omitempty"`
+	Environment     string     `json:"environment,omitempty"`
+	Severity        string     `json:"severity"`
+	Enabled         bool       `json:"enabled"`
+	CreatedBy       string     `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	LastEvaluatedAt *time.Time `json:"last_evaluated_at,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+}
+
+// Window is the look-back period of the rule's condition.
+func (r *AlertRule) Window() time.Duration {
+	return time.Duration(r.WindowSeconds) * time.Second
+}
+
+// MetricSample is a single series value returned by a PromQL instant query.
+type MetricSample struct {
+	Labels    map[string]string `json:"labels"`
+	Value     float64           `json:"value"`
+	Timestamp time.Time         `json:"timestamp"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ReleaseArtifact is a file uploaded for a release of a project, such as a
+// source map. Name is the URL or path the file is served at, optionally
+// starting with "~/" to match any origin.
+type ReleaseArtifact struct {
+	ID         string    `json:"id"`
+	ProjectID  string    `json:"project_id"`
+	Release    string    `json:"release"`
+	Name       string    `json:"name"`
+	StorageKey string    `json:"-"`
+	Size       int64     `json:"size"`
+	Checksum   string    `json:"checksum"`
+	UploadedBy string    `json:"uploaded_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
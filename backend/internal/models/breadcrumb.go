@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Breadcrumb categories
+const (
+	BreadcrumbNavigation = "navigation" // data: from, to
+	BreadcrumbClick      = "click"      // data: selector, text
+	BreadcrumbConsole    = "console"    // data: arguments
+	BreadcrumbXHR        = "xhr"        // data: method, url, status_code, duration
+	BreadcrumbFetch      = "fetch"      // data: method, url, status_code, duration
+	BreadcrumbCustom     = "custom"     // data: anything
+)
+
+// Breadcrumb is an event that happened before an error, such as a page
+// navigation, a click, a console message or a network request. Level is
+// one of debug, info, warning, error or fatal.
+type Breadcrumb struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Category  string                 `json:"category"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
@@ -9,31 +9,91 @@ type Error struct {
 	ProjectID      string            `json:"projectId"`
 	Message        string            `json:"message"`
 	StackTrace     string            `json:"stackTrace"`
+	Frames         []StackFrame      `json:"frames,omitempty"`
 	Fingerprint    string            `json:"fingerprint"`
 	OccurredAt     time.Time         `json:"occurredAt"`
 	LastSeen       time.Time         `json:"lastSeen"`
 	Environment    string            `json:"environment"`
+	Release        string            `json:"release,omitempty"`      // release of the tracked event
+	Breadcrumbs    []Breadcrumb      `json:"breadcrumbs,omitempty"`  // breadcrumbs of the tracked event
+	FirstRelease   string            `json:"firstRelease,omitempty"` // first release the group was seen in
+	LastRelease    string            `json:"lastRelease,omitempty"`  // latest release the group was seen in
 	Count          int               `json:"count"`
+	UserCount      int               `json:"userCount"`    // distinct users affected
+	SessionCount   int               `json:"sessionCount"` // distinct sessions affected
+	Stats          *ErrorStats       `json:"stats,omitempty"`
 	Source         string            `json:"source"`
 	Type           string            `json:"type"`
 	URL            string            `json:"url"`
 	UserID         string            `json:"userId"`
 	SessionID      string            `json:"sessionId"`
 	Status         string            `json:"status"`
+	StatusDetails  *StatusDetails    `json:"statusDetails,omitempty"`
+	Assignee       *Assignee         `json:"assignee,omitempty"`
 	ComponentStack string            `json:"componentStack"`
 	BrowserInfo    string            `json:"browserInfo"`
 	Occurrences    []ErrorOccurrence `json:"occurrences,omitempty"`
 	Tags           []ErrorTag        `json:"tags,omitempty"`
+
+	// LegacyFingerprint is the fingerprint the event had before stack-aware
+	// grouping, set when it is grouped by default so that it still finds the
+	// groups opened then
+	LegacyFingerprint string `json:"-"`
+}
+
+// ErrorStats are the occurrence histograms of an error group: the last 24
+// hours by hour and the last 30 days by day, oldest bucket first
+type ErrorStats struct {
+	Hourly []HistogramBucket `json:"hourly"`
+	Daily  []HistogramBucket `json:"daily"`
+}
+
+// HistogramBucket is the number of occurrences from Time until the next bucket
+type HistogramBucket struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+// StatusDetails holds the conditions of a RESOLVED or IGNORED status. An
+// issue resolved in the next release stays resolved for events of
+// AfterRelease and older releases. An ignored issue is reopened when any of
+// its conditions is met, or stays ignored for good without one.
+type StatusDetails struct {
+	InNextRelease   bool       `json:"inNextRelease,omitempty"`
+	AfterRelease    string     `json:"afterRelease,omitempty"`
+	IgnoreUntil     *time.Time `json:"ignoreUntil,omitempty"`
+	IgnoreCount     int        `json:"ignoreCount,omitempty"`
+	IgnoreUserCount int        `json:"ignoreUserCount,omitempty"`
+	// ChangedAt and CountAtChange are when the status was set and the
+	// group's count at that time; the conditions count from there
+	ChangedAt     time.Time `json:"changedAt"`
+	CountAtChange int       `json:"countAtChange"`
 }
 
 // ErrorOccurrence represents a single occurrence of an error
 type ErrorOccurrence struct {
-	ID        string                 `json:"id"`
-	ErrorID   string                 `json:"errorId"`
-	UserID    string                 `json:"userId"`
-	SessionID string                 `json:"sessionId"`
-	Timestamp time.Time              `json:"timestamp"`
-	Metadata  map[string]interface{} `json:"metadata"`
+	ID          string                 `json:"id"`
+	ErrorID     string                 `json:"errorId"`
+	UserID      string                 `json:"userId"`
+	SessionID   string                 `json:"sessionId"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Release     string                 `json:"release,omitempty"`
+	Frames      []StackFrame           `json:"frames,omitempty"`
+	Breadcrumbs []Breadcrumb           `json:"breadcrumbs,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+// StackFrame is one parsed frame of a stack trace
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	InApp    bool   `json:"inApp"`
+	// Source lines around the frame, filled in when a source map embeds them
+	PreContext  []string `json:"preContext,omitempty"`
+	ContextLine string   `json:"contextLine,omitempty"`
+	PostContext []string `json:"postContext,omitempty"`
 }
 
 // ErrorTag represents a tag associated with an error
@@ -43,3 +103,18 @@ type ErrorTag struct {
 	Key     string `json:"key"`
 	Value   string `json:"value"`
 }
+
+// ErrorMerge records an error group merged into another. Source is the
+// merged group as it was, so that it can be split out again.
+type ErrorMerge struct {
+	ID            string     `json:"id"`
+	ProjectID     string     `json:"projectId"`
+	TargetErrorID string     `json:"targetErrorId"`
+	SourceErrorID string     `json:"sourceErrorId"`
+	Source        *Error     `json:"source"`
+	Fingerprints  []string   `json:"fingerprints"`
+	MergedBy      string     `json:"mergedBy,omitempty"`
+	MergedAt      time.Time  `json:"mergedAt"`
+	UnmergedBy    string     `json:"unmergedBy,omitempty"`
+	UnmergedAt    *time.Time `json:"unmergedAt,omitempty"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Activity types of the error activity log
+const (
+	ActivityFirstSeen     = "first_seen"
+	ActivityStatusChanged = "status_changed"
+	ActivityRegressed     = "regressed"
+	ActivityAssigned      = "assigned"
+	ActivityUnassigned    = "unassigned"
+	ActivityCommented     = "commented"
+	ActivityMerged        = "merged"
+	ActivityUnmerged      = "unmerged"
+)
+
+// Assignee is the user or team an issue is assigned to
+type Assignee struct {
+	Type  string `json:"type"` // "user" or "team"
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// UserSummary identifies a user who can be mentioned in or notified about an issue
+type UserSummary struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ErrorComment is a comment on an error group. Replies point to the comment
+// they answer with ParentID and are nested under it in Replies.
+type ErrorComment struct {
+	ID         string          `json:"id"`
+	ProjectID  string          `json:"projectId"`
+	ErrorID    string          `json:"errorId"`
+	ParentID   string          `json:"parentId,omitempty"`
+	AuthorID   string          `json:"authorId,omitempty"`
+	AuthorName string          `json:"authorName,omitempty"`
+	Body       string          `json:"body"`
+	Mentions   []string        `json:"mentions"` // ids of the mentioned users
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	Replies    []*ErrorComment `json:"replies,omitempty"`
+}
+
+// ErrorActivity is an entry of an error group's append-only activity log.
+// ActorID is empty for changes made while tracking events, such as a
+// regression.
+type ErrorActivity struct {
+	ID        string                 `json:"id"`
+	ProjectID string                 `json:"projectId"`
+	ErrorID   string                 `json:"errorId"`
+	Type      string                 `json:"type"`
+	ActorID   string                 `json:"actorId,omitempty"`
+	ActorName string                 `json:"actorName,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// ErrorTimeline is everything that happened on an error group
+type ErrorTimeline struct {
+	Assignee *Assignee        `json:"assignee"`
+	Comments []*ErrorComment  `json:"comments"`
+	Activity []*ErrorActivity `json:"activity"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// Actions that can be applied to many error groups at once
+const (
+	BulkResolve = "resolve"
+	BulkIgnore  = "ignore"
+	BulkAssign  = "assign"
+	BulkTag     = "tag"
+	BulkDelete  = "delete"
+)
+
+// BulkAction is a change applied to every selected error group
+type BulkAction struct {
+	Type string `json:"action"`
+	// StatusDetails are the optional conditions of resolve and ignore
+	StatusDetails *StatusDetails `json:"statusDetails,omitempty"`
+	// AssigneeUserID or AssigneeTeamID is who assign assigns to; neither unassigns
+	AssigneeUserID string `json:"userId,omitempty"`
+	AssigneeTeamID string `json:"teamId,omitempty"`
+	// Tags are added by tag
+	Tags []ErrorTag `json:"tags,omitempty"`
+}
+
+// Status is the status the action sets, or "" if it does not change it
+func (a *BulkAction) Status() string {
+	switch a.Type {
+	case BulkResolve:
+		return "RESOLVED"
+	case BulkIgnore:
+		return "IGNORED"
+	}
+	return ""
+}
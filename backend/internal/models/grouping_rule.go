@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// GroupingRule overrides the fingerprint of matching errors. Every non-empty
+// matcher must match: MatchType exactly, MatchMessage and MatchFrame as
+// regular expressions against the message and any stack frame ("function file").
+type GroupingRule struct {
+	ID           string    `json:"id"`
+	ProjectID    string    `json:"project_id"`
+	Name         string    `json:"name"`
+	MatchType    string    `json:"match_type,omitempty"`
+	MatchMessage string    `json:"match_message,omitempty"`
+	MatchFrame   string    `json:"match_frame,omitempty"`
+	Fingerprint  []string  `json:"fingerprint"`
+	Priority     int       `json:"priority"`
+	Enabled      bool      `json:"enabled"`
+	CreatedBy    string    `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
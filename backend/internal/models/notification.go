@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Notification channel types
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+)
+
+// Notification delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySending   = "sending"
+	DeliveryRetrying  = "retrying"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// NotificationChannel is a destination alert notifications are sent to
+type NotificationChannel struct {
+	ID         string    `json:"id"`
+	ProjectID  string    `json:"project_id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	URL        string    `json:"url,omitempty"`
+	Secret     string    `json:"secret,omitempty"`
+	Recipients []string  `json:"recipients,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NotificationDelivery is one notification sent, or being retried, to a channel
+type NotificationDelivery struct {
+	ID            string     `json:"id"`
+	ProjectID     string     `json:"project_id"`
+	ChannelID     string     `json:"channel_id"`
+	AlertID       string     `json:"alert_id,omitempty"`
+	Event         string     `json:"event"`
+	Payload       []byte     `json:"-"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	ResponseCode  *int       `json:"response_code,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import "time"
+
+// Role is a member's level of access to an organization or project.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleViewer Role = "viewer"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleMember: 2,
+	RoleAdmin:  3,
+	RoleOwner:  4,
+}
+
+// IsValid reports whether r is one of the known roles.
+func (r Role) IsValid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// AtLeast reports whether r grants at least the access of min.
+func (r Role) AtLeast(min Role) bool {
+	return roleRank[r] >= roleRank[min]
+}
+
+// HighestRole returns the most privileged of the given roles, or "" if none.
+func HighestRole(roles ...Role) Role {
+	var best Role
+	for _, r := range roles {
+		if roleRank[r] > roleRank[best] {
+			best = r
+		}
+	}
+	return best
+}
+
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Role      Role      `json:"role,omitempty"`
+}
+
+// Member is a user's membership in an organization or project.
+type Member struct {
+	UserID    string    `json:"userId"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Team struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organizationId"`
+	Name           string    `json:"name"`
+	Slug           string    `json:"slug"`
+	CreatedAt      time.Time `json:"createdAt"`
+	Members        []Member  `json:"members,omitempty"`
+}
+
+// ProjectTeam grants every member of a team a role on a project.
+type ProjectTeam struct {
+	ProjectID string    `json:"projectId"`
+	TeamID    string    `json:"teamId"`
+	TeamName  string    `json:"teamName"`
+	TeamSlug  string    `json:"teamSlug"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Invitation asks someone by email to join an organization or a project.
+type Invitation struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organizationId,omitempty"`
+	ProjectID      string     `json:"projectId,omitempty"`
+	Email          string     `json:"email"`
+	Role           Role       `json:"role"`
+	Token          string     `json:"-"`
+	InvitedBy      string     `json:"invitedBy"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	AcceptedAt     *time.Time `json:"acceptedAt"`
+}
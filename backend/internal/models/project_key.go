@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ProjectKey is an ingest credential that lets an application report
+// events for a single project without a logged-in user.
+type ProjectKey struct {
+	ID         string     `json:"id"`
+	ProjectID  string     `json:"projectId"`
+	Name       string     `json:"name"`
+	PublicKey  string     `json:"publicKey"`
+	DSN        string     `json:"dsn,omitempty"`
+	SentryDSN  string     `json:"sentryDsn,omitempty"` // for Sentry SDKs
+	RateLimit  int        `json:"rateLimit"`           // events per minute, 0 is unlimited
+	CreatedBy  string     `json:"createdBy"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
+// IsActive reports whether the key can still be used for ingestion.
+func (k *ProjectKey) IsActive() bool {
+	return k.RevokedAt == nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Release is a version of a project's code. Events are attached to the
+// release they were sent from.
+type Release struct {
+	ID           string     `json:"id"`
+	ProjectID    string     `json:"project_id"`
+	Version      string     `json:"version"`
+	Ref          string     `json:"ref,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	DateReleased *time.Time `json:"date_released,omitempty"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// NewIssues counts the error groups first seen in the release
+	NewIssues  int     `json:"new_issues"`
+	LastDeploy *Deploy `json:"last_deploy,omitempty"`
+}
+
+// Deploy records a release being deployed to an environment
+type Deploy struct {
+	ID           string     `json:"id"`
+	ProjectID    string     `json:"project_id"`
+	ReleaseID    string     `json:"release_id"`
+	Environment  string     `json:"environment"`
+	Name         string     `json:"name,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	DateStarted  *time.Time `json:"date_started,omitempty"`
+	DateFinished time.Time  `json:"date_finished"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
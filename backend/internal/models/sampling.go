@@ -0,0 +1,29 @@
+package models
+
+// SamplingConfig decides which share of a project's events are stored as
+// occurrences. Rates are between 0 (exclusive) and 1; sampled-out events
+// still count towards their group.
+type SamplingConfig struct {
+	ProjectID string `json:"project_id"`
+	// SampleRate applies to events no other rate matches
+	SampleRate float64 `json:"sample_rate"`
+	// EnvironmentRates override SampleRate for events of an environment
+	EnvironmentRates map[string]float64 `json:"environment_rates"`
+	// TypeRates override the other rates for events of an error type
+	TypeRates map[string]float64 `json:"type_rates"`
+	// KeepFirst is how many events of a new group are stored before
+	// sampling applies to it
+	KeepFirst int `json:"keep_first"`
+}
+
+// RateFor returns the sample rate of an event: the rate of its error type,
+// else that of its environment, else the base rate
+func (c *SamplingConfig) RateFor(environment, errorType string) float64 {
+	if rate, ok := c.TypeRates[errorType]; ok {
+		return rate
+	}
+	if rate, ok := c.EnvironmentRates[environment]; ok {
+		return rate
+	}
+	return c.SampleRate
+}
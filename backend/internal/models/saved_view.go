@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SavedView is a named error search: a query in the search syntax and the
+// filters and sort order it is listed with. It is private to its owner
+// unless Shared with the project.
+type SavedView struct {
+	ID          string    `json:"id"`
+	ProjectID   string    `json:"project_id"`
+	OwnerID     string    `json:"owner_id"`
+	OwnerName   string    `json:"owner_name,omitempty"`
+	Name        string    `json:"name"`
+	Query       string    `json:"query"`
+	Environment string    `json:"environment,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Sort        string    `json:"sort,omitempty"`
+	Shared      bool      `json:"shared"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
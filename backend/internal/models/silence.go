@@ -0,0 +1,67 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// Matcher selects alerts by one label. Value is matched exactly, or as an
+// anchored regular expression when IsRegex is set.
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"is_regex,omitempty"`
+}
+
+// Silence suppresses notifications for alerts whose labels match all of its
+// matchers, between StartsAt and EndsAt
+type Silence struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"project_id"`
+	Matchers  []Matcher `json:"matchers"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActiveAt reports whether the silence is in effect at t
+func (s *Silence) ActiveAt(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Matches reports whether every matcher matches the labels. Matchers must
+// have been validated, so regular expressions compile.
+func (s *Silence) Matches(labels map[string]string) bool {
+	for _, m := range s.Matchers {
+		value := labels[m.Name]
+		if m.IsRegex {
+			re, err := regexp.Compile("^(?:" + m.Value + ")$")
+			if err != nil || !re.MatchString(value) {
+				return false
+			}
+			continue
+		}
+		if value != m.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// MaintenanceWindow suppresses every notification of a project while it lasts
+type MaintenanceWindow struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"project_id"`
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ActiveAt reports whether the window is in effect at t
+func (w *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	return !t.Before(w.StartsAt) && t.Before(w.EndsAt)
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Outcomes of an ingested event
+const (
+	IngestAccepted      = "accepted"
+	IngestRateLimited   = "rate_limited"
+	IngestOverQuota     = "over_quota" // dropped: the monthly quota is used up
+	IngestSpike         = "spike"      // dropped by spike protection
+	IngestQueueFull     = "queue_full" // dropped: the ingest queue is full
+	IngestQueueClosed   = "queue_closed"
+	IngestPrepareFailed = "prepare_failed" // dropped: the queued event could not be prepared
+	IngestStoreFailed   = "store_failed"   // dropped: the queued event could not be stored
+)
+
+// IngestLimits are the limits on the events a project may send
+type IngestLimits struct {
+	ProjectID string `json:"project_id"`
+	// RateLimit is in events per minute; 0 uses the server default
+	RateLimit int `json:"rate_limit"`
+	// MonthlyQuota is in events per calendar month, in UTC; 0 is unlimited
+	MonthlyQuota int64 `json:"monthly_quota"`
+	// SpikeProtection samples events that arrive far faster than usual
+	SpikeProtection bool `json:"spike_protection"`
+}
+
+// UsageDay counts the events a project sent on a day, by outcome. Dropped
+// events are those over the quota or dropped by spike protection.
+type UsageDay struct {
+	Day         time.Time `json:"day"`
+	Accepted    int64     `json:"accepted"`
+	RateLimited int64     `json:"rate_limited"`
+	Dropped     int64     `json:"dropped"`
+}
+
+// Usage is a project's usage over the current quota period
+type Usage struct {
+	PeriodStart time.Time     `json:"period_start"`
+	PeriodEnd   time.Time     `json:"period_end"`
+	Limits      *IngestLimits `json:"limits"`
+	Accepted    int64         `json:"accepted"`
+	RateLimited int64         `json:"rate_limited"`
+	Dropped     int64         `json:"dropped"`
+	// Remaining is the quota left in the period, nil without a quota
+	Remaining *int64     `json:"remaining"`
+	Days      []UsageDay `json:"days"`
+}
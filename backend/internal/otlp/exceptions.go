@@ -0,0 +1,154 @@
+package otlp
+
+import (
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+
+	"pulseguard/internal/models"
+)
+
+// DefaultEnvironment is the environment of exceptions whose resource names none
+const DefaultEnvironment = "production"
+
+// Exception is an exception recorded as a span event, following the OTel
+// semantic conventions for exceptions
+type Exception struct {
+	Type       string
+	Message    string
+	Stacktrace string
+	Escaped    bool
+	Time       time.Time
+	TraceID    string
+	SpanID     string
+	SpanName   string
+	Scope      string
+	// Resource, Span and Event are the string forms of the attributes of
+	// the resource, the span and the event
+	Resource map[string]string
+	Span     map[string]string
+	Event    map[string]string
+}
+
+// Exceptions returns the exceptions recorded in the spans of an export
+// request: span events with exception.* attributes
+func Exceptions(req *coltracepb.ExportTraceServiceRequest) []*Exception {
+	var out []*Exception
+	for _, rs := range req.ResourceSpans {
+		var resource map[string]string
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				var spanAttrs map[string]string
+				for _, event := range span.Events {
+					attrs := attributes(event.Attributes)
+					if attrs["exception.type"] == "" && attrs["exception.message"] == "" {
+						continue
+					}
+					if resource == nil {
+						resource = attributes(rs.GetResource().GetAttributes())
+					}
+					if spanAttrs == nil {
+						spanAttrs = attributes(span.Attributes)
+					}
+					escaped, _ := strconv.ParseBool(attrs["exception.escaped"])
+					out = append(out, &Exception{
+						Type:       attrs["exception.type"],
+						Message:    attrs["exception.message"],
+						Stacktrace: attrs["exception.stacktrace"],
+						Escaped:    escaped,
+						Time:       time.Unix(0, int64(event.TimeUnixNano)),
+						TraceID:    hex.EncodeToString(span.TraceId),
+						SpanID:     hex.EncodeToString(span.SpanId),
+						SpanName:   span.Name,
+						Scope:      ss.GetScope().GetName(),
+						Resource:   resource,
+						Span:       spanAttrs,
+						Event:      attrs,
+					})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// ToError maps the exception to an error event of the project. The stack
+// trace is left for ErrorService.Track to parse.
+func (e *Exception) ToError(projectID string, now time.Time) *models.Error {
+	out := &models.Error{
+		ProjectID:   projectID,
+		Type:        e.Type,
+		Message:     e.Message,
+		StackTrace:  e.Stacktrace,
+		Environment: firstNonEmpty(e.Resource["deployment.environment.name"], e.Resource["deployment.environment"]),
+		Release:     e.Resource["service.version"],
+		OccurredAt:  now,
+		Status:      "ACTIVE",
+		URL:         firstNonEmpty(e.Span["url.full"], e.Span["http.url"]),
+		UserID:      firstNonEmpty(e.Span["enduser.id"], e.Resource["enduser.id"]),
+		SessionID:   firstNonEmpty(e.Span["session.id"], e.Resource["session.id"]),
+		BrowserInfo: firstNonEmpty(e.Span["user_agent.original"], e.Span["http.user_agent"]),
+		Source:      firstNonEmpty(e.Event["code.filepath"], e.Span["code.filepath"], e.SpanName),
+	}
+	if out.Message == "" {
+		out.Message = out.Type
+	}
+	if out.Environment == "" {
+		out.Environment = DefaultEnvironment
+	}
+	if !e.Time.IsZero() && e.Time.Unix() > 0 && e.Time.Before(now) {
+		out.OccurredAt = e.Time
+	}
+	if service := e.Resource["service.name"]; service != "" {
+		out.Tags = append(out.Tags, models.ErrorTag{Key: "service", Value: service})
+	}
+	if e.SpanName != "" {
+		out.Tags = append(out.Tags, models.ErrorTag{Key: "transaction", Value: e.SpanName})
+	}
+	return out
+}
+
+// Metadata returns what the occurrence keeps of the exception beyond the
+// error fields, notably the span it links to
+func (e *Exception) Metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"source":       "otlp",
+		"trace_id":     e.TraceID,
+		"span_id":      e.SpanID,
+		"span_name":    e.SpanName,
+		"scope":        e.Scope,
+		"escaped":      e.Escaped,
+		"service_name": e.Resource["service.name"],
+	}
+}
+
+// attributes returns the scalar attributes as strings; arrays, maps and
+// bytes are left out
+func attributes(kvs []*commonpb.KeyValue) map[string]string {
+	attrs := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		switch v := kv.GetValue().GetValue().(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_BoolValue:
+			attrs[kv.Key] = strconv.FormatBool(v.BoolValue)
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.Key] = strconv.FormatInt(v.IntValue, 10)
+		case *commonpb.AnyValue_DoubleValue:
+			attrs[kv.Key] = strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+		}
+	}
+	return attrs
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
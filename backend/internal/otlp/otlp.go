@@ -0,0 +1,262 @@
+// Package otlp decodes OTLP/HTTP export requests, in protobuf or JSON, and
+// scopes them to a project before they are forwarded.
+package otlp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+
+	// ProjectIDAttribute is the resource attribute that scopes telemetry to a
+	// project. The collector turns it into a Prometheus and Loki label and
+	// Tempo stores it in a dedicated column; queries filter on it
+	ProjectIDAttribute = "project_id"
+
+	// legacyProjectIDAttribute is copied to project_id by the collector's
+	// logs pipeline, so senders may not set it either
+	legacyProjectIDAttribute = "projectId"
+)
+
+var (
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrInvalidRequest         = errors.New("invalid OTLP request")
+)
+
+// Signal is a kind of telemetry, which has its own OTLP/HTTP path
+type Signal string
+
+const (
+	Traces  Signal = "traces"
+	Logs    Signal = "logs"
+	Metrics Signal = "metrics"
+)
+
+// Path is the path of the signal under an OTLP/HTTP endpoint
+func (s Signal) Path() string {
+	return "/v1/" + string(s)
+}
+
+// NewRequest returns an empty export request of the signal
+func NewRequest(signal Signal) proto.Message {
+	switch signal {
+	case Traces:
+		return &coltracepb.ExportTraceServiceRequest{}
+	case Logs:
+		return &collogspb.ExportLogsServiceRequest{}
+	default:
+		return &colmetricspb.ExportMetricsServiceRequest{}
+	}
+}
+
+// NewResponse returns the export response of the signal, which reports
+// every record as accepted
+func NewResponse(signal Signal) proto.Message {
+	switch signal {
+	case Traces:
+		return &coltracepb.ExportTraceServiceResponse{}
+	case Logs:
+		return &collogspb.ExportLogsServiceResponse{}
+	default:
+		return &colmetricspb.ExportMetricsServiceResponse{}
+	}
+}
+
+// MediaType returns the encoding of an OTLP/HTTP request from its
+// Content-Type: ContentTypeProtobuf or ContentTypeJSON
+func MediaType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	switch mediaType {
+	case ContentTypeProtobuf, "application/protobuf":
+		return ContentTypeProtobuf, nil
+	case ContentTypeJSON:
+		return ContentTypeJSON, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+}
+
+// Unmarshal decodes body, of the given media type, into msg
+func Unmarshal(mediaType string, body []byte, msg proto.Message) error {
+	if mediaType == ContentTypeJSON {
+		var err error
+		if body, err = hexIDsToBase64(body); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, msg); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+		}
+		return nil
+	}
+	if err := proto.Unmarshal(body, msg); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+// Marshal encodes msg in the given media type
+func Marshal(mediaType string, msg proto.Message) ([]byte, error) {
+	if mediaType == ContentTypeJSON {
+		return protojson.Marshal(msg)
+	}
+	return proto.Marshal(msg)
+}
+
+// idFields are the OTLP JSON fields that hold trace and span IDs
+var idFields = map[string]bool{
+	"traceId": true, "spanId": true, "parentSpanId": true,
+	"trace_id": true, "span_id": true, "parent_span_id": true,
+}
+
+// hexIDsToBase64 rewrites the trace and span IDs of an OTLP JSON body, which
+// OTLP encodes in hex rather than the base64 protojson expects for bytes
+func hexIDsToBase64(body []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the request")
+	}
+	if err := rewriteIDs(doc); err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func rewriteIDs(v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if s, ok := value.(string); ok && idFields[key] {
+				id, err := hex.DecodeString(s)
+				if err != nil {
+					return fmt.Errorf("invalid %s %q", key, s)
+				}
+				v[key] = base64.StdEncoding.EncodeToString(id)
+				continue
+			}
+			if err := rewriteIDs(value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := rewriteIDs(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Stamp sets the project_id attribute on every resource of an export
+// request, replacing any project_id the sender set. Record attributes become
+// labels too, so any project_id on spans, log records and data points is
+// dropped.
+func Stamp(msg proto.Message, projectID string) {
+	switch req := msg.(type) {
+	case *coltracepb.ExportTraceServiceRequest:
+		for _, rs := range req.ResourceSpans {
+			rs.Resource = stampResource(rs.Resource, projectID)
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					span.Attributes = withoutProjectID(span.Attributes)
+					for _, event := range span.Events {
+						event.Attributes = withoutProjectID(event.Attributes)
+					}
+					for _, link := range span.Links {
+						link.Attributes = withoutProjectID(link.Attributes)
+					}
+				}
+			}
+		}
+	case *collogspb.ExportLogsServiceRequest:
+		for _, rl := range req.ResourceLogs {
+			rl.Resource = stampResource(rl.Resource, projectID)
+			for _, sl := range rl.ScopeLogs {
+				for _, record := range sl.LogRecords {
+					record.Attributes = withoutProjectID(record.Attributes)
+				}
+			}
+		}
+	case *colmetricspb.ExportMetricsServiceRequest:
+		for _, rm := range req.ResourceMetrics {
+			rm.Resource = stampResource(rm.Resource, projectID)
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					stampMetric(m)
+				}
+			}
+		}
+	}
+}
+
+func stampResource(res *resourcepb.Resource, projectID string) *resourcepb.Resource {
+	if res == nil {
+		res = &resourcepb.Resource{}
+	}
+	res.Attributes = append(withoutProjectID(res.Attributes), &commonpb.KeyValue{
+		Key:   ProjectIDAttribute,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: projectID}},
+	})
+	return res
+}
+
+func stampMetric(m *metricspb.Metric) {
+	switch data := m.Data.(type) {
+	case *metricspb.Metric_Gauge:
+		for _, dp := range data.Gauge.GetDataPoints() {
+			dp.Attributes = withoutProjectID(dp.Attributes)
+		}
+	case *metricspb.Metric_Sum:
+		for _, dp := range data.Sum.GetDataPoints() {
+			dp.Attributes = withoutProjectID(dp.Attributes)
+		}
+	case *metricspb.Metric_Histogram:
+		for _, dp := range data.Histogram.GetDataPoints() {
+			dp.Attributes = withoutProjectID(dp.Attributes)
+		}
+	case *metricspb.Metric_ExponentialHistogram:
+		for _, dp := range data.ExponentialHistogram.GetDataPoints() {
+			dp.Attributes = withoutProjectID(dp.Attributes)
+		}
+	case *metricspb.Metric_Summary:
+		for _, dp := range data.Summary.GetDataPoints() {
+			dp.Attributes = withoutProjectID(dp.Attributes)
+		}
+	}
+}
+
+// withoutProjectID drops the attributes a sender could pick a project with
+func withoutProjectID(attrs []*commonpb.KeyValue) []*commonpb.KeyValue {
+	kept := attrs[:0]
+	for _, kv := range attrs {
+		if key := kv.GetKey(); key != ProjectIDAttribute && key != legacyProjectIDAttribute {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,344 @@
+package otlp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const traceID = "5b8efff798038103d269b633813fc60c"
+const spanID = "eee19b7ec3c1b174"
+
+// exceptionJSON is a trace export with one span that recorded an exception
+// and a log event, in OTLP JSON with hex IDs
+const exceptionJSON = `{
+	"resourceSpans": [{
+		"resource": {"attributes": [
+			{"key": "service.name", "value": {"stringValue": "checkout"}},
+			{"key": "service.version", "value": {"stringValue": "1.2.3"}},
+			{"key": "deployment.environment.name", "value": {"stringValue": "staging"}}
+		]},
+		"scopeSpans": [{
+			"scope": {"name": "http"},
+			"spans": [{
+				"traceId": "` + traceID + `",
+				"spanId": "` + spanID + `",
+				"parentSpanId": "",
+				"name": "POST /pay",
+				"attributes": [
+					{"key": "url.full", "value": {"stringValue": "https://shop.example.com/pay"}},
+					{"key": "enduser.id", "value": {"stringValue": "42"}},
+					{"key": "http.status_code", "value": {"intValue": "500"}}
+				],
+				"events": [
+					{"name": "log", "timeUnixNano": "1760702400000000000", "attributes": [
+						{"key": "message", "value": {"stringValue": "retrying"}}
+					]},
+					{"name": "exception", "timeUnixNano": "1760702400000000000", "attributes": [
+						{"key": "exception.type", "value": {"stringValue": "ValueError"}},
+						{"key": "exception.message", "value": {"stringValue": "bad amount"}},
+						{"key": "exception.stacktrace", "value": {"stringValue": "Traceback"}},
+						{"key": "exception.escaped", "value": {"boolValue": true}}
+					]}
+				],
+				"unknownField": 1
+			}]
+		}]
+	}]
+}`
+
+func TestMediaType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"application/x-protobuf", ContentTypeProtobuf},
+		{"application/protobuf", ContentTypeProtobuf},
+		{"application/json", ContentTypeJSON},
+		{"Application/JSON; charset=utf-8", ContentTypeJSON},
+		{"text/plain", ""},
+		{"", ""},
+		{"application/json; charset", ""},
+	}
+	for _, tt := range tests {
+		got, err := MediaType(tt.contentType)
+		if tt.want == "" {
+			if !errors.Is(err, ErrUnsupportedContentType) {
+				t.Errorf("MediaType(%q) = %q, %v, want ErrUnsupportedContentType", tt.contentType, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("MediaType(%q) = %q, %v, want %q", tt.contentType, got, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	valid, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{Name: "GET /", TraceId: make([]byte, 16), SpanId: make([]byte, 8)}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		mediaType string
+		body      string
+		spans     int
+		wantErr   string
+	}{
+		{name: "protobuf", mediaType: ContentTypeProtobuf, body: string(valid), spans: 1},
+		{name: "empty protobuf", mediaType: ContentTypeProtobuf, body: ""},
+		{name: "json", mediaType: ContentTypeJSON, body: exceptionJSON, spans: 1},
+		{name: "empty json object", mediaType: ContentTypeJSON, body: "{}"},
+		{
+			name:      "json with snake_case fields",
+			mediaType: ContentTypeJSON,
+			body:      `{"resource_spans":[{"scope_spans":[{"spans":[{"trace_id":"` + traceID + `","span_id":"` + spanID + `"}]}]}]}`,
+			spans:     1,
+		},
+		{
+			name:      "json time as a number",
+			mediaType: ContentTypeJSON,
+			body:      `{"resourceSpans":[{"scopeSpans":[{"spans":[{"startTimeUnixNano":1760702400000000000}]}]}]}`,
+			spans:     1,
+		},
+
+		// Malformed bodies are rejected with ErrInvalidRequest, never a panic
+		{name: "protobuf garbage", mediaType: ContentTypeProtobuf, body: "\xff\xff\xff\xff", wantErr: "invalid OTLP request"},
+		{name: "truncated protobuf", mediaType: ContentTypeProtobuf, body: string(valid[:len(valid)-3]), wantErr: "invalid OTLP request"},
+		{name: "json sent as protobuf", mediaType: ContentTypeProtobuf, body: exceptionJSON, wantErr: "invalid OTLP request"},
+		{name: "empty json", mediaType: ContentTypeJSON, body: "", wantErr: "invalid OTLP request"},
+		{name: "not json", mediaType: ContentTypeJSON, body: "resourceSpans", wantErr: "invalid OTLP request"},
+		{name: "truncated json", mediaType: ContentTypeJSON, body: exceptionJSON[:200], wantErr: "invalid OTLP request"},
+		{name: "data after the request", mediaType: ContentTypeJSON, body: "{} {}", wantErr: "unexpected data after the request"},
+		{name: "json array", mediaType: ContentTypeJSON, body: "[]", wantErr: "invalid OTLP request"},
+		{name: "json null", mediaType: ContentTypeJSON, body: "null", wantErr: "invalid OTLP request"},
+		{
+			name:      "field of the wrong type",
+			mediaType: ContentTypeJSON,
+			body:      `{"resourceSpans":{"scopeSpans":[]}}`,
+			wantErr:   "invalid OTLP request",
+		},
+		{
+			name:      "trace id not hex",
+			mediaType: ContentTypeJSON,
+			body:      `{"resourceSpans":[{"scopeSpans":[{"spans":[{"traceId":"not-hex"}]}]}]}`,
+			wantErr:   `invalid traceId "not-hex"`,
+		},
+		{
+			name:      "span id of odd length",
+			mediaType: ContentTypeJSON,
+			body:      `{"resourceSpans":[{"scopeSpans":[{"spans":[{"spanId":"abc"}]}]}]}`,
+			wantErr:   `invalid spanId "abc"`,
+		},
+		{
+			name:      "negative time",
+			mediaType: ContentTypeJSON,
+			body:      `{"resourceSpans":[{"scopeSpans":[{"spans":[{"startTimeUnixNano":"-1"}]}]}]}`,
+			wantErr:   "invalid OTLP request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewRequest(Traces).(*coltracepb.ExportTraceServiceRequest)
+			err := Unmarshal(tt.mediaType, []byte(tt.body), req)
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Unmarshal() error = %v, want ErrInvalidRequest containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			spans := 0
+			for _, rs := range req.ResourceSpans {
+				for _, ss := range rs.ScopeSpans {
+					spans += len(ss.Spans)
+				}
+			}
+			if spans != tt.spans {
+				t.Errorf("Unmarshal() decoded %d spans, want %d", spans, tt.spans)
+			}
+		})
+	}
+}
+
+func TestExceptions(t *testing.T) {
+	req := &coltracepb.ExportTraceServiceRequest{}
+	if err := Unmarshal(ContentTypeJSON, []byte(exceptionJSON), req); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	exceptions := Exceptions(req)
+	if len(exceptions) != 1 {
+		t.Fatalf("Exceptions() returned %d exceptions, want 1", len(exceptions))
+	}
+	e := exceptions[0]
+	if e.Type != "ValueError" || e.Message != "bad amount" || e.Stacktrace != "Traceback" || !e.Escaped {
+		t.Errorf("exception = %+v", e)
+	}
+	if e.TraceID != traceID || e.SpanID != spanID || e.SpanName != "POST /pay" || e.Scope != "http" {
+		t.Errorf("exception span = %s %s %q %q", e.TraceID, e.SpanID, e.SpanName, e.Scope)
+	}
+	if e.Span["http.status_code"] != "500" || e.Resource["service.name"] != "checkout" {
+		t.Errorf("exception attributes = %v, %v", e.Span, e.Resource)
+	}
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	got := e.ToError("project-1", now)
+	if got.Environment != "staging" || got.Release != "1.2.3" || got.URL != "https://shop.example.com/pay" ||
+		got.UserID != "42" || got.Source != "POST /pay" || !got.OccurredAt.Equal(time.Unix(1760702400, 0)) {
+		t.Errorf("ToError() = %+v", got)
+	}
+}
+
+func TestExceptionsWithoutAttributes(t *testing.T) {
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{
+					Events: []*tracepb.Span_Event{
+						{Name: "exception"},
+						{Name: "exception", TimeUnixNano: 1 << 63, Attributes: []*commonpb.KeyValue{
+							{Key: "exception.type", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "Panic"}}},
+							{Key: "exception.escaped", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "maybe"}}},
+							{Key: "exception.message"},
+						}},
+					},
+				}},
+			}},
+		}},
+	}
+
+	exceptions := Exceptions(req)
+	if len(exceptions) != 1 {
+		t.Fatalf("Exceptions() returned %d exceptions, want 1", len(exceptions))
+	}
+	e := exceptions[0]
+	if e.Type != "Panic" || e.Escaped || e.TraceID != "" || e.Scope != "" {
+		t.Errorf("exception = %+v", e)
+	}
+
+	// A time past int64 is ignored, as are a missing message and environment
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	got := e.ToError("project-1", now)
+	if got.Message != "Panic" || got.Environment != DefaultEnvironment || !got.OccurredAt.Equal(now) {
+		t.Errorf("ToError() = %+v", got)
+	}
+}
+
+func TestStamp(t *testing.T) {
+	req := &collogspb.ExportLogsServiceRequest{}
+	body := `{"resourceLogs":[
+		{"resource":{"attributes":[
+			{"key":"project_id","value":{"stringValue":"someone-else"}},
+			{"key":"service.name","value":{"stringValue":"api"}}
+		]}},
+		{}
+	]}`
+	if err := Unmarshal(ContentTypeJSON, []byte(body), req); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	Stamp(req, "project-1")
+
+	for i, rl := range req.ResourceLogs {
+		attrs := attributes(rl.GetResource().GetAttributes())
+		if attrs[ProjectIDAttribute] != "project-1" {
+			t.Errorf("resource %d project_id = %q, want project-1", i, attrs[ProjectIDAttribute])
+		}
+		n := 0
+		for _, kv := range rl.Resource.Attributes {
+			if kv.Key == ProjectIDAttribute {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Errorf("resource %d has %d project_id attributes, want 1", i, n)
+		}
+	}
+	if attrs := attributes(req.ResourceLogs[0].Resource.Attributes); attrs["service.name"] != "api" {
+		t.Errorf("Stamp() dropped service.name: %v", attrs)
+	}
+
+	// A resource without attributes gets the project too
+	res := stampResource(&resourcepb.Resource{}, "project-2")
+	if attributes(res.Attributes)[ProjectIDAttribute] != "project-2" {
+		t.Errorf("stampResource() = %v", res)
+	}
+}
+
+func TestStampDropsRecordProjectID(t *testing.T) {
+	logs := &collogspb.ExportLogsServiceRequest{}
+	body := `{"resourceLogs":[{"scopeLogs":[{"logRecords":[{"attributes":[
+		{"key":"project_id","value":{"stringValue":"someone-else"}},
+		{"key":"projectId","value":{"stringValue":"someone-else"}},
+		{"key":"level","value":{"stringValue":"error"}}
+	]}]}]}]}`
+	if err := Unmarshal(ContentTypeJSON, []byte(body), logs); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	Stamp(logs, "project-1")
+	attrs := attributes(logs.ResourceLogs[0].ScopeLogs[0].LogRecords[0].Attributes)
+	if len(attrs) != 1 || attrs["level"] != "error" {
+		t.Errorf("log record attributes = %v, want only level", attrs)
+	}
+
+	metrics := &colmetricspb.ExportMetricsServiceRequest{}
+	body = `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+		{"name":"requests","sum":{"dataPoints":[{"asInt":"1","attributes":[
+			{"key":"project_id","value":{"stringValue":"someone-else"}},
+			{"key":"route","value":{"stringValue":"/"}}
+		]}]}},
+		{"name":"latency","histogram":{"dataPoints":[{"count":"1","attributes":[
+			{"key":"project_id","value":{"stringValue":"someone-else"}}
+		]}]}}
+	]}]}]}`
+	if err := Unmarshal(ContentTypeJSON, []byte(body), metrics); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	Stamp(metrics, "project-1")
+	rm := metrics.ResourceMetrics[0]
+	if attrs := attributes(rm.Resource.Attributes); attrs[ProjectIDAttribute] != "project-1" {
+		t.Errorf("resource attributes = %v, want project-1", attrs)
+	}
+	m := rm.ScopeMetrics[0].Metrics
+	if attrs := attributes(m[0].GetSum().DataPoints[0].Attributes); len(attrs) != 1 || attrs["route"] != "/" {
+		t.Errorf("sum data point attributes = %v, want only route", attrs)
+	}
+	if attrs := attributes(m[1].GetHistogram().DataPoints[0].Attributes); len(attrs) != 0 {
+		t.Errorf("histogram data point attributes = %v, want none", attrs)
+	}
+
+	traces := &coltracepb.ExportTraceServiceRequest{}
+	if err := Unmarshal(ContentTypeJSON, []byte(exceptionJSON), traces); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	span := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	span.Attributes = append(span.Attributes, &commonpb.KeyValue{
+		Key:   ProjectIDAttribute,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "someone-else"}},
+	})
+	Stamp(traces, "project-1")
+	if _, ok := attributes(span.Attributes)[ProjectIDAttribute]; ok {
+		t.Errorf("span attributes = %v, want no project_id", attributes(span.Attributes))
+	}
+}
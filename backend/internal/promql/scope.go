@@ -0,0 +1,69 @@
+// Package promql limits the PromQL queries of a project to its own series.
+// Telemetry of every project shares one Prometheus; each series carries the
+// project in its project_id label. OTLP ingest stamps it on every resource,
+// which the collector turns into labels, and the backend sets it on the HTTP
+// metrics of the requests it authorized for a project.
+package promql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// ProjectLabel is the label that holds the project of a series
+const ProjectLabel = "project_id"
+
+var (
+	ErrInvalidQuery = errors.New("invalid PromQL query")
+	ErrUnscoped     = errors.New("query cannot be limited to the project")
+)
+
+// Scope returns query with every series selector limited to the project:
+// selectors without a project_id matcher get project_id="<projectID>", and
+// a query with a selector that matches project_id any other way is
+// rejected with ErrUnscoped.
+func Scope(query, projectID string) (string, error) {
+	if projectID == "" {
+		return "", fmt.Errorf("%w: no project", ErrUnscoped)
+	}
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+
+	err = parser.Walk(scopeVisitor(projectID), expr, nil)
+	if err != nil {
+		return "", err
+	}
+	return expr.String(), nil
+}
+
+// scopeVisitor adds the project matcher to the selectors it visits. Walk
+// reaches the selectors of range vectors and subqueries as well.
+type scopeVisitor string
+
+func (v scopeVisitor) Visit(node parser.Node, _ []parser.Node) (parser.Visitor, error) {
+	sel, ok := node.(*parser.VectorSelector)
+	if !ok {
+		return v, nil
+	}
+
+	projectID := string(v)
+	scoped := false
+	for _, m := range sel.LabelMatchers {
+		if m.Name != ProjectLabel {
+			continue
+		}
+		if m.Type != labels.MatchEqual || m.Value != projectID {
+			return nil, fmt.Errorf("%w: %s may only match %s=%q", ErrUnscoped, sel, ProjectLabel, projectID)
+		}
+		scoped = true
+	}
+	if !scoped {
+		sel.LabelMatchers = append(sel.LabelMatchers, labels.MustNewMatcher(labels.MatchEqual, ProjectLabel, projectID))
+	}
+	return v, nil
+}
@@ -0,0 +1,68 @@
+package promql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"metric", `up`, `up{project_id="p1"}`},
+		{"matchers", `http_requests_total{job="api",code=~"5.."}`, `http_requests_total{code=~"5..",job="api",project_id="p1"}`},
+		{"already scoped", `up{project_id="p1"}`, `up{project_id="p1"}`},
+		{"name matcher", `{__name__=~"http_.*"}`, `{__name__=~"http_.*",project_id="p1"}`},
+		{"range", `rate(http_requests_total[5m])`, `rate(http_requests_total{project_id="p1"}[5m])`},
+		{"subquery", `max_over_time(up[1h:5m])`, `max_over_time(up{project_id="p1"}[1h:5m])`},
+		{
+			"binary",
+			`sum by (job) (rate(errors_total[5m])) / on(job) sum by (job) (rate(requests_total[5m])) > 0.05`,
+			`sum by (job) (rate(errors_total{project_id="p1"}[5m])) / on (job) sum by (job) (rate(requests_total{project_id="p1"}[5m])) > 0.05`,
+		},
+		{"offset", `up offset 1h`, `up{project_id="p1"} offset 1h`},
+		{"no selector", `1 + 1`, `1 + 1`},
+		{"label_replace", `label_replace(up, "project_id", "p2", "", "")`, `label_replace(up{project_id="p1"}, "project_id", "p2", "", "")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Scope(tt.query, "p1")
+			if err != nil {
+				t.Fatalf("Scope(%q) error = %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("Scope(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		projectID string
+		want      error
+	}{
+		{"other project", `up{project_id="p2"}`, "p1", ErrUnscoped},
+		{"regex over projects", `up{project_id=~".+"}`, "p1", ErrUnscoped},
+		{"regex including the project", `up{project_id=~"p1|p2"}`, "p1", ErrUnscoped},
+		{"not equal", `up{project_id!="p1"}`, "p1", ErrUnscoped},
+		{"one selector of several", `up{project_id="p1"} + up{project_id="p2"}`, "p1", ErrUnscoped},
+		{"in a subquery", `max_over_time(up{project_id!=""}[1h:])`, "p1", ErrUnscoped},
+		{"no project", `up`, "", ErrUnscoped},
+		{"syntax error", `sum(rate(up[5m])`, "p1", ErrInvalidQuery},
+		{"empty", ``, "p1", ErrInvalidQuery},
+		{"unknown function", `nope(up)`, "p1", ErrInvalidQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Scope(tt.query, tt.projectID)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Scope(%q) = %q, %v, want %v", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"pulseguard/internal/models"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type AlertRepository struct {
@@ -14,25 +18,229 @@ func NewAlertRepository(db *sql.DB) *AlertRepository {
     return &AlertRepository{db: db}
 }
 
+const alertColumns = `
+    id, project_id, COALESCE(rule_id::text, ''), message, severity, status, fingerprint, source,
+    labels, value, COALESCE(started_at, created_at), resolved_at, COALESCE(resolved_by::text, ''),
+    acknowledged_at, COALESCE(acknowledged_by::text, ''), created_at
+`
+
+// openAlertSQL matches alerts that have not been resolved yet
+const openAlertSQL = `status IN ('firing', 'acknowledged')`
+
+// Create inserts a new alert and records that it fired
 func (r *AlertRepository) Create(ctx context.Context, alert *models.Alert) error {
+    labels, err := json.Marshal(alert.Labels)
+    if err != nil {
+        return err
+    }
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
     query := `
-        INSERT INTO alerts (id, project_id, message, severity, created_at)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO alerts (id, project_id, rule_id, message, severity, status, fingerprint, source, labels, value, started_at, resolved_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
     `
-    _, err := r.db.ExecContext(ctx, query,
-        alert.ID, alert.ProjectID, alert.Message, alert.Severity, alert.CreatedAt,
+    _, err = tx.ExecContext(ctx, query,
+        alert.ID, alert.ProjectID, toNullString(alert.RuleID), alert.Message, alert.Severity,
+        alert.Status, alert.Fingerprint, alert.Source, labels, alert.Value,
+        alert.StartedAt, alert.ResolvedAt, alert.CreatedAt,
     )
-    return err
+    if err != nil {
+        return err
+    }
+
+    if err := insertAlertEvent(ctx, tx, alert, models.AlertEventFired, "", "", alert.StartedAt); err != nil {
+        return err
+    }
+    return tx.Commit()
 }
 
 func (r *AlertRepository) ListByProject(ctx context.Context, projectID string) ([]*models.Alert, error) {
     query := `
-        SELECT id, project_id, message, severity, created_at
+        SELECT ` + alertColumns + `
         FROM alerts
         WHERE project_id = $1
         ORDER BY created_at DESC
     `
-    rows, err := r.db.QueryContext(ctx, query, projectID)
+    return r.list(ctx, query, projectID)
+}
+
+// GetByID returns one of the project's alerts
+func (r *AlertRepository) GetByID(ctx context.Context, projectID, id string) (*models.Alert, error) {
+    query := `
+        SELECT ` + alertColumns + `
+        FROM alerts
+        WHERE project_id = $1 AND id = $2
+    `
+    return scanAlert(r.db.QueryRowContext(ctx, query, projectID, id))
+}
+
+// ListFiringByRule returns the rule's alerts that have not resolved yet
+func (r *AlertRepository) ListFiringByRule(ctx context.Context, ruleID string) ([]*models.Alert, error) {
+    query := `
+        SELECT ` + alertColumns + `
+        FROM alerts
+        WHERE rule_id = $1 AND ` + openAlertSQL
+    return r.list(ctx, query, ruleID)
+}
+
+// UpsertFiring records an externally managed firing alert, or refreshes the
+// open alert with the same source and fingerprint. It reports whether the
+// alert is new; alert.ID and alert.StartedAt are set to the stored values.
+func (r *AlertRepository) UpsertFiring(ctx context.Context, alert *models.Alert) (bool, error) {
+    labels, err := json.Marshal(alert.Labels)
+    if err != nil {
+        return false, err
+    }
+
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return false, err
+    }
+    defer tx.Rollback()
+
+    query := `
+        INSERT INTO alerts (id, project_id, message, severity, status, fingerprint, source, labels, started_at, created_at)
+        VALUES ($1, $2, $3, $4, 'firing', $5, $6, $7, $8, $9)
+        ON CONFLICT (project_id, source, fingerprint) WHERE ` + openAlertSQL + ` AND rule_id IS NULL AND fingerprint <> ''
+        DO UPDATE SET message = EXCLUDED.message, severity = EXCLUDED.severity, labels = EXCLUDED.labels
+        RETURNING id, started_at, (xmax = 0)
+    `
+    var inserted bool
+    err = tx.QueryRowContext(ctx, query,
+        alert.ID, alert.ProjectID, alert.Message, alert.Severity, alert.Fingerprint, alert.Source,
+        labels, alert.StartedAt, alert.CreatedAt,
+    ).Scan(&alert.ID, &alert.StartedAt, &inserted)
+    if err != nil {
+        return false, err
+    }
+
+    if inserted {
+        if err := insertAlertEvent(ctx, tx, alert, models.AlertEventFired, "", "", alert.StartedAt); err != nil {
+            return false, err
+        }
+    }
+    return inserted, tx.Commit()
+}
+
+// ResolveByFingerprint resolves the open alert with the given source and
+// fingerprint, returning sql.ErrNoRows if none is open
+func (r *AlertRepository) ResolveByFingerprint(ctx context.Context, projectID, source, fingerprint string, resolvedAt time.Time) (*models.Alert, error) {
+    query := `
+        UPDATE alerts SET status = 'resolved', resolved_at = $4
+        WHERE project_id = $1 AND source = $2 AND fingerprint = $3 AND rule_id IS NULL AND ` + openAlertSQL + `
+        RETURNING ` + alertColumns
+    return r.transition(ctx, models.AlertEventResolved, "", "", resolvedAt, query, projectID, source, fingerprint, resolvedAt)
+}
+
+// Resolve marks an open alert as resolved by PulseGuard itself
+func (r *AlertRepository) Resolve(ctx context.Context, id string, resolvedAt time.Time) error {
+    query := `
+        UPDATE alerts SET status = 'resolved', resolved_at = $2
+        WHERE id = $1 AND ` + openAlertSQL + `
+        RETURNING ` + alertColumns
+    _, err := r.transition(ctx, models.AlertEventResolved, "", "", resolvedAt, query, id, resolvedAt)
+    if err == sql.ErrNoRows {
+        return nil
+    }
+    return err
+}
+
+// Acknowledge marks a firing alert as acknowledged by the user, returning
+// sql.ErrNoRows if the project has no such firing alert
+func (r *AlertRepository) Acknowledge(ctx context.Context, projectID, id, userID, note string, at time.Time) (*models.Alert, error) {
+    query := `
+        UPDATE alerts SET status = 'acknowledged', acknowledged_at = $3, acknowledged_by = $4
+        WHERE project_id = $1 AND id = $2 AND status = 'firing'
+        RETURNING ` + alertColumns
+    return r.transition(ctx, models.AlertEventAcknowledged, userID, note, at, query, projectID, id, at, userID)
+}
+
+// ResolveBy marks an open alert as resolved by the user, returning
+// sql.ErrNoRows if the project has no such open alert
+func (r *AlertRepository) ResolveBy(ctx context.Context, projectID, id, userID, note string, at time.Time) (*models.Alert, error) {
+    query := `
+        UPDATE alerts SET status = 'resolved', resolved_at = $3, resolved_by = $4
+        WHERE project_id = $1 AND id = $2 AND ` + openAlertSQL + `
+        RETURNING ` + alertColumns
+    return r.transition(ctx, models.AlertEventResolved, userID, note, at, query, projectID, id, at, userID)
+}
+
+// Reopen puts a resolved alert back to firing, returning sql.ErrNoRows if
+// the project has no such resolved alert
+func (r *AlertRepository) Reopen(ctx context.Context, projectID, id, userID, note string, at time.Time) (*models.Alert, error) {
+    query := `
+        UPDATE alerts SET status = 'firing', resolved_at = NULL, resolved_by = NULL,
+            acknowledged_at = NULL, acknowledged_by = NULL
+        WHERE project_id = $1 AND id = $2 AND status = 'resolved'
+        RETURNING ` + alertColumns
+    return r.transition(ctx, models.AlertEventReopened, userID, note, at, query, projectID, id)
+}
+
+// ListEvents returns the history of one of the project's alerts, oldest first
+func (r *AlertRepository) ListEvents(ctx context.Context, projectID, alertID string) ([]*models.AlertEvent, error) {
+    query := `
+        SELECT id, alert_id, project_id, type, COALESCE(actor_id::text, ''), COALESCE(note, ''), created_at
+        FROM alert_events
+        WHERE project_id = $1 AND alert_id = $2
+        ORDER BY created_at, id
+    `
+    rows, err := r.db.QueryContext(ctx, query, projectID, alertID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    events := []*models.AlertEvent{}
+    for rows.Next() {
+        var e models.AlertEvent
+        if err := rows.Scan(&e.ID, &e.AlertID, &e.ProjectID, &e.Type, &e.ActorID, &e.Note, &e.CreatedAt); err != nil {
+            return nil, err
+        }
+        events = append(events, &e)
+    }
+    return events, rows.Err()
+}
+
+// UpdateValue records the latest observed value of a firing alert
+func (r *AlertRepository) UpdateValue(ctx context.Context, id string, value *float64) error {
+    _, err := r.db.ExecContext(ctx, `UPDATE alerts SET value = $2 WHERE id = $1`, id, value)
+    return err
+}
+
+// transition runs a status update returning alertColumns and records the
+// matching event in the same transaction
+func (r *AlertRepository) transition(ctx context.Context, eventType, actorID, note string, at time.Time, query string, args ...any) (*models.Alert, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        return nil, err
+    }
+    defer tx.Rollback()
+
+    alert, err := scanAlert(tx.QueryRowContext(ctx, query, args...))
+    if err != nil {
+        return nil, err
+    }
+    if err := insertAlertEvent(ctx, tx, alert, eventType, actorID, note, at); err != nil {
+        return nil, err
+    }
+    return alert, tx.Commit()
+}
+
+func insertAlertEvent(ctx context.Context, tx *sql.Tx, alert *models.Alert, eventType, actorID, note string, at time.Time) error {
+    _, err := tx.ExecContext(ctx, `
+        INSERT INTO alert_events (id, alert_id, project_id, type, actor_id, note, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+    `, uuid.NewString(), alert.ID, alert.ProjectID, eventType, toNullString(actorID), toNullString(note), at)
+    return err
+}
+
+func (r *AlertRepository) list(ctx context.Context, query string, args ...any) ([]*models.Alert, error) {
+    rows, err := r.db.QueryContext(ctx, query, args...)
     if err != nil {
         return nil, err
     }
@@ -40,11 +248,40 @@ func (r *AlertRepository) ListByProject(ctx context.Context, projectID string) (
 
     var alerts []*models.Alert
     for rows.Next() {
-        var a models.Alert
-        if err := rows.Scan(&a.ID, &a.ProjectID, &a.Message, &a.Severity, &a.CreatedAt); err != nil {
+        a, err := scanAlert(rows)
+        if err != nil {
             return nil, err
         }
-        alerts = append(alerts, &a)
+        alerts = append(alerts, a)
     }
-    return alerts, nil
-}
\ No newline at end of file
+    return alerts, rows.Err()
+}
+
+func scanAlert(row rowScanner) (*models.Alert, error) {
+    var a models.Alert
+    var labels []byte
+    var value sql.NullFloat64
+    var resolvedAt, acknowledgedAt sql.NullTime
+    err := row.Scan(
+        &a.ID, &a.ProjectID, &a.RuleID, &a.Message, &a.Severity, &a.Status, &a.Fingerprint, &a.Source,
+        &labels, &value, &a.StartedAt, &resolvedAt, &a.ResolvedBy, &acknowledgedAt, &a.AcknowledgedBy, &a.CreatedAt,
+    )
+    if err != nil {
+        return nil, err
+    }
+    if len(labels) > 0 {
+        if err := json.Unmarshal(labels, &a.Labels); err != nil {
+            return nil, err
+        }
+    }
+    if value.Valid {
+        a.Value = &value.Float64
+    }
+    if resolvedAt.Valid {
+        a.ResolvedAt = &resolvedAt.Time
+    }
+    if acknowledgedAt.Valid {
+        a.AcknowledgedAt = &acknowledgedAt.Time
+    }
+    return &a, nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+type AlertRuleRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRuleRepository(db *sql.DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+const alertRuleColumns = `
+	id, project_id, name, condition_type, threshold, window_seconds, COALESCE(query, ''),
+	COALESCE(environment, ''), severity, enabled, COALESCE(created_by::text, ''),
+	created_at, updated_at, last_evaluated_at, COALESCE(last_error, '')
+`
+
+// Create inserts a new alert rule.
+func (repo *AlertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO alert_rules (id, project_id, name, condition_type, threshold, window_seconds, query,
+			environment, severity, enabled, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`,
+		rule.ID,
+		rule.ProjectID,
+		rule.Name,
+		rule.ConditionType,
+		rule.Threshold,
+		rule.WindowSeconds,
+		toNullString(rule.Query),
+		toNullString(rule.Environment),
+		rule.Severity,
+		rule.Enabled,
+		toNullString(rule.CreatedBy),
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+	return err
+}
+
+// ListByProject returns the project's alert rules, newest first.
+func (repo *AlertRuleRepository) ListByProject(ctx context.Context, projectID string) ([]*models.AlertRule, error) {
+	return repo.list(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE project_id = $1 ORDER BY created_at DESC`, projectID)
+}
+
+// GetByID returns a rule of the project.
+func (repo *AlertRuleRepository) GetByID(ctx context.Context, projectID, id string) (*models.AlertRule, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE project_id = $1 AND id = $2`, projectID, id)
+	return scanAlertRule(row)
+}
+
+// Update saves the editable fields of a rule.
+func (repo *AlertRuleRepository) Update(ctx context.Context, rule *models.AlertRule) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE alert_rules
+		SET name = $3, condition_type = $4, threshold = $5, window_seconds = $6, query = $7,
+			environment = $8, severity = $9, enabled = $10, updated_at = $11
+		WHERE project_id = $1 AND id = $2
+	`,
+		rule.ProjectID,
+		rule.ID,
+		rule.Name,
+		rule.ConditionType,
+		rule.Threshold,
+		rule.WindowSeconds,
+		toNullString(rule.Query),
+		toNullString(rule.Environment),
+		rule.Severity,
+		rule.Enabled,
+		rule.UpdatedAt,
+	)
+	return requireAffected(res, err)
+}
+
+// Delete removes a rule. Its past alerts are kept.
+func (repo *AlertRuleRepository) Delete(ctx context.Context, projectID, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE project_id = $1 AND id = $2`, projectID, id)
+	return requireAffected(res, err)
+}
+
+// ClaimDue marks every enabled rule not evaluated within the last interval as
+// evaluated now and returns them. Claiming in one statement keeps several API
+// replicas from evaluating the same rule twice.
+func (repo *AlertRuleRepository) ClaimDue(ctx context.Context, interval time.Duration) ([]*models.AlertRule, error) {
+	return repo.list(ctx, `
+		UPDATE alert_rules SET last_evaluated_at = NOW()
+		WHERE enabled AND (last_evaluated_at IS NULL OR last_evaluated_at <= NOW() - $1 * INTERVAL '1 millisecond')
+		RETURNING `+alertRuleColumns, interval.Milliseconds())
+}
+
+// SetLastError records the outcome of the rule's latest evaluation.
+func (repo *AlertRuleRepository) SetLastError(ctx context.Context, id, lastError string) error {
+	_, err := repo.db.ExecContext(ctx, `UPDATE alert_rules SET last_error = $2 WHERE id = $1`, id, toNullString(lastError))
+	return err
+}
+
+func (repo *AlertRuleRepository) list(ctx context.Context, query string, args ...any) ([]*models.AlertRule, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*models.AlertRule{}
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func scanAlertRule(row rowScanner) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	var lastEvaluatedAt sql.NullTime
+	err := row.Scan(
+		&rule.ID,
+		&rule.ProjectID,
+		&rule.Name,
+		&rule.ConditionType,
+		&rule.Threshold,
+		&rule.WindowSeconds,
+		&rule.Query,
+		&rule.Environment,
+		&rule.Severity,
+		&rule.Enabled,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+		&lastEvaluatedAt,
+		&rule.LastError,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastEvaluatedAt.Valid {
+		rule.LastEvaluatedAt = &lastEvaluatedAt.Time
+	}
+	return &rule, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pulseguard/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type ArtifactRepository struct {
+	db *sql.DB
+}
+
+func NewArtifactRepository(db *sql.DB) *ArtifactRepository {
+	return &ArtifactRepository{db: db}
+}
+
+const artifactColumns = `
+	id, project_id, release, name, storage_key, size, checksum, COALESCE(uploaded_by::text, ''), created_at
+`
+
+// Upsert stores an artifact, replacing the one with the same release and
+// name. It returns the storage key of the replaced artifact, if any, so its
+// content can be removed.
+func (repo *ArtifactRepository) Upsert(ctx context.Context, a *models.ReleaseArtifact) (string, error) {
+	var replacedKey sql.NullString
+	err := repo.db.QueryRowContext(ctx, `
+		WITH previous AS (
+			SELECT storage_key FROM release_artifacts WHERE project_id = $2 AND release = $3 AND name = $4
+		)
+		INSERT INTO release_artifacts (id, project_id, release, name, storage_key, size, checksum, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (project_id, release, name) DO UPDATE
+		SET storage_key = EXCLUDED.storage_key, size = EXCLUDED.size, checksum = EXCLUDED.checksum,
+			uploaded_by = EXCLUDED.uploaded_by, created_at = EXCLUDED.created_at
+		RETURNING id, (SELECT storage_key FROM previous)
+	`,
+		a.ID,
+		a.ProjectID,
+		a.Release,
+		a.Name,
+		a.StorageKey,
+		a.Size,
+		a.Checksum,
+		toNullString(a.UploadedBy),
+		a.CreatedAt,
+	).Scan(&a.ID, &replacedKey)
+	return replacedKey.String, err
+}
+
+// ListByRelease returns the artifacts of a release, by name.
+func (repo *ArtifactRepository) ListByRelease(ctx context.Context, projectID, release string) ([]*models.ReleaseArtifact, error) {
+	return repo.list(ctx, `SELECT `+artifactColumns+` FROM release_artifacts WHERE project_id = $1 AND release = $2 ORDER BY name`, projectID, release)
+}
+
+// FindByNames returns the release's artifacts with any of the given names.
+func (repo *ArtifactRepository) FindByNames(ctx context.Context, projectID, release string, names []string) ([]*models.ReleaseArtifact, error) {
+	return repo.list(ctx, `SELECT `+artifactColumns+` FROM release_artifacts WHERE project_id = $1 AND release = $2 AND name = ANY($3)`,
+		projectID, release, pq.StringArray(names))
+}
+
+// GetByID returns an artifact of the project.
+func (repo *ArtifactRepository) GetByID(ctx context.Context, projectID, id string) (*models.ReleaseArtifact, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT `+artifactColumns+` FROM release_artifacts WHERE project_id = $1 AND id = $2`, projectID, id)
+	return scanArtifact(row)
+}
+
+// Delete removes an artifact of the project.
+func (repo *ArtifactRepository) Delete(ctx context.Context, projectID, id string) error {
+	return requireAffected(repo.db.ExecContext(ctx, `DELETE FROM release_artifacts WHERE project_id = $1 AND id = $2`, projectID, id))
+}
+
+func (repo *ArtifactRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.ReleaseArtifact, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	artifacts := []*models.ReleaseArtifact{}
+	for rows.Next() {
+		a, err := scanArtifact(rows)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, rows.Err()
+}
+
+func scanArtifact(row rowScanner) (*models.ReleaseArtifact, error) {
+	var a models.ReleaseArtifact
+	err := row.Scan(&a.ID, &a.ProjectID, &a.Release, &a.Name, &a.StorageKey, &a.Size, &a.Checksum, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
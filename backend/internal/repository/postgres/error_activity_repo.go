@@ -0,0 +1,313 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"pulseguard/internal/models"
+)
+
+var (
+	// ErrInvalidAssignee is returned when assigning an issue to a user or team without access to the project
+	ErrInvalidAssignee = errors.New("assignee has no access to the project")
+	// ErrCommentParentNotFound is returned when replying to a comment that is not on the same error
+	ErrCommentParentNotFound = errors.New("parent comment not found")
+)
+
+// projectUsersSQL selects the ids of the users with access to project $1,
+// see effectiveRoleSQL
+const projectUsersSQL = `
+	SELECT p.owner_id FROM projects p WHERE p.id = $1
+	UNION
+	SELECT pm.user_id FROM project_members pm WHERE pm.project_id = $1
+	UNION
+	SELECT om.user_id FROM organization_members om JOIN projects p ON p.organization_id = om.organization_id WHERE p.id = $1
+	UNION
+	SELECT tm.user_id FROM project_teams pt JOIN team_members tm ON tm.team_id = pt.team_id WHERE pt.project_id = $1
+`
+
+// ProjectUsers returns the users with access to the project, who can be
+// mentioned in and assigned to its issues
+func (r *ErrorRepository) ProjectUsers(ctx context.Context, projectID string) ([]models.UserSummary, error) {
+	return r.listUsers(ctx, `
+        SELECT u.id, u.name, u.email FROM users u
+        WHERE u.id IN (`+projectUsersSQL+`)
+        ORDER BY u.name`, projectID)
+}
+
+// TeamUsers returns the members of a team
+func (r *ErrorRepository) TeamUsers(ctx context.Context, teamID string) ([]models.UserSummary, error) {
+	return r.listUsers(ctx, `
+        SELECT u.id, u.name, u.email FROM team_members tm
+        JOIN users u ON u.id = tm.user_id
+        WHERE tm.team_id = $1
+        ORDER BY u.name`, teamID)
+}
+
+func (r *ErrorRepository) listUsers(ctx context.Context, query string, args ...any) ([]models.UserSummary, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.UserSummary{}
+	for rows.Next() {
+		var u models.UserSummary
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// Assign assigns the error to a user or a team, or unassigns it when both are
+// empty, records the change in its activity log and reports whether the
+// assignee changed. The user needs access to the project and the team must
+// have been granted it, otherwise it fails with ErrInvalidAssignee. It
+// returns sql.ErrNoRows if the project has no such error.
+func (r *ErrorRepository) Assign(ctx context.Context, projectID, errorID, userID, teamID, actorID string) (*models.Assignee, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current sql.NullString
+	err = tx.QueryRowContext(ctx, `
+        SELECT COALESCE(assignee_user_id, assignee_team_id)::text FROM errors
+        WHERE id = $1 AND project_id = $2
+        FOR UPDATE`, errorID, projectID).Scan(&current)
+	if err != nil {
+		return nil, false, err
+	}
+
+	assignee, err := lookupAssignee(ctx, tx, projectID, userID, teamID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE errors SET assignee_user_id = $2, assignee_team_id = $3
+        WHERE id = $1`,
+		errorID, toNullString(userID), toNullString(teamID))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to assign error: %w", err)
+	}
+
+	changed := (assignee == nil && current.Valid) || (assignee != nil && assignee.ID != current.String)
+	switch {
+	case !changed:
+	case assignee != nil:
+		err = recordActivity(ctx, tx, projectID, errorID, models.ActivityAssigned, actorID, map[string]interface{}{
+			"assignee": assignee,
+		})
+	default:
+		err = recordActivity(ctx, tx, projectID, errorID, models.ActivityUnassigned, actorID, nil)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return assignee, changed, nil
+}
+
+// lookupAssignee returns the user or team to assign the project's issues to,
+// nil when both are empty. It fails with ErrInvalidAssignee if they have no
+// access to the project.
+func lookupAssignee(ctx context.Context, tx *sql.Tx, projectID, userID, teamID string) (*models.Assignee, error) {
+	var assignee *models.Assignee
+	var err error
+	switch {
+	case userID != "":
+		assignee = &models.Assignee{Type: "user", ID: userID}
+		err = tx.QueryRowContext(ctx, `
+            SELECT u.name, u.email FROM users u
+            WHERE u.id = $2 AND u.id IN (`+projectUsersSQL+`)`,
+			projectID, userID).Scan(&assignee.Name, &assignee.Email)
+	case teamID != "":
+		assignee = &models.Assignee{Type: "team", ID: teamID}
+		err = tx.QueryRowContext(ctx, `
+            SELECT t.name FROM project_teams pt
+            JOIN teams t ON t.id = pt.team_id
+            WHERE pt.project_id = $1 AND pt.team_id = $2`,
+			projectID, teamID).Scan(&assignee.Name)
+	}
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidAssignee
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up assignee: %w", err)
+	}
+	return assignee, nil
+}
+
+// CreateComment adds a comment to the error and records it in the activity
+// log. It returns sql.ErrNoRows if the project has no such error and
+// ErrCommentParentNotFound if the comment replies to one that is not on the
+// same error.
+func (r *ErrorRepository) CreateComment(ctx context.Context, comment *models.ErrorComment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT true FROM errors WHERE id = $1 AND project_id = $2`,
+		comment.ErrorID, comment.ProjectID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if comment.ParentID != "" {
+		err = tx.QueryRowContext(ctx, `
+            SELECT true FROM error_comments WHERE id = $1 AND error_id = $2`,
+			comment.ParentID, comment.ErrorID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return ErrCommentParentNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to look up parent comment: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO error_comments (id, project_id, error_id, parent_id, author_id, body, mentions, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		comment.ID, comment.ProjectID, comment.ErrorID, toNullString(comment.ParentID), toNullString(comment.AuthorID),
+		comment.Body, pq.StringArray(comment.Mentions), comment.CreatedAt, comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert comment: %w", err)
+	}
+
+	data := map[string]interface{}{"commentId": comment.ID}
+	if comment.ParentID != "" {
+		data["parentId"] = comment.ParentID
+	}
+	if len(comment.Mentions) > 0 {
+		data["mentions"] = comment.Mentions
+	}
+	if err := recordActivity(ctx, tx, comment.ProjectID, comment.ErrorID, models.ActivityCommented, comment.AuthorID, data); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Timeline returns the assignee, comments and activity log of the error,
+// oldest first. Comments are returned flat; replies point to their parent.
+// It returns sql.ErrNoRows if the project has no such error.
+func (r *ErrorRepository) Timeline(ctx context.Context, projectID, errorID string) (*models.ErrorTimeline, error) {
+	assignee, err := r.getAssignee(ctx, projectID, errorID)
+	if err != nil {
+		return nil, err
+	}
+	timeline := &models.ErrorTimeline{
+		Assignee: assignee,
+		Comments: []*models.ErrorComment{},
+		Activity: []*models.ErrorActivity{},
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT c.id, c.project_id, c.error_id, COALESCE(c.parent_id::text, ''), COALESCE(c.author_id::text, ''),
+            COALESCE(u.name, ''), c.body, c.mentions, c.created_at, c.updated_at
+        FROM error_comments c
+        LEFT JOIN users u ON u.id = c.author_id
+        WHERE c.project_id = $1 AND c.error_id = $2
+        ORDER BY c.created_at, c.id`, projectID, errorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c models.ErrorComment
+		var mentions pq.StringArray
+		if err := rows.Scan(&c.ID, &c.ProjectID, &c.ErrorID, &c.ParentID, &c.AuthorID, &c.AuthorName,
+			&c.Body, &mentions, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		c.Mentions = mentions
+		timeline.Comments = append(timeline.Comments, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+        SELECT a.id, a.project_id, a.error_id, a.type, COALESCE(a.actor_id::text, ''), COALESCE(u.name, ''), a.data, a.created_at
+        FROM error_activity a
+        LEFT JOIN users u ON u.id = a.actor_id
+        WHERE a.project_id = $1 AND a.error_id = $2
+        ORDER BY a.created_at, a.id`, projectID, errorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a models.ErrorActivity
+		if err := rows.Scan(&a.ID, &a.ProjectID, &a.ErrorID, &a.Type, &a.ActorID, &a.ActorName,
+			jsonColumn{&a.Data}, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan activity: %w", err)
+		}
+		timeline.Activity = append(timeline.Activity, &a)
+	}
+	return timeline, rows.Err()
+}
+
+// getAssignee returns who the error is assigned to, nil when nobody. It
+// returns sql.ErrNoRows if the project has no such error.
+func (r *ErrorRepository) getAssignee(ctx context.Context, projectID, errorID string) (*models.Assignee, error) {
+	var userID, userName, userEmail, teamID, teamName sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+        SELECT u.id::text, u.name, u.email, t.id::text, t.name
+        FROM errors e
+        LEFT JOIN users u ON u.id = e.assignee_user_id
+        LEFT JOIN teams t ON t.id = e.assignee_team_id
+        WHERE e.id = $1 AND e.project_id = $2`, errorID, projectID).
+		Scan(&userID, &userName, &userEmail, &teamID, &teamName)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case userID.Valid:
+		return &models.Assignee{Type: "user", ID: userID.String, Name: userName.String, Email: userEmail.String}, nil
+	case teamID.Valid:
+		return &models.Assignee{Type: "team", ID: teamID.String, Name: teamName.String}, nil
+	}
+	return nil, nil
+}
+
+// recordActivity appends an entry to the error's activity log. actorID is
+// empty for changes made while tracking events.
+func recordActivity(ctx context.Context, tx *sql.Tx, projectID, errorID, activityType, actorID string, data map[string]interface{}) error {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO error_activity (id, project_id, error_id, type, actor_id, data, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		uuid.NewString(), projectID, errorID, activityType, toNullString(actorID), b, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"pulseguard/internal/models"
+)
+
+// BulkUpdate applies an action to the project's errors with the given ids,
+// or when ids is nil to those matching the filters, in one transaction. It
+// returns how many errors the action changed: those whose status was set or
+// assignee changed, that got a new tag, or that were deleted. Resolving in
+// the next release fails with ErrNoRelease if the project has no release,
+// and assigning with ErrInvalidAssignee if the assignee has no access.
+func (r *ErrorRepository) BulkUpdate(ctx context.Context, filters ErrorFilters, ids []string, action *models.BulkAction, actorID string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	where, args := errorFiltersSQL(filters)
+	if ids != nil {
+		where, args = "project_id = $1 AND id = ANY($2::uuid[])", []interface{}{filters.ProjectID, pq.StringArray(ids)}
+	}
+
+	// Lock the selection so concurrent changes apply before or after the whole of it
+	rows, err := tx.QueryContext(ctx, `SELECT id, status FROM errors WHERE `+where+` ORDER BY id FOR UPDATE`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to select errors: %w", err)
+	}
+	var selected []string
+	previous := map[string]string{}
+	for rows.Next() {
+		var id string
+		var status sql.NullString
+		if err := rows.Scan(&id, &status); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan error: %w", err)
+		}
+		selected = append(selected, id)
+		previous[id] = status.String
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(selected) == 0 {
+		return 0, nil
+	}
+
+	var affected int
+	switch action.Type {
+	case models.BulkResolve, models.BulkIgnore:
+		affected, err = bulkSetStatus(ctx, tx, filters.ProjectID, selected, previous, action, actorID)
+	case models.BulkAssign:
+		affected, err = bulkAssign(ctx, tx, filters.ProjectID, selected, action, actorID)
+	case models.BulkTag:
+		affected, err = bulkTag(ctx, tx, selected, action.Tags)
+	case models.BulkDelete:
+		affected, err = bulkDelete(ctx, tx, filters.ProjectID, selected)
+	default:
+		err = fmt.Errorf("unknown bulk action %q", action.Type)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return affected, nil
+}
+
+func bulkSetStatus(ctx context.Context, tx *sql.Tx, projectID string, ids []string, previous map[string]string, action *models.BulkAction, actorID string) (int, error) {
+	details := action.StatusDetails
+	if err := stampStatusDetails(ctx, tx, projectID, details); err != nil {
+		return 0, err
+	}
+	status := action.Status()
+
+	rows, err := tx.QueryContext(ctx, `
+        UPDATE errors
+        SET status = $1,
+            status_details = CASE WHEN $2::jsonb IS NULL THEN NULL
+                ELSE $2::jsonb || jsonb_build_object('countAtChange', count) END
+        WHERE id = ANY($3::uuid[])
+        RETURNING id, status_details`,
+		status, statusDetailsJSON(details), pq.StringArray(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to update error status: %w", err)
+	}
+	type change struct {
+		id      string
+		details *models.StatusDetails
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.id, jsonColumn{&c.details}); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan error: %w", err)
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, c := range changes {
+		data := map[string]interface{}{"from": previous[c.id], "to": status, "bulk": true}
+		if c.details != nil {
+			data["details"] = c.details
+		}
+		if err := recordActivity(ctx, tx, projectID, c.id, models.ActivityStatusChanged, actorID, data); err != nil {
+			return 0, err
+		}
+	}
+	return len(changes), nil
+}
+
+func bulkAssign(ctx context.Context, tx *sql.Tx, projectID string, ids []string, action *models.BulkAction, actorID string) (int, error) {
+	assignee, err := lookupAssignee(ctx, tx, projectID, action.AssigneeUserID, action.AssigneeTeamID)
+	if err != nil {
+		return 0, err
+	}
+	userID, teamID := toNullString(action.AssigneeUserID), toNullString(action.AssigneeTeamID)
+
+	rows, err := tx.QueryContext(ctx, `
+        UPDATE errors SET assignee_user_id = $1, assignee_team_id = $2
+        WHERE id = ANY($3::uuid[])
+            AND (assignee_user_id IS DISTINCT FROM $1::uuid OR assignee_team_id IS DISTINCT FROM $2::uuid)
+        RETURNING id`,
+		userID, teamID, pq.StringArray(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign errors: %w", err)
+	}
+	changed, err := scanIDs(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range changed {
+		if assignee != nil {
+			err = recordActivity(ctx, tx, projectID, id, models.ActivityAssigned, actorID, map[string]interface{}{
+				"assignee": assignee,
+				"bulk":     true,
+			})
+		} else {
+			err = recordActivity(ctx, tx, projectID, id, models.ActivityUnassigned, actorID, map[string]interface{}{
+				"bulk": true,
+			})
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(changed), nil
+}
+
+func bulkTag(ctx context.Context, tx *sql.Tx, ids []string, tags []models.ErrorTag) (int, error) {
+	tagged := map[string]bool{}
+	for _, tag := range tags {
+		rows, err := tx.QueryContext(ctx, `
+            INSERT INTO error_tags (id, error_id, key, value)
+            SELECT gen_random_uuid(), e.id, $2::text, $3::text FROM unnest($1::uuid[]) AS e(id)
+            ON CONFLICT (error_id, key, value) DO NOTHING
+            RETURNING error_id`,
+			pq.StringArray(ids), tag.Key, tag.Value)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert tag: %w", err)
+		}
+		added, err := scanIDs(rows)
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range added {
+			tagged[id] = true
+		}
+	}
+	return len(tagged), nil
+}
+
+// bulkDelete deletes the errors with everything recorded about them. Their
+// occurrences, tags and rollups go with them; comments, activity and merges
+// have no foreign key, so that they survive merging, and are removed here.
+func bulkDelete(ctx context.Context, tx *sql.Tx, projectID string, ids []string) (int, error) {
+	for _, st := range []struct {
+		query string
+		what  string
+	}{
+		{`DELETE FROM error_comments WHERE project_id = $1 AND error_id = ANY($2::uuid[])`, "comments"},
+		{`DELETE FROM error_activity WHERE project_id = $1 AND error_id = ANY($2::uuid[])`, "activity"},
+		{`DELETE FROM error_merges WHERE project_id = $1 AND target_error_id = ANY($2::uuid[])`, "merges"},
+	} {
+		if _, err := tx.ExecContext(ctx, st.query, projectID, pq.StringArray(ids)); err != nil {
+			return 0, fmt.Errorf("failed to delete %s: %w", st.what, err)
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM errors WHERE project_id = $1 AND id = ANY($2::uuid[])`, projectID, pq.StringArray(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete errors: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func scanIDs(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
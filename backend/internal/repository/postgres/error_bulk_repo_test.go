@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pulseguard/internal/models"
+)
+
+func TestBulkSetStatus(t *testing.T) {
+	db := openTestDB(t)
+	seedUsers(t, db)
+	seedProject(t, db, shopID, aliceID, "shop", "")
+	seedProject(t, db, blogID, aliceID, "blog", "")
+	seedReleases(t, db)
+	repo := NewErrorRepository(db)
+	ctx := context.Background()
+
+	active := seedGroup(t, db, "active", "ACTIVE", nil, 4, "1.9")
+	resolved := seedGroup(t, db, "resolved", "RESOLVED", nil, 7, "1.9")
+	ignored := seedGroup(t, db, "ignored", "IGNORED", nil, 9, "1.9")
+	ids := []string{active, resolved, ignored}
+
+	statusOf := func(id string) (string, *models.StatusDetails) {
+		t.Helper()
+		var status string
+		var details *models.StatusDetails
+		if err := db.QueryRow(`SELECT status, status_details FROM errors WHERE id = $1`, id).Scan(&status, jsonColumn{&details}); err != nil {
+			t.Fatalf("read group: %v", err)
+		}
+		return status, details
+	}
+
+	t.Run("resolve in the next release", func(t *testing.T) {
+		action := &models.BulkAction{Type: models.BulkResolve, StatusDetails: &models.StatusDetails{InNextRelease: true}}
+		n, err := repo.BulkUpdate(ctx, ErrorFilters{ProjectID: shopID}, ids, action, aliceID)
+		if err != nil || n != 3 {
+			t.Fatalf("BulkUpdate() = %d, %v, want 3", n, err)
+		}
+		for id, count := range map[string]int{active: 4, resolved: 7, ignored: 9} {
+			status, details := statusOf(id)
+			// The latest release is the last created, by id among releases
+			// created at the same time
+			if status != "RESOLVED" || details == nil || details.AfterRelease != "2.0-hotfix" || details.CountAtChange != count {
+				t.Errorf("group %s is %s with %+v, want RESOLVED after 2.0-hotfix at count %d", id, status, details, count)
+			}
+		}
+
+		var activity int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM error_activity WHERE type = $1 AND data->>'to' = 'RESOLVED'`, models.ActivityStatusChanged).Scan(&activity); err != nil || activity != 3 {
+			t.Errorf("recorded %d status changes, %v, want 3", activity, err)
+		}
+
+		// The group stays resolved until a newer release than 2.0-hotfix
+		e := groupEvent("active", "")
+		e.Release = "2.0"
+		if got, err := repo.Track(ctx, e, nil); err != nil || got.Status != "RESOLVED" {
+			t.Errorf("Track() in an older release = %v, %v, want RESOLVED", got, err)
+		}
+		e = groupEvent("active", "")
+		e.Release = "2.1"
+		if got, err := repo.Track(ctx, e, nil); err != nil || got.Status != "REGRESSED" {
+			t.Errorf("Track() in a new release = %v, %v, want REGRESSED", got, err)
+		}
+	})
+
+	t.Run("resolve in the next release without releases", func(t *testing.T) {
+		blogGroup := "60000000-0000-0000-0000-000000000001"
+		mustExec(t, db, `
+			INSERT INTO errors (id, project_id, message, fingerprint, occurred_at, last_seen, environment, count, status)
+			VALUES ($1, $2, 'failed', 'blog', now(), now(), 'production', 1, 'ACTIVE')
+		`, blogGroup, blogID)
+		action := &models.BulkAction{Type: models.BulkResolve, StatusDetails: &models.StatusDetails{InNextRelease: true}}
+		if _, err := repo.BulkUpdate(ctx, ErrorFilters{ProjectID: blogID}, []string{blogGroup}, action, aliceID); !errors.Is(err, ErrNoRelease) {
+			t.Errorf("BulkUpdate() error = %v, want %v", err, ErrNoRelease)
+		}
+	})
+
+	t.Run("ignore until a count", func(t *testing.T) {
+		action := &models.BulkAction{Type: models.BulkIgnore, StatusDetails: &models.StatusDetails{IgnoreCount: 2}}
+		n, err := repo.BulkUpdate(ctx, ErrorFilters{ProjectID: shopID}, []string{resolved}, action, aliceID)
+		if err != nil || n != 1 {
+			t.Fatalf("BulkUpdate() = %d, %v, want 1", n, err)
+		}
+		if status, details := statusOf(resolved); status != "IGNORED" || details == nil || details.CountAtChange != 7 {
+			t.Fatalf("group is %s with %+v, want IGNORED at count 7", status, details)
+		}
+		for i, want := range []string{"IGNORED", "ACTIVE"} {
+			got, err := repo.Track(ctx, groupEvent("resolved", ""), nil)
+			if err != nil || got.Status != want {
+				t.Errorf("Track() %d after ignoring = %v, %v, want %s", i+1, got, err, want)
+			}
+		}
+	})
+
+	t.Run("resolve without conditions", func(t *testing.T) {
+		action := &models.BulkAction{Type: models.BulkResolve}
+		n, err := repo.BulkUpdate(ctx, ErrorFilters{ProjectID: shopID}, []string{ignored}, action, aliceID)
+		if err != nil || n != 1 {
+			t.Fatalf("BulkUpdate() = %d, %v, want 1", n, err)
+		}
+		if status, details := statusOf(ignored); status != "RESOLVED" || details != nil {
+			t.Errorf("group is %s with %+v, want RESOLVED without details", status, details)
+		}
+	})
+
+	t.Run("groups of another project", func(t *testing.T) {
+		action := &models.BulkAction{Type: models.BulkIgnore}
+		n, err := repo.BulkUpdate(ctx, ErrorFilters{ProjectID: blogID}, ids, action, aliceID)
+		if err != nil || n != 0 {
+			t.Errorf("BulkUpdate() = %d, %v, want 0", n, err)
+		}
+		if status, _ := statusOf(ignored); status != "RESOLVED" {
+			t.Errorf("group of another project is %s, want RESOLVED", status)
+		}
+	})
+}
@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"pulseguard/internal/models"
+)
+
+var (
+	// ErrMergeEnvironmentMismatch is returned when merging groups of different environments
+	ErrMergeEnvironmentMismatch = errors.New("error groups belong to different environments")
+	// ErrMergeTargetGone is returned when unmerging from a group that has itself been merged away
+	ErrMergeTargetGone = errors.New("merge target no longer exists")
+)
+
+const errorColumns = `id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status, status_details, frames, first_release, last_release`
+
+const errorMergeColumns = `id, project_id, target_error_id, source_error_id, source, fingerprints,
+	COALESCE(merged_by::text, ''), merged_at, COALESCE(unmerged_by::text, ''), unmerged_at`
+
+// Merge folds the source groups into the target group. Their fingerprints,
+// occurrences, rollups and tags move to the target and the source rows are
+// removed; a merge record per source keeps what is needed to unmerge it. It
+// returns sql.ErrNoRows if any of the groups is not in the project.
+func (r *ErrorRepository) Merge(ctx context.Context, projectID, targetID string, sourceIDs []string, userID string) (*models.Error, []*models.ErrorMerge, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	target, err := lockError(ctx, tx, projectID, targetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	merges := make([]*models.ErrorMerge, 0, len(sourceIDs))
+	for _, sourceID := range sourceIDs {
+		source, err := lockError(ctx, tx, projectID, sourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if source.Environment != target.Environment {
+			return nil, nil, ErrMergeEnvironmentMismatch
+		}
+
+		merge, err := mergeInto(ctx, tx, target, source, userID, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		merges = append(merges, merge)
+
+		target.Count += source.Count
+		if source.OccurredAt.Before(target.OccurredAt) {
+			target.OccurredAt = source.OccurredAt
+		}
+		if source.LastSeen.After(target.LastSeen) {
+			target.LastSeen = source.LastSeen
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE errors SET count = $2, occurred_at = $3, last_seen = $4
+        WHERE id = $1`,
+		target.ID, target.Count, target.OccurredAt, target.LastSeen)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update merged error: %w", err)
+	}
+	if err := refreshAffectedCounts(ctx, tx, target); err != nil {
+		return nil, nil, err
+	}
+
+	merged := make([]map[string]interface{}, 0, len(merges))
+	for _, merge := range merges {
+		merged = append(merged, map[string]interface{}{
+			"mergeId": merge.ID,
+			"errorId": merge.SourceErrorID,
+			"message": merge.Source.Message,
+		})
+	}
+	err = recordActivity(ctx, tx, projectID, target.ID, models.ActivityMerged, userID, map[string]interface{}{
+		"sources": merged,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return target, merges, nil
+}
+
+func mergeInto(ctx context.Context, tx *sql.Tx, target, source *models.Error, userID string, now time.Time) (*models.ErrorMerge, error) {
+	var fingerprints pq.StringArray
+	err := tx.QueryRowContext(ctx, `
+        SELECT COALESCE(array_agg(fingerprint ORDER BY created_at), '{}') FROM error_fingerprints WHERE error_id = $1`,
+		source.ID).Scan(&fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fingerprints: %w", err)
+	}
+
+	var tags []byte
+	err = tx.QueryRowContext(ctx, `
+        SELECT COALESCE(json_agg(json_build_object('key', key, 'value', value)), '[]') FROM error_tags WHERE error_id = $1`,
+		source.ID).Scan(&tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	snapshot, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rollups move with the fingerprints; what they counted so far is
+	// kept for unmerging to tell what they counted since
+	rollups, err := moveRollups(ctx, tx, source.ID, target.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := &models.ErrorMerge{
+		ID:            uuid.NewString(),
+		ProjectID:     target.ProjectID,
+		TargetErrorID: target.ID,
+		SourceErrorID: source.ID,
+		Source:        source,
+		Fingerprints:  fingerprints,
+		MergedBy:      userID,
+		MergedAt:      now,
+	}
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO error_merges (id, project_id, target_error_id, source_error_id, source, fingerprints, tags, rollup_count, merged_by, merged_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		merge.ID, merge.ProjectID, merge.TargetErrorID, merge.SourceErrorID, snapshot, fingerprints, tags,
+		rollups, toNullString(userID), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record merge: %w", err)
+	}
+
+	statements := []struct {
+		query string
+		what  string
+	}{
+		{`UPDATE error_fingerprints SET error_id = $1 WHERE error_id = $2`, "fingerprints"},
+		{`UPDATE error_occurrences SET error_id = $1 WHERE error_id = $2`, "occurrences"},
+		{`INSERT INTO error_tags (id, error_id, key, value)
+          SELECT gen_random_uuid(), $1, key, value FROM error_tags WHERE error_id = $2
+          ON CONFLICT (error_id, key, value) DO NOTHING`, "tags"},
+	}
+	for _, st := range statements {
+		if _, err := tx.ExecContext(ctx, st.query, target.ID, source.ID); err != nil {
+			return nil, fmt.Errorf("failed to move %s: %w", st.what, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM errors WHERE id = $1`, source.ID); err != nil {
+		return nil, fmt.Errorf("failed to remove merged error: %w", err)
+	}
+	return merge, nil
+}
+
+// Unmerge splits a merged group out of its target again. The group is
+// restored under its original id and takes back its fingerprints, tags and
+// every occurrence and rollup of one of its fingerprints, including those
+// tracked since the merge. It returns sql.ErrNoRows if the project has no
+// such active merge.
+func (r *ErrorRepository) Unmerge(ctx context.Context, projectID, mergeID, userID string) (*models.Error, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	merge, err := scanErrorMerge(tx.QueryRowContext(ctx, `
+        SELECT `+errorMergeColumns+` FROM error_merges
+        WHERE project_id = $1 AND id = $2 AND unmerged_at IS NULL
+        FOR UPDATE`, projectID, mergeID))
+	if err != nil {
+		return nil, err
+	}
+	var tags []byte
+	var mergedRollups int64
+	err = tx.QueryRowContext(ctx, `SELECT tags, rollup_count FROM error_merges WHERE id = $1`, merge.ID).
+		Scan(&tags, &mergedRollups)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := lockError(ctx, tx, projectID, merge.TargetErrorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMergeTargetGone
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	source := merge.Source
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO errors (`+errorColumns+`)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`,
+		source.ID, source.ProjectID, source.Message, source.StackTrace, source.Fingerprint,
+		source.OccurredAt, source.LastSeen, source.Environment, source.Count, source.Source,
+		source.Type, source.URL, source.ComponentStack, source.BrowserInfo, source.UserID,
+		source.SessionID, source.Status, statusDetailsJSON(source.StatusDetails), framesJSON(source.Frames), source.FirstRelease, source.LastRelease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore error: %w", err)
+	}
+
+	fingerprints := pq.StringArray(merge.Fingerprints)
+	_, err = tx.ExecContext(ctx, `
+        UPDATE error_fingerprints SET error_id = $1
+        WHERE error_id = $2 AND fingerprint = ANY($3)`,
+		source.ID, target.ID, fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move fingerprints: %w", err)
+	}
+
+	var lastSeen sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+        WITH moved AS (
+            UPDATE error_occurrences SET error_id = $1
+            WHERE error_id = $2 AND fingerprint = ANY($3)
+            RETURNING timestamp
+        )
+        SELECT MAX(timestamp) FROM moved`,
+		source.ID, target.ID, fingerprints).Scan(&lastSeen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move occurrences: %w", err)
+	}
+
+	// The events the fingerprints counted since the merge, sampled or not,
+	// count towards the restored group, and the target gives back all of them
+	rollups, err := moveRollups(ctx, tx, target.ID, source.ID, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	source.Count += int(rollups - mergedRollups)
+	if lastSeen.Valid && lastSeen.Time.After(source.LastSeen) {
+		source.LastSeen = lastSeen.Time
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE errors SET count = $2, last_seen = $3 WHERE id = $1`,
+		source.ID, source.Count, source.LastSeen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update restored error: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        UPDATE errors SET count = count - $2,
+            last_seen = COALESCE((SELECT MAX(timestamp) FROM error_occurrences WHERE error_id = $1), last_seen)
+        WHERE id = $1`,
+		target.ID, source.Count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update merge target: %w", err)
+	}
+	for _, e := range []*models.Error{source, target} {
+		if err := refreshAffectedCounts(ctx, tx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO error_tags (id, error_id, key, value)
+        SELECT gen_random_uuid(), $1, t.key, t.value
+        FROM json_to_recordset($2::json) AS t(key TEXT, value TEXT)
+        ON CONFLICT (error_id, key, value) DO NOTHING`,
+		source.ID, tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE error_merges SET unmerged_at = $2, unmerged_by = $3 WHERE id = $1`,
+		merge.ID, time.Now(), toNullString(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record unmerge: %w", err)
+	}
+
+	// Both groups get an entry: the restored group gets back its own log
+	for _, entry := range []struct{ errorID, key, otherID string }{
+		{target.ID, "errorId", source.ID},
+		{source.ID, "targetId", target.ID},
+	} {
+		err = recordActivity(ctx, tx, projectID, entry.errorID, models.ActivityUnmerged, userID, map[string]interface{}{
+			"mergeId": merge.ID,
+			entry.key: entry.otherID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return source, nil
+}
+
+// ListMerges returns the groups merged into the error, newest first,
+// including those unmerged again
+func (r *ErrorRepository) ListMerges(ctx context.Context, projectID, errorID string) ([]*models.ErrorMerge, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT `+errorMergeColumns+` FROM error_merges
+        WHERE project_id = $1 AND target_error_id = $2
+        ORDER BY merged_at DESC`, projectID, errorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query merges: %w", err)
+	}
+	defer rows.Close()
+
+	merges := []*models.ErrorMerge{}
+	for rows.Next() {
+		merge, err := scanErrorMerge(rows)
+		if err != nil {
+			return nil, err
+		}
+		merges = append(merges, merge)
+	}
+	return merges, rows.Err()
+}
+
+func lockError(ctx context.Context, tx *sql.Tx, projectID, id string) (*models.Error, error) {
+	var e models.Error
+	err := tx.QueryRowContext(ctx, `
+        SELECT `+errorColumns+`
+        FROM errors WHERE id = $1 AND project_id = $2
+        FOR UPDATE`, id, projectID).
+		Scan(&e.ID, &e.ProjectID, &e.Message, &e.StackTrace, &e.Fingerprint, &e.OccurredAt,
+			&e.LastSeen, &e.Environment, &e.Count, &e.Source, &e.Type, &e.URL, &e.ComponentStack,
+			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status, jsonColumn{&e.StatusDetails}, jsonColumn{&e.Frames}, &e.FirstRelease, &e.LastRelease)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func scanErrorMerge(row rowScanner) (*models.ErrorMerge, error) {
+	var m models.ErrorMerge
+	var source []byte
+	var fingerprints pq.StringArray
+	var unmergedAt sql.NullTime
+	err := row.Scan(&m.ID, &m.ProjectID, &m.TargetErrorID, &m.SourceErrorID, &source, &fingerprints,
+		&m.MergedBy, &m.MergedAt, &m.UnmergedBy, &unmergedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(source, &m.Source); err != nil {
+		return nil, fmt.Errorf("failed to decode merged error: %w", err)
+	}
+	m.Fingerprints = fingerprints
+	if unmergedAt.Valid {
+		m.UnmergedAt = &unmergedAt.Time
+	}
+	return &m, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+// groupEvent returns an event of the shop project's group with fingerprint,
+// in production
+func groupEvent(fingerprint, userID string) *models.Error {
+	return &models.Error{
+		ProjectID:   shopID,
+		Environment: "production",
+		Type:        "Error",
+		Message:     "failed " + fingerprint,
+		Fingerprint: fingerprint,
+		UserID:      userID,
+		OccurredAt:  time.Now(),
+	}
+}
+
+// groupCounts returns the stored count and user count of a group
+func groupCounts(t *testing.T, db *sql.DB, id string) (count, users int) {
+	t.Helper()
+	if err := db.QueryRow(`SELECT count, user_count FROM errors WHERE id = $1`, id).Scan(&count, &users); err != nil {
+		t.Fatalf("read group %s: %v", id, err)
+	}
+	return count, users
+}
+
+func TestMergeAndUnmerge(t *testing.T) {
+	db := openTestDB(t)
+	seedUsers(t, db)
+	seedProject(t, db, shopID, aliceID, "shop", "")
+	repo := NewErrorRepository(db)
+	ctx := context.Background()
+
+	track := func(events ...TrackedEvent) *models.Error {
+		t.Helper()
+		e, err := repo.TrackBatch(ctx, events)
+		if err != nil {
+			t.Fatalf("TrackBatch() error = %v", err)
+		}
+		return e
+	}
+
+	target := track(
+		TrackedEvent{Error: groupEvent("a", "u1")},
+		TrackedEvent{Error: groupEvent("a", "u2")},
+		TrackedEvent{Error: groupEvent("a", "u2")},
+	)
+	// The source counts a client-sampled event of weight 4, and a sampled-out
+	// event that leaves no occurrence: only the rollups know about them
+	repo.rand = func() float64 { return 0.99 }
+	source := track(
+		TrackedEvent{Error: groupEvent("b", "u2")},
+		TrackedEvent{Error: groupEvent("b", "u3"), Weight: 4, SampleRate: 0.5},
+	)
+	if count, users := groupCounts(t, db, source.ID); count != 5 || users != 2 {
+		t.Fatalf("source counts %d events of %d users, want 5 of 2", count, users)
+	}
+
+	merged, merges, err := repo.Merge(ctx, shopID, target.ID, []string{source.ID}, aliceID)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if merged.Count != 8 || len(merges) != 1 {
+		t.Fatalf("Merge() = count %d with %d merges, want count 8 with 1 merge", merged.Count, len(merges))
+	}
+	if count, users := groupCounts(t, db, target.ID); count != 8 || users != 3 {
+		t.Errorf("target counts %d events of %d users, want 8 of 3", count, users)
+	}
+	var sourceRows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM errors WHERE id = $1`, source.ID).Scan(&sourceRows); err != nil || sourceRows != 0 {
+		t.Errorf("source group left behind: %d rows, %v", sourceRows, err)
+	}
+
+	// The source's fingerprint keeps routing to the target
+	if e := track(TrackedEvent{Error: groupEvent("b", "u4")}); e.ID != target.ID {
+		t.Fatalf("event of the merged fingerprint tracked in %s, want the target %s", e.ID, target.ID)
+	}
+	if count, users := groupCounts(t, db, target.ID); count != 9 || users != 4 {
+		t.Errorf("target counts %d events of %d users, want 9 of 4", count, users)
+	}
+
+	t.Run("rejected merges", func(t *testing.T) {
+		staging := groupEvent("c", "")
+		staging.Environment = "staging"
+		other := track(TrackedEvent{Error: staging})
+		if _, _, err := repo.Merge(ctx, shopID, target.ID, []string{other.ID}, aliceID); !errors.Is(err, ErrMergeEnvironmentMismatch) {
+			t.Errorf("Merge() across environments error = %v, want %v", err, ErrMergeEnvironmentMismatch)
+		}
+		if _, _, err := repo.Merge(ctx, shopID, target.ID, []string{source.ID}, aliceID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Merge() of a merged group error = %v, want sql.ErrNoRows", err)
+		}
+		if _, _, err := repo.Merge(ctx, blogID, target.ID, []string{other.ID}, aliceID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Merge() in another project error = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	// Unmerging hands back what the source counted, including the event it
+	// got since the merge
+	restored, err := repo.Unmerge(ctx, shopID, merges[0].ID, aliceID)
+	if err != nil {
+		t.Fatalf("Unmerge() error = %v", err)
+	}
+	if restored.ID != source.ID || restored.Count != 6 {
+		t.Errorf("Unmerge() = %s with count %d, want %s with count 6", restored.ID, restored.Count, source.ID)
+	}
+	if count, users := groupCounts(t, db, source.ID); count != 6 || users != 3 {
+		t.Errorf("restored source counts %d events of %d users, want 6 of 3", count, users)
+	}
+	if count, users := groupCounts(t, db, target.ID); count != 3 || users != 2 {
+		t.Errorf("target counts %d events of %d users, want 3 of 2", count, users)
+	}
+	if e := track(TrackedEvent{Error: groupEvent("b", "")}); e.ID != source.ID {
+		t.Errorf("event of the unmerged fingerprint tracked in %s, want the source %s", e.ID, source.ID)
+	}
+	if _, err := repo.Unmerge(ctx, shopID, merges[0].ID, aliceID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("second Unmerge() error = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+// OccurrenceFilters selects a page of an error group's occurrences, newest
+// first. BeforeTime and BeforeID are the timestamp and id of the last
+// occurrence of the previous page, zero for the first page.
+type OccurrenceFilters struct {
+	ProjectID   string
+	ErrorID     string
+	UserID      string
+	SessionID   string
+	Release     string
+	Environment string
+	StartDate   time.Time
+	EndDate     time.Time
+	BeforeTime  time.Time
+	BeforeID    string
+	Limit       int
+}
+
+const occurrenceColumns = `id, error_id, user_id, session_id, timestamp, metadata, frames, release, breadcrumbs`
+
+// occurrenceSummaryColumns leave out the frames and breadcrumbs, which only
+// the occurrence detail returns
+const occurrenceSummaryColumns = `id, error_id, user_id, session_id, timestamp, metadata, NULL, release, NULL`
+
+// ListOccurrences returns a page of the error's occurrences. The environment
+// is that of the group, so filtering on another one returns no occurrences.
+// It returns sql.ErrNoRows if the project has no such error.
+func (r *ErrorRepository) ListOccurrences(ctx context.Context, filters OccurrenceFilters) ([]models.ErrorOccurrence, error) {
+	var environment string
+	err := r.db.QueryRowContext(ctx, `SELECT environment FROM errors WHERE id = $1 AND project_id = $2`,
+		filters.ErrorID, filters.ProjectID).Scan(&environment)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := []models.ErrorOccurrence{}
+	if filters.Environment != "" && filters.Environment != environment {
+		return occurrences, nil
+	}
+
+	query := `SELECT ` + occurrenceSummaryColumns + ` FROM error_occurrences WHERE error_id = $1`
+	args := []interface{}{filters.ErrorID}
+	where := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(" AND "+cond, len(args))
+	}
+	if filters.UserID != "" {
+		where("user_id = $%d", filters.UserID)
+	}
+	if filters.SessionID != "" {
+		where("session_id = $%d", filters.SessionID)
+	}
+	if filters.Release != "" {
+		where("release = $%d", filters.Release)
+	}
+	if !filters.StartDate.IsZero() {
+		where("timestamp >= $%d", filters.StartDate)
+	}
+	if !filters.EndDate.IsZero() {
+		where("timestamp <= $%d", filters.EndDate)
+	}
+	if !filters.BeforeTime.IsZero() {
+		args = append(args, filters.BeforeTime, filters.BeforeID)
+		query += fmt.Sprintf(" AND (timestamp, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+	args = append(args, filters.Limit)
+	query += fmt.Sprintf(" ORDER BY timestamp DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query occurrences: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		o, err := scanOccurrence(rows)
+		if err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, *o)
+	}
+	return occurrences, rows.Err()
+}
+
+// GetOccurrence returns one occurrence of the error with its frames and
+// breadcrumbs. It returns sql.ErrNoRows if the project has no such
+// occurrence.
+func (r *ErrorRepository) GetOccurrence(ctx context.Context, projectID, errorID, occurrenceID string) (*models.ErrorOccurrence, error) {
+	return scanOccurrence(r.db.QueryRowContext(ctx, `
+        SELECT `+occurrenceColumns+`
+        FROM error_occurrences
+        WHERE id = $1 AND error_id = $2
+            AND EXISTS (SELECT 1 FROM errors WHERE id = $2 AND project_id = $3)`,
+		occurrenceID, errorID, projectID))
+}
+
+func scanOccurrence(row rowScanner) (*models.ErrorOccurrence, error) {
+	var o models.ErrorOccurrence
+	var metadataJSON []byte
+	err := row.Scan(&o.ID, &o.ErrorID, &o.UserID, &o.SessionID, &o.Timestamp, &metadataJSON,
+		jsonColumn{&o.Frames}, &o.Release, jsonColumn{&o.Breadcrumbs})
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan occurrence: %w", err)
+	}
+	if metadataJSON != nil {
+		if err := json.Unmarshal(metadataJSON, &o.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+	return &o, nil
+}
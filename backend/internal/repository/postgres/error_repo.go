@@ -2,69 +2,186 @@ package postgres
 
 import (
 	"context"
-	"crypto/md5"
 	"database/sql"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"pulseguard/internal/models"
+	"pulseguard/internal/search"
 )
 
+// ErrNoRelease is returned when resolving in the next release a project without releases
+var ErrNoRelease = errors.New("project has no releases")
+
 var regexpBrowser = regexp.MustCompile(`Chrome|Firefox|Safari|Edge|Opera|MSIE|Trident`)
 
 type ErrorRepository struct {
 	db *sql.DB
+	// rand draws the events sampled out
+	rand func() float64
 }
 
 func NewErrorRepository(db *sql.DB) *ErrorRepository {
-	return &ErrorRepository{db: db}
+	return &ErrorRepository{db: db, rand: rand.Float64}
 }
 
 type ErrorFilters struct {
 	ProjectID   string
 	Environment string
 	Status      string
-	Search      string
+	Query       []search.Term
 	UserID      string
 	SessionID   string
 	StartDate   time.Time
 	EndDate     time.Time
+	Sort        string // one of the keys of errorSortOrders, last_seen by default
 	Page        int
 	Limit       int
 }
 
+// errorSortOrders are the orders the error list can be sorted in
+var errorSortOrders = map[string]string{
+	"last_seen":  "last_seen DESC, id DESC",
+	"first_seen": "occurred_at DESC, id DESC",
+	"frequency":  "count DESC, last_seen DESC, id DESC",
+	"users":      "user_count DESC, last_seen DESC, id DESC",
+	"sessions":   "session_count DESC, last_seen DESC, id DESC",
+}
+
+// IsErrorSort reports whether the error list can be sorted by sort
+func IsErrorSort(sort string) bool {
+	_, ok := errorSortOrders[sort]
+	return ok
+}
+
+// errorFiltersSQL builds the condition on the errors table selecting the
+// filtered errors, and its arguments
+func errorFiltersSQL(filters ErrorFilters) (string, []interface{}) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conds := []string{"1=1"}
+	for _, f := range []struct {
+		column string
+		value  string
+	}{
+		{"project_id", filters.ProjectID},
+		{"environment", filters.Environment},
+		{"status", filters.Status},
+		{"user_id", filters.UserID},
+		{"session_id", filters.SessionID},
+	} {
+		if f.value != "" {
+			conds = append(conds, f.column+" = "+arg(f.value))
+		}
+	}
+	if !filters.StartDate.IsZero() {
+		conds = append(conds, "occurred_at >= "+arg(filters.StartDate))
+	}
+	if !filters.EndDate.IsZero() {
+		conds = append(conds, "occurred_at <= "+arg(filters.EndDate))
+	}
+	conds = append(conds, searchSQL(filters.Query, arg)...)
+	return strings.Join(conds, " AND "), args
+}
+
+// TrackedEvent is an event to record in its group, with the metadata its
+// occurrence keeps
+type TrackedEvent struct {
+	Error    *models.Error
+	Metadata map[string]interface{}
+	// Weight is how many events this one stands for when the client sampled
+	// it; 0 counts as 1
+	Weight int
+	// SampleRate is the chance the event is stored as an occurrence once its
+	// group counts KeepFirst events; 0 stores every event. A sampled-out
+	// event still counts towards its group, its histogram and its users.
+	SampleRate float64
+	KeepFirst  int
+}
+
+func (t TrackedEvent) weight() int {
+	return max(t.Weight, 1)
+}
+
+// sampledOut reports whether the event is left without an occurrence, in a
+// group that counted count events before it. The first event of a group is
+// always kept.
+func (t TrackedEvent) sampledOut(count int, draw func() float64) bool {
+	if t.SampleRate <= 0 || t.SampleRate >= 1 || count == 0 || count < t.KeepFirst {
+		return false
+	}
+	return draw() >= t.SampleRate
+}
+
+// sampleEvents walks events as updateError records them, in a group that
+// counted count events before them. It returns the group's count after each
+// event and whether each event is stored as an occurrence, drawing from draw.
+// Every event counts for its weight, stored or not.
+func sampleEvents(count int, events []TrackedEvent, draw func() float64) (counts []int, kept []bool) {
+	counts = make([]int, len(events))
+	kept = make([]bool, len(events))
+	for i, tracked := range events {
+		kept[i] = !tracked.sampledOut(count, draw)
+		count += tracked.weight()
+		counts[i] = count
+	}
+	return counts, kept
+}
+
+// Track records an occurrence of the error, adding it to the project's group
+// with the same environment and fingerprint or opening a new group.
+// errorData.Fingerprint must already be set.
 func (r *ErrorRepository) Track(ctx context.Context, errorData *models.Error, metadata map[string]interface{}) (*models.Error, error) {
-	fingerprint := generateErrorFingerprint(errorData)
+	return r.TrackBatch(ctx, []TrackedEvent{{Error: errorData, Metadata: metadata}})
+}
+
+// TrackBatch records events that share a project, environment and
+// fingerprint, in order, as Track would one at a time. The group is locked
+// once and its count, status and rollups are updated once for the whole
+// batch.
+func (r *ErrorRepository) TrackBatch(ctx context.Context, events []TrackedEvent) (*models.Error, error) {
+	errorData := events[0].Error
+	fingerprint := errorData.Fingerprint
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	var existingError models.Error
-	err = tx.QueryRowContext(ctx, `
-        SELECT id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status
-        FROM errors
-        WHERE project_id = $1 AND environment = $2 AND message = $3 AND source = $4 AND type = $5 AND fingerprint = $6`,
-		errorData.ProjectID, errorData.Environment, errorData.Message, errorData.Source, errorData.Type, fingerprint).
-		Scan(&existingError.ID, &existingError.ProjectID, &existingError.Message, &existingError.StackTrace,
-			&existingError.Fingerprint, &existingError.OccurredAt, &existingError.LastSeen, &existingError.Environment,
-			&existingError.Count, &existingError.Source, &existingError.Type, &existingError.URL,
-			&existingError.ComponentStack, &existingError.BrowserInfo, &existingError.UserID,
-			&existingError.SessionID, &existingError.Status)
+	releases := make(map[string]bool)
+	for _, event := range events {
+		if releases[event.Error.Release] {
+			continue
+		}
+		releases[event.Error.Release] = true
+		if err := ensureRelease(ctx, tx, errorData.ProjectID, event.Error.Release); err != nil {
+			return nil, err
+		}
+	}
+
+	existingError, err := lockGroup(ctx, tx, errorData.ProjectID, errorData.Environment, fingerprint)
+	if err == sql.ErrNoRows && errorData.LegacyFingerprint != "" && errorData.LegacyFingerprint != fingerprint {
+		existingError, err = adoptLegacyGroup(ctx, tx, errorData, fingerprint)
+	}
 	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("failed to check existing error: %w", err)
+		return nil, err
 	}
 
 	if err == nil {
-		updatedError, err := r.updateError(ctx, tx, &existingError, metadata)
+		updatedError, err := r.updateError(ctx, tx, existingError, events)
 		if err != nil {
 			return nil, err
 		}
@@ -77,43 +194,42 @@ func (r *ErrorRepository) Track(ctx context.Context, errorData *models.Error, me
 	errorData.ID = uuid.NewString()
 	errorData.Fingerprint = fingerprint
 	errorData.LastSeen = errorData.OccurredAt
-	errorData.Count = 1
+	// The count and rollups are written by updateError with the whole batch
+	errorData.Count = 0
+	errorData.FirstRelease = errorData.Release
+	errorData.LastRelease = errorData.Release
 	if errorData.Status == "" {
 		errorData.Status = "ACTIVE"
 	}
 
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO errors (id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+        INSERT INTO errors (id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status, frames, first_release, last_release)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
 		errorData.ID, errorData.ProjectID, errorData.Message, errorData.StackTrace, errorData.Fingerprint,
 		errorData.OccurredAt, errorData.LastSeen, errorData.Environment, errorData.Count, errorData.Source,
 		errorData.Type, errorData.URL, errorData.ComponentStack, errorData.BrowserInfo, errorData.UserID,
-		errorData.SessionID, errorData.Status)
+		errorData.SessionID, errorData.Status, framesJSON(errorData.Frames), errorData.FirstRelease, errorData.LastRelease)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert error: %w", err)
 	}
 
-	occurrenceID := uuid.NewString()
-	metadataJSON, _ := json.Marshal(metadata)
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO error_occurrences (id, error_id, user_id, session_id, timestamp, metadata)
-        VALUES ($1, $2, $3, $4, $5, $6)`,
-		occurrenceID, errorData.ID, errorData.UserID, errorData.SessionID, errorData.OccurredAt, metadataJSON)
+        INSERT INTO error_fingerprints (project_id, environment, fingerprint, error_id)
+        VALUES ($1, $2, $3, $4)`,
+		errorData.ProjectID, errorData.Environment, fingerprint, errorData.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert occurrence: %w", err)
+		return nil, fmt.Errorf("failed to insert fingerprint: %w", err)
 	}
 
-	tags := extractTags(errorData)
-	for _, tag := range tags {
-		tagID := uuid.NewString()
-		_, err = tx.ExecContext(ctx, `
-            INSERT INTO error_tags (id, error_id, key, value)
-            VALUES ($1, $2, $3, $4)
-            ON CONFLICT (error_id, key, value) DO NOTHING`,
-			tagID, errorData.ID, tag.Key, tag.Value)
-		if err != nil {
-			return nil, fmt.Errorf("failed to insert tag: %w", err)
-		}
+	err = recordActivity(ctx, tx, errorData.ProjectID, errorData.ID, models.ActivityFirstSeen, "", map[string]interface{}{
+		"release": errorData.Release,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if errorData, err = r.updateError(ctx, tx, errorData, events); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -123,101 +239,288 @@ func (r *ErrorRepository) Track(ctx context.Context, errorData *models.Error, me
 	return errorData, nil
 }
 
-func (r *ErrorRepository) updateError(ctx context.Context, tx *sql.Tx, errorData *models.Error, metadata map[string]interface{}) (*models.Error, error) {
-	newCount := errorData.Count + 1
-	_, err := tx.ExecContext(ctx, `
-        UPDATE errors
-        SET count = $1, last_seen = $2, status = CASE WHEN status = 'RESOLVED' THEN 'ACTIVE' ELSE status END
-        WHERE id = $3`,
-		newCount, time.Now(), errorData.ID)
+// lockGroup returns the group that events with the fingerprint belong to,
+// locked for update, or sql.ErrNoRows. Merged groups keep receiving the
+// events of every fingerprint merged into them.
+func lockGroup(ctx context.Context, tx *sql.Tx, projectID, environment, fingerprint string) (*models.Error, error) {
+	var existingError models.Error
+	err := tx.QueryRowContext(ctx, `
+        SELECT e.id, e.project_id, e.message, e.stack_trace, e.fingerprint, e.occurred_at, e.last_seen, e.environment, e.count, e.source, e.type, e.url, e.component_stack, e.browser_info, e.user_id, e.session_id, e.status, e.status_details, e.first_release, e.last_release
+        FROM error_fingerprints f
+        JOIN errors e ON e.id = f.error_id
+        WHERE f.project_id = $1 AND f.environment = $2 AND f.fingerprint = $3
+        FOR UPDATE OF e`,
+		projectID, environment, fingerprint).
+		Scan(&existingError.ID, &existingError.ProjectID, &existingError.Message, &existingError.StackTrace,
+			&existingError.Fingerprint, &existingError.OccurredAt, &existingError.LastSeen, &existingError.Environment,
+			&existingError.Count, &existingError.Source, &existingError.Type, &existingError.URL,
+			&existingError.ComponentStack, &existingError.BrowserInfo, &existingError.UserID,
+			&existingError.SessionID, &existingError.Status, jsonColumn{&existingError.StatusDetails}, &existingError.FirstRelease, &existingError.LastRelease)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to update error: %w", err)
+		return nil, fmt.Errorf("failed to check existing error: %w", err)
+	}
+	return &existingError, nil
+}
+
+// adoptLegacyGroup returns the group opened under the event's legacy
+// fingerprint before stack-aware grouping, locked for update, and attaches
+// fingerprint to it so that later events find it directly. Only a group that
+// no other fingerprint routes to yet is adopted: the legacy fingerprint
+// hashes the message alone, so once one stack has taken the group over,
+// events with other stacks and the same message open groups of their own.
+// It returns sql.ErrNoRows when there is no such group.
+func adoptLegacyGroup(ctx context.Context, tx *sql.Tx, errorData *models.Error, fingerprint string) (*models.Error, error) {
+	legacyGroup, err := lockGroup(ctx, tx, errorData.ProjectID, errorData.Environment, errorData.LegacyFingerprint)
+	if err != nil {
+		return nil, err
+	}
+	// The legacy group may have been merged into a group of its own
+	// fingerprint since
+	if legacyGroup.Fingerprint != errorData.LegacyFingerprint {
+		return nil, sql.ErrNoRows
+	}
+	var adopted bool
+	err = tx.QueryRowContext(ctx, `
+        SELECT EXISTS (SELECT 1 FROM error_fingerprints WHERE error_id = $1 AND fingerprint <> $2)`,
+		legacyGroup.ID, errorData.LegacyFingerprint).Scan(&adopted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check fingerprints: %w", err)
+	}
+	if adopted {
+		return nil, sql.ErrNoRows
 	}
 
-	occurrenceID := uuid.NewString()
-	metadataJSON, _ := json.Marshal(metadata)
 	_, err = tx.ExecContext(ctx, `
-        INSERT INTO error_occurrences (id, error_id, user_id, session_id, timestamp, metadata)
-        VALUES ($1, $2, $3, $4, $5, $6)`,
-		occurrenceID, errorData.ID, errorData.UserID, errorData.SessionID, time.Now(), metadataJSON)
+        INSERT INTO error_fingerprints (project_id, environment, fingerprint, error_id)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT DO NOTHING`,
+		errorData.ProjectID, errorData.Environment, fingerprint, legacyGroup.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach fingerprint: %w", err)
+	}
+	return legacyGroup, nil
+}
+
+// updateError records events as occurrences of the group errorData, new or
+// existing, and reopens the group if it was resolved or its ignore
+// conditions are met, see nextStatus. Each event is checked against the
+// group as the events before it left it, but the group's row is written
+// once. Occurrences and the histogram are stamped with the time each event
+// occurred, and last_seen is the latest of them.
+func (r *ErrorRepository) updateError(ctx context.Context, tx *sql.Tx, errorData *models.Error, events []TrackedEvent) (*models.Error, error) {
+	now := time.Now()
+	newCount := errorData.Count
+	lastSeen := errorData.LastSeen
+	rollups := newEventRollups(events[0].Error.Fingerprint)
+	var tags []models.ErrorTag
+
+	var affected *affectedUsers
+	if details := errorData.StatusDetails; errorData.Status == "IGNORED" && details != nil && details.IgnoreUserCount > 0 {
+		var err error
+		if affected, err = loadAffectedUsers(ctx, tx, errorData.ID, details.ChangedAt, events); err != nil {
+			return nil, err
+		}
+	}
+
+	counts, kept := sampleEvents(newCount, events, r.rand)
+	for i, tracked := range events {
+		event := tracked.Error
+		newCount = counts[i]
+		at := event.OccurredAt
+		if at.IsZero() {
+			at = now
+		}
+		if at.After(lastSeen) {
+			lastSeen = at
+		}
+
+		if kept[i] {
+			occurrenceID := uuid.NewString()
+			metadataJSON, _ := json.Marshal(tracked.Metadata)
+			_, err := tx.ExecContext(ctx, `
+                INSERT INTO error_occurrences (id, error_id, user_id, session_id, timestamp, metadata, fingerprint, frames, release, breadcrumbs)
+                VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+				occurrenceID, errorData.ID, event.UserID, event.SessionID, at, metadataJSON, event.Fingerprint,
+				framesJSON(event.Frames), event.Release, breadcrumbsJSON(event.Breadcrumbs))
+			if err != nil {
+				return nil, fmt.Errorf("failed to insert occurrence: %w", err)
+			}
+		}
+		rollups.add(event.UserID, event.SessionID, at, tracked.weight())
+		affected.add(event.UserID, at)
+		tags = append(tags, extractTags(event)...)
+
+		status, err := nextStatus(ctx, tx, errorData, event, newCount, affected, now)
+		if err != nil {
+			return nil, err
+		}
+		if status != errorData.Status {
+			activityType := models.ActivityStatusChanged
+			if status == "REGRESSED" {
+				activityType = models.ActivityRegressed
+			}
+			err = recordActivity(ctx, tx, errorData.ProjectID, errorData.ID, activityType, "", map[string]interface{}{
+				"from":    errorData.Status,
+				"to":      status,
+				"release": event.Release,
+			})
+			if err != nil {
+				return nil, err
+			}
+			errorData.Status = status
+			errorData.StatusDetails = nil
+		}
+
+		if event.Release != "" {
+			if errorData.FirstRelease == "" {
+				errorData.FirstRelease = event.Release
+			}
+			errorData.LastRelease = event.Release
+		}
+	}
+
+	if err := insertTags(ctx, tx, errorData.ID, tags); err != nil {
+		return nil, err
+	}
+
+	// One statement writes the group's row and rollups, however many events
+	args := append([]interface{}{errorData.ID}, rollups.args()...)
+	args = append(args, newCount, lastSeen, errorData.Status, statusDetailsJSON(errorData.StatusDetails),
+		errorData.FirstRelease, errorData.LastRelease)
+	err := tx.QueryRowContext(ctx, `
+        WITH `+rollupStatements+`
+        UPDATE errors
+        SET count = $9, last_seen = $10, status = $11, status_details = $12, first_release = $13, last_release = $14,
+            user_count = user_count + `+newUserCount+`,
+            session_count = session_count + `+newSessionCount+`
+        WHERE id = $1
+        RETURNING user_count, session_count`, args...).
+		Scan(&errorData.UserCount, &errorData.SessionCount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to insert occurrence: %w", err)
+		return nil, fmt.Errorf("failed to update error: %w", err)
 	}
 
 	errorData.Count = newCount
-	errorData.LastSeen = time.Now()
+	errorData.LastSeen = lastSeen
 	return errorData, nil
 }
 
-func (r *ErrorRepository) GetErrors(ctx context.Context, filters ErrorFilters) ([]*models.Error, int, error) {
-	query := `SELECT id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status
-              FROM errors WHERE 1=1`
-	args := []interface{}{}
-	countQuery := `SELECT COUNT(*) FROM errors WHERE 1=1`
-	countArgs := []interface{}{}
-	argIndex := 1
-
-	if filters.ProjectID != "" {
-		query += fmt.Sprintf(" AND project_id = $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND project_id = $%d", argIndex)
-		args = append(args, filters.ProjectID)
-		countArgs = append(countArgs, filters.ProjectID)
-		argIndex++
-	}
-	if filters.Environment != "" {
-		query += fmt.Sprintf(" AND environment = $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND environment = $%d", argIndex)
-		args = append(args, filters.Environment)
-		countArgs = append(countArgs, filters.Environment)
-		argIndex++
-	}
-	if filters.Status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND status = $%d", argIndex)
-		args = append(args, filters.Status)
-		countArgs = append(countArgs, filters.Status)
-		argIndex++
-	}
-	if filters.UserID != "" {
-		query += fmt.Sprintf(" AND user_id = $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND user_id = $%d", argIndex)
-		args = append(args, filters.UserID)
-		countArgs = append(countArgs, filters.UserID)
-		argIndex++
-	}
-	if filters.SessionID != "" {
-		query += fmt.Sprintf(" AND session_id = $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND session_id = $%d", argIndex)
-		args = append(args, filters.SessionID)
-		countArgs = append(countArgs, filters.SessionID)
-		argIndex++
+// nextStatus returns the status of a group after a new event. A resolved
+// group is reopened as ACTIVE, or as REGRESSED when the event comes from a
+// newer release than the group was last seen in. Resolved in the next
+// release, it stays resolved unless the event comes from a release newer
+// than the one that was current when it was resolved. An ignored group is
+// reopened once one of its ignore conditions is met.
+func nextStatus(ctx context.Context, tx *sql.Tx, e, event *models.Error, newCount int, affected *affectedUsers, now time.Time) (string, error) {
+	details := e.StatusDetails
+	switch e.Status {
+	case "RESOLVED":
+		if details != nil && details.InNextRelease {
+			newer, err := isNewerRelease(ctx, tx, e.ProjectID, event.Release, details.AfterRelease)
+			if err != nil || !newer {
+				return e.Status, err
+			}
+			return "REGRESSED", nil
+		}
+		regressed, err := isNewerRelease(ctx, tx, e.ProjectID, event.Release, e.LastRelease)
+		if err != nil {
+			return "", err
+		}
+		if regressed {
+			return "REGRESSED", nil
+		}
+		return "ACTIVE", nil
+
+	case "IGNORED":
+		if details == nil {
+			return e.Status, nil
+		}
+		if details.IgnoreUntil != nil && !now.Before(*details.IgnoreUntil) {
+			return "ACTIVE", nil
+		}
+		if details.IgnoreCount > 0 && newCount-details.CountAtChange >= details.IgnoreCount {
+			return "ACTIVE", nil
+		}
+		if details.IgnoreUserCount > 0 && affected != nil && affected.count >= details.IgnoreUserCount {
+			return "ACTIVE", nil
+		}
 	}
-	if !filters.StartDate.IsZero() {
-		query += fmt.Sprintf(" AND occurred_at >= $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND occurred_at >= $%d", argIndex)
-		args = append(args, filters.StartDate)
-		countArgs = append(countArgs, filters.StartDate)
-		argIndex++
+	return e.Status, nil
+}
+
+// affectedUsers counts the users an ignored group affected since it was
+// ignored, from its rollups and then from the events of the batch, so that
+// sampled-out events count too
+type affectedUsers struct {
+	since time.Time
+	count int
+	known map[string]bool
+}
+
+// loadAffectedUsers counts the users first seen by the group since the given
+// time, and looks up which users of the events it has seen before
+func loadAffectedUsers(ctx context.Context, tx *sql.Tx, errorID string, since time.Time, events []TrackedEvent) (*affectedUsers, error) {
+	affected := &affectedUsers{since: since, known: make(map[string]bool)}
+	err := tx.QueryRowContext(ctx, `
+        SELECT COUNT(*) FROM (
+            SELECT user_id FROM error_users WHERE error_id = $1
+            GROUP BY user_id HAVING MIN(first_seen) >= $2
+        ) AS users`, errorID, since).Scan(&affected.count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count affected users: %w", err)
 	}
-	if !filters.EndDate.IsZero() {
-		query += fmt.Sprintf(" AND occurred_at <= $%d", argIndex)
-		countQuery += fmt.Sprintf(" AND occurred_at <= $%d", argIndex)
-		args = append(args, filters.EndDate)
-		countArgs = append(countArgs, filters.EndDate)
-		argIndex++
-	}
-	if filters.Search != "" {
-		query += fmt.Sprintf(" AND (message ILIKE $%d OR source ILIKE $%d OR url ILIKE $%d)", argIndex, argIndex, argIndex)
-		countQuery += fmt.Sprintf(" AND (message ILIKE $%d OR source ILIKE $%d OR url ILIKE $%d)", argIndex, argIndex, argIndex)
-		searchTerm := "%" + filters.Search + "%"
-		args = append(args, searchTerm, searchTerm, searchTerm)
-		countArgs = append(countArgs, searchTerm, searchTerm, searchTerm)
-		argIndex += 3
-	}
-
-	query += " ORDER BY last_seen DESC"
+
+	var userIDs pq.StringArray
+	for _, event := range events {
+		if event.Error.UserID != "" {
+			userIDs = append(userIDs, event.Error.UserID)
+		}
+	}
+	rows, err := tx.QueryContext(ctx, `
+        SELECT DISTINCT user_id FROM error_users WHERE error_id = $1 AND user_id = ANY($2)`,
+		errorID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up affected users: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan affected user: %w", err)
+		}
+		affected.known[userID] = true
+	}
+	return affected, rows.Err()
+}
+
+// add counts the user of an event if the group has not seen it before
+func (a *affectedUsers) add(userID string, at time.Time) {
+	if a == nil || userID == "" || a.known[userID] {
+		return
+	}
+	a.known[userID] = true
+	if !at.Before(a.since) {
+		a.count++
+	}
+}
+
+func (r *ErrorRepository) GetErrors(ctx context.Context, filters ErrorFilters) ([]*models.Error, int, error) {
+	where, args := errorFiltersSQL(filters)
+	countArgs := append([]interface{}{}, args...)
+	countQuery := `SELECT COUNT(*) FROM errors WHERE ` + where
+
+	query := `SELECT id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status, status_details, first_release, last_release, user_count, session_count
+              FROM errors WHERE ` + where
+	order, ok := errorSortOrders[filters.Sort]
+	if !ok {
+		order = errorSortOrders["last_seen"]
+	}
+	query += " ORDER BY " + order
 	if filters.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
 		args = append(args, filters.Limit, (filters.Page-1)*filters.Limit)
 	}
 
@@ -232,7 +535,8 @@ func (r *ErrorRepository) GetErrors(ctx context.Context, filters ErrorFilters) (
 		var e models.Error
 		err := rows.Scan(&e.ID, &e.ProjectID, &e.Message, &e.StackTrace, &e.Fingerprint, &e.OccurredAt,
 			&e.LastSeen, &e.Environment, &e.Count, &e.Source, &e.Type, &e.URL, &e.ComponentStack,
-			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status)
+			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status, jsonColumn{&e.StatusDetails}, &e.FirstRelease, &e.LastRelease,
+			&e.UserCount, &e.SessionCount)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan error: %w", err)
 		}
@@ -245,28 +549,30 @@ func (r *ErrorRepository) GetErrors(ctx context.Context, filters ErrorFilters) (
 		return nil, 0, fmt.Errorf("failed to count errors: %w", err)
 	}
 
-	for _, e := range errors {
-		e.Tags, err = r.getTagsForError(ctx, e.ID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to fetch tags for error %s: %w", e.ID, err)
-		}
-		e.Occurrences, err = r.getOccurrencesForError(ctx, e.ID, 10)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to fetch occurrences for error %s: %w", e.ID, err)
-		}
+	// Occurrences are not preloaded; they are paged through ListOccurrences
+	if err := r.loadTags(ctx, errors); err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadStats(ctx, errors); err != nil {
+		return nil, 0, err
 	}
 
 	return errors, total, nil
 }
 
-func (r *ErrorRepository) GetErrorByID(ctx context.Context, id string) (*models.Error, error) {
+func (r *ErrorRepository) GetErrorByID(ctx context.Context, projectID, id string) (*models.Error, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
 	var e models.Error
 	err := r.db.QueryRowContext(ctx, `
-        SELECT id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status
-        FROM errors WHERE id = $1`, id).
+        SELECT `+errorColumns+`, user_count, session_count
+        FROM errors WHERE id = $1 AND project_id = $2`, id, projectID).
 		Scan(&e.ID, &e.ProjectID, &e.Message, &e.StackTrace, &e.Fingerprint, &e.OccurredAt,
 			&e.LastSeen, &e.Environment, &e.Count, &e.Source, &e.Type, &e.URL, &e.ComponentStack,
-			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status)
+			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status, jsonColumn{&e.StatusDetails}, jsonColumn{&e.Frames}, &e.FirstRelease, &e.LastRelease,
+			&e.UserCount, &e.SessionCount)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -274,6 +580,10 @@ func (r *ErrorRepository) GetErrorByID(ctx context.Context, id string) (*models.
 		return nil, fmt.Errorf("failed to query error: %w", err)
 	}
 
+	e.Assignee, err = r.getAssignee(ctx, projectID, e.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignee: %w", err)
+	}
 	e.Tags, err = r.getTagsForError(ctx, e.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch tags: %w", err)
@@ -282,25 +592,69 @@ func (r *ErrorRepository) GetErrorByID(ctx context.Context, id string) (*models.
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch occurrences: %w", err)
 	}
+	if err := r.loadStats(ctx, []*models.Error{&e}); err != nil {
+		return nil, err
+	}
 
 	return &e, nil
 }
 
-func (r *ErrorRepository) UpdateErrorStatus(ctx context.Context, id, status string) (*models.Error, error) {
+// UpdateErrorStatus sets the status of an error and the conditions that go
+// with it, and records the change in its activity log. Resolving in the next
+// release records the project's latest release, or fails with ErrNoRelease
+// if it has none.
+func (r *ErrorRepository) UpdateErrorStatus(ctx context.Context, projectID, id, status string, details *models.StatusDetails, actorID string) (*models.Error, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := stampStatusDetails(ctx, tx, projectID, details); err != nil {
+		return nil, err
+	}
+
 	var e models.Error
-	err := r.db.QueryRowContext(ctx, `
+	var previous string
+	err = tx.QueryRowContext(ctx, `
         UPDATE errors
-        SET status = $1, last_seen = $2
-        WHERE id = $3
-        RETURNING id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count, source, type, url, component_stack, browser_info, user_id, session_id, status`,
-		status, time.Now(), id).
-		Scan(&e.ID, &e.ProjectID, &e.Message, &e.StackTrace, &e.Fingerprint, &e.OccurredAt,
+        SET status = $1, last_seen = $2,
+            status_details = CASE WHEN $5::jsonb IS NULL THEN NULL
+                ELSE $5::jsonb || jsonb_build_object('countAtChange', count) END
+        FROM (SELECT id AS old_id, status AS old_status FROM errors WHERE id = $3 AND project_id = $4 FOR UPDATE) old
+        WHERE id = old.old_id
+        RETURNING old.old_status, `+errorColumns+`, user_count, session_count`,
+		status, time.Now(), id, projectID, statusDetailsJSON(details)).
+		Scan(&previous, &e.ID, &e.ProjectID, &e.Message, &e.StackTrace, &e.Fingerprint, &e.OccurredAt,
 			&e.LastSeen, &e.Environment, &e.Count, &e.Source, &e.Type, &e.URL, &e.ComponentStack,
-			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status)
+			&e.BrowserInfo, &e.UserID, &e.SessionID, &e.Status, jsonColumn{&e.StatusDetails}, jsonColumn{&e.Frames}, &e.FirstRelease, &e.LastRelease,
+			&e.UserCount, &e.SessionCount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to update error status: %w", err)
 	}
 
+	data := map[string]interface{}{"from": previous, "to": status}
+	if e.StatusDetails != nil {
+		data["details"] = e.StatusDetails
+	}
+	if err := recordActivity(ctx, tx, projectID, e.ID, models.ActivityStatusChanged, actorID, data); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	e.Assignee, err = r.getAssignee(ctx, projectID, e.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch assignee: %w", err)
+	}
 	e.Tags, err = r.getTagsForError(ctx, e.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch tags: %w", err)
@@ -309,10 +663,36 @@ func (r *ErrorRepository) UpdateErrorStatus(ctx context.Context, id, status stri
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch occurrences: %w", err)
 	}
+	if err := r.loadStats(ctx, []*models.Error{&e}); err != nil {
+		return nil, err
+	}
 
 	return &e, nil
 }
 
+// stampStatusDetails records when the status conditions were set and, for
+// resolving in the next release, the project's latest release. It fails with
+// ErrNoRelease if the project has none.
+func stampStatusDetails(ctx context.Context, tx *sql.Tx, projectID string, details *models.StatusDetails) error {
+	if details == nil {
+		return nil
+	}
+	details.ChangedAt = time.Now()
+	if !details.InNextRelease {
+		return nil
+	}
+	err := tx.QueryRowContext(ctx, `
+        SELECT version FROM releases WHERE project_id = $1
+        ORDER BY created_at DESC, id DESC LIMIT 1`, projectID).Scan(&details.AfterRelease)
+	if err == sql.ErrNoRows {
+		return ErrNoRelease
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find latest release: %w", err)
+	}
+	return nil
+}
+
 func (r *ErrorRepository) ListRecentByProject(ctx context.Context, projectID string, limit int) ([]*models.Error, error) {
     query := `
         SELECT id, project_id, message, last_seen, count, type, session_id, status
@@ -345,19 +725,74 @@ func (r *ErrorRepository) CountByProject(ctx context.Context, projectID string)
     return count, err
 }
 
+// CountOccurrencesSince counts the project's error occurrences since the given time,
+// optionally limited to one environment
+func (r *ErrorRepository) CountOccurrencesSince(ctx context.Context, projectID, environment string, since time.Time) (int64, error) {
+	var count int64
+	query := `
+		SELECT COUNT(*)
+		FROM error_occurrences o
+		JOIN errors e ON e.id = o.error_id
+		WHERE e.project_id = $1 AND o.timestamp >= $2 AND ($3 = '' OR e.environment = $3)
+	`
+	err := r.db.QueryRowContext(ctx, query, projectID, since, environment).Scan(&count)
+	return count, err
+}
+
+// ListFirstSeenSince returns the project's error groups first seen since the given time
+func (r *ErrorRepository) ListFirstSeenSince(ctx context.Context, projectID, environment string, since time.Time) ([]*models.Error, error) {
+	query := `
+		SELECT id, project_id, message, COALESCE(type, ''), fingerprint, environment, occurred_at, last_seen, count
+		FROM errors
+		WHERE project_id = $1 AND occurred_at >= $2 AND ($3 = '' OR environment = $3)
+		ORDER BY occurred_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, projectID, since, environment)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	errs := make([]*models.Error, 0)
+	for rows.Next() {
+		var e models.Error
+		if err := rows.Scan(&e.ID, &e.ProjectID, &e.Message, &e.Type, &e.Fingerprint, &e.Environment, &e.OccurredAt, &e.LastSeen, &e.Count); err != nil {
+			return nil, err
+		}
+		errs = append(errs, &e)
+	}
+	return errs, rows.Err()
+}
+
 // <-
 //  @utilities
 //  ->
 
-func generateErrorFingerprint(errorData *models.Error) string {
-	components := []string{errorData.Message, errorData.Source, errorData.Type}
-	data := strings.Join(components, "|")
-	hash := md5.Sum([]byte(data))
-	return hex.EncodeToString(hash[:])
+// insertTags adds tags to the error, skipping those it already has
+func insertTags(ctx context.Context, tx *sql.Tx, errorID string, tags []models.ErrorTag) error {
+	seen := make(map[models.ErrorTag]bool, len(tags))
+	for _, tag := range tags {
+		tag.ID, tag.ErrorID = "", ""
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		_, err := tx.ExecContext(ctx, `
+            INSERT INTO error_tags (id, error_id, key, value)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (error_id, key, value) DO NOTHING`,
+			uuid.NewString(), errorID, tag.Key, tag.Value)
+		if err != nil {
+			return fmt.Errorf("failed to insert tag: %w", err)
+		}
+	}
+	return nil
 }
 
+// extractTags returns the tags sent with an event and those derived from
+// its browser, type, environment and URL
 func extractTags(errorData *models.Error) []models.ErrorTag {
-	var tags []models.ErrorTag
+	tags := append([]models.ErrorTag{}, errorData.Tags...)
 
 	if errorData.BrowserInfo != "" {
 		browserMatch := regexpBrowser.FindString(errorData.BrowserInfo)
@@ -425,12 +860,46 @@ func (r *ErrorRepository) getTagsForError(ctx context.Context, errorID string) (
 	return tags, nil
 }
 
+// loadTags fills in the tags of the errors with a single query
+func (r *ErrorRepository) loadTags(ctx context.Context, errs []*models.Error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.Error, len(errs))
+	ids := make([]string, 0, len(errs))
+	for _, e := range errs {
+		byID[e.ID] = e
+		ids = append(ids, e.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, error_id, key, value
+        FROM error_tags
+        WHERE error_id = ANY($1::uuid[])`, pq.StringArray(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t models.ErrorTag
+		if err := rows.Scan(&t.ID, &t.ErrorID, &t.Key, &t.Value); err != nil {
+			return fmt.Errorf("failed to scan tag: %w", err)
+		}
+		e := byID[t.ErrorID]
+		e.Tags = append(e.Tags, t)
+	}
+	return rows.Err()
+}
+
+// getOccurrencesForError returns the newest occurrences of the error,
+// without their frames and breadcrumbs
 func (r *ErrorRepository) getOccurrencesForError(ctx context.Context, errorID string, limit int) ([]models.ErrorOccurrence, error) {
 	rows, err := r.db.QueryContext(ctx, `
-        SELECT id, error_id, user_id, session_id, timestamp, metadata
+        SELECT `+occurrenceSummaryColumns+`
         FROM error_occurrences
         WHERE error_id = $1
-        ORDER BY timestamp DESC
+        ORDER BY timestamp DESC, id DESC
         LIMIT $2`, errorID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query occurrences: %w", err)
@@ -439,17 +908,95 @@ func (r *ErrorRepository) getOccurrencesForError(ctx context.Context, errorID st
 
 	var occurrences []models.ErrorOccurrence
 	for rows.Next() {
-		var o models.ErrorOccurrence
-		var metadataJSON []byte
-		if err := rows.Scan(&o.ID, &o.ErrorID, &o.UserID, &o.SessionID, &o.Timestamp, &metadataJSON); err != nil {
-			return nil, fmt.Errorf("failed to scan occurrence: %w", err)
-		}
-		if metadataJSON != nil {
-			if err := json.Unmarshal(metadataJSON, &o.Metadata); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-			}
+		o, err := scanOccurrence(rows)
+		if err != nil {
+			return nil, err
 		}
-		occurrences = append(occurrences, o)
+		occurrences = append(occurrences, *o)
 	}
 	return occurrences, nil
-}
\ No newline at end of file
+}
+
+// framesJSON encodes parsed stack frames for a JSONB column, NULL when there are none
+func framesJSON(frames []models.StackFrame) []byte {
+	if len(frames) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(frames)
+	return b
+}
+
+// breadcrumbsJSON encodes breadcrumbs for a JSONB column, NULL when there are none
+func breadcrumbsJSON(crumbs []models.Breadcrumb) []byte {
+	if len(crumbs) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(crumbs)
+	return b
+}
+
+// statusDetailsJSON encodes status details for a JSONB column, NULL when there are none
+func statusDetailsJSON(details *models.StatusDetails) []byte {
+	if details == nil {
+		return nil
+	}
+	b, _ := json.Marshal(details)
+	return b
+}
+
+// jsonColumn decodes a nullable JSONB column into dst, leaving it as is on NULL
+type jsonColumn struct {
+	dst interface{}
+}
+
+func (c jsonColumn) Scan(src interface{}) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T as JSON", src)
+	}
+	if err := json.Unmarshal(b, c.dst); err != nil {
+		return fmt.Errorf("failed to unmarshal %T: %w", c.dst, err)
+	}
+	return nil
+}
+
+// ensureRelease registers a release the first time an event is tracked with it
+func ensureRelease(ctx context.Context, tx *sql.Tx, projectID, version string) error {
+	if version == "" {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO releases (id, project_id, version, created_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (project_id, version) DO NOTHING`,
+		uuid.NewString(), projectID, version, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to register release: %w", err)
+	}
+	return nil
+}
+
+// isNewerRelease reports whether release was created after previous. It is
+// false when either is unknown.
+func isNewerRelease(ctx context.Context, tx *sql.Tx, projectID, release, previous string) (bool, error) {
+	if release == "" || previous == "" || release == previous {
+		return false, nil
+	}
+	var newer bool
+	err := tx.QueryRowContext(ctx, `
+        SELECT COALESCE((SELECT (r.created_at, r.id) > (p.created_at, p.id)
+            FROM releases r, releases p
+            WHERE r.project_id = $1 AND r.version = $2 AND p.project_id = $1 AND p.version = $3), false)`,
+		projectID, release, previous).Scan(&newer)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare releases: %w", err)
+	}
+	return newer, nil
+}
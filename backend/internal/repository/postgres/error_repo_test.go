@@ -0,0 +1,363 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"pulseguard/internal/grouping"
+	"pulseguard/internal/models"
+)
+
+// trackedEvents returns n events of one group, sampled at rate once the
+// group counts keepFirst events
+func trackedEvents(n int, weight int, rate float64, keepFirst int) []TrackedEvent {
+	events := make([]TrackedEvent, n)
+	for i := range events {
+		events[i] = TrackedEvent{Error: &models.Error{}, Weight: weight, SampleRate: rate, KeepFirst: keepFirst}
+	}
+	return events
+}
+
+func TestSampleEventsKeepFirst(t *testing.T) {
+	// Every draw samples the event out, so only the events kept regardless
+	// of the rate are stored
+	draw := func() float64 { return 0.99 }
+
+	tests := []struct {
+		name      string
+		count     int
+		keepFirst int
+		wantKept  int
+	}{
+		{"new group keeps its first events", 0, 5, 5},
+		{"group already past some of them", 3, 5, 2},
+		{"group past all of them", 8, 5, 0},
+		{"new group keeps its first event", 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, kept := sampleEvents(tt.count, trackedEvents(20, 0, 0.1, tt.keepFirst), draw)
+			for i, k := range kept {
+				if want := i < tt.wantKept; k != want {
+					t.Errorf("event %d kept = %v, want %v", i, k, want)
+				}
+			}
+			if got := counts[len(counts)-1]; got != tt.count+20 {
+				t.Errorf("count = %d, want %d", got, tt.count+20)
+			}
+		})
+	}
+
+	t.Run("unsampled events", func(t *testing.T) {
+		for _, rate := range []float64{0, 1} {
+			_, kept := sampleEvents(10, trackedEvents(20, 0, rate, 0), draw)
+			for i, k := range kept {
+				if !k {
+					t.Errorf("rate %v: event %d sampled out", rate, i)
+				}
+			}
+		}
+	})
+}
+
+func TestSampleEventsCounts(t *testing.T) {
+	draw := rand.New(rand.NewPCG(1, 2)).Float64
+
+	// Server-side sampled events count once each, kept or not
+	const n = 10000
+	counts, kept := sampleEvents(0, trackedEvents(n, 0, 0.1, 100), draw)
+	for i, count := range counts {
+		if count != i+1 {
+			t.Fatalf("count after event %d = %d, want %d", i, count, i+1)
+		}
+	}
+	stored := 0
+	for _, k := range kept {
+		if k {
+			stored++
+		}
+	}
+	// The first 100, then about a tenth of the others
+	if want := 100 + (n-100)/10; stored < want*9/10 || stored > want*11/10 {
+		t.Errorf("stored %d events, want about %d", stored, want)
+	}
+
+	// Client-sampled events count for their weight; weights of 0 count as 1
+	events := []TrackedEvent{{Weight: 4}, {Weight: 0}, {Weight: 3}, {Weight: 1}, {Weight: 10}}
+	for i := range events {
+		events[i].Error = &models.Error{}
+	}
+	counts, _ = sampleEvents(7, events, draw)
+	want := []int{11, 12, 15, 16, 26}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Errorf("counts = %v, want %v", counts, want)
+			break
+		}
+	}
+}
+
+func TestTrackBatchLegacyGroups(t *testing.T) {
+	const legacyID = "40000000-0000-0000-0000-000000000001"
+
+	db := openTestDB(t)
+	seedUsers(t, db)
+	seedProject(t, db, shopID, aliceID, "shop", "")
+	repo := NewErrorRepository(db)
+	ctx := context.Background()
+
+	// Two stacks of the same message share their legacy fingerprint
+	event := func(function string) *models.Error {
+		e := grouping.Event{
+			Type:    "TypeError",
+			Message: "x is undefined",
+			Source:  "app.js",
+			Frames:  []models.StackFrame{{Function: function, File: "src/app.js", Line: 1, InApp: true}},
+		}
+		return &models.Error{
+			ProjectID:         shopID,
+			Environment:       "production",
+			Type:              e.Type,
+			Message:           e.Message,
+			Source:            e.Source,
+			Frames:            e.Frames,
+			Fingerprint:       grouping.Fingerprint(grouping.DefaultComponents(e)),
+			LegacyFingerprint: grouping.LegacyFingerprint(e),
+			OccurredAt:        time.Now(),
+		}
+	}
+	render, save := event("render"), event("save")
+	if render.LegacyFingerprint != save.LegacyFingerprint || render.Fingerprint == save.Fingerprint {
+		t.Fatal("the stacks should differ by fingerprint only")
+	}
+
+	// A group opened before stack-aware grouping, registered under its
+	// legacy fingerprint as the merges migration left it
+	mustExec(t, db, `
+		INSERT INTO errors (id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count,
+			source, type, url, component_stack, browser_info, user_id, session_id, status)
+		VALUES ($1, $2, 'x is undefined', '', $3, now(), now(), 'production', 3, 'app.js', 'TypeError', '', '', '', '', '', 'ACTIVE')
+	`, legacyID, shopID, render.LegacyFingerprint)
+	mustExec(t, db, `INSERT INTO error_fingerprints (project_id, environment, fingerprint, error_id) VALUES ($1, 'production', $2, $3)`,
+		shopID, render.LegacyFingerprint, legacyID)
+
+	tests := []struct {
+		name       string
+		event      *models.Error
+		wantLegacy bool
+	}{
+		{"first stack adopts the legacy group", render, true},
+		{"another stack opens its own group", save, false},
+		{"the adopting stack stays in the legacy group", event("render"), true},
+		{"the other stack stays in its own group", event("save"), false},
+	}
+	var saveGroup string
+	for _, tt := range tests {
+		got, err := repo.Track(ctx, tt.event, nil)
+		if err != nil {
+			t.Fatalf("%s: Track() error = %v", tt.name, err)
+		}
+		if (got.ID == legacyID) != tt.wantLegacy {
+			t.Errorf("%s: tracked in %s, legacy group is %s", tt.name, got.ID, legacyID)
+		}
+		if tt.event.Fingerprint == save.Fingerprint {
+			if saveGroup != "" && got.ID != saveGroup {
+				t.Errorf("%s: tracked in %s, want %s", tt.name, got.ID, saveGroup)
+			}
+			saveGroup = got.ID
+		}
+	}
+
+	t.Run("merged legacy group", func(t *testing.T) {
+		// Once the legacy fingerprint routes to a group of another
+		// fingerprint, new stacks are not adopted by it
+		mustExec(t, db, `UPDATE error_fingerprints SET error_id = $1 WHERE fingerprint = $2`, saveGroup, render.LegacyFingerprint)
+		got, err := repo.Track(ctx, event("load"), nil)
+		if err != nil {
+			t.Fatalf("Track() error = %v", err)
+		}
+		if got.ID == legacyID || got.ID == saveGroup {
+			t.Errorf("tracked in %s, want a group of its own", got.ID)
+		}
+	})
+}
+
+// seedReleases inserts releases of the shop project. 1.10 is newer than 1.9
+// although it sorts before it, and 2.0-hotfix was created at the same time
+// as 2.0, after it by id.
+func seedReleases(t *testing.T, db *sql.DB) {
+	t.Helper()
+	now := time.Now()
+	mustExec(t, db, `INSERT INTO releases (id, project_id, version, created_at) VALUES
+		('50000000-0000-0000-0000-000000000001', $1, '1.9', $2),
+		('50000000-0000-0000-0000-000000000002', $1, '1.10', $3),
+		('50000000-0000-0000-0000-000000000003', $1, '2.0', $4),
+		('50000000-0000-0000-0000-000000000004', $1, '2.0-hotfix', $4)`,
+		shopID, now.Add(-3*time.Hour), now.Add(-2*time.Hour), now.Add(-time.Hour))
+}
+
+// seedGroup inserts a group of the shop project in production that counted
+// count events, last in release lastRelease, and returns its id
+func seedGroup(t *testing.T, db *sql.DB, fingerprint, status string, details *models.StatusDetails, count int, lastRelease string) string {
+	t.Helper()
+	var id string
+	err := db.QueryRow(`
+		INSERT INTO errors (id, project_id, message, stack_trace, fingerprint, occurred_at, last_seen, environment, count,
+			source, type, url, component_stack, browser_info, user_id, session_id, status, status_details, first_release, last_release)
+		VALUES (md5($2)::uuid, $1, 'failed', '', $2, now(), now(), 'production', $3, '', 'Error', '', '', '', '', '', $4, $5, $6, $6)
+		RETURNING id
+	`, shopID, fingerprint, count, status, statusDetailsJSON(details), lastRelease).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert group %s: %v", fingerprint, err)
+	}
+	mustExec(t, db, `INSERT INTO error_fingerprints (project_id, environment, fingerprint, error_id) VALUES ($1, 'production', $2, $3)`,
+		shopID, fingerprint, id)
+	return id
+}
+
+func TestIsNewerRelease(t *testing.T) {
+	db := openTestDB(t)
+	seedUsers(t, db)
+	seedProject(t, db, shopID, aliceID, "shop", "")
+	seedReleases(t, db)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	defer tx.Rollback()
+
+	tests := []struct {
+		release, previous string
+		want              bool
+	}{
+		{"1.10", "1.9", true},
+		{"1.9", "1.10", false},
+		{"2.0", "1.10", true},
+		{"2.0-hotfix", "2.0", true},
+		{"2.0", "2.0-hotfix", false},
+		{"1.9", "1.9", false},
+		{"3.0", "1.9", false},
+		{"1.9", "3.0", false},
+		{"", "1.9", false},
+		{"1.9", "", false},
+	}
+	for _, tt := range tests {
+		got, err := isNewerRelease(context.Background(), tx, shopID, tt.release, tt.previous)
+		if err != nil {
+			t.Fatalf("isNewerRelease(%q, %q) error = %v", tt.release, tt.previous, err)
+		}
+		if got != tt.want {
+			t.Errorf("isNewerRelease(%q, %q) = %v, want %v", tt.release, tt.previous, got, tt.want)
+		}
+	}
+}
+
+func TestStatusTransitions(t *testing.T) {
+	db := openTestDB(t)
+	seedUsers(t, db)
+	seedProject(t, db, shopID, aliceID, "shop", "")
+	seedReleases(t, db)
+	repo := NewErrorRepository(db)
+
+	now := time.Now()
+	changedAt := now.Add(-time.Hour)
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+	inNextRelease := &models.StatusDetails{InNextRelease: true, AfterRelease: "1.10", ChangedAt: changedAt}
+	ignoreCount := &models.StatusDetails{IgnoreCount: 3, ChangedAt: changedAt, CountAtChange: 10}
+	ignoreUsers := &models.StatusDetails{IgnoreUserCount: 2, ChangedAt: changedAt, CountAtChange: 10}
+
+	// An event is written as release/user; either may be empty
+	tests := []struct {
+		name        string
+		status      string
+		details     *models.StatusDetails
+		lastRelease string
+		events      []string
+		want        string
+	}{
+		{"active stays active", "ACTIVE", nil, "1.9", []string{"1.10/"}, "ACTIVE"},
+		{"regressed stays regressed", "REGRESSED", nil, "1.9", []string{"1.10/"}, "REGRESSED"},
+
+		{"resolved reopens without a release", "RESOLVED", nil, "1.10", []string{"/"}, "ACTIVE"},
+		{"resolved reopens in an older release", "RESOLVED", nil, "1.10", []string{"1.9/"}, "ACTIVE"},
+		{"resolved reopens in its last release", "RESOLVED", nil, "1.10", []string{"1.10/"}, "ACTIVE"},
+		{"resolved regresses in a newer release", "RESOLVED", nil, "1.9", []string{"1.10/"}, "REGRESSED"},
+		{"resolved regresses in a release of the same time", "RESOLVED", nil, "2.0", []string{"2.0-hotfix/"}, "REGRESSED"},
+		{"resolved without a last release reopens", "RESOLVED", nil, "", []string{"2.0/"}, "ACTIVE"},
+
+		{"in next release, seen in the current release", "RESOLVED", inNextRelease, "1.10", []string{"1.10/"}, "RESOLVED"},
+		{"in next release, seen in an older release", "RESOLVED", inNextRelease, "1.10", []string{"1.9/"}, "RESOLVED"},
+		{"in next release, seen without a release", "RESOLVED", inNextRelease, "1.10", []string{"/"}, "RESOLVED"},
+		{"in next release, seen in a newer release", "RESOLVED", inNextRelease, "1.10", []string{"2.0/"}, "REGRESSED"},
+
+		{"ignored for good", "IGNORED", nil, "", []string{"/", "/", "/"}, "IGNORED"},
+		{"ignored until later", "IGNORED", &models.StatusDetails{IgnoreUntil: &future, ChangedAt: changedAt}, "", []string{"/"}, "IGNORED"},
+		{"ignored until passed", "IGNORED", &models.StatusDetails{IgnoreUntil: &past, ChangedAt: changedAt}, "", []string{"/"}, "ACTIVE"},
+		{"ignored count not reached", "IGNORED", ignoreCount, "", []string{"/", "/"}, "IGNORED"},
+		{"ignored count reached", "IGNORED", ignoreCount, "", []string{"/", "/", "/"}, "ACTIVE"},
+		{"ignored users not reached", "IGNORED", ignoreUsers, "", []string{"/before", "/u1", "/u1"}, "IGNORED"},
+		{"ignored users reached", "IGNORED", ignoreUsers, "", []string{"/before", "/u1", "/u2"}, "ACTIVE"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fingerprint := fmt.Sprint("status-", i)
+			id := seedGroup(t, db, fingerprint, tt.status, tt.details, 10, tt.lastRelease)
+			// A user the group affected before it was ignored
+			mustExec(t, db, `INSERT INTO error_users (error_id, fingerprint, user_id, first_seen) VALUES ($1, $2, 'before', $3)`,
+				id, fingerprint, changedAt.Add(-time.Hour))
+
+			var events []TrackedEvent
+			for _, event := range tt.events {
+				release, userID, _ := strings.Cut(event, "/")
+				e := groupEvent(fingerprint, userID)
+				e.Release = release
+				events = append(events, TrackedEvent{Error: e})
+			}
+			got, err := repo.TrackBatch(context.Background(), events)
+			if err != nil {
+				t.Fatalf("TrackBatch() error = %v", err)
+			}
+			if got.ID != id || got.Status != tt.want {
+				t.Fatalf("TrackBatch() = %s %s, want %s %s", got.ID, got.Status, id, tt.want)
+			}
+
+			var status string
+			var details sql.NullString
+			var activity []string
+			if err := db.QueryRow(`SELECT status, status_details FROM errors WHERE id = $1`, id).Scan(&status, &details); err != nil {
+				t.Fatalf("read group: %v", err)
+			}
+			rows, err := db.Query(`SELECT type FROM error_activity WHERE error_id = $1 ORDER BY created_at`, id)
+			if err != nil {
+				t.Fatalf("read activity: %v", err)
+			}
+			for rows.Next() {
+				var activityType string
+				rows.Scan(&activityType)
+				activity = append(activity, activityType)
+			}
+			rows.Close()
+
+			if status != tt.want {
+				t.Errorf("stored status = %s, want %s", status, tt.want)
+			}
+			switch {
+			case tt.want == tt.status:
+				if len(activity) != 0 || details.Valid != (tt.details != nil) {
+					t.Errorf("unchanged status recorded %v, details %v", activity, details)
+				}
+			case details.Valid:
+				t.Errorf("status changed to %s but kept its details %s", status, details.String)
+			case tt.want == "REGRESSED" && fmt.Sprint(activity) != "["+models.ActivityRegressed+"]",
+				tt.want != "REGRESSED" && fmt.Sprint(activity) != "["+models.ActivityStatusChanged+"]":
+				t.Errorf("activity = %v for a change to %s", activity, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"pulseguard/internal/models"
+)
+
+const (
+	hourlyBuckets = 24
+	dailyBuckets  = 30
+)
+
+// eventRollups collects the histogram buckets and the users and sessions of
+// a batch of events of one fingerprint, so that they are written at once.
+// Rollups are kept per fingerprint so that they move with the fingerprints
+// when groups are merged and unmerged.
+type eventRollups struct {
+	fingerprint string
+	hourly      map[time.Time]int
+	users       map[string]time.Time
+	sessions    map[string]time.Time
+}
+
+func newEventRollups(fingerprint string) *eventRollups {
+	return &eventRollups{
+		fingerprint: fingerprint,
+		hourly:      make(map[time.Time]int),
+		users:       make(map[string]time.Time),
+		sessions:    make(map[string]time.Time),
+	}
+}
+
+// add counts weight events of a user and session at the given time
+func (r *eventRollups) add(userID, sessionID string, at time.Time, weight int) {
+	r.hourly[at.UTC().Truncate(time.Hour)] += weight
+	firstSeen(r.users, userID, at)
+	firstSeen(r.sessions, sessionID, at)
+}
+
+func firstSeen(seen map[string]time.Time, id string, at time.Time) {
+	if id == "" {
+		return
+	}
+	if first, ok := seen[id]; !ok || at.Before(first) {
+		seen[id] = at
+	}
+}
+
+// args returns the arguments $2 to $8 of rollupStatements
+func (r *eventRollups) args() []interface{} {
+	var buckets, users, userSeen, sessions, sessionSeen pq.StringArray
+	var counts pq.Int64Array
+	for bucket, count := range r.hourly {
+		buckets = append(buckets, bucket.Format(time.RFC3339Nano))
+		counts = append(counts, int64(count))
+	}
+	for id, at := range r.users {
+		users = append(users, id)
+		userSeen = append(userSeen, at.Format(time.RFC3339Nano))
+	}
+	for id, at := range r.sessions {
+		sessions = append(sessions, id)
+		sessionSeen = append(sessionSeen, at.Format(time.RFC3339Nano))
+	}
+	return []interface{}{r.fingerprint, buckets, counts, users, userSeen, sessions, sessionSeen}
+}
+
+// rollupStatements records the rollups of a batch of events of the group $1
+// as WITH queries, with the arguments of eventRollups.args. new_user and
+// new_session return the users and sessions new to the fingerprint; those
+// the group has not seen under another fingerprint either are added to its
+// counts by the statement, see newUserCount and newSessionCount.
+const rollupStatements = `
+        hourly AS (
+            INSERT INTO error_hourly_counts (error_id, fingerprint, bucket, count)
+            SELECT $1, $2, b.bucket, b.count FROM unnest($3::timestamptz[], $4::int[]) AS b(bucket, count)
+            ON CONFLICT (error_id, fingerprint, bucket) DO UPDATE SET count = error_hourly_counts.count + EXCLUDED.count
+        ), new_user AS (
+            INSERT INTO error_users (error_id, fingerprint, user_id, first_seen)
+            SELECT $1, $2, u.user_id, u.first_seen FROM unnest($5::text[], $6::timestamptz[]) AS u(user_id, first_seen)
+            ON CONFLICT DO NOTHING
+            RETURNING user_id
+        ), new_session AS (
+            INSERT INTO error_sessions (error_id, fingerprint, session_id, first_seen)
+            SELECT $1, $2, s.session_id, s.first_seen FROM unnest($7::text[], $8::timestamptz[]) AS s(session_id, first_seen)
+            ON CONFLICT DO NOTHING
+            RETURNING session_id
+        )`
+
+// newUserCount and newSessionCount count the rows of new_user and
+// new_session the group had no row for; the statement does not see the rows
+// its WITH queries insert
+const (
+	newUserCount = `(SELECT COUNT(*) FROM new_user n
+            WHERE NOT EXISTS (SELECT 1 FROM error_users u WHERE u.error_id = $1 AND u.user_id = n.user_id))`
+	newSessionCount = `(SELECT COUNT(*) FROM new_session n
+            WHERE NOT EXISTS (SELECT 1 FROM error_sessions s WHERE s.error_id = $1 AND s.session_id = n.session_id))`
+)
+
+// moveRollups moves the histogram, users and sessions of the fingerprints
+// from one group to another, or all of them when fingerprints is nil, and
+// returns the number of events they counted
+func moveRollups(ctx context.Context, tx *sql.Tx, fromID, toID string, fingerprints pq.StringArray) (int64, error) {
+	var moved int64
+	err := tx.QueryRowContext(ctx, `
+        WITH moved AS (
+            UPDATE error_hourly_counts SET error_id = $2
+            WHERE error_id = $1 AND ($3::text[] IS NULL OR fingerprint = ANY($3))
+            RETURNING count
+        )
+        SELECT COALESCE(SUM(count), 0) FROM moved`,
+		fromID, toID, fingerprints).Scan(&moved)
+	if err != nil {
+		return 0, fmt.Errorf("failed to move histogram: %w", err)
+	}
+
+	for _, table := range []string{"error_users", "error_sessions"} {
+		_, err := tx.ExecContext(ctx, `
+            UPDATE `+table+` SET error_id = $2
+            WHERE error_id = $1 AND ($3::text[] IS NULL OR fingerprint = ANY($3))`,
+			fromID, toID, fingerprints)
+		if err != nil {
+			return 0, fmt.Errorf("failed to move %s: %w", strings.TrimPrefix(table, "error_"), err)
+		}
+	}
+	return moved, nil
+}
+
+// rollupCount returns the number of events the group's histogram counted
+func rollupCount(ctx context.Context, tx *sql.Tx, errorID string) (int64, error) {
+	var count int64
+	err := tx.QueryRowContext(ctx, `
+        SELECT COALESCE(SUM(count), 0) FROM error_hourly_counts WHERE error_id = $1`, errorID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum histogram: %w", err)
+	}
+	return count, nil
+}
+
+// refreshAffectedCounts sets the group's user and session counts from its
+// rollups, after rollups moved between groups
+func refreshAffectedCounts(ctx context.Context, tx *sql.Tx, e *models.Error) error {
+	err := tx.QueryRowContext(ctx, `
+        UPDATE errors SET
+            user_count = (SELECT COUNT(DISTINCT user_id) FROM error_users WHERE error_id = $1),
+            session_count = (SELECT COUNT(DISTINCT session_id) FROM error_sessions WHERE error_id = $1)
+        WHERE id = $1
+        RETURNING user_count, session_count`, e.ID).Scan(&e.UserCount, &e.SessionCount)
+	if err != nil {
+		return fmt.Errorf("failed to update affected counts: %w", err)
+	}
+	return nil
+}
+
+// loadStats fills in the histograms of the errors with a single query
+func (r *ErrorRepository) loadStats(ctx context.Context, errs []*models.Error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	hourStart := now.Truncate(time.Hour).Add(-(hourlyBuckets - 1) * time.Hour)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -(dailyBuckets - 1))
+
+	byID := make(map[string]*models.Error, len(errs))
+	ids := make([]string, 0, len(errs))
+	for _, e := range errs {
+		e.Stats = &models.ErrorStats{
+			Hourly: make([]models.HistogramBucket, hourlyBuckets),
+			Daily:  make([]models.HistogramBucket, dailyBuckets),
+		}
+		for i := range e.Stats.Hourly {
+			e.Stats.Hourly[i].Time = hourStart.Add(time.Duration(i) * time.Hour)
+		}
+		for i := range e.Stats.Daily {
+			e.Stats.Daily[i].Time = dayStart.AddDate(0, 0, i)
+		}
+		byID[e.ID] = e
+		ids = append(ids, e.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT error_id, bucket, count FROM error_hourly_counts
+        WHERE error_id = ANY($1::uuid[]) AND bucket >= $2`,
+		pq.StringArray(ids), dayStart)
+	if err != nil {
+		return fmt.Errorf("failed to query histograms: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var errorID string
+		var bucket time.Time
+		var count int
+		if err := rows.Scan(&errorID, &bucket, &count); err != nil {
+			return fmt.Errorf("failed to scan histogram: %w", err)
+		}
+		stats := byID[errorID].Stats
+		bucket = bucket.UTC()
+		if i := int(bucket.Sub(hourStart) / time.Hour); i >= 0 && i < hourlyBuckets {
+			stats.Hourly[i].Count += count
+		}
+		if i := int(bucket.Sub(dayStart) / (24 * time.Hour)); i >= 0 && i < dailyBuckets {
+			stats.Daily[i].Count += count
+		}
+	}
+	return rows.Err()
+}
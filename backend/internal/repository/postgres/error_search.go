@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"pulseguard/internal/search"
+)
+
+// searchColumns are the text keys matched against a column of errors.
+// Those marked contains match anywhere in the column, the others the whole
+// value unless it has a wildcard.
+var searchColumns = map[string]struct {
+	column   string
+	contains bool
+}{
+	"message":       {"message", true},
+	"source":        {"source", true},
+	"url":           {"url", true},
+	"type":          {"type", false},
+	"environment":   {"environment", false},
+	"first_release": {"first_release", false},
+	"last_release":  {"last_release", false},
+	"fingerprint":   {"fingerprint", false},
+}
+
+var searchNumberColumns = map[string]string{
+	"times_seen": "count",
+	"users":      "user_count",
+	"sessions":   "session_count",
+}
+
+var searchDateColumns = map[string]string{
+	"age":        "occurred_at",
+	"first_seen": "occurred_at",
+	"last_seen":  "last_seen",
+}
+
+var searchIsConditions = map[string]string{
+	"unresolved":    "status IN ('ACTIVE', 'REGRESSED', 'INVESTIGATING')",
+	"resolved":      "status = 'RESOLVED'",
+	"ignored":       "status = 'IGNORED'",
+	"regressed":     "status = 'REGRESSED'",
+	"investigating": "status = 'INVESTIGATING'",
+	"active":        "status = 'ACTIVE'",
+	"assigned":      "(assignee_user_id IS NOT NULL OR assignee_team_id IS NOT NULL)",
+	"unassigned":    "(assignee_user_id IS NULL AND assignee_team_id IS NULL)",
+}
+
+// searchHasConditions are the has: values that check an attribute rather than a tag
+var searchHasConditions = map[string]string{
+	"release":  "last_release <> ''",
+	"user":     "user_count > 0",
+	"session":  "session_count > 0",
+	"assignee": "(assignee_user_id IS NOT NULL OR assignee_team_id IS NOT NULL)",
+}
+
+// searchSQL compiles search terms into conditions on the errors table,
+// adding their values to the query arguments with arg, which returns the
+// placeholder of the value
+func searchSQL(terms []search.Term, arg func(v interface{}) string) []string {
+	conds := make([]string, 0, len(terms))
+	for _, term := range terms {
+		var cond string
+		switch term.Kind {
+		case search.KindNumber:
+			cond = fmt.Sprintf("%s %s %s", searchNumberColumns[term.Key], term.Op, arg(term.Number))
+		case search.KindDate:
+			cond = fmt.Sprintf("%s %s %s", searchDateColumns[term.Key], term.Op, arg(term.Time))
+		default:
+			alternatives := make([]string, 0, len(term.Values))
+			for _, value := range term.Values {
+				alternatives = append(alternatives, "("+searchValueSQL(term, value, arg)+")")
+			}
+			cond = strings.Join(alternatives, " OR ")
+		}
+		if term.Negated {
+			// Conditions on NULL assignees are NULL, which NOT would not turn into true
+			cond = "NOT COALESCE((" + cond + "), false)"
+		} else {
+			cond = "(" + cond + ")"
+		}
+		conds = append(conds, cond)
+	}
+	return conds
+}
+
+func searchValueSQL(term search.Term, value string, arg func(v interface{}) string) string {
+	switch term.Kind {
+	case search.KindIs:
+		return searchIsConditions[value]
+
+	case search.KindHas:
+		if cond, ok := searchHasConditions[value]; ok {
+			return cond
+		}
+		return "EXISTS (SELECT 1 FROM error_tags t WHERE t.error_id = errors.id AND t.key = " + arg(value) + ")"
+
+	case search.KindTag:
+		return "EXISTS (SELECT 1 FROM error_tags t WHERE t.error_id = errors.id AND t.key = " + arg(term.Key) +
+			" AND " + textMatchSQL("t.value", value, false, arg) + ")"
+	}
+
+	switch term.Key {
+	case "":
+		p := arg(likePattern(value, true))
+		return fmt.Sprintf("message ILIKE %[1]s OR type ILIKE %[1]s OR source ILIKE %[1]s OR url ILIKE %[1]s", p)
+	case "status":
+		return "status = " + arg(strings.ToUpper(value))
+	case "release":
+		return "EXISTS (SELECT 1 FROM error_occurrences o WHERE o.error_id = errors.id AND " +
+			textMatchSQL("o.release", value, false, arg) + ")"
+	case "user":
+		return "EXISTS (SELECT 1 FROM error_users u WHERE u.error_id = errors.id AND " +
+			textMatchSQL("u.user_id", value, false, arg) + ")"
+	case "session":
+		return "EXISTS (SELECT 1 FROM error_sessions s WHERE s.error_id = errors.id AND " +
+			textMatchSQL("s.session_id", value, false, arg) + ")"
+	case "assigned":
+		// #slug is a team; anything else a user's id, email or name, or a team's id
+		if slug, ok := strings.CutPrefix(value, "#"); ok {
+			return "assignee_team_id IN (SELECT id FROM teams WHERE slug = " + arg(slug) + ")"
+		}
+		p := arg(value)
+		return fmt.Sprintf("assignee_user_id IN (SELECT id FROM users WHERE id::text = %[1]s OR email = %[1]s OR name = %[1]s)"+
+			" OR assignee_team_id::text = %[1]s", p)
+	}
+
+	col := searchColumns[term.Key]
+	return textMatchSQL(col.column, value, col.contains, arg)
+}
+
+// textMatchSQL matches a column against a value exactly, or
+// case-insensitively when the value has a * wildcard or contains is set, in
+// which case the value may appear anywhere in the column
+func textMatchSQL(column, value string, contains bool, arg func(v interface{}) string) string {
+	if !contains && !strings.Contains(value, "*") {
+		return column + " = " + arg(value)
+	}
+	return column + " ILIKE " + arg(likePattern(value, contains))
+}
+
+// likePattern escapes value for LIKE and turns its * wildcards into %
+func likePattern(value string, contains bool) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`).Replace(value)
+	if contains {
+		return "%" + escaped + "%"
+	}
+	return escaped
+}
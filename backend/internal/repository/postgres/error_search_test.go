@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"pulseguard/internal/search"
+)
+
+func TestSearchSQL(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tags := "EXISTS (SELECT 1 FROM error_tags t WHERE t.error_id = errors.id AND t.key = "
+
+	tests := []struct {
+		query string
+		conds []string
+		args  []interface{}
+	}{
+		{"", []string{}, nil},
+		{
+			"time_out*",
+			[]string{"((message ILIKE $1 OR type ILIKE $1 OR source ILIKE $1 OR url ILIKE $1))"},
+			[]interface{}{`%time\_out%%`},
+		},
+		{"message:100%", []string{"((message ILIKE $1))"}, []interface{}{`%100\%%`}},
+		{
+			"type:[TypeError,Range*]",
+			[]string{"((type = $1) OR (type ILIKE $2))"},
+			[]interface{}{"TypeError", "Range%"},
+		},
+		{"status:resolved", []string{"((status = $1))"}, []interface{}{"RESOLVED"}},
+		{"times_seen:>100", []string{"(count > $1)"}, []interface{}{int64(100)}},
+		{"users:<=3", []string{"(user_count <= $1)"}, []interface{}{int64(3)}},
+		{"age:-24h", []string{"(occurred_at >= $1)"}, []interface{}{now.Add(-24 * time.Hour)}},
+		{"is:unresolved", []string{"((status IN ('ACTIVE', 'REGRESSED', 'INVESTIGATING')))"}, nil},
+		{
+			"!is:assigned",
+			[]string{"NOT COALESCE((((assignee_user_id IS NOT NULL OR assignee_team_id IS NOT NULL))), false)"},
+			nil,
+		},
+		{"has:release", []string{"((last_release <> ''))"}, nil},
+		{"has:browser", []string{"((" + tags + "$1)))"}, []interface{}{"browser"}},
+		{
+			"browser:Chrome*",
+			[]string{"((" + tags + "$1 AND t.value ILIKE $2)))"},
+			[]interface{}{"browser", "Chrome%"},
+		},
+		{
+			"assigned:#backend",
+			[]string{"((assignee_team_id IN (SELECT id FROM teams WHERE slug = $1)))"},
+			[]interface{}{"backend"},
+		},
+		{
+			"user:42 release:1.0",
+			[]string{
+				"((EXISTS (SELECT 1 FROM error_users u WHERE u.error_id = errors.id AND u.user_id = $1)))",
+				"((EXISTS (SELECT 1 FROM error_occurrences o WHERE o.error_id = errors.id AND o.release = $2)))",
+			},
+			[]interface{}{"42", "1.0"},
+		},
+		// Values only ever reach the query as arguments
+		{
+			`message:"'); DROP TABLE errors; --"`,
+			[]string{"((message ILIKE $1))"},
+			[]interface{}{"%'); DROP TABLE errors; --%"},
+		},
+		{
+			`"$1" os:"x' OR 1=1"`,
+			[]string{
+				"((message ILIKE $1 OR type ILIKE $1 OR source ILIKE $1 OR url ILIKE $1))",
+				"((" + tags + "$2 AND t.value = $3)))",
+			},
+			[]interface{}{"%$1%", "os", "x' OR 1=1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			terms, err := search.Parse(tt.query, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			var args []interface{}
+			conds := searchSQL(terms, func(v interface{}) string {
+				args = append(args, v)
+				return "$" + strconv.Itoa(len(args))
+			})
+			if !reflect.DeepEqual(conds, tt.conds) {
+				t.Errorf("conditions =\n%s\nwant\n%s", strings.Join(conds, "\n"), strings.Join(tt.conds, "\n"))
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("args = %#v, want %#v", args, tt.args)
+			}
+		})
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	tests := []struct {
+		value    string
+		contains bool
+		want     string
+	}{
+		{"abc", false, "abc"},
+		{"abc", true, "%abc%"},
+		{"a*c", false, "a%c"},
+		{`50%_off\`, false, `50\%\_off\\`},
+		{"", true, "%%"},
+	}
+	for _, tt := range tests {
+		if got := likePattern(tt.value, tt.contains); got != tt.want {
+			t.Errorf("likePattern(%q, %v) = %q, want %q", tt.value, tt.contains, got, tt.want)
+		}
+	}
+}
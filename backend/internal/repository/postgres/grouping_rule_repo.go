@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pulseguard/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type GroupingRuleRepository struct {
+	db *sql.DB
+}
+
+func NewGroupingRuleRepository(db *sql.DB) *GroupingRuleRepository {
+	return &GroupingRuleRepository{db: db}
+}
+
+const groupingRuleColumns = `
+	id, project_id, name, COALESCE(match_type, ''), COALESCE(match_message, ''), COALESCE(match_frame, ''),
+	fingerprint, priority, enabled, COALESCE(created_by::text, ''), created_at, updated_at
+`
+
+// Create inserts a new grouping rule.
+func (repo *GroupingRuleRepository) Create(ctx context.Context, rule *models.GroupingRule) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO grouping_rules (id, project_id, name, match_type, match_message, match_frame,
+			fingerprint, priority, enabled, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		rule.ID,
+		rule.ProjectID,
+		rule.Name,
+		toNullString(rule.MatchType),
+		toNullString(rule.MatchMessage),
+		toNullString(rule.MatchFrame),
+		pq.StringArray(rule.Fingerprint),
+		rule.Priority,
+		rule.Enabled,
+		toNullString(rule.CreatedBy),
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	)
+	return err
+}
+
+// ListByProject returns the project's grouping rules in the order they are applied.
+func (repo *GroupingRuleRepository) ListByProject(ctx context.Context, projectID string) ([]*models.GroupingRule, error) {
+	return repo.list(ctx, `SELECT `+groupingRuleColumns+` FROM grouping_rules WHERE project_id = $1 ORDER BY priority, created_at`, projectID)
+}
+
+// ListEnabled returns the project's enabled grouping rules in the order they are applied.
+func (repo *GroupingRuleRepository) ListEnabled(ctx context.Context, projectID string) ([]*models.GroupingRule, error) {
+	return repo.list(ctx, `SELECT `+groupingRuleColumns+` FROM grouping_rules WHERE project_id = $1 AND enabled ORDER BY priority, created_at`, projectID)
+}
+
+// GetByID returns a grouping rule of the project.
+func (repo *GroupingRuleRepository) GetByID(ctx context.Context, projectID, id string) (*models.GroupingRule, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT `+groupingRuleColumns+` FROM grouping_rules WHERE project_id = $1 AND id = $2`, projectID, id)
+	return scanGroupingRule(row)
+}
+
+// Update saves the editable fields of a grouping rule.
+func (repo *GroupingRuleRepository) Update(ctx context.Context, rule *models.GroupingRule) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE grouping_rules
+		SET name = $3, match_type = $4, match_message = $5, match_frame = $6, fingerprint = $7,
+			priority = $8, enabled = $9, updated_at = $10
+		WHERE project_id = $1 AND id = $2
+	`,
+		rule.ProjectID,
+		rule.ID,
+		rule.Name,
+		toNullString(rule.MatchType),
+		toNullString(rule.MatchMessage),
+		toNullString(rule.MatchFrame),
+		pq.StringArray(rule.Fingerprint),
+		rule.Priority,
+		rule.Enabled,
+		rule.UpdatedAt,
+	)
+	return requireAffected(res, err)
+}
+
+// Delete removes a grouping rule of the project.
+func (repo *GroupingRuleRepository) Delete(ctx context.Context, projectID, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM grouping_rules WHERE project_id = $1 AND id = $2`, projectID, id)
+	return requireAffected(res, err)
+}
+
+func (repo *GroupingRuleRepository) list(ctx context.Context, query string, args ...any) ([]*models.GroupingRule, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*models.GroupingRule{}
+	for rows.Next() {
+		rule, err := scanGroupingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func scanGroupingRule(row rowScanner) (*models.GroupingRule, error) {
+	var rule models.GroupingRule
+	var fingerprint pq.StringArray
+	err := row.Scan(
+		&rule.ID,
+		&rule.ProjectID,
+		&rule.Name,
+		&rule.MatchType,
+		&rule.MatchMessage,
+		&rule.MatchFrame,
+		&fingerprint,
+		&rule.Priority,
+		&rule.Enabled,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rule.Fingerprint = fingerprint
+	return &rule, nil
+}
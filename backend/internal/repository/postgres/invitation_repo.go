@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+type InvitationRepository struct {
+	db *sql.DB
+}
+
+func NewInvitationRepository(db *sql.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+const invitationColumns = `
+	id, COALESCE(organization_id::text, ''), COALESCE(project_id::text, ''), email, role, token,
+	COALESCE(invited_by::text, ''), created_at, expires_at, accepted_at
+`
+
+// Create stores a new invitation.
+func (repo *InvitationRepository) Create(ctx context.Context, inv *models.Invitation) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO invitations (id, organization_id, project_id, email, role, token, invited_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		inv.ID,
+		toNullString(inv.OrganizationID),
+		toNullString(inv.ProjectID),
+		inv.Email,
+		inv.Role,
+		inv.Token,
+		toNullString(inv.InvitedBy),
+		inv.CreatedAt,
+		inv.ExpiresAt,
+	)
+	return err
+}
+
+// GetByToken retrieves an invitation by its secret token.
+func (repo *InvitationRepository) GetByToken(ctx context.Context, token string) (*models.Invitation, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT `+invitationColumns+` FROM invitations WHERE token = $1`, token)
+	return scanInvitation(row)
+}
+
+// ListPendingForOrganization retrieves the organization's unanswered invitations.
+func (repo *InvitationRepository) ListPendingForOrganization(ctx context.Context, organizationID string) ([]*models.Invitation, error) {
+	return repo.list(ctx, `SELECT `+invitationColumns+` FROM invitations
+		WHERE organization_id = $1 AND accepted_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC`, organizationID)
+}
+
+// ListPendingForProject retrieves the project's unanswered invitations.
+func (repo *InvitationRepository) ListPendingForProject(ctx context.Context, projectID string) ([]*models.Invitation, error) {
+	return repo.list(ctx, `SELECT `+invitationColumns+` FROM invitations
+		WHERE project_id = $1 AND accepted_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC`, projectID)
+}
+
+// MarkAccepted records that the invitation was accepted.
+func (repo *InvitationRepository) MarkAccepted(ctx context.Context, id string, acceptedAt time.Time) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE invitations SET accepted_at = $2
+		WHERE id = $1 AND accepted_at IS NULL
+	`, id, acceptedAt)
+	return requireAffected(res, err)
+}
+
+// Delete removes an invitation.
+func (repo *InvitationRepository) Delete(ctx context.Context, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM invitations WHERE id = $1`, id)
+	return requireAffected(res, err)
+}
+
+func (repo *InvitationRepository) list(ctx context.Context, query string, args ...any) ([]*models.Invitation, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.Invitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
+
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	var inv models.Invitation
+	var acceptedAt sql.NullTime
+	err := row.Scan(
+		&inv.ID,
+		&inv.OrganizationID,
+		&inv.ProjectID,
+		&inv.Email,
+		&inv.Role,
+		&inv.Token,
+		&inv.InvitedBy,
+		&inv.CreatedAt,
+		&inv.ExpiresAt,
+		&acceptedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if acceptedAt.Valid {
+		inv.AcceptedAt = &acceptedAt.Time
+	}
+	return &inv, nil
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pulseguard/internal/models"
+)
+
+// MembershipRepository stores who belongs to which organization, team and project.
+type MembershipRepository struct {
+	db *sql.DB
+}
+
+func NewMembershipRepository(db *sql.DB) *MembershipRepository {
+	return &MembershipRepository{db: db}
+}
+
+// OrganizationRole returns the user's role in the organization, or "" when
+// the user is not a member.
+func (repo *MembershipRepository) OrganizationRole(ctx context.Context, organizationID, userID string) (models.Role, error) {
+	var role models.Role
+	err := repo.db.QueryRowContext(ctx, `
+		SELECT role FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// AddOrganizationMember adds the user to the organization, or changes their
+// role if they already belong to it.
+func (repo *MembershipRepository) AddOrganizationMember(ctx context.Context, organizationID, userID string, role models.Role) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, organizationID, userID, role)
+	return err
+}
+
+// UpdateOrganizationMemberRole changes an existing member's role.
+func (repo *MembershipRepository) UpdateOrganizationMemberRole(ctx context.Context, organizationID, userID string, role models.Role) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE organization_members SET role = $3
+		WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID, role)
+	return requireAffected(res, err)
+}
+
+// RemoveOrganizationMember removes the user from the organization and its teams.
+func (repo *MembershipRepository) RemoveOrganizationMember(ctx context.Context, organizationID, userID string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`, organizationID, userID)
+	if err := requireAffected(res, err); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM team_members tm
+		USING teams t
+		WHERE tm.team_id = t.id AND t.organization_id = $1 AND tm.user_id = $2
+	`, organizationID, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListOrganizationMembers returns every member of the organization.
+func (repo *MembershipRepository) ListOrganizationMembers(ctx context.Context, organizationID string) ([]models.Member, error) {
+	return repo.listMembers(ctx, `
+		SELECT u.id, u.email, u.name, m.role, m.created_at
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.created_at
+	`, organizationID)
+}
+
+// CountOrganizationOwners returns how many owners the organization has.
+func (repo *MembershipRepository) CountOrganizationOwners(ctx context.Context, organizationID string) (int, error) {
+	var count int
+	err := repo.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM organization_members
+		WHERE organization_id = $1 AND role = 'owner'
+	`, organizationID).Scan(&count)
+	return count, err
+}
+
+// ProjectMemberRole returns the user's direct role on the project, or "" when
+// the user is not a direct member.
+func (repo *MembershipRepository) ProjectMemberRole(ctx context.Context, projectID, userID string) (models.Role, error) {
+	var role models.Role
+	err := repo.db.QueryRowContext(ctx, `
+		SELECT role FROM project_members
+		WHERE project_id = $1 AND user_id = $2
+	`, projectID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// AddProjectMember adds the user to the project, or changes their role if
+// they already belong to it.
+func (repo *MembershipRepository) AddProjectMember(ctx context.Context, projectID, userID string, role models.Role) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO project_members (project_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, projectID, userID, role)
+	return err
+}
+
+// UpdateProjectMemberRole changes an existing member's role.
+func (repo *MembershipRepository) UpdateProjectMemberRole(ctx context.Context, projectID, userID string, role models.Role) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE project_members SET role = $3
+		WHERE project_id = $1 AND user_id = $2
+	`, projectID, userID, role)
+	return requireAffected(res, err)
+}
+
+// RemoveProjectMember removes the user's direct membership of the project.
+func (repo *MembershipRepository) RemoveProjectMember(ctx context.Context, projectID, userID string) error {
+	res, err := repo.db.ExecContext(ctx, `
+		DELETE FROM project_members
+		WHERE project_id = $1 AND user_id = $2
+	`, projectID, userID)
+	return requireAffected(res, err)
+}
+
+// ListProjectMembers returns the project's direct members.
+func (repo *MembershipRepository) ListProjectMembers(ctx context.Context, projectID string) ([]models.Member, error) {
+	return repo.listMembers(ctx, `
+		SELECT u.id, u.email, u.name, m.role, m.created_at
+		FROM project_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.project_id = $1
+		ORDER BY m.created_at
+	`, projectID)
+}
+
+// GrantTeam gives every member of the team a role on the project.
+func (repo *MembershipRepository) GrantTeam(ctx context.Context, projectID, teamID string, role models.Role) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO project_teams (project_id, team_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (project_id, team_id) DO UPDATE SET role = EXCLUDED.role
+	`, projectID, teamID, role)
+	return err
+}
+
+// RevokeTeam removes the team's access to the project.
+func (repo *MembershipRepository) RevokeTeam(ctx context.Context, projectID, teamID string) error {
+	res, err := repo.db.ExecContext(ctx, `
+		DELETE FROM project_teams
+		WHERE project_id = $1 AND team_id = $2
+	`, projectID, teamID)
+	return requireAffected(res, err)
+}
+
+// ListProjectTeams returns the teams with access to the project.
+func (repo *MembershipRepository) ListProjectTeams(ctx context.Context, projectID string) ([]models.ProjectTeam, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT pt.project_id, t.id, t.name, t.slug, pt.role, pt.created_at
+		FROM project_teams pt
+		JOIN teams t ON t.id = pt.team_id
+		WHERE pt.project_id = $1
+		ORDER BY t.name
+	`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []models.ProjectTeam{}
+	for rows.Next() {
+		var t models.ProjectTeam
+		if err := rows.Scan(&t.ProjectID, &t.TeamID, &t.TeamName, &t.TeamSlug, &t.Role, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
+
+func (repo *MembershipRepository) listMembers(ctx context.Context, query string, args ...any) ([]models.Member, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.Member{}
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.UserID, &m.Email, &m.Name, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// requireAffected turns an update that matched no rows into sql.ErrNoRows.
+func requireAffected(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
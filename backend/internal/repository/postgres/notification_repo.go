@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+const channelColumns = `
+	id, project_id, name, type, COALESCE(url, ''), COALESCE(secret, ''), recipients, enabled,
+	COALESCE(created_by::text, ''), created_at, updated_at
+`
+
+const deliveryColumns = `
+	id, project_id, channel_id, COALESCE(alert_id::text, ''), event, payload, status, attempts,
+	COALESCE(last_error, ''), response_code, next_attempt_at, created_at, updated_at, delivered_at
+`
+
+// DeliveryFilters narrows ListDeliveries
+type DeliveryFilters struct {
+	ProjectID string
+	ChannelID string
+	AlertID   string
+	Status    string
+	Limit     int
+}
+
+// CreateChannel inserts a new notification channel.
+func (repo *NotificationRepository) CreateChannel(ctx context.Context, ch *models.NotificationChannel) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO notification_channels (id, project_id, name, type, url, secret, recipients, enabled, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		ch.ID,
+		ch.ProjectID,
+		ch.Name,
+		ch.Type,
+		toNullString(ch.URL),
+		toNullString(ch.Secret),
+		pq.StringArray(ch.Recipients),
+		ch.Enabled,
+		toNullString(ch.CreatedBy),
+		ch.CreatedAt,
+		ch.UpdatedAt,
+	)
+	return err
+}
+
+// ListChannels returns every channel of the project.
+func (repo *NotificationRepository) ListChannels(ctx context.Context, projectID string) ([]*models.NotificationChannel, error) {
+	return repo.listChannels(ctx, `SELECT `+channelColumns+` FROM notification_channels WHERE project_id = $1 ORDER BY created_at`, projectID)
+}
+
+// ListEnabledChannels returns the project's channels that should receive notifications.
+func (repo *NotificationRepository) ListEnabledChannels(ctx context.Context, projectID string) ([]*models.NotificationChannel, error) {
+	return repo.listChannels(ctx, `SELECT `+channelColumns+` FROM notification_channels WHERE project_id = $1 AND enabled ORDER BY created_at`, projectID)
+}
+
+// GetChannel returns a channel of the project.
+func (repo *NotificationRepository) GetChannel(ctx context.Context, projectID, id string) (*models.NotificationChannel, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT `+channelColumns+` FROM notification_channels WHERE project_id = $1 AND id = $2`, projectID, id)
+	return scanChannel(row)
+}
+
+// UpdateChannel saves the editable fields of a channel.
+func (repo *NotificationRepository) UpdateChannel(ctx context.Context, ch *models.NotificationChannel) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE notification_channels
+		SET name = $3, url = $4, secret = $5, recipients = $6, enabled = $7, updated_at = $8
+		WHERE project_id = $1 AND id = $2
+	`,
+		ch.ProjectID,
+		ch.ID,
+		ch.Name,
+		toNullString(ch.URL),
+		toNullString(ch.Secret),
+		pq.StringArray(ch.Recipients),
+		ch.Enabled,
+		ch.UpdatedAt,
+	)
+	return requireAffected(res, err)
+}
+
+// DeleteChannel removes a channel and its delivery log.
+func (repo *NotificationRepository) DeleteChannel(ctx context.Context, projectID, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM notification_channels WHERE project_id = $1 AND id = $2`, projectID, id)
+	return requireAffected(res, err)
+}
+
+// CreateDelivery queues a notification for a channel.
+func (repo *NotificationRepository) CreateDelivery(ctx context.Context, d *models.NotificationDelivery) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO notification_deliveries (id, project_id, channel_id, alert_id, event, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`,
+		d.ID,
+		d.ProjectID,
+		d.ChannelID,
+		toNullString(d.AlertID),
+		d.Event,
+		d.Payload,
+		d.Status,
+		d.NextAttemptAt,
+		d.CreatedAt,
+	)
+	return err
+}
+
+// ClaimDueDeliveries marks up to limit deliveries that are due as sending and
+// returns them. Deliveries stuck in sending for longer than staleAfter (for
+// example after a crash) are claimed again.
+func (repo *NotificationRepository) ClaimDueDeliveries(ctx context.Context, limit int, staleAfter time.Duration) ([]*models.NotificationDelivery, error) {
+	return repo.listDeliveries(ctx, `
+		UPDATE notification_deliveries
+		SET status = 'sending', attempts = attempts + 1, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM notification_deliveries
+			WHERE (status IN ('pending', 'retrying') AND next_attempt_at <= NOW())
+				OR (status = 'sending' AND updated_at <= NOW() - $2 * INTERVAL '1 millisecond')
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns, limit, staleAfter.Milliseconds())
+}
+
+// MarkDelivered records a successful delivery.
+func (repo *NotificationRepository) MarkDelivered(ctx context.Context, id string, deliveredAt time.Time) error {
+	_, err := repo.db.ExecContext(ctx, `
+		UPDATE notification_deliveries
+		SET status = 'delivered', delivered_at = $2, last_error = NULL, response_code = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, id, deliveredAt)
+	return err
+}
+
+// MarkAttemptFailed records a failed attempt. The delivery is retried at
+// nextAttemptAt, or given up on when nextAttemptAt is nil.
+func (repo *NotificationRepository) MarkAttemptFailed(ctx context.Context, id, lastError string, responseCode *int, nextAttemptAt *time.Time) error {
+	status := models.DeliveryFailed
+	next := time.Now()
+	if nextAttemptAt != nil {
+		status = models.DeliveryRetrying
+		next = *nextAttemptAt
+	}
+
+	_, err := repo.db.ExecContext(ctx, `
+		UPDATE notification_deliveries
+		SET status = $2, last_error = $3, response_code = $4, next_attempt_at = $5, updated_at = NOW()
+		WHERE id = $1
+	`, id, status, lastError, responseCode, next)
+	return err
+}
+
+// ListDeliveries returns the delivery log, newest first.
+func (repo *NotificationRepository) ListDeliveries(ctx context.Context, filters DeliveryFilters) ([]*models.NotificationDelivery, error) {
+	conditions := []string{"project_id = $1"}
+	args := []any{filters.ProjectID}
+
+	if filters.ChannelID != "" {
+		args = append(args, filters.ChannelID)
+		conditions = append(conditions, fmt.Sprintf("channel_id::text = $%d", len(args)))
+	}
+	if filters.AlertID != "" {
+		args = append(args, filters.AlertID)
+		conditions = append(conditions, fmt.Sprintf("alert_id::text = $%d", len(args)))
+	}
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	limit := filters.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	args = append(args, limit)
+
+	query := `SELECT ` + deliveryColumns + ` FROM notification_deliveries
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at DESC
+		LIMIT $` + fmt.Sprint(len(args))
+
+	return repo.listDeliveries(ctx, query, args...)
+}
+
+func (repo *NotificationRepository) listChannels(ctx context.Context, query string, args ...any) ([]*models.NotificationChannel, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []*models.NotificationChannel{}
+	for rows.Next() {
+		ch, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+	return channels, rows.Err()
+}
+
+func (repo *NotificationRepository) listDeliveries(ctx context.Context, query string, args ...any) ([]*models.NotificationDelivery, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.NotificationDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanChannel(row rowScanner) (*models.NotificationChannel, error) {
+	var ch models.NotificationChannel
+	var recipients pq.StringArray
+	err := row.Scan(
+		&ch.ID,
+		&ch.ProjectID,
+		&ch.Name,
+		&ch.Type,
+		&ch.URL,
+		&ch.Secret,
+		&recipients,
+		&ch.Enabled,
+		&ch.CreatedBy,
+		&ch.CreatedAt,
+		&ch.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	ch.Recipients = []string(recipients)
+	return &ch, nil
+}
+
+func scanDelivery(row rowScanner) (*models.NotificationDelivery, error) {
+	var d models.NotificationDelivery
+	var responseCode sql.NullInt64
+	var deliveredAt sql.NullTime
+	err := row.Scan(
+		&d.ID,
+		&d.ProjectID,
+		&d.ChannelID,
+		&d.AlertID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.LastError,
+		&responseCode,
+		&d.NextAttemptAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&deliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if responseCode.Valid {
+		code := int(responseCode.Int64)
+		d.ResponseCode = &code
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pulseguard/internal/models"
+)
+
+type OrganizationRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepository(db *sql.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// Create inserts a new organization and makes its creator the owner.
+func (repo *OrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organizations (id, name, slug, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, org.ID, org.Name, org.Slug, org.CreatedBy, org.CreatedAt, org.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, 'owner')
+	`, org.ID, org.CreatedBy)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListForUser retrieves the organizations the user belongs to, with the user's role.
+func (repo *OrganizationRepository) ListForUser(ctx context.Context, userID string) ([]*models.Organization, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT o.id, o.name, o.slug, COALESCE(o.created_by::text, ''), o.created_at, o.updated_at, m.role
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []*models.Organization
+	for rows.Next() {
+		var o models.Organization
+		if err := rows.Scan(&o.ID, &o.Name, &o.Slug, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt, &o.Role); err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, &o)
+	}
+	return orgs, rows.Err()
+}
+
+// GetBySlugForUser retrieves an organization by slug together with the user's
+// role in it. Role is empty when the user is not a member.
+func (repo *OrganizationRepository) GetBySlugForUser(ctx context.Context, slug, userID string) (*models.Organization, error) {
+	var o models.Organization
+	err := repo.db.QueryRowContext(ctx, `
+		SELECT o.id, o.name, o.slug, COALESCE(o.created_by::text, ''), o.created_at, o.updated_at, COALESCE(m.role, '')
+		FROM organizations o
+		LEFT JOIN organization_members m ON m.organization_id = o.id AND m.user_id = $2
+		WHERE o.slug = $1
+	`, slug, userID).Scan(&o.ID, &o.Name, &o.Slug, &o.CreatedBy, &o.CreatedAt, &o.UpdatedAt, &o.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Delete removes the organization. Its projects are kept and become personal
+// projects of their owners.
+func (repo *OrganizationRepository) Delete(ctx context.Context, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM organizations WHERE id = $1`, id)
+	return requireAffected(res, err)
+}
+
+// CreateTeam inserts a new team in an organization.
+func (repo *OrganizationRepository) CreateTeam(ctx context.Context, team *models.Team) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO teams (id, organization_id, name, slug, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, team.ID, team.OrganizationID, team.Name, team.Slug, team.CreatedAt)
+	return err
+}
+
+// ListTeams retrieves the organization's teams with their members.
+func (repo *OrganizationRepository) ListTeams(ctx context.Context, organizationID string) ([]*models.Team, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT id, organization_id, name, slug, created_at
+		FROM teams
+		WHERE organization_id = $1
+		ORDER BY name
+	`, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*models.Team
+	byID := map[string]*models.Team{}
+	for rows.Next() {
+		var t models.Team
+		if err := rows.Scan(&t.ID, &t.OrganizationID, &t.Name, &t.Slug, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		t.Members = []models.Member{}
+		teams = append(teams, &t)
+		byID[t.ID] = &t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	memberRows, err := repo.db.QueryContext(ctx, `
+		SELECT tm.team_id, u.id, u.email, u.name, COALESCE(om.role, 'viewer'), tm.created_at
+		FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		JOIN users u ON u.id = tm.user_id
+		LEFT JOIN organization_members om ON om.organization_id = t.organization_id AND om.user_id = tm.user_id
+		WHERE t.organization_id = $1
+		ORDER BY tm.created_at
+	`, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var teamID string
+		var m models.Member
+		if err := memberRows.Scan(&teamID, &m.UserID, &m.Email, &m.Name, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if t, ok := byID[teamID]; ok {
+			t.Members = append(t.Members, m)
+		}
+	}
+
+	return teams, memberRows.Err()
+}
+
+// GetTeamBySlug retrieves a team of the organization by its slug.
+func (repo *OrganizationRepository) GetTeamBySlug(ctx context.Context, organizationID, slug string) (*models.Team, error) {
+	var t models.Team
+	err := repo.db.QueryRowContext(ctx, `
+		SELECT id, organization_id, name, slug, created_at
+		FROM teams
+		WHERE organization_id = $1 AND slug = $2
+	`, organizationID, slug).Scan(&t.ID, &t.OrganizationID, &t.Name, &t.Slug, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTeamByID retrieves a team by its ID.
+func (repo *OrganizationRepository) GetTeamByID(ctx context.Context, id string) (*models.Team, error) {
+	var t models.Team
+	err := repo.db.QueryRowContext(ctx, `
+		SELECT id, organization_id, name, slug, created_at
+		FROM teams
+		WHERE id = $1
+	`, id).Scan(&t.ID, &t.OrganizationID, &t.Name, &t.Slug, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeleteTeam removes a team and every project grant it held.
+func (repo *OrganizationRepository) DeleteTeam(ctx context.Context, teamID string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM teams WHERE id = $1`, teamID)
+	return requireAffected(res, err)
+}
+
+// AddTeamMember adds a user to a team.
+func (repo *OrganizationRepository) AddTeamMember(ctx context.Context, teamID, userID string) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, teamID, userID)
+	return err
+}
+
+// RemoveTeamMember removes a user from a team.
+func (repo *OrganizationRepository) RemoveTeamMember(ctx context.Context, teamID, userID string) error {
+	res, err := repo.db.ExecContext(ctx, `
+		DELETE FROM team_members WHERE team_id = $1 AND user_id = $2
+	`, teamID, userID)
+	return requireAffected(res, err)
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+type ProjectKeyRepository struct {
+	db *sql.DB
+}
+
+func NewProjectKeyRepository(db *sql.DB) *ProjectKeyRepository {
+	return &ProjectKeyRepository{db: db}
+}
+
+const projectKeyColumns = `id, project_id, name, public_key, rate_limit, COALESCE(created_by::text, ''), created_at, last_used_at, revoked_at`
+
+// Create inserts a new ingest key for a project.
+func (repo *ProjectKeyRepository) Create(ctx context.Context, key *models.ProjectKey) error {
+	query := `
+		INSERT INTO project_keys (id, project_id, name, public_key, rate_limit, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := repo.db.ExecContext(ctx, query,
+		key.ID,
+		key.ProjectID,
+		key.Name,
+		key.PublicKey,
+		key.RateLimit,
+		toNullString(key.CreatedBy),
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert project key: %w", err)
+	}
+	return nil
+}
+
+// ListByProject returns every key of a project, including revoked ones.
+func (repo *ProjectKeyRepository) ListByProject(ctx context.Context, projectID string) ([]*models.ProjectKey, error) {
+	query := `SELECT ` + projectKeyColumns + `
+		FROM project_keys
+		WHERE project_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := repo.db.QueryContext(ctx, query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*models.ProjectKey, 0)
+	for rows.Next() {
+		k, err := scanProjectKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// GetByID returns a key belonging to the given project.
+func (repo *ProjectKeyRepository) GetByID(ctx context.Context, projectID, id string) (*models.ProjectKey, error) {
+	query := `SELECT ` + projectKeyColumns + `
+		FROM project_keys
+		WHERE project_id = $1 AND id = $2`
+
+	return scanProjectKey(repo.db.QueryRowContext(ctx, query, projectID, id))
+}
+
+// GetActiveByPublicKey looks up a key that has not been revoked.
+func (repo *ProjectKeyRepository) GetActiveByPublicKey(ctx context.Context, publicKey string) (*models.ProjectKey, error) {
+	query := `SELECT ` + projectKeyColumns + `
+		FROM project_keys
+		WHERE public_key = $1 AND revoked_at IS NULL`
+
+	return scanProjectKey(repo.db.QueryRowContext(ctx, query, publicKey))
+}
+
+// Rotate replaces the public key of an active key.
+func (repo *ProjectKeyRepository) Rotate(ctx context.Context, projectID, id, publicKey string) (*models.ProjectKey, error) {
+	query := `
+		UPDATE project_keys
+		SET public_key = $1, last_used_at = NULL
+		WHERE project_id = $2 AND id = $3 AND revoked_at IS NULL
+		RETURNING ` + projectKeyColumns
+
+	return scanProjectKey(repo.db.QueryRowContext(ctx, query, publicKey, projectID, id))
+}
+
+// Revoke marks a key as revoked so it can no longer authenticate ingestion.
+func (repo *ProjectKeyRepository) Revoke(ctx context.Context, projectID, id string) (*models.ProjectKey, error) {
+	query := `
+		UPDATE project_keys
+		SET revoked_at = COALESCE(revoked_at, $1)
+		WHERE project_id = $2 AND id = $3
+		RETURNING ` + projectKeyColumns
+
+	return scanProjectKey(repo.db.QueryRowContext(ctx, query, time.Now(), projectID, id))
+}
+
+// UpdateRateLimit sets the events per minute a key may send.
+func (repo *ProjectKeyRepository) UpdateRateLimit(ctx context.Context, projectID, id string, rateLimit int) (*models.ProjectKey, error) {
+	query := `
+		UPDATE project_keys
+		SET rate_limit = $1
+		WHERE project_id = $2 AND id = $3
+		RETURNING ` + projectKeyColumns
+
+	return scanProjectKey(repo.db.QueryRowContext(ctx, query, rateLimit, projectID, id))
+}
+
+// TouchLastUsed records when a key was last used for ingestion.
+func (repo *ProjectKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	_, err := repo.db.ExecContext(ctx, `UPDATE project_keys SET last_used_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProjectKey(row rowScanner) (*models.ProjectKey, error) {
+	var k models.ProjectKey
+	var lastUsedAt, revokedAt sql.NullTime
+	if err := row.Scan(&k.ID, &k.ProjectID, &k.Name, &k.PublicKey, &k.RateLimit, &k.CreatedBy, &k.CreatedAt, &lastUsedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return &k, nil
+}
@@ -9,6 +9,9 @@ import (
 	"strings"
 )
 
+// ErrAmbiguousProject is returned when a slug matches more than one project
+var ErrAmbiguousProject = errors.New("project slug is ambiguous")
+
 type ProjectRepository struct {
 	db *sql.DB
 }
@@ -17,18 +20,42 @@ func NewProjectRepository(db *sql.DB) *ProjectRepository {
 	return &ProjectRepository{db: db}
 }
 
-// Create inserts a new project into the database.
+// effectiveRoleSQL selects the strongest role user $1 holds on project p,
+// whether through ownership, direct membership, the project's organization
+// or one of the user's teams. It is NULL when the user has no access.
+const effectiveRoleSQL = `(
+	SELECT r.role FROM (
+		SELECT 'owner' AS role WHERE p.owner_id = $1
+		UNION ALL
+		SELECT pm.role FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = $1
+		UNION ALL
+		SELECT om.role FROM organization_members om WHERE om.organization_id = p.organization_id AND om.user_id = $1
+		UNION ALL
+		SELECT pt.role FROM project_teams pt JOIN team_members tm ON tm.team_id = pt.team_id WHERE pt.project_id = p.id AND tm.user_id = $1
+	) r
+	ORDER BY CASE r.role WHEN 'owner' THEN 4 WHEN 'admin' THEN 3 WHEN 'member' THEN 2 ELSE 1 END DESC
+	LIMIT 1
+)`
+
+// Create inserts a new project into the database and records its owner as a member.
 func (repo *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-        INSERT INTO projects (id, name, slug, description, owner_id, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO projects (id, name, slug, description, owner_id, organization_id, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
     `
-	_, err := repo.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		project.ID,
 		project.Name,
 		project.Slug,
 		project.Description,
 		project.OwnerID,
+		toNullString(project.OrganizationID),
 		project.CreatedAt,
 		project.UpdatedAt,
 	)
@@ -40,21 +67,31 @@ func (repo *ProjectRepository) Create(ctx context.Context, project *models.Proje
 		return err
 	}
 
-	return err
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO project_members (project_id, user_id, role)
+		VALUES ($1, $2, 'owner')
+	`, project.ID, project.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// ListByOwner retrieves all projects owned by the specified owner ID.
-func (repo *ProjectRepository) ListByOwner(ctx context.Context, ownerID string) ([]*models.Project, error) {
+// ListForUser retrieves all projects the user can access, with the user's role on each.
+func (repo *ProjectRepository) ListForUser(ctx context.Context, userID string) ([]*models.Project, error) {
 	query := `
-		SELECT p.id, p.name, p.slug, p.description, p.owner_id, p.created_at, p.updated_at, COUNT(e.id) AS error_count
+		SELECT p.id, p.name, p.slug, p.description, p.owner_id, COALESCE(p.organization_id::text, ''),
+			p.created_at, p.updated_at, COUNT(e.id) AS error_count, pr.role
 		FROM projects p
+		CROSS JOIN LATERAL (SELECT ` + effectiveRoleSQL + ` AS role) pr
 		LEFT JOIN errors e ON p.id = e.project_id
-		WHERE p.owner_id = $1
-		GROUP BY p.id
+		WHERE pr.role IS NOT NULL
+		GROUP BY p.id, pr.role
 		ORDER BY p.created_at DESC;
 	`
 
-	rows, err := repo.db.QueryContext(ctx, query, ownerID)
+	rows, err := repo.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -63,19 +100,21 @@ func (repo *ProjectRepository) ListByOwner(ctx context.Context, ownerID string)
 	var projects []*models.Project
 	for rows.Next() {
 		var p models.Project
-		if err := rows.Scan(&p.ID, &p.Name, &p.Slug, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt, &p.ErrorCount); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Slug, &p.Description, &p.OwnerID, &p.OrganizationID,
+			&p.CreatedAt, &p.UpdatedAt, &p.ErrorCount, &p.Role); err != nil {
 			return nil, err
 		}
 		projects = append(projects, &p)
 	}
 
-	return projects, nil
+	return projects, rows.Err()
 }
 
 // GetBySlug retrieves a project by its slug from the database.
 func (repo *ProjectRepository) GetBySlug(ctx context.Context, slug string) (*models.Project, error) {
 	query := `
-		SELECT p.id, p.name, p.slug, p.description, p.owner_id, p.created_at, p.updated_at, COUNT(e.id) as error_count
+		SELECT p.id, p.name, p.slug, p.description, p.owner_id, COALESCE(p.organization_id::text, ''),
+			p.created_at, p.updated_at, COUNT(e.id) as error_count
 		FROM projects p
 		LEFT JOIN errors e ON p.id = e.project_id
 		WHERE p.slug = $1
@@ -88,6 +127,7 @@ func (repo *ProjectRepository) GetBySlug(ctx context.Context, slug string) (*mod
 		&p.Slug,
 		&p.Description,
 		&p.OwnerID,
+		&p.OrganizationID,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 		&p.ErrorCount,
@@ -98,72 +138,150 @@ func (repo *ProjectRepository) GetBySlug(ctx context.Context, slug string) (*mod
 	return &p, nil
 }
 
-// DeleteBySlug deletes a project by its slug from the database.
-func (repo *ProjectRepository) DeleteBySlug(ctx context.Context, slug string) (*models.Project, error) {
-    // First, select the project to return it after deletion
-    query := `
-        SELECT p.id, p.name, p.slug, p.description, p.owner_id, p.created_at, p.updated_at, COUNT(e.id) as error_count
-        FROM projects p
-        LEFT JOIN errors e ON p.id = e.project_id
-        WHERE p.slug = $1
-        GROUP BY p.id
-    `
-    var p models.Project
-    err := repo.db.QueryRowContext(ctx, query, slug).Scan(
-        &p.ID,
-        &p.Name,
-        &p.Slug,
-        &p.Description,
-        &p.OwnerID,
-        &p.CreatedAt,
-        &p.UpdatedAt,
-        &p.ErrorCount,
-    )
-    if err != nil {
-        if errors.Is(err, sql.ErrNoRows) {
-            return nil, fmt.Errorf("project with slug %s not found", slug)
-        }
-        return nil, err
-    }
-
-    // Now delete the project
-    deleteQuery := `
-        DELETE FROM projects
-        WHERE slug = $1
-    `
-    result, err := repo.db.ExecContext(ctx, deleteQuery, slug)
-    if err != nil {
-        return nil, err
-    }
-
-    // Optional: Check if any rows were affected
-    rowsAffected, err := result.RowsAffected()
-    if err != nil {
-        return nil, err
-    }
-    if rowsAffected == 0 {
-        return nil, fmt.Errorf("no project deleted with slug %s", slug)
-    }
-
-    return &p, nil
+// LookupID resolves a project ID or slug to the project's ID. It returns
+// sql.ErrNoRows when nothing matches and ErrAmbiguousProject when a slug is
+// shared by several projects.
+func (repo *ProjectRepository) LookupID(ctx context.Context, ref string) (string, error) {
+	rows, err := repo.db.QueryContext(ctx, `
+		SELECT id::text FROM projects
+		WHERE id::text = $1 OR slug = $1
+		ORDER BY (id::text = $1) DESC
+		LIMIT 2
+	`, ref)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return "", err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(ids) == 0:
+		return "", sql.ErrNoRows
+	case len(ids) > 1 && ids[0] != ref:
+		return "", ErrAmbiguousProject
+	}
+	return ids[0], nil
+}
+
+// GetByIDForUser retrieves a project by its ID together with the user's role
+// on it. Role is empty when the user has no access. It runs on every
+// project-scoped request, so ErrorCount is left unset; see CountErrors.
+func (repo *ProjectRepository) GetByIDForUser(ctx context.Context, id, userID string) (*models.Project, error) {
+	query := `
+		SELECT p.id, p.name, p.slug, p.description, p.owner_id, COALESCE(p.organization_id::text, ''),
+			p.created_at, p.updated_at, COALESCE(pr.role, '')
+		FROM projects p
+		CROSS JOIN LATERAL (SELECT ` + effectiveRoleSQL + ` AS role) pr
+		WHERE p.id = $2
+	`
+	var p models.Project
+	err := repo.db.QueryRowContext(ctx, query, userID, id).Scan(
+		&p.ID,
+		&p.Name,
+		&p.Slug,
+		&p.Description,
+		&p.OwnerID,
+		&p.OrganizationID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&p.Role,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetBySlugForUser retrieves a project by slug together with the user's role
+// on it, preferring a project the user can access when several share the slug.
+// As with GetByIDForUser, ErrorCount is left unset.
+func (repo *ProjectRepository) GetBySlugForUser(ctx context.Context, slug, userID string) (*models.Project, error) {
+	query := `
+		SELECT p.id, p.name, p.slug, p.description, p.owner_id, COALESCE(p.organization_id::text, ''),
+			p.created_at, p.updated_at, COALESCE(pr.role, '')
+		FROM projects p
+		CROSS JOIN LATERAL (SELECT ` + effectiveRoleSQL + ` AS role) pr
+		WHERE p.slug = $2
+		ORDER BY (pr.role IS NOT NULL) DESC
+		LIMIT 1
+	`
+	var p models.Project
+	err := repo.db.QueryRowContext(ctx, query, userID, slug).Scan(
+		&p.ID,
+		&p.Name,
+		&p.Slug,
+		&p.Description,
+		&p.OwnerID,
+		&p.OrganizationID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&p.Role,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CountErrors returns the number of error groups of the project.
+func (repo *ProjectRepository) CountErrors(ctx context.Context, projectID string) (int, error) {
+	var count int
+	err := repo.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM errors WHERE project_id = $1`, projectID).Scan(&count)
+	return count, err
+}
+
+// DeleteByID deletes a project by its ID from the database.
+func (repo *ProjectRepository) DeleteByID(ctx context.Context, id string) (*models.Project, error) {
+	query := `
+		DELETE FROM projects
+		WHERE id = $1
+		RETURNING id, name, slug, description, owner_id, COALESCE(organization_id::text, ''), created_at, updated_at
+	`
+	var p models.Project
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(
+		&p.ID,
+		&p.Name,
+		&p.Slug,
+		&p.Description,
+		&p.OwnerID,
+		&p.OrganizationID,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
 }
 
 // UpdateProject updates an existing project in the database.
-func (repo *ProjectRepository) UpdateProject(ctx context.Context, oldSlug string, project *models.Project) (*models.Project, error) {
+func (repo *ProjectRepository) UpdateProject(ctx context.Context, project *models.Project) (*models.Project, error) {
 	query := `
 		UPDATE projects
 		SET name = $1, slug = $2, description = $3, updated_at = NOW()
-		WHERE slug = $4
-		RETURNING id, name, slug, description, owner_id, created_at, updated_at
+		WHERE id = $4
+		RETURNING id, name, slug, description, owner_id, COALESCE(organization_id::text, ''), created_at, updated_at
 	`
 
 	var p models.Project
-	err := repo.db.QueryRowContext(ctx, query, project.Name, project.Slug, project.Description, oldSlug).Scan(
+	err := repo.db.QueryRowContext(ctx, query, project.Name, project.Slug, project.Description, project.ID).Scan(
 		&p.ID,
 		&p.Name,
 		&p.Slug,
 		&p.Description,
 		&p.OwnerID,
+		&p.OrganizationID,
 		&p.CreatedAt,
 		&p.UpdatedAt,
 	)
@@ -177,11 +295,12 @@ func (repo *ProjectRepository) UpdateProject(ctx context.Context, oldSlug string
 	return &p, nil
 }
 
-// DeleteAllByOwner deletes all projects owned by the specified owner ID.
+// DeleteAllByOwner deletes all personal projects owned by the specified owner ID.
+// Projects that belong to an organization are left for the organization's admins.
 func (repo *ProjectRepository) DeleteAllByOwner(ctx context.Context, ownerID string) ([]*models.Project, error) {
 	query := `
 		DELETE FROM projects
-		WHERE owner_id = $1
+		WHERE owner_id = $1 AND organization_id IS NULL
 		RETURNING id, name, slug, description, owner_id, created_at, updated_at
 	`
 
@@ -217,4 +336,4 @@ func (repo *ProjectRepository) DeleteAllByOwner(ctx context.Context, ownerID str
 	}
 
 	return deletedProjects, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"pulseguard/internal/models"
+)
+
+const (
+	aliceID = "00000000-0000-0000-0000-00000000000a"
+	bobID   = "00000000-0000-0000-0000-00000000000b"
+	carolID = "00000000-0000-0000-0000-00000000000c"
+
+	shopID    = "10000000-0000-0000-0000-000000000001"
+	blogID    = "10000000-0000-0000-0000-000000000002"
+	bobShopID = "10000000-0000-0000-0000-000000000003"
+	missingID = "10000000-0000-0000-0000-0000000000ff"
+)
+
+// seedUsers inserts alice, bob and carol
+func seedUsers(t *testing.T, db *sql.DB) {
+	t.Helper()
+	mustExec(t, db, `INSERT INTO users (id, name, email) VALUES
+		($1, 'alice', 'alice@example.com'),
+		($2, 'bob', 'bob@example.com'),
+		($3, 'carol', 'carol@example.com')`, aliceID, bobID, carolID)
+}
+
+// seedProject inserts a project, recording its owner as a member like
+// ProjectRepository.Create does
+func seedProject(t *testing.T, db *sql.DB, id, ownerID, slug, organizationID string) {
+	t.Helper()
+	mustExec(t, db, `
+		INSERT INTO projects (id, owner_id, name, slug, organization_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, now(), now())
+	`, id, ownerID, slug+"-"+id[len(id)-1:], slug, organizationID)
+	mustExec(t, db, `INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, 'owner')`, id, ownerID)
+}
+
+func TestProjectForUser(t *testing.T) {
+	db := openTestDB(t)
+	seedUsers(t, db)
+	// alice owns shop and blog, bob owns another project called shop
+	seedProject(t, db, shopID, aliceID, "shop", "")
+	seedProject(t, db, blogID, aliceID, "blog", "")
+	seedProject(t, db, bobShopID, bobID, "shop", "")
+	mustExec(t, db, `INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, 'viewer')`, blogID, carolID)
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	byID := []struct {
+		name      string
+		projectID string
+		userID    string
+		wantErr   error
+		wantRole  models.Role
+	}{
+		{"owner", shopID, aliceID, nil, models.RoleOwner},
+		{"member", blogID, carolID, nil, models.RoleViewer},
+		{"non-member", shopID, carolID, nil, ""},
+		{"owner of another project", shopID, bobID, nil, ""},
+		{"unknown project", missingID, aliceID, sql.ErrNoRows, ""},
+	}
+	for _, tt := range byID {
+		t.Run("id/"+tt.name, func(t *testing.T) {
+			project, err := repo.GetByIDForUser(ctx, tt.projectID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByIDForUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if project.ID != tt.projectID || project.Role != tt.wantRole {
+				t.Errorf("GetByIDForUser() = %s as %q, want %s as %q", project.ID, project.Role, tt.projectID, tt.wantRole)
+			}
+		})
+	}
+
+	bySlug := []struct {
+		name        string
+		slug        string
+		userID      string
+		wantErr     error
+		wantProject string
+		wantRole    models.Role
+	}{
+		{"own project of a shared slug", "shop", bobID, nil, bobShopID, models.RoleOwner},
+		{"other project of a shared slug", "shop", aliceID, nil, shopID, models.RoleOwner},
+		{"member", "blog", carolID, nil, blogID, models.RoleViewer},
+		{"non-member", "blog", bobID, nil, blogID, ""},
+		{"unknown slug", "wiki", aliceID, sql.ErrNoRows, "", ""},
+	}
+	for _, tt := range bySlug {
+		t.Run("slug/"+tt.name, func(t *testing.T) {
+			project, err := repo.GetBySlugForUser(ctx, tt.slug, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetBySlugForUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if project.ID != tt.wantProject || project.Role != tt.wantRole {
+				t.Errorf("GetBySlugForUser() = %s as %q, want %s as %q", project.ID, project.Role, tt.wantProject, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestEffectiveRole(t *testing.T) {
+	const (
+		acmeID   = "20000000-0000-0000-0000-000000000001"
+		globexID = "20000000-0000-0000-0000-000000000002"
+		teamID   = "30000000-0000-0000-0000-000000000001"
+		daveID   = "00000000-0000-0000-0000-00000000000d"
+		erinID   = "00000000-0000-0000-0000-00000000000e"
+	)
+
+	db := openTestDB(t)
+	seedUsers(t, db)
+	mustExec(t, db, `INSERT INTO users (id, name, email) VALUES
+		($1, 'dave', 'dave@example.com'),
+		($2, 'erin', 'erin@example.com')`, daveID, erinID)
+	mustExec(t, db, `INSERT INTO organizations (id, name, slug) VALUES
+		($1, 'Acme', 'acme'),
+		($2, 'Globex', 'globex')`, acmeID, globexID)
+	// shop belongs to acme, blog is a personal project of alice
+	seedProject(t, db, shopID, aliceID, "shop", acmeID)
+	seedProject(t, db, blogID, aliceID, "blog", "")
+	mustExec(t, db, `INSERT INTO organization_members (organization_id, user_id, role) VALUES
+		($1, $3, 'admin'),
+		($1, $4, 'viewer'),
+		($2, $5, 'owner')`, acmeID, globexID, bobID, carolID, erinID)
+	mustExec(t, db, `INSERT INTO teams (id, organization_id, name, slug) VALUES ($1, $2, 'Web', 'web')`, teamID, acmeID)
+	mustExec(t, db, `INSERT INTO team_members (team_id, user_id) VALUES ($1, $2), ($1, $3)`, teamID, carolID, daveID)
+	mustExec(t, db, `INSERT INTO project_teams (project_id, team_id, role) VALUES ($1, $2, 'member')`, shopID, teamID)
+	mustExec(t, db, `INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, 'viewer')`, shopID, bobID)
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		projectID string
+		userID    string
+		want      models.Role
+	}{
+		{"owner", shopID, aliceID, models.RoleOwner},
+		{"organization admin inherits over a weaker direct role", shopID, bobID, models.RoleAdmin},
+		{"team role beats a weaker organization role", shopID, carolID, models.RoleMember},
+		{"team member", shopID, daveID, models.RoleMember},
+		{"owner of another organization", shopID, erinID, ""},
+		{"organization roles stay in the organization", blogID, bobID, ""},
+		{"team roles stay on the team's projects", blogID, carolID, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := repo.GetByIDForUser(ctx, tt.projectID, tt.userID)
+			if err != nil {
+				t.Fatalf("GetByIDForUser() error = %v", err)
+			}
+			if project.Role != tt.want {
+				t.Errorf("role = %q, want %q", project.Role, tt.want)
+			}
+		})
+	}
+
+	t.Run("listed projects carry the same roles", func(t *testing.T) {
+		projects, err := repo.ListForUser(ctx, bobID)
+		if err != nil {
+			t.Fatalf("ListForUser() error = %v", err)
+		}
+		if len(projects) != 1 || projects[0].ID != shopID || projects[0].Role != models.RoleAdmin {
+			t.Errorf("ListForUser() = %+v, want only shop as admin", projects)
+		}
+	})
+
+	t.Run("organization membership", func(t *testing.T) {
+		orgs := NewOrganizationRepository(db)
+		for _, tt := range []struct {
+			userID string
+			want   models.Role
+		}{
+			{bobID, models.RoleAdmin},
+			{carolID, models.RoleViewer},
+			{daveID, ""},
+			{erinID, ""},
+		} {
+			org, err := orgs.GetBySlugForUser(ctx, "acme", tt.userID)
+			if err != nil {
+				t.Fatalf("GetBySlugForUser() error = %v", err)
+			}
+			if org.Role != tt.want {
+				t.Errorf("GetBySlugForUser(%s) role = %q, want %q", tt.userID, org.Role, tt.want)
+			}
+		}
+		if _, err := orgs.GetBySlugForUser(ctx, "initech", bobID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetBySlugForUser(unknown) error = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+// TestEffectiveRoleRanking keeps the ranking in effectiveRoleSQL in the
+// order of models.Role; roles it does not name rank lowest
+func TestEffectiveRoleRanking(t *testing.T) {
+	ranks := map[models.Role]int{}
+	for _, m := range regexp.MustCompile(`WHEN '(\w+)' THEN (\d+)`).FindAllStringSubmatch(effectiveRoleSQL, -1) {
+		rank, _ := strconv.Atoi(m[2])
+		ranks[models.Role(m[1])] = rank
+	}
+
+	roles := []models.Role{models.RoleViewer, models.RoleMember, models.RoleAdmin, models.RoleOwner}
+	for _, a := range roles {
+		for _, b := range roles {
+			if (ranks[a] >= ranks[b]) != a.AtLeast(b) {
+				t.Errorf("effectiveRoleSQL ranks %s %d and %s %d, but AtLeast(%s, %s) = %v", a, ranks[a], b, ranks[b], a, b, a.AtLeast(b))
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pulseguard/internal/models"
+)
+
+type ReleaseRepository struct {
+	db *sql.DB
+}
+
+func NewReleaseRepository(db *sql.DB) *ReleaseRepository {
+	return &ReleaseRepository{db: db}
+}
+
+// releaseQuery selects releases with their new issue count and latest deploy
+const releaseQuery = `
+	SELECT r.id, r.project_id, r.version, COALESCE(r.ref, ''), COALESCE(r.url, ''), r.date_released,
+		COALESCE(r.created_by::text, ''), r.created_at,
+		(SELECT COUNT(*) FROM errors e WHERE e.project_id = r.project_id AND e.first_release = r.version),
+		d.id, d.environment, COALESCE(d.name, ''), COALESCE(d.url, ''), d.date_started, d.date_finished,
+		COALESCE(d.created_by::text, ''), d.created_at
+	FROM releases r
+	LEFT JOIN LATERAL (
+		SELECT * FROM deploys WHERE release_id = r.id ORDER BY date_finished DESC LIMIT 1
+	) d ON true
+`
+
+const deployColumns = `
+	id, project_id, release_id, environment, COALESCE(name, ''), COALESCE(url, ''), date_started, date_finished,
+	COALESCE(created_by::text, ''), created_at
+`
+
+// Upsert creates a release, or fills in the details of one that already
+// exists, for example because events were tracked with it. It reports
+// whether the release was created.
+func (repo *ReleaseRepository) Upsert(ctx context.Context, release *models.Release) (bool, error) {
+	var created bool
+	err := repo.db.QueryRowContext(ctx, `
+		INSERT INTO releases (id, project_id, version, ref, url, date_released, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (project_id, version) DO UPDATE
+		SET ref = COALESCE(EXCLUDED.ref, releases.ref),
+			url = COALESCE(EXCLUDED.url, releases.url),
+			date_released = COALESCE(EXCLUDED.date_released, releases.date_released),
+			created_by = COALESCE(releases.created_by, EXCLUDED.created_by)
+		RETURNING id, created_at, (xmax = 0)
+	`,
+		release.ID,
+		release.ProjectID,
+		release.Version,
+		toNullString(release.Ref),
+		toNullString(release.URL),
+		release.DateReleased,
+		toNullString(release.CreatedBy),
+		release.CreatedAt,
+	).Scan(&release.ID, &release.CreatedAt, &created)
+	return created, err
+}
+
+// List returns the project's most recent releases, newest first.
+func (repo *ReleaseRepository) List(ctx context.Context, projectID string, limit int) ([]*models.Release, error) {
+	rows, err := repo.db.QueryContext(ctx, releaseQuery+` WHERE r.project_id = $1 ORDER BY r.created_at DESC, r.id DESC LIMIT $2`, projectID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	releases := []*models.Release{}
+	for rows.Next() {
+		release, err := scanRelease(rows)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return releases, rows.Err()
+}
+
+// GetByVersion returns a release of the project.
+func (repo *ReleaseRepository) GetByVersion(ctx context.Context, projectID, version string) (*models.Release, error) {
+	row := repo.db.QueryRowContext(ctx, releaseQuery+` WHERE r.project_id = $1 AND r.version = $2`, projectID, version)
+	return scanRelease(row)
+}
+
+// CreateDeploy records a deploy of a release.
+func (repo *ReleaseRepository) CreateDeploy(ctx context.Context, deploy *models.Deploy) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO deploys (id, project_id, release_id, environment, name, url, date_started, date_finished, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		deploy.ID,
+		deploy.ProjectID,
+		deploy.ReleaseID,
+		deploy.Environment,
+		toNullString(deploy.Name),
+		toNullString(deploy.URL),
+		deploy.DateStarted,
+		deploy.DateFinished,
+		toNullString(deploy.CreatedBy),
+		deploy.CreatedAt,
+	)
+	return err
+}
+
+// ListDeploys returns the deploys of a release, most recent first.
+func (repo *ReleaseRepository) ListDeploys(ctx context.Context, projectID, releaseID string) ([]*models.Deploy, error) {
+	rows, err := repo.db.QueryContext(ctx, `SELECT `+deployColumns+` FROM deploys WHERE project_id = $1 AND release_id = $2 ORDER BY date_finished DESC`,
+		projectID, releaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deploys := []*models.Deploy{}
+	for rows.Next() {
+		var d models.Deploy
+		var started sql.NullTime
+		err := rows.Scan(&d.ID, &d.ProjectID, &d.ReleaseID, &d.Environment, &d.Name, &d.URL, &started, &d.DateFinished,
+			&d.CreatedBy, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if started.Valid {
+			d.DateStarted = &started.Time
+		}
+		deploys = append(deploys, &d)
+	}
+	return deploys, rows.Err()
+}
+
+func scanRelease(row rowScanner) (*models.Release, error) {
+	var r models.Release
+	var released, deployStarted, deployFinished, deployCreated sql.NullTime
+	var deployID, deployEnv, deployName, deployURL, deployBy sql.NullString
+	err := row.Scan(&r.ID, &r.ProjectID, &r.Version, &r.Ref, &r.URL, &released, &r.CreatedBy, &r.CreatedAt, &r.NewIssues,
+		&deployID, &deployEnv, &deployName, &deployURL, &deployStarted, &deployFinished, &deployBy, &deployCreated)
+	if err != nil {
+		return nil, err
+	}
+	if released.Valid {
+		r.DateReleased = &released.Time
+	}
+	if deployID.Valid {
+		r.LastDeploy = &models.Deploy{
+			ID:           deployID.String,
+			ProjectID:    r.ProjectID,
+			ReleaseID:    r.ID,
+			Environment:  deployEnv.String,
+			Name:         deployName.String,
+			URL:          deployURL.String,
+			DateFinished: deployFinished.Time,
+			CreatedBy:    deployBy.String,
+			CreatedAt:    deployCreated.Time,
+		}
+		if deployStarted.Valid {
+			r.LastDeploy.DateStarted = &deployStarted.Time
+		}
+	}
+	return &r, nil
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"pulseguard/internal/models"
+)
+
+// SamplingRepository stores the sampling configuration of projects
+type SamplingRepository struct {
+	db *sql.DB
+}
+
+func NewSamplingRepository(db *sql.DB) *SamplingRepository {
+	return &SamplingRepository{db: db}
+}
+
+// GetConfig returns the sampling configuration of a project
+func (r *SamplingRepository) GetConfig(ctx context.Context, projectID string) (*models.SamplingConfig, error) {
+	cfg := &models.SamplingConfig{ProjectID: projectID}
+	err := r.db.QueryRowContext(ctx, `
+        SELECT sample_rate, sample_environment_rates, sample_type_rates, sample_keep_first
+        FROM projects WHERE id = $1`, projectID).
+		Scan(&cfg.SampleRate, jsonColumn{&cfg.EnvironmentRates}, jsonColumn{&cfg.TypeRates}, &cfg.KeepFirst)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// UpdateConfig replaces the sampling configuration of a project
+func (r *SamplingRepository) UpdateConfig(ctx context.Context, cfg *models.SamplingConfig) error {
+	environmentRates, _ := json.Marshal(rateMap(cfg.EnvironmentRates))
+	typeRates, _ := json.Marshal(rateMap(cfg.TypeRates))
+	return requireAffected(r.db.ExecContext(ctx, `
+        UPDATE projects
+        SET sample_rate = $2, sample_environment_rates = $3, sample_type_rates = $4, sample_keep_first = $5, updated_at = now()
+        WHERE id = $1`,
+		cfg.ProjectID, cfg.SampleRate, environmentRates, typeRates, cfg.KeepFirst))
+}
+
+// rateMap stores a nil map as an empty object
+func rateMap(rates map[string]float64) map[string]float64 {
+	if rates == nil {
+		return map[string]float64{}
+	}
+	return rates
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"pulseguard/internal/models"
+)
+
+type SavedViewRepository struct {
+	db *sql.DB
+}
+
+func NewSavedViewRepository(db *sql.DB) *SavedViewRepository {
+	return &SavedViewRepository{db: db}
+}
+
+const savedViewColumns = `
+	v.id, v.project_id, v.owner_id, COALESCE(u.name, ''), v.name, v.query, COALESCE(v.environment, ''),
+	COALESCE(v.status, ''), COALESCE(v.sort, ''), v.shared, v.created_at, v.updated_at
+`
+
+const savedViewFrom = ` FROM saved_views v LEFT JOIN users u ON u.id = v.owner_id`
+
+// Create inserts a new saved view.
+func (repo *SavedViewRepository) Create(ctx context.Context, view *models.SavedView) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO saved_views (id, project_id, owner_id, name, query, environment, status, sort, shared, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		view.ID,
+		view.ProjectID,
+		view.OwnerID,
+		view.Name,
+		view.Query,
+		toNullString(view.Environment),
+		toNullString(view.Status),
+		toNullString(view.Sort),
+		view.Shared,
+		view.CreatedAt,
+		view.UpdatedAt,
+	)
+	return err
+}
+
+// ListVisible returns the project's views the user can see: their own and
+// the shared ones, by name.
+func (repo *SavedViewRepository) ListVisible(ctx context.Context, projectID, userID string) ([]*models.SavedView, error) {
+	rows, err := repo.db.QueryContext(ctx, `SELECT `+savedViewColumns+savedViewFrom+`
+		WHERE v.project_id = $1 AND (v.owner_id = $2 OR v.shared)
+		ORDER BY lower(v.name), v.created_at`, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := []*models.SavedView{}
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// GetVisible returns a view of the project if the user owns it or it is shared.
+func (repo *SavedViewRepository) GetVisible(ctx context.Context, projectID, id, userID string) (*models.SavedView, error) {
+	row := repo.db.QueryRowContext(ctx, `SELECT `+savedViewColumns+savedViewFrom+`
+		WHERE v.project_id = $1 AND v.id = $2 AND (v.owner_id = $3 OR v.shared)`, projectID, id, userID)
+	return scanSavedView(row)
+}
+
+// Update saves the editable fields of a saved view.
+func (repo *SavedViewRepository) Update(ctx context.Context, view *models.SavedView) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE saved_views
+		SET name = $3, query = $4, environment = $5, status = $6, sort = $7, shared = $8, updated_at = $9
+		WHERE project_id = $1 AND id = $2
+	`,
+		view.ProjectID,
+		view.ID,
+		view.Name,
+		view.Query,
+		toNullString(view.Environment),
+		toNullString(view.Status),
+		toNullString(view.Sort),
+		view.Shared,
+		view.UpdatedAt,
+	)
+	return requireAffected(res, err)
+}
+
+// Delete removes a saved view of the project.
+func (repo *SavedViewRepository) Delete(ctx context.Context, projectID, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM saved_views WHERE project_id = $1 AND id = $2`, projectID, id)
+	return requireAffected(res, err)
+}
+
+func scanSavedView(row rowScanner) (*models.SavedView, error) {
+	var view models.SavedView
+	err := row.Scan(
+		&view.ID,
+		&view.ProjectID,
+		&view.OwnerID,
+		&view.OwnerName,
+		&view.Name,
+		&view.Query,
+		&view.Environment,
+		&view.Status,
+		&view.Sort,
+		&view.Shared,
+		&view.CreatedAt,
+		&view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
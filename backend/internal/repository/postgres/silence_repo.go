@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+type SilenceRepository struct {
+	db *sql.DB
+}
+
+func NewSilenceRepository(db *sql.DB) *SilenceRepository {
+	return &SilenceRepository{db: db}
+}
+
+const silenceColumns = `id, project_id, matchers, COALESCE(comment, ''), COALESCE(created_by::text, ''), starts_at, ends_at, created_at`
+
+const maintenanceWindowColumns = `id, project_id, name, COALESCE(created_by::text, ''), starts_at, ends_at, created_at`
+
+func (repo *SilenceRepository) CreateSilence(ctx context.Context, s *models.Silence) error {
+	matchers, err := json.Marshal(s.Matchers)
+	if err != nil {
+		return err
+	}
+	_, err = repo.db.ExecContext(ctx, `
+		INSERT INTO alert_silences (id, project_id, matchers, comment, created_by, starts_at, ends_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, s.ID, s.ProjectID, matchers, toNullString(s.Comment), toNullString(s.CreatedBy), s.StartsAt, s.EndsAt, s.CreatedAt)
+	return err
+}
+
+// ListSilences returns the project's silences, newest first. Silences that
+// ended before the given time are left out unless includeExpired is set.
+func (repo *SilenceRepository) ListSilences(ctx context.Context, projectID string, includeExpired bool, now time.Time) ([]*models.Silence, error) {
+	query := `SELECT ` + silenceColumns + ` FROM alert_silences WHERE project_id = $1 AND ($2 OR ends_at > $3) ORDER BY starts_at DESC`
+	return repo.listSilences(ctx, query, projectID, includeExpired, now)
+}
+
+// ListActiveSilences returns the project's silences in effect at the given time
+func (repo *SilenceRepository) ListActiveSilences(ctx context.Context, projectID string, at time.Time) ([]*models.Silence, error) {
+	query := `SELECT ` + silenceColumns + ` FROM alert_silences WHERE project_id = $1 AND starts_at <= $2 AND ends_at > $2`
+	return repo.listSilences(ctx, query, projectID, at)
+}
+
+// ExpireSilence ends an active silence at the given time and deletes one
+// that has not started yet. Silences that already ended are left as they
+// are; sql.ErrNoRows is returned only when the project has no such silence.
+func (repo *SilenceRepository) ExpireSilence(ctx context.Context, projectID, id string, at time.Time) error {
+	res, err := repo.db.ExecContext(ctx, `
+		UPDATE alert_silences SET ends_at = $3
+		WHERE project_id = $1 AND id = $2 AND starts_at < $3 AND ends_at > $3
+	`, projectID, id, at)
+	if err = requireAffected(res, err); err != sql.ErrNoRows {
+		return err
+	}
+
+	res, err = repo.db.ExecContext(ctx, `
+		DELETE FROM alert_silences WHERE project_id = $1 AND id = $2 AND starts_at >= $3
+	`, projectID, id, at)
+	if err = requireAffected(res, err); err != sql.ErrNoRows {
+		return err
+	}
+
+	var exists bool
+	err = repo.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM alert_silences WHERE project_id = $1 AND id = $2)
+	`, projectID, id).Scan(&exists)
+	if err == nil && !exists {
+		err = sql.ErrNoRows
+	}
+	return err
+}
+
+func (repo *SilenceRepository) CreateMaintenanceWindow(ctx context.Context, w *models.MaintenanceWindow) error {
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO maintenance_windows (id, project_id, name, created_by, starts_at, ends_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, w.ID, w.ProjectID, w.Name, toNullString(w.CreatedBy), w.StartsAt, w.EndsAt, w.CreatedAt)
+	return err
+}
+
+// ListMaintenanceWindows returns the project's windows, latest first.
+// Windows that ended before the given time are left out unless includePast is set.
+func (repo *SilenceRepository) ListMaintenanceWindows(ctx context.Context, projectID string, includePast bool, now time.Time) ([]*models.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceWindowColumns + ` FROM maintenance_windows WHERE project_id = $1 AND ($2 OR ends_at > $3) ORDER BY starts_at DESC`
+	return repo.listMaintenanceWindows(ctx, query, projectID, includePast, now)
+}
+
+// ListActiveMaintenanceWindows returns the project's windows in effect at the given time
+func (repo *SilenceRepository) ListActiveMaintenanceWindows(ctx context.Context, projectID string, at time.Time) ([]*models.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceWindowColumns + ` FROM maintenance_windows WHERE project_id = $1 AND starts_at <= $2 AND ends_at > $2`
+	return repo.listMaintenanceWindows(ctx, query, projectID, at)
+}
+
+func (repo *SilenceRepository) DeleteMaintenanceWindow(ctx context.Context, projectID, id string) error {
+	res, err := repo.db.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE project_id = $1 AND id = $2`, projectID, id)
+	return requireAffected(res, err)
+}
+
+func (repo *SilenceRepository) listSilences(ctx context.Context, query string, args ...any) ([]*models.Silence, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	silences := []*models.Silence{}
+	for rows.Next() {
+		var s models.Silence
+		var matchers []byte
+		if err := rows.Scan(&s.ID, &s.ProjectID, &matchers, &s.Comment, &s.CreatedBy, &s.StartsAt, &s.EndsAt, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(matchers, &s.Matchers); err != nil {
+			return nil, err
+		}
+		silences = append(silences, &s)
+	}
+	return silences, rows.Err()
+}
+
+func (repo *SilenceRepository) listMaintenanceWindows(ctx context.Context, query string, args ...any) ([]*models.MaintenanceWindow, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	windows := []*models.MaintenanceWindow{}
+	for rows.Next() {
+		var w models.MaintenanceWindow
+		if err := rows.Scan(&w.ID, &w.ProjectID, &w.Name, &w.CreatedBy, &w.StartsAt, &w.EndsAt, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		windows = append(windows, &w)
+	}
+	return windows, rows.Err()
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// testDatabaseEnv names the Postgres database the repository tests run
+// against; they are skipped when it is unset
+const testDatabaseEnv = "PULSEGUARD_TEST_DATABASE_URL"
+
+// migrationsDir holds the migrations the test schema is built from
+const migrationsDir = "../../db/migrations"
+
+// migrate applies the Up section of every migration, in version order, as
+// goose does on a new database
+func migrate(t *testing.T, db *sql.DB) {
+	t.Helper()
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		t.Fatalf("read migrations: %v", err)
+	}
+
+	type migration struct {
+		version uint64
+		name    string
+	}
+	var migrations []migration
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			t.Fatalf("migration %s has no version", entry.Name())
+		}
+		migrations = append(migrations, migration{version, entry.Name()})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+
+	for _, m := range migrations {
+		content, err := os.ReadFile(filepath.Join(migrationsDir, m.name))
+		if err != nil {
+			t.Fatalf("read migration %s: %v", m.name, err)
+		}
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+		if _, err := db.Exec(up); err != nil {
+			t.Fatalf("apply migration %s: %v", m.name, err)
+		}
+	}
+}
+
+// openTestDB connects to the test database with a fresh schema of its own,
+// which is dropped when the test ends
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv(testDatabaseEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	// One connection, so that every query sees the search_path set below
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("pulseguard_test_%d", time.Now().UnixNano())
+	if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA %s; SET search_path TO %s", schema, schema)); err != nil {
+		db.Close()
+		t.Fatalf("create test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		db.Close()
+	})
+
+	migrate(t, db)
+	return db
+}
+
+// mustExec runs statements that set up a test
+func mustExec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec %q: %v", query, err)
+	}
+}
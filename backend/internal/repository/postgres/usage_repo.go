@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"pulseguard/internal/models"
+)
+
+// UsageRepository stores the ingest limits of projects and counts the
+// events they send
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// GetLimits returns the ingest limits of a project
+func (r *UsageRepository) GetLimits(ctx context.Context, projectID string) (*models.IngestLimits, error) {
+	limits := &models.IngestLimits{ProjectID: projectID}
+	err := r.db.QueryRowContext(ctx, `
+        SELECT ingest_rate_limit, monthly_event_quota, spike_protection
+        FROM projects WHERE id = $1`, projectID).
+		Scan(&limits.RateLimit, &limits.MonthlyQuota, &limits.SpikeProtection)
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// UpdateLimits sets the ingest limits of a project
+func (r *UsageRepository) UpdateLimits(ctx context.Context, limits *models.IngestLimits) error {
+	return requireAffected(r.db.ExecContext(ctx, `
+        UPDATE projects
+        SET ingest_rate_limit = $2, monthly_event_quota = $3, spike_protection = $4, updated_at = now()
+        WHERE id = $1`,
+		limits.ProjectID, limits.RateLimit, limits.MonthlyQuota, limits.SpikeProtection))
+}
+
+// AddUsage adds counts to a project's usage on a day
+func (r *UsageRepository) AddUsage(ctx context.Context, projectID string, usage models.UsageDay) error {
+	_, err := r.db.ExecContext(ctx, `
+        INSERT INTO project_usage (project_id, day, accepted, rate_limited, dropped)
+        VALUES ($1, $2::date, $3, $4, $5)
+        ON CONFLICT (project_id, day) DO UPDATE SET
+            accepted = project_usage.accepted + EXCLUDED.accepted,
+            rate_limited = project_usage.rate_limited + EXCLUDED.rate_limited,
+            dropped = project_usage.dropped + EXCLUDED.dropped`,
+		projectID, usage.Day.Format("2006-01-02"), usage.Accepted, usage.RateLimited, usage.Dropped)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// ListUsage returns a project's usage on the days from start until end,
+// oldest first; days without events are left out
+func (r *UsageRepository) ListUsage(ctx context.Context, projectID string, start, end time.Time) ([]models.UsageDay, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT day, accepted, rate_limited, dropped
+        FROM project_usage
+        WHERE project_id = $1 AND day >= $2::date AND day < $3::date
+        ORDER BY day`,
+		projectID, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]models.UsageDay, 0)
+	for rows.Next() {
+		var d models.UsageDay
+		if err := rows.Scan(&d.Day, &d.Accepted, &d.RateLimited, &d.Dropped); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// CountAccepted returns how many events of a project were accepted from
+// the day of start on
+func (r *UsageRepository) CountAccepted(ctx context.Context, projectID string, start time.Time) (int64, error) {
+	var n int64
+	err := r.db.QueryRowContext(ctx, `
+        SELECT COALESCE(SUM(accepted), 0) FROM project_usage
+        WHERE project_id = $1 AND day >= $2::date`,
+		projectID, start.Format("2006-01-02")).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count accepted events: %w", err)
+	}
+	return n, nil
+}
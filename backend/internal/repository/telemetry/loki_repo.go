@@ -38,7 +38,9 @@ type lokiResponse struct {
 
 // QueryLogs
 func (r *LokiRepository) QueryLogs(ctx context.Context, projectID string, start, end time.Time) ([]*models.Log, error) {
-	query := `{service_name="pulseguard"}`
+	// The collector indexes project_id as a label, from the resource stamped
+	// by OTLP ingest or the attributes of the record
+	query := fmt.Sprintf(`{service_name="pulseguard", project_id=%q}`, projectID)
 
 	u, err := url.Parse(fmt.Sprintf(
 		"%s/loki/api/v1/query_range?query=%s&limit=100&start=%d&end=%d",
@@ -79,32 +81,12 @@ func (r *LokiRepository) QueryLogs(ctx context.Context, projectID string, start,
 				return nil, fmt.Errorf("parse timestamp: %w", err)
 			}
 			timestamp := time.Unix(0, ns)
-			rawMessage := value[1]
-
-			// Try to extract JSON body
-			var parsed map[string]interface{}
-			if err := json.Unmarshal([]byte(rawMessage), &parsed); err != nil {
-				continue
-			}
-
-			// Check for "project_id" in root or nested "attributes"
-			var currentProjectID string
-			if v, ok := parsed["project_id"].(string); ok {
-				currentProjectID = v
-			} else if attr, ok := parsed["attributes"].(map[string]interface{}); ok {
-				if v, ok := attr["project_id"].(string); ok {
-					currentProjectID = v
-				}
-			}
-
-			if currentProjectID == projectID {
-				logs = append(logs, &models.Log{
-					ID:        value[0],
-					ProjectID: projectID,
-					Message:   rawMessage,
-					Timestamp: timestamp,
-				})
-			}
+			logs = append(logs, &models.Log{
+				ID:        value[0],
+				ProjectID: projectID,
+				Message:   value[1],
+				Timestamp: timestamp,
+			})
 		}
 	}
 
@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"pulseguard/internal/otlp"
+)
+
+// OTLPRepository forwards OTLP export requests to an OTLP/HTTP endpoint,
+// normally the collector that ships them to Tempo, Loki and Prometheus
+type OTLPRepository struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOTLPRepository(baseURL string) *OTLPRepository {
+	return &OTLPRepository{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Export sends an export request of the signal as protobuf
+func (r *OTLPRepository) Export(ctx context.Context, signal otlp.Signal, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal OTLP request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", r.baseURL+signal.Path(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", otlp.ContentTypeProtobuf)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", signal, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("OTLP export of %s failed: %d - %s", signal, resp.StatusCode, string(msg))
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"pulseguard/internal/models"
+	"pulseguard/internal/promql"
 )
 
 type PrometheusRepository struct {
@@ -37,6 +38,8 @@ type prometheusResult struct {
 	Value  []any     `json:"value"`
 }
 
+// QueryMetrics returns the current values of the application metrics of the
+// project
 func (r *PrometheusRepository) QueryMetrics(ctx context.Context, projectID string) ([]*models.Metric, error) {
 	queries := map[string]string{
 		"http_requests_total":      `pulseguard_http_requests_total`,
@@ -51,8 +54,10 @@ func (r *PrometheusRepository) QueryMetrics(ctx context.Context, projectID strin
 	metrics := make([]*models.Metric, 0)
 
 	for metricName, queryTemplate := range queries {
-		query := queryTemplate
-		// query := fmt.Sprintf(`%s{project_id="%s"}`, queryTemplate, projectID)
+		query, err := promql.Scope(queryTemplate, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("scope query for %s: %w", metricName, err)
+		}
 		u, err := url.Parse(fmt.Sprintf("%s/api/v1/query?query=%s", r.baseURL, url.QueryEscape(query)))
 		if err != nil {
 			return nil, fmt.Errorf("parse query URL for %s: %w", metricName, err)
@@ -108,3 +113,54 @@ func (r *PrometheusRepository) QueryMetrics(ctx context.Context, projectID strin
 
 	return metrics, nil
 }
+
+// Query runs a PromQL instant query and returns one sample per resulting series.
+func (r *PrometheusRepository) Query(ctx context.Context, query string) ([]models.MetricSample, error) {
+	u := fmt.Sprintf("%s/api/v1/query?query=%s", r.baseURL, url.QueryEscape(query))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create query request: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected query status: %d", resp.StatusCode)
+	}
+
+	var promResp prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&promResp); err != nil {
+		return nil, fmt.Errorf("decode query response: %w", err)
+	}
+
+	samples := make([]models.MetricSample, 0, len(promResp.Data.Result))
+	for _, result := range promResp.Data.Result {
+		if len(result.Value) < 2 {
+			continue
+		}
+		timestamp, ok := result.Value[0].(float64)
+		if !ok {
+			continue
+		}
+		raw, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		samples = append(samples, models.MetricSample{
+			Labels:    result.Metric,
+			Value:     value,
+			Timestamp: time.Unix(int64(timestamp), 0),
+		})
+	}
+
+	return samples, nil
+}
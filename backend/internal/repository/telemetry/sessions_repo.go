@@ -44,26 +44,28 @@ func (r *SessionRepository) CreateSession(ctx context.Context, session *models.S
 	return nil
 }
 
-func (r *SessionRepository) UpdateSessionEnd(ctx context.Context, sessionID string, endTime time.Time) error {
+func (r *SessionRepository) UpdateSessionEnd(ctx context.Context, projectID, sessionID string, endTime time.Time) error {
 	query := `
         UPDATE sessions
         SET end_time = $1, duration_ms = EXTRACT(EPOCH FROM ($1 - start_time)) * 1000
-        WHERE session_id = $2
+        WHERE session_id = $2 AND ($3 = '' OR project_id::text = $3)
     `
-	_, err := r.db.ExecContext(ctx, query, endTime, sessionID)
+	_, err := r.db.ExecContext(ctx, query, endTime, sessionID, projectID)
 	if err != nil {
 		return fmt.Errorf("update session end: %w", err)
 	}
 	return nil
 }
 
-func (r *SessionRepository) IncrementErrorCount(ctx context.Context, sessionID string) error {
+// IncrementErrorCount counts an error against a session of the project;
+// sessions of other projects are never touched
+func (r *SessionRepository) IncrementErrorCount(ctx context.Context, projectID, sessionID string) error {
 	query := `
         UPDATE sessions
         SET error_count = error_count + 1
-        WHERE session_id = $1
+        WHERE session_id = $1 AND project_id::text = $2
     `
-	_, err := r.db.ExecContext(ctx, query, sessionID)
+	_, err := r.db.ExecContext(ctx, query, sessionID, projectID)
 	if err != nil {
 		return fmt.Errorf("increment error count: %w", err)
 	}
@@ -112,3 +114,16 @@ func (r *SessionRepository) CountSessions(ctx context.Context, projectID string)
 	err := r.db.QueryRowContext(ctx, query, projectID).Scan(&count)
 	return count, err
 }
+
+// CountSessionsWithErrorsSince returns how many of the project's sessions started
+// since the given time, and how many of those recorded at least one error.
+func (r *SessionRepository) CountSessionsWithErrorsSince(ctx context.Context, projectID string, since time.Time) (int64, int64, error) {
+	var total, errored int64
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE error_count > 0)
+		FROM sessions
+		WHERE project_id = $1 AND start_time >= $2
+	`
+	err := r.db.QueryRowContext(ctx, query, projectID, since).Scan(&total, &errored)
+	return total, errored, err
+}
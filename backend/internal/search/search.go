@@ -0,0 +1,403 @@
+// Package search parses the error search syntax, for example
+//
+//	is:unresolved browser:Chrome times_seen:>100 age:-24h !message:"timeout"
+//
+// A query is a list of terms that must all match. A term is free text,
+// matched against the message, type, source and url, or key:value. A
+// leading ! negates a term. Values with spaces are quoted, key:[a,b]
+// matches any of several values and * is a wildcard in text values.
+// Numeric keys take a comparison such as >100, and date keys either a
+// relative time (-24h: within the last 24 hours, +24h: longer ago) or a
+// comparison with a date such as >=2026-01-02. Keys that are not built in
+// match the error's tags.
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type of value a key takes
+type Kind int
+
+const (
+	KindText   Kind = iota // free text, or an error attribute compared as text
+	KindNumber             // a count, compared with Op
+	KindDate               // a time, compared with Op
+	KindIs                 // a status or assignment state
+	KindHas                // the name of a tag the error must have
+	KindTag                // the value of a tag
+)
+
+// Op compares a number or a date
+type Op string
+
+const (
+	OpEqual        Op = "="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+)
+
+// keys are the built-in keys; any other key is a tag
+var keys = map[string]Kind{
+	"is":            KindIs,
+	"has":           KindHas,
+	"message":       KindText,
+	"type":          KindText,
+	"source":        KindText,
+	"url":           KindText,
+	"environment":   KindText,
+	"status":        KindText,
+	"release":       KindText,
+	"first_release": KindText,
+	"last_release":  KindText,
+	"fingerprint":   KindText,
+	"user":          KindText,
+	"session":       KindText,
+	"assigned":      KindText,
+	"times_seen":    KindNumber,
+	"users":         KindNumber,
+	"sessions":      KindNumber,
+	"age":           KindDate,
+	"first_seen":    KindDate,
+	"last_seen":     KindDate,
+}
+
+// IsValues are the values is: accepts
+var IsValues = []string{"unresolved", "resolved", "ignored", "regressed", "investigating", "active", "assigned", "unassigned"}
+
+var durationUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// Term is one condition of a query
+type Term struct {
+	Pos     int // byte offset of the term in the query
+	Negated bool
+	Key     string // empty for free text; the tag name for KindTag
+	Kind    Kind
+	Op      Op        // for KindNumber and KindDate
+	Values  []string  // alternatives for text, tag, is and has terms
+	Number  int64     // for KindNumber
+	Time    time.Time // for KindDate
+}
+
+// ParseError reports an invalid query and where in it the problem is
+type ParseError struct {
+	Pos int // byte offset in the query
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid search at position %d: %s", e.Pos, e.Msg)
+}
+
+// Parse parses a query. Relative times are resolved against now.
+func Parse(query string, now time.Time) ([]Term, error) {
+	p := &parser{input: query, now: now}
+	var terms []Term
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			return terms, nil
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+}
+
+type parser struct {
+	input string
+	pos   int
+	now   time.Time
+}
+
+func (p *parser) errorf(pos int, format string, args ...interface{}) error {
+	return &ParseError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && isSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) term() (Term, error) {
+	term := Term{Pos: p.pos}
+	if p.input[p.pos] == '!' {
+		term.Negated = true
+		p.pos++
+		if p.pos >= len(p.input) || isSpace(p.input[p.pos]) {
+			return term, p.errorf(term.Pos, "! must be followed by a term")
+		}
+	}
+
+	if key, ok := p.key(); ok {
+		return p.keyedTerm(term, key)
+	}
+
+	// Free text
+	start := p.pos
+	var text string
+	if p.input[p.pos] == '"' {
+		s, err := p.quoted()
+		if err != nil {
+			return term, err
+		}
+		text = s
+	} else {
+		text = p.bare(false)
+	}
+	if text == "" {
+		return term, p.errorf(start, "empty search term")
+	}
+	term.Kind = KindText
+	term.Values = []string{text}
+	return term, nil
+}
+
+// key reads a key followed by a colon, leaving the position unchanged when
+// there is none
+func (p *parser) key() (string, bool) {
+	end := p.pos
+	for end < len(p.input) && isKeyChar(p.input[end], end == p.pos) {
+		end++
+	}
+	if end == p.pos || end >= len(p.input) || p.input[end] != ':' {
+		return "", false
+	}
+	key := p.input[p.pos:end]
+	p.pos = end + 1
+	return key, true
+}
+
+func (p *parser) keyedTerm(term Term, key string) (Term, error) {
+	kind, ok := keys[key]
+	if !ok {
+		kind = KindTag
+	}
+	term.Key = key
+	term.Kind = kind
+	valuePos := p.pos
+
+	if p.pos >= len(p.input) || isSpace(p.input[p.pos]) {
+		return term, p.errorf(valuePos, "missing value for %s", key)
+	}
+
+	switch kind {
+	case KindNumber, KindDate:
+		op := p.op()
+		raw := p.bare(false)
+		if raw == "" {
+			return term, p.errorf(p.pos, "missing value for %s", key)
+		}
+		if kind == KindNumber {
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || n < 0 {
+				return term, p.errorf(valuePos, "%s takes a number, got %q", key, raw)
+			}
+			term.Op, term.Number = op, n
+			return term, nil
+		}
+		return p.date(term, op, raw, valuePos)
+	}
+
+	values, err := p.values()
+	if err != nil {
+		return term, err
+	}
+	for _, v := range values {
+		if v == "" {
+			return term, p.errorf(valuePos, "empty value for %s", key)
+		}
+	}
+	if kind == KindIs {
+		for i, v := range values {
+			values[i] = strings.ToLower(v)
+			if !contains(IsValues, values[i]) {
+				return term, p.errorf(valuePos, "unknown value %q for is, expected one of %s", v, strings.Join(IsValues, ", "))
+			}
+		}
+	}
+	term.Op = OpEqual
+	term.Values = values
+	return term, nil
+}
+
+// date resolves a relative time or a date with a comparison
+func (p *parser) date(term Term, op Op, raw string, pos int) (Term, error) {
+	if raw[0] == '-' || raw[0] == '+' {
+		if op != OpEqual {
+			return term, p.errorf(pos, "a relative time such as -24h cannot be combined with a comparison")
+		}
+		d, err := parseDuration(raw[1:])
+		if err != nil {
+			return term, p.errorf(pos, "invalid relative time %q, expected for example -24h or +7d", raw)
+		}
+		term.Time = p.now.Add(-d)
+		// -24h: within the last 24 hours; +24h: more than 24 hours ago
+		if raw[0] == '-' {
+			term.Op = OpGreaterEqual
+		} else {
+			term.Op = OpLessEqual
+		}
+		return term, nil
+	}
+
+	t, err := parseDate(raw)
+	if err != nil {
+		return term, p.errorf(pos, "invalid date %q, expected for example 2026-01-02 or a relative time such as -24h", raw)
+	}
+	if op == OpEqual {
+		return term, p.errorf(pos, "compare %s with a date using >, >=, < or <=", term.Key)
+	}
+	term.Op, term.Time = op, t
+	return term, nil
+}
+
+func (p *parser) op() Op {
+	for _, op := range []Op{OpGreaterEqual, OpLessEqual, OpGreater, OpLess} {
+		if strings.HasPrefix(p.input[p.pos:], string(op)) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return OpEqual
+}
+
+// values reads a single value or a [a,b] list
+func (p *parser) values() ([]string, error) {
+	if p.input[p.pos] != '[' {
+		v, err := p.value(false)
+		return []string{v}, err
+	}
+
+	open := p.pos
+	p.pos++
+	var values []string
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			return nil, p.errorf(open, "missing ] to close the list")
+		}
+		v, err := p.value(true)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			return nil, p.errorf(open, "missing ] to close the list")
+		}
+		switch p.input[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return values, nil
+		default:
+			return nil, p.errorf(p.pos, "expected , or ] in the list")
+		}
+	}
+}
+
+func (p *parser) value(inList bool) (string, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		return p.quoted()
+	}
+	return p.bare(inList), nil
+}
+
+// bare reads an unquoted value up to the next space, or in a list the next
+// comma or closing bracket
+func (p *parser) bare(inList bool) string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if isSpace(c) || inList && (c == ',' || c == ']') {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// quoted reads a double-quoted string in which \" and \\ are escapes
+func (p *parser) quoted() (string, error) {
+	open := p.pos
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.input):
+			b.WriteByte(p.input[p.pos+1])
+			p.pos += 2
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf(open, "unterminated quote")
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	unit, ok := durationUnits[s[len(s)-1]]
+	if !ok {
+		return 0, fmt.Errorf("invalid duration unit in %q", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isKeyChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9', c == '.', c == '-':
+		return !first
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
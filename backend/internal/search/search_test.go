@@ -0,0 +1,155 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []Term
+	}{
+		{name: "empty", query: "", want: nil},
+		{name: "blank", query: " \t\n", want: nil},
+		{
+			name:  "full query",
+			query: `is:unresolved browser:Chrome times_seen:>100 age:-24h !message:"timeout"`,
+			want: []Term{
+				{Pos: 0, Key: "is", Kind: KindIs, Op: OpEqual, Values: []string{"unresolved"}},
+				{Pos: 14, Key: "browser", Kind: KindTag, Op: OpEqual, Values: []string{"Chrome"}},
+				{Pos: 29, Key: "times_seen", Kind: KindNumber, Op: OpGreater, Number: 100},
+				{Pos: 45, Key: "age", Kind: KindDate, Op: OpGreaterEqual, Time: now.Add(-24 * time.Hour)},
+				{Pos: 54, Negated: true, Key: "message", Kind: KindText, Op: OpEqual, Values: []string{"timeout"}},
+			},
+		},
+		{
+			name:  "free text",
+			query: `undefined "is not a function"`,
+			want: []Term{
+				{Pos: 0, Kind: KindText, Values: []string{"undefined"}},
+				{Pos: 10, Kind: KindText, Values: []string{"is not a function"}},
+			},
+		},
+		{
+			name:  "escapes in quotes",
+			query: `"say \"hi\" \\ bye"`,
+			want:  []Term{{Pos: 0, Kind: KindText, Values: []string{`say "hi" \ bye`}}},
+		},
+		{
+			name:  "list",
+			query: `type:[TypeError, "Range Error"]`,
+			want:  []Term{{Pos: 0, Key: "type", Kind: KindText, Op: OpEqual, Values: []string{"TypeError", "Range Error"}}},
+		},
+		{
+			name:  "is is case-insensitive",
+			query: "is:[Resolved,IGNORED]",
+			want:  []Term{{Pos: 0, Key: "is", Kind: KindIs, Op: OpEqual, Values: []string{"resolved", "ignored"}}},
+		},
+		{
+			name:  "older than",
+			query: "last_seen:+7d",
+			want:  []Term{{Pos: 0, Key: "last_seen", Kind: KindDate, Op: OpLessEqual, Time: now.Add(-7 * 24 * time.Hour)}},
+		},
+		{
+			name:  "date comparison",
+			query: "first_seen:>=2026-01-02",
+			want: []Term{{Pos: 0, Key: "first_seen", Kind: KindDate, Op: OpGreaterEqual,
+				Time: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "colon without key is text",
+			query: ":foo",
+			want:  []Term{{Pos: 0, Kind: KindText, Values: []string{":foo"}}},
+		},
+		{
+			name:  "colon in a value",
+			query: "url:https://example.com",
+			want:  []Term{{Pos: 0, Key: "url", Kind: KindText, Op: OpEqual, Values: []string{"https://example.com"}}},
+		},
+		{
+			name:  "positions are byte offsets",
+			query: "é has:release",
+			want: []Term{
+				{Pos: 0, Kind: KindText, Values: []string{"é"}},
+				{Pos: 3, Key: "has", Kind: KindHas, Op: OpEqual, Values: []string{"release"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.query, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{`"abc`, 0, "unterminated quote"},
+		{`"a\"`, 0, "unterminated quote"},
+		{`message:"abc`, 8, "unterminated quote"},
+		{`type:["a`, 6, "unterminated quote"},
+		{`""`, 0, "empty search term"},
+		{`!""`, 1, "empty search term"},
+		{"!", 0, "! must be followed by a term"},
+		{"a ! b", 2, "! must be followed by a term"},
+		{"message:", 8, "missing value for message"},
+		{"message: timeout", 8, "missing value for message"},
+		{"é message:", 11, "missing value for message"},
+		{"times_seen:>", 12, "missing value for times_seen"},
+		{"times_seen:abc", 11, `times_seen takes a number, got "abc"`},
+		{"times_seen:-1", 11, `times_seen takes a number, got "-1"`},
+		{"users:>99999999999999999999", 6, `users takes a number, got "99999999999999999999"`},
+		{"is:open", 3, `unknown value "open" for is, expected one of unresolved, resolved, ignored, regressed, investigating, active, assigned, unassigned`},
+		{"is:[resolved,]", 3, "empty value for is"},
+		{`browser:""`, 8, "empty value for browser"},
+		{"browser:[a,b", 8, "missing ] to close the list"},
+		{"browser:[", 8, "missing ] to close the list"},
+		{"browser:[a b]", 11, "expected , or ] in the list"},
+		{"age:>-24h", 4, "a relative time such as -24h cannot be combined with a comparison"},
+		{"age:-24x", 4, `invalid relative time "-24x", expected for example -24h or +7d`},
+		{"age:-h", 4, `invalid relative time "-h", expected for example -24h or +7d`},
+		{"age:--1h", 4, `invalid relative time "--1h", expected for example -24h or +7d`},
+		{"first_seen:>2026-13-01", 11, `invalid date "2026-13-01", expected for example 2026-01-02 or a relative time such as -24h`},
+		{"first_seen:2026-01-02", 11, "compare first_seen with a date using >, >=, < or <="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			terms, err := Parse(tt.query, now)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("Parse(%q) = %+v, %v, want a ParseError", tt.query, terms, err)
+			}
+			if perr.Pos != tt.pos || perr.Msg != tt.msg {
+				t.Errorf("Parse(%q) error at %d: %q, want at %d: %q", tt.query, perr.Pos, perr.Msg, tt.pos, tt.msg)
+			}
+			if terms != nil {
+				t.Errorf("Parse(%q) returned terms %+v with an error", tt.query, terms)
+			}
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	err := &ParseError{Pos: 4, Msg: "unterminated quote"}
+	if got, want := err.Error(), "invalid search at position 4: unterminated quote"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
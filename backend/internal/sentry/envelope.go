@@ -0,0 +1,97 @@
+package sentry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Envelope holds the error events of an envelope. Items of other types,
+// such as transactions, sessions and attachments, are counted in Skipped.
+type Envelope struct {
+	EventID string
+	Events  []*Event
+	Skipped int
+}
+
+type envelopeHeader struct {
+	EventID string `json:"event_id"`
+}
+
+type itemHeader struct {
+	Type   string `json:"type"`
+	Length *int   `json:"length"`
+}
+
+// ParseEnvelope reads an envelope: a header line, then items that each have
+// a header line and a payload of the header's length, or up to the next
+// newline when it has none
+func ParseEnvelope(body []byte) (*Envelope, error) {
+	line, rest := nextLine(body)
+	var header envelopeHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, fmt.Errorf("invalid envelope header: %w", err)
+	}
+	env := &Envelope{EventID: header.EventID}
+
+	for len(rest) > 0 {
+		line, rest = nextLine(rest)
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var item itemHeader
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("invalid item header: %w", err)
+		}
+
+		var payload []byte
+		if item.Length != nil {
+			n := *item.Length
+			if n < 0 || n > len(rest) {
+				return nil, fmt.Errorf("item length %d exceeds the envelope", n)
+			}
+			payload, rest = rest[:n], rest[n:]
+			rest = bytes.TrimPrefix(rest, []byte("\n"))
+		} else {
+			payload, rest = nextLine(rest)
+		}
+
+		if item.Type != "event" {
+			env.Skipped++
+			continue
+		}
+		event, err := ParseEvent(payload)
+		if err != nil {
+			return nil, err
+		}
+		if event.EventID == "" {
+			event.EventID = env.EventID
+		}
+		env.Events = append(env.Events, event)
+	}
+	return env, nil
+}
+
+func nextLine(b []byte) (line, rest []byte) {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		return b[:i], b[i+1:]
+	}
+	return b, nil
+}
+
+// AuthKey returns the public key of a Sentry auth header, as in
+// "Sentry sentry_key=abc, sentry_version=7", or "" if it has none
+func AuthKey(header string) string {
+	header = strings.TrimSpace(header)
+	if len(header) >= 7 && strings.EqualFold(header[:7], "sentry ") {
+		header = header[7:]
+	}
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && key == "sentry_key" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
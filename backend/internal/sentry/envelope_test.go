@@ -0,0 +1,144 @@
+package sentry
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvelope(t *testing.T) {
+	event := `{"event_id":"e1","message":"boom"}`
+	multiline := "{\"message\":\"line\\nbreak\",\n\"level\":\"error\"}"
+	attachment := "binary\n\x00data\n"
+
+	tests := []struct {
+		name     string
+		body     string
+		eventIDs []string
+		skipped  int
+		wantErr  string
+	}{
+		{name: "header only", body: `{"event_id":"env"}`},
+		{name: "header and newline", body: "{}\n"},
+		{
+			name:     "newline-delimited event",
+			body:     "{\"event_id\":\"env\"}\n{\"type\":\"event\"}\n" + event + "\n",
+			eventIDs: []string{"e1"},
+		},
+		{
+			name:     "last item without trailing newline",
+			body:     "{}\n{\"type\":\"event\"}\n" + event,
+			eventIDs: []string{"e1"},
+		},
+		{
+			name:     "event takes the envelope's id",
+			body:     "{\"event_id\":\"env\"}\n{\"type\":\"event\"}\n{\"message\":\"boom\"}\n",
+			eventIDs: []string{"env"},
+		},
+		{
+			name:     "length-prefixed event",
+			body:     "{}\n{\"type\":\"event\",\"length\":" + strconv.Itoa(len(event)) + "}\n" + event + "\n",
+			eventIDs: []string{"e1"},
+		},
+		{
+			name: "length-prefixed event with newlines in its payload",
+			body: "{\"event_id\":\"env\"}\n{\"type\":\"event\",\"length\":" + strconv.Itoa(len(multiline)) + "}\n" +
+				multiline + "\n",
+			eventIDs: []string{"env"},
+		},
+		{
+			name: "length-prefixed item without newline before the next",
+			body: "{}\n{\"type\":\"event\",\"length\":" + strconv.Itoa(len(event)) + "}\n" + event +
+				"{\"type\":\"event\"}\n" + event,
+			eventIDs: []string{"e1", "e1"},
+		},
+		{
+			name: "skipped items around an event",
+			body: "{}\n" +
+				"{\"type\":\"attachment\",\"length\":" + strconv.Itoa(len(attachment)) + "}\n" + attachment + "\n" +
+				"{\"type\":\"session\"}\n{\"started\":\"2026-10-17T12:00:00Z\"}\n" +
+				"{\"type\":\"event\"}\n" + event + "\n" +
+				"{\"type\":\"transaction\"}\nnot json\n",
+			eventIDs: []string{"e1"},
+			skipped:  3,
+		},
+		{
+			name:     "blank lines between items",
+			body:     "{}\n\n{\"type\":\"event\"}\n" + event + "\n\n\n",
+			eventIDs: []string{"e1"},
+		},
+		{
+			name:    "empty length-prefixed item",
+			body:    "{}\n{\"type\":\"client_report\",\"length\":0}\n\n",
+			skipped: 1,
+		},
+
+		// Malformed envelopes are rejected with an error, never a panic
+		{name: "empty", body: "", wantErr: "invalid envelope header"},
+		{name: "header not json", body: "event_id=1\n", wantErr: "invalid envelope header"},
+		{name: "header not an object", body: "[1]\n", wantErr: "invalid envelope header"},
+		{name: "item header not json", body: "{}\ntype=event\n" + event, wantErr: "invalid item header"},
+		{name: "item header truncated", body: "{}\n{\"type\":\"ev", wantErr: "invalid item header"},
+		{name: "length of the wrong type", body: "{}\n{\"type\":\"event\",\"length\":\"10\"}\n" + event, wantErr: "invalid item header"},
+		{name: "length overflowing int", body: "{}\n{\"type\":\"event\",\"length\":1e40}\n" + event, wantErr: "invalid item header"},
+		{name: "negative length", body: "{}\n{\"type\":\"event\",\"length\":-1}\n" + event, wantErr: "item length -1 exceeds the envelope"},
+		{
+			name:    "length past the end",
+			body:    "{}\n{\"type\":\"event\",\"length\":1000}\n" + event,
+			wantErr: "item length 1000 exceeds the envelope",
+		},
+		{
+			name:    "length cutting the event short",
+			body:    "{}\n{\"type\":\"event\",\"length\":10}\n" + event + "\n",
+			wantErr: "invalid event",
+		},
+		{
+			name:    "newline in an event without length",
+			body:    "{}\n{\"type\":\"event\"}\n" + multiline + "\n",
+			wantErr: "invalid event",
+		},
+		{name: "event without payload", body: "{}\n{\"type\":\"event\"}\n", wantErr: "invalid event"},
+		{name: "event not json", body: "{}\n{\"type\":\"event\"}\nboom\n", wantErr: "invalid event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := ParseEnvelope([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseEnvelope() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseEnvelope() error = %v", err)
+			}
+			var ids []string
+			for _, e := range env.Events {
+				ids = append(ids, e.EventID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.eventIDs, ",") || env.Skipped != tt.skipped {
+				t.Errorf("ParseEnvelope() events %v, skipped %d, want %v, %d", ids, env.Skipped, tt.eventIDs, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestAuthKey(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"Sentry sentry_key=abc, sentry_version=7", "abc"},
+		{"sentry sentry_version=7,sentry_key= abc ", "abc"},
+		{"sentry_key=abc", "abc"},
+		{"Sentry sentry_version=7", ""},
+		{"", ""},
+		{"Sentry", ""},
+	}
+	for _, tt := range tests {
+		if got := AuthKey(tt.header); got != tt.want {
+			t.Errorf("AuthKey(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
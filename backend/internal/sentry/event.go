@@ -0,0 +1,483 @@
+// Package sentry reads events sent by Sentry SDKs, through either the store
+// endpoint (one JSON event) or the envelope endpoint (a header line followed
+// by items), and maps them onto errors.
+package sentry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/stacktrace"
+)
+
+// Caps on the tags taken from an event, as Sentry applies them
+const (
+	MaxTags        = 50
+	MaxTagKeyLen   = 32
+	MaxTagValueLen = 200
+)
+
+// DefaultEnvironment is the environment of events that do not set one, as in Sentry
+const DefaultEnvironment = "production"
+
+// Event is the part of a Sentry event payload that is mapped onto an error
+type Event struct {
+	EventID     string                            `json:"event_id"`
+	Timestamp   Timestamp                         `json:"timestamp"`
+	Platform    string                            `json:"platform"`
+	Level       string                            `json:"level"`
+	Logger      string                            `json:"logger"`
+	Transaction string                            `json:"transaction"`
+	Culprit     string                            `json:"culprit"`
+	ServerName  string                            `json:"server_name"`
+	Release     string                            `json:"release"`
+	Environment string                            `json:"environment"`
+	Message     Message                           `json:"message"`
+	LogEntry    Message                           `json:"logentry"`
+	Exception   Exceptions                        `json:"exception"`
+	Breadcrumbs Breadcrumbs                       `json:"breadcrumbs"`
+	Tags        Tags                              `json:"tags"`
+	User        *User                             `json:"user"`
+	Request     *Request                          `json:"request"`
+	Contexts    map[string]map[string]interface{} `json:"contexts"`
+	Extra       map[string]interface{}            `json:"extra"`
+	Fingerprint []string                          `json:"fingerprint"`
+	SDK         *SDK                              `json:"sdk"`
+}
+
+// Exception is one exception of an event; chained exceptions are listed
+// oldest first, so the one raised last comes last
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Module     string      `json:"module"`
+	Stacktrace *Stacktrace `json:"stacktrace"`
+}
+
+// Stacktrace lists frames outermost first, the reverse of models.Error
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+type Frame struct {
+	Filename    string   `json:"filename"`
+	AbsPath     string   `json:"abs_path"`
+	Function    string   `json:"function"`
+	Module      string   `json:"module"`
+	Lineno      int      `json:"lineno"`
+	Colno       int      `json:"colno"`
+	InApp       *bool    `json:"in_app"`
+	PreContext  []string `json:"pre_context"`
+	ContextLine string   `json:"context_line"`
+	PostContext []string `json:"post_context"`
+}
+
+type Breadcrumb struct {
+	Timestamp Timestamp              `json:"timestamp"`
+	Type      string                 `json:"type"`
+	Category  string                 `json:"category"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+type User struct {
+	ID        String `json:"id"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+	IPAddress string `json:"ip_address"`
+}
+
+type Request struct {
+	URL     string `json:"url"`
+	Method  string `json:"method"`
+	Headers Tags   `json:"headers"`
+}
+
+type SDK struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ParseEvent decodes the JSON payload of an event
+func ParseEvent(payload []byte) (*Event, error) {
+	var e Event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	return &e, nil
+}
+
+// ToError maps the event onto an error of the project. The last exception
+// gives the type, message and frames; an event without one is reported by
+// its message. Events without a timestamp, or stamped in the future, occur at
+// now.
+func (e *Event) ToError(projectID string, now time.Time) *models.Error {
+	out := &models.Error{
+		ProjectID:   projectID,
+		Message:     e.message(),
+		Environment: e.Environment,
+		Release:     e.Release,
+		OccurredAt:  now,
+		Status:      "ACTIVE",
+	}
+	if out.Environment == "" {
+		out.Environment = DefaultEnvironment
+	}
+	if t := time.Time(e.Timestamp); !t.IsZero() && t.Before(now) {
+		out.OccurredAt = t
+	}
+
+	if exc := e.primaryException(); exc != nil {
+		out.Type = exc.Type
+		if exc.Value != "" {
+			out.Message = exc.Value
+		}
+		if exc.Stacktrace != nil {
+			out.Frames = e.frames(exc.Stacktrace.Frames)
+		}
+		out.StackTrace = renderStack(e.Platform, out.Type, out.Message, out.Frames)
+	}
+	if out.Message == "" {
+		out.Message = out.Type
+	}
+
+	out.Source = e.Culprit
+	if len(out.Frames) > 0 {
+		out.Source = out.Frames[0].File
+	} else if out.Source == "" {
+		out.Source = e.Transaction
+	}
+
+	if e.User != nil {
+		out.UserID = firstNonEmpty(string(e.User.ID), e.User.Email, e.User.Username)
+	}
+	if e.Request != nil {
+		out.URL = e.Request.URL
+		out.BrowserInfo = e.Request.Headers.Get("User-Agent")
+	}
+	if out.BrowserInfo == "" {
+		// The browser context names it, e.g. Chrome 120, which the browser tag matches
+		if browser := e.Contexts["browser"]; browser != nil {
+			name, _ := browser["name"].(string)
+			version, _ := browser["version"].(string)
+			out.BrowserInfo = strings.TrimSpace(name + " " + version)
+		}
+	}
+
+	for _, b := range e.Breadcrumbs {
+		category := b.Category
+		if category == "" {
+			category = b.Type
+		}
+		out.Breadcrumbs = append(out.Breadcrumbs, models.Breadcrumb{
+			Timestamp: time.Time(b.Timestamp),
+			Category:  category,
+			Level:     b.Level,
+			Message:   b.Message,
+			Data:      b.Data,
+		})
+	}
+
+	for _, tag := range e.Tags {
+		if len(out.Tags) == MaxTags {
+			break
+		}
+		if tag.Key == "" || tag.Value == "" || len(tag.Key) > MaxTagKeyLen {
+			continue
+		}
+		out.Tags = append(out.Tags, models.ErrorTag{Key: tag.Key, Value: truncate(tag.Value, MaxTagValueLen)})
+	}
+	return out
+}
+
+// Metadata returns what the occurrence keeps of the event beyond the error fields
+func (e *Event) Metadata() map[string]interface{} {
+	m := map[string]interface{}{
+		"eventId":  e.EventID,
+		"platform": e.Platform,
+		"level":    e.Level,
+	}
+	for key, value := range map[string]string{
+		"logger":      e.Logger,
+		"transaction": e.Transaction,
+		"serverName":  e.ServerName,
+	} {
+		if value != "" {
+			m[key] = value
+		}
+	}
+	if e.SDK != nil {
+		m["sdk"] = e.SDK.Name + "/" + e.SDK.Version
+	}
+	if e.User != nil {
+		m["user"] = e.User
+	}
+	if len(e.Contexts) > 0 {
+		m["contexts"] = e.Contexts
+	}
+	if len(e.Extra) > 0 {
+		m["extra"] = e.Extra
+	}
+	return m
+}
+
+func (e *Event) message() string {
+	for _, m := range []Message{e.LogEntry, e.Message} {
+		if s := firstNonEmpty(m.Formatted, m.Message); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func (e *Event) primaryException() *Exception {
+	if len(e.Exception) == 0 {
+		return nil
+	}
+	return &e.Exception[len(e.Exception)-1]
+}
+
+// frames converts Sentry frames into frames innermost first
+func (e *Event) frames(frames []Frame) []models.StackFrame {
+	out := make([]models.StackFrame, 0, len(frames))
+	for i := len(frames) - 1; i >= 0; i-- {
+		f := frames[i]
+		frame := models.StackFrame{
+			Function:    f.Function,
+			File:        firstNonEmpty(f.AbsPath, f.Filename),
+			Line:        f.Lineno,
+			Column:      f.Colno,
+			PreContext:  f.PreContext,
+			ContextLine: f.ContextLine,
+			PostContext: f.PostContext,
+		}
+		// JVM frames name the class in module and only the method in function
+		if e.Platform == "java" && f.Module != "" && f.Function != "" {
+			frame.Function = f.Module + "." + f.Function
+		}
+		if f.InApp != nil {
+			frame.InApp = *f.InApp
+		} else {
+			frame.InApp = stacktrace.InApp(frame)
+		}
+		out = append(out, frame)
+	}
+	return out
+}
+
+// renderStack writes frames as a stack trace in the format of the platform,
+// which package stacktrace parses back into the same frames
+func renderStack(platform, typ, message string, frames []models.StackFrame) string {
+	var b strings.Builder
+	header := message
+	if typ != "" {
+		header = typ + ": " + message
+	}
+
+	switch platform {
+	case "python":
+		b.WriteString("Traceback (most recent call last):\n")
+		for i := len(frames) - 1; i >= 0; i-- {
+			f := frames[i]
+			fmt.Fprintf(&b, "  File \"%s\", line %d, in %s\n", f.File, f.Line, f.Function)
+		}
+		b.WriteString(header)
+	case "java":
+		b.WriteString(header)
+		for _, f := range frames {
+			fmt.Fprintf(&b, "\n\tat %s(%s:%d)", f.Function, f.File, f.Line)
+		}
+	default:
+		b.WriteString(header)
+		for _, f := range frames {
+			location := f.File
+			if f.Line > 0 {
+				location += ":" + strconv.Itoa(f.Line)
+				if f.Column > 0 {
+					location += ":" + strconv.Itoa(f.Column)
+				}
+			}
+			if f.Function == "" {
+				fmt.Fprintf(&b, "\n    at %s", location)
+			} else {
+				fmt.Fprintf(&b, "\n    at %s (%s)", f.Function, location)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Timestamp is a time sent either as Unix seconds or as an RFC 3339 string,
+// which Sentry allows without a time zone for UTC
+type Timestamp time.Time
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if data[0] != '"' {
+		secs, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %s", data)
+		}
+		*t = unixTime(secs)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			*t = Timestamp(parsed.UTC())
+			return nil
+		}
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		*t = unixTime(secs)
+		return nil
+	}
+	return fmt.Errorf("invalid timestamp %q", s)
+}
+
+func unixTime(secs float64) Timestamp {
+	whole := int64(secs)
+	return Timestamp(time.Unix(whole, int64((secs-float64(whole))*1e9)).UTC())
+}
+
+// Message is a message sent either as a string or as a log entry object
+type Message struct {
+	Message   string `json:"message"`
+	Formatted string `json:"formatted"`
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &m.Formatted)
+	}
+	type message Message
+	return json.Unmarshal(data, (*message)(m))
+}
+
+// Exceptions are sent either as a list or as an object with the list in values
+type Exceptions []Exception
+
+func (e *Exceptions) UnmarshalJSON(data []byte) error {
+	return unmarshalValues(data, (*[]Exception)(e))
+}
+
+// Breadcrumbs are sent either as a list or as an object with the list in values
+type Breadcrumbs []Breadcrumb
+
+func (b *Breadcrumbs) UnmarshalJSON(data []byte) error {
+	return unmarshalValues(data, (*[]Breadcrumb)(b))
+}
+
+func unmarshalValues[T any](data []byte, dst *[]T) error {
+	if len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, dst)
+	}
+	var wrapped struct {
+		Values []T `json:"values"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+	*dst = wrapped.Values
+	return nil
+}
+
+// Tag is a key and value of tags or request headers
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Tags are sent either as an object or as a list of [key, value] pairs,
+// with values that are not always strings. Object keys are sorted.
+type Tags []Tag
+
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var pairs [][]interface{}
+		if err := json.Unmarshal(data, &pairs); err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			if len(pair) == 2 && pair[0] != nil && pair[1] != nil {
+				*t = append(*t, Tag{Key: fmt.Sprint(pair[0]), Value: fmt.Sprint(pair[1])})
+			}
+		}
+		return nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := object[key]; value != nil {
+			*t = append(*t, Tag{Key: key, Value: fmt.Sprint(value)})
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the first tag with the key, ignoring case
+func (t Tags) Get(key string) string {
+	for _, tag := range t {
+		if strings.EqualFold(tag.Key, key) {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// String is a string that may be sent as a number, such as a user id
+type String string
+
+func (s *String) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, (*string)(s))
+	}
+	if !bytes.Equal(data, []byte("null")) {
+		*s = String(data)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/promql"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/notifier"
+
+	"github.com/google/uuid"
+)
+
+const ruleEvaluationTimeout = 30 * time.Second
+
+// AlertEvaluator periodically evaluates every enabled alert rule and records
+// firing and resolved transitions as alert instances.
+type AlertEvaluator struct {
+	alertRepo      *postgres.AlertRepository
+	ruleRepo       *postgres.AlertRuleRepository
+	errorService   *ErrorService
+	sessionService *SessionService
+	metricsService *MetricsService
+	notifications  *NotificationService
+	logger         *logger.Logger
+	interval       time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+func NewAlertEvaluator(
+	alertRepo *postgres.AlertRepository,
+	ruleRepo *postgres.AlertRuleRepository,
+	errorService *ErrorService,
+	sessionService *SessionService,
+	metricsService *MetricsService,
+	notifications *NotificationService,
+	logger *logger.Logger,
+	interval time.Duration,
+) *AlertEvaluator {
+	return &AlertEvaluator{
+		alertRepo:      alertRepo,
+		ruleRepo:       ruleRepo,
+		errorService:   errorService,
+		sessionService: sessionService,
+		metricsService: metricsService,
+		notifications:  notifications,
+		logger:         logger,
+		interval:       interval,
+	}
+}
+
+// Start evaluates rules every interval until Stop is called or ctx ends.
+func (e *AlertEvaluator) Start(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.cancel != nil {
+		return
+	}
+
+	ctx, e.cancel = context.WithCancel(ctx)
+	e.done = make(chan struct{})
+
+	go func() {
+		defer close(e.done)
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			e.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the evaluation loop and waits for the current run to finish.
+func (e *AlertEvaluator) Stop() {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel = nil
+	e.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// RunOnce evaluates every rule that is due.
+func (e *AlertEvaluator) RunOnce(ctx context.Context) {
+	// Claim with half the interval as slack so ticker jitter never skips a run
+	rules, err := e.ruleRepo.ClaimDue(ctx, e.interval/2)
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Error(ctx, "Failed to load due alert rules", err)
+		}
+		return
+	}
+
+	for _, rule := range rules {
+		if ctx.Err() != nil {
+			return
+		}
+		e.evaluate(ctx, rule)
+	}
+}
+
+// firingSeries is one thing a rule currently fires for
+type firingSeries struct {
+	fingerprint string
+	message     string
+	labels      map[string]string
+	value       float64
+}
+
+func (e *AlertEvaluator) evaluate(ctx context.Context, rule *models.AlertRule) {
+	ctx, cancel := context.WithTimeout(logger.WithProjectID(ctx, rule.ProjectID), ruleEvaluationTimeout)
+	defer cancel()
+
+	series, err := e.check(ctx, rule)
+	if err == nil {
+		err = e.applyTransitions(ctx, rule, series)
+	}
+
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+		e.logger.Error(ctx, "Failed to evaluate alert rule", err, "rule_id", rule.ID)
+	}
+	if rule.LastError != lastError {
+		if err := e.ruleRepo.SetLastError(ctx, rule.ID, lastError); err != nil {
+			e.logger.Error(ctx, "Failed to record alert rule status", err, "rule_id", rule.ID)
+		}
+	}
+}
+
+// check returns the series the rule's condition currently fires for
+func (e *AlertEvaluator) check(ctx context.Context, rule *models.AlertRule) ([]firingSeries, error) {
+	since := time.Now().Add(-rule.Window())
+
+	switch rule.ConditionType {
+	case models.ConditionNewFingerprint:
+		errs, err := e.errorService.ListFirstSeenSince(ctx, rule.ProjectID, rule.Environment, since)
+		if err != nil {
+			return nil, err
+		}
+		series := make([]firingSeries, 0, len(errs))
+		for _, item := range errs {
+			series = append(series, firingSeries{
+				fingerprint: item.Fingerprint,
+				message:     fmt.Sprintf("New error: %s", errorTitle(item)),
+				labels: map[string]string{
+					"error_id":    item.ID,
+					"environment": item.Environment,
+				},
+				value: float64(item.Count),
+			})
+		}
+		return series, nil
+
+	case models.ConditionErrorCount:
+		count, err := e.errorService.CountOccurrencesSince(ctx, rule.ProjectID, rule.Environment, since)
+		if err != nil {
+			return nil, err
+		}
+		if float64(count) <= rule.Threshold {
+			return nil, nil
+		}
+		return []firingSeries{{
+			message: fmt.Sprintf("%d errors in the last %s (threshold %g)", count, rule.Window(), rule.Threshold),
+			labels:  map[string]string{},
+			value:   float64(count),
+		}}, nil
+
+	case models.ConditionSessionErrorRate:
+		total, errored, err := e.sessionService.CountSessionsWithErrorsSince(ctx, rule.ProjectID, since)
+		if err != nil {
+			return nil, err
+		}
+		if total == 0 {
+			return nil, nil
+		}
+		rate := float64(errored) / float64(total)
+		if rate <= rule.Threshold {
+			return nil, nil
+		}
+		return []firingSeries{{
+			message: fmt.Sprintf("%.1f%% of sessions had errors in the last %s (threshold %.1f%%)", rate*100, rule.Window(), rule.Threshold*100),
+			labels:  map[string]string{},
+			value:   rate,
+		}}, nil
+
+	case models.ConditionPromQL:
+		// Rules are checked when saved, but only the scoped query may run
+		query, err := promql.Scope(rule.Query, rule.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		samples, err := e.metricsService.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		series := make([]firingSeries, 0, len(samples))
+		for _, sample := range samples {
+			if sample.Value <= rule.Threshold {
+				continue
+			}
+			series = append(series, firingSeries{
+				fingerprint: labelsFingerprint(sample.Labels),
+				message:     fmt.Sprintf("%s = %g", formatLabels(sample.Labels), sample.Value),
+				labels:      sample.Labels,
+				value:       sample.Value,
+			})
+		}
+		return series, nil
+	}
+
+	return nil, fmt.Errorf("unknown condition type %q", rule.ConditionType)
+}
+
+// applyTransitions opens alerts for new series, refreshes the ones still
+// firing and resolves the ones that stopped
+func (e *AlertEvaluator) applyTransitions(ctx context.Context, rule *models.AlertRule, series []firingSeries) error {
+	open, err := e.alertRepo.ListFiringByRule(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+	byFingerprint := make(map[string]*models.Alert, len(open))
+	for _, alert := range open {
+		byFingerprint[alert.Fingerprint] = alert
+	}
+
+	now := time.Now()
+	for _, s := range series {
+		value := s.value
+		if alert, ok := byFingerprint[s.fingerprint]; ok {
+			delete(byFingerprint, s.fingerprint)
+			if err := e.alertRepo.UpdateValue(ctx, alert.ID, &value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		alert := &models.Alert{
+			ID:          uuid.NewString(),
+			ProjectID:   rule.ProjectID,
+			RuleID:      rule.ID,
+			Message:     fmt.Sprintf("%s: %s", rule.Name, s.message),
+			Severity:    rule.Severity,
+			Status:      models.AlertStatusFiring,
+			Fingerprint: s.fingerprint,
+			Source:      models.AlertSourceRule,
+			Labels:      s.labels,
+			Value:       &value,
+			StartedAt:   now,
+			CreatedAt:   now,
+		}
+		if err := e.alertRepo.Create(ctx, alert); err != nil {
+			return err
+		}
+		e.logger.Info(ctx, "Alert firing", "rule_id", rule.ID, "alert_id", alert.ID)
+		e.notifications.NotifyAlert(ctx, alert, notifier.EventAlertFiring)
+	}
+
+	for _, alert := range byFingerprint {
+		if err := e.alertRepo.Resolve(ctx, alert.ID, now); err != nil {
+			return err
+		}
+		e.logger.Info(ctx, "Alert resolved", "rule_id", rule.ID, "alert_id", alert.ID)
+
+		resolvedAt := now
+		alert.Status = models.AlertStatusResolved
+		alert.ResolvedAt = &resolvedAt
+		e.notifications.NotifyAlert(ctx, alert, notifier.EventAlertResolved)
+	}
+
+	return nil
+}
+
+func errorTitle(e *models.Error) string {
+	if e.Type == "" {
+		return e.Message
+	}
+	return e.Type + ": " + e.Message
+}
+
+// labelsFingerprint identifies a metric series by its sorted label set
+func labelsFingerprint(labels map[string]string) string {
+	h := sha1.New()
+	for _, key := range sortedKeys(labels) {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(labels[key]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func formatLabels(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for _, key := range sortedKeys(labels) {
+		parts = append(parts, fmt.Sprintf("%s=%q", key, labels[key]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/telemetry"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// prometheusServer answers instant queries like the Prometheus HTTP API. It
+// only evaluates plain series selectors, returning the loaded series their
+// matchers select.
+func prometheusServer(t *testing.T, series map[string]float64) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expr, err := parser.ParseExpr(r.URL.Query().Get("query"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sel, ok := expr.(*parser.VectorSelector)
+		if !ok {
+			http.Error(w, "only series selectors are supported", http.StatusBadRequest)
+			return
+		}
+
+		result := make([]map[string]any, 0)
+		for text, value := range series {
+			lset, err := parser.ParseMetric(text)
+			if err != nil {
+				t.Errorf("ParseMetric(%q) error = %v", text, err)
+				continue
+			}
+			if !matchesAll(sel.LabelMatchers, lset) {
+				continue
+			}
+			result = append(result, map[string]any{
+				"metric": lset.Map(),
+				"value":  []any{float64(600), strconv.FormatFloat(value, 'f', -1, 64)},
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"status": "success",
+			"data":   map[string]any{"resultType": "vector", "result": result},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func matchesAll(matchers []*labels.Matcher, lset labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lset.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEvaluatorScopesPromQLRules(t *testing.T) {
+	srv := prometheusServer(t, map[string]float64{
+		`pulseguard_http_errors_total{project_id="project-1", path="/checkout"}`: 100,
+		`pulseguard_http_errors_total{project_id="project-1", path="/login"}`:    10,
+		`pulseguard_http_errors_total{project_id="project-2", path="/checkout"}`: 1000,
+		`pulseguard_http_errors_total{path="/checkout"}`:                         5000,
+	})
+	evaluator := &AlertEvaluator{
+		metricsService: NewMetricsService(telemetry.NewPrometheusRepository(srv.URL)),
+	}
+
+	tests := []struct {
+		name      string
+		projectID string
+		query     string
+		want      map[string]float64
+	}{
+		{
+			name:      "only the project's series",
+			projectID: "project-1",
+			query:     `pulseguard_http_errors_total`,
+			want:      map[string]float64{"/checkout": 100},
+		},
+		{
+			name:      "the rule's own matchers still apply",
+			projectID: "project-1",
+			query:     `pulseguard_http_errors_total{path="/login"}`,
+			want:      map[string]float64{},
+		},
+		{
+			name:      "an explicit matcher for the project",
+			projectID: "project-1",
+			query:     `pulseguard_http_errors_total{project_id="project-1"}`,
+			want:      map[string]float64{"/checkout": 100},
+		},
+		{
+			name:      "another project",
+			projectID: "project-2",
+			query:     `pulseguard_http_errors_total`,
+			want:      map[string]float64{"/checkout": 1000},
+		},
+		{
+			name:      "a project without series",
+			projectID: "project-3",
+			query:     `pulseguard_http_errors_total`,
+			want:      map[string]float64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &models.AlertRule{
+				ProjectID:     tt.projectID,
+				ConditionType: models.ConditionPromQL,
+				Query:         tt.query,
+				Threshold:     20,
+			}
+			series, err := evaluator.check(context.Background(), rule)
+			if err != nil {
+				t.Fatalf("check() error = %v", err)
+			}
+
+			got := make(map[string]float64, len(series))
+			for _, s := range series {
+				if id := s.labels["project_id"]; id != tt.projectID {
+					t.Errorf("check() fired for project_id %q", id)
+				}
+				got[s.labels["path"]] = s.value
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("check() = %v, want %v", got, tt.want)
+			}
+			for path, value := range tt.want {
+				if got[path] != value {
+					t.Errorf("check() %s = %g, want %g", path, got[path], value)
+				}
+			}
+		})
+	}
+}
@@ -2,36 +2,240 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"pulseguard/internal/models"
+	"pulseguard/internal/promql"
 	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/notifier"
 
 	"github.com/google/uuid"
 )
 
+var (
+    ErrAlertRuleNotFound = errors.New("alert rule not found")
+    ErrInvalidAlertRule  = errors.New("invalid alert rule")
+    ErrAlertNotFound     = errors.New("alert not found")
+    ErrInvalidTransition = errors.New("alert cannot make this transition from its current status")
+    ErrAlertConflict     = errors.New("another alert with the same fingerprint is already open")
+)
+
+const defaultRuleWindow = 5 * time.Minute
+
 type AlertService struct {
     alertRepo *postgres.AlertRepository
+    ruleRepo  *postgres.AlertRuleRepository
+    projectRepo *postgres.ProjectRepository
+    notifications *NotificationService
 }
 
-func NewAlertService(alertRepo *postgres.AlertRepository) *AlertService {
-    return &AlertService{alertRepo: alertRepo}
+func NewAlertService(alertRepo *postgres.AlertRepository, ruleRepo *postgres.AlertRuleRepository, projectRepo *postgres.ProjectRepository, notifications *NotificationService) *AlertService {
+    return &AlertService{alertRepo: alertRepo, ruleRepo: ruleRepo, projectRepo: projectRepo, notifications: notifications}
 }
 
 func (s *AlertService) Create(ctx context.Context, projectID, message, severity string) (*models.Alert, error) {
+    now := time.Now()
     alert := &models.Alert{
         ID:        uuid.NewString(),
         ProjectID: projectID,
         Message:   message,
         Severity:  severity,
-        CreatedAt: time.Now(),
+        Status:    models.AlertStatusFiring,
+        Source:    models.AlertSourceManual,
+        Labels:    map[string]string{},
+        StartedAt: now,
+        CreatedAt: now,
     }
     if err := s.alertRepo.Create(ctx, alert); err != nil {
         return nil, err
     }
+    s.notifications.NotifyAlert(ctx, alert, notifier.EventAlertFiring)
     return alert, nil
 }
 
 func (s *AlertService) ListByProject(ctx context.Context, projectID string) ([]*models.Alert, error) {
     return s.alertRepo.ListByProject(ctx, projectID)
-}
\ No newline at end of file
+}
+
+// GetAlert returns one of the project's alerts
+func (s *AlertService) GetAlert(ctx context.Context, projectID, alertID string) (*models.Alert, error) {
+    if _, err := uuid.Parse(alertID); err != nil {
+        return nil, ErrAlertNotFound
+    }
+    alert, err := s.alertRepo.GetByID(ctx, projectID, alertID)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, ErrAlertNotFound
+        }
+        return nil, err
+    }
+    return alert, nil
+}
+
+// AcknowledgeAlert marks a firing alert as being handled by the user
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, projectID, alertID, userID, note string) (*models.Alert, error) {
+    if _, err := uuid.Parse(alertID); err != nil {
+        return nil, ErrAlertNotFound
+    }
+    alert, err := s.alertRepo.Acknowledge(ctx, projectID, alertID, userID, note, time.Now())
+    if err != nil {
+        return nil, s.transitionError(ctx, projectID, alertID, err)
+    }
+    s.notifications.NotifyAlert(ctx, alert, notifier.EventAlertAcknowledged)
+    return alert, nil
+}
+
+// ResolveAlert closes an open alert on behalf of the user
+func (s *AlertService) ResolveAlert(ctx context.Context, projectID, alertID, userID, note string) (*models.Alert, error) {
+    if _, err := uuid.Parse(alertID); err != nil {
+        return nil, ErrAlertNotFound
+    }
+    alert, err := s.alertRepo.ResolveBy(ctx, projectID, alertID, userID, note, time.Now())
+    if err != nil {
+        return nil, s.transitionError(ctx, projectID, alertID, err)
+    }
+    s.notifications.NotifyAlert(ctx, alert, notifier.EventAlertResolved)
+    return alert, nil
+}
+
+// ReopenAlert puts a resolved alert back to firing
+func (s *AlertService) ReopenAlert(ctx context.Context, projectID, alertID, userID, note string) (*models.Alert, error) {
+    if _, err := uuid.Parse(alertID); err != nil {
+        return nil, ErrAlertNotFound
+    }
+    alert, err := s.alertRepo.Reopen(ctx, projectID, alertID, userID, note, time.Now())
+    if err != nil {
+        if isUniqueViolation(err) {
+            return nil, ErrAlertConflict
+        }
+        return nil, s.transitionError(ctx, projectID, alertID, err)
+    }
+    s.notifications.NotifyAlert(ctx, alert, notifier.EventAlertFiring)
+    return alert, nil
+}
+
+// ListAlertEvents returns the transition history of one of the project's alerts
+func (s *AlertService) ListAlertEvents(ctx context.Context, projectID, alertID string) ([]*models.AlertEvent, error) {
+    if _, err := s.GetAlert(ctx, projectID, alertID); err != nil {
+        return nil, err
+    }
+    return s.alertRepo.ListEvents(ctx, projectID, alertID)
+}
+
+// transitionError tells a missing alert apart from one in the wrong status
+func (s *AlertService) transitionError(ctx context.Context, projectID, alertID string, err error) error {
+    if !errors.Is(err, sql.ErrNoRows) {
+        return err
+    }
+    if _, err := s.GetAlert(ctx, projectID, alertID); err != nil {
+        return err
+    }
+    return ErrInvalidTransition
+}
+
+// CreateRule validates and stores a new alert rule for the project
+func (s *AlertService) CreateRule(ctx context.Context, rule *models.AlertRule) (*models.AlertRule, error) {
+    if err := normalizeRule(rule); err != nil {
+        return nil, err
+    }
+
+    now := time.Now()
+    rule.ID = uuid.NewString()
+    rule.CreatedAt = now
+    rule.UpdatedAt = now
+
+    if err := s.ruleRepo.Create(ctx, rule); err != nil {
+        return nil, err
+    }
+    return rule, nil
+}
+
+func (s *AlertService) ListRules(ctx context.Context, projectID string) ([]*models.AlertRule, error) {
+    return s.ruleRepo.ListByProject(ctx, projectID)
+}
+
+// GetRule returns one of the project's rules
+func (s *AlertService) GetRule(ctx context.Context, projectID, ruleID string) (*models.AlertRule, error) {
+    if _, err := uuid.Parse(ruleID); err != nil {
+        return nil, ErrAlertRuleNotFound
+    }
+    rule, err := s.ruleRepo.GetByID(ctx, projectID, ruleID)
+    if err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, ErrAlertRuleNotFound
+        }
+        return nil, err
+    }
+    return rule, nil
+}
+
+// UpdateRule validates and saves changes to an existing rule
+func (s *AlertService) UpdateRule(ctx context.Context, rule *models.AlertRule) (*models.AlertRule, error) {
+    if err := normalizeRule(rule); err != nil {
+        return nil, err
+    }
+    rule.UpdatedAt = time.Now()
+
+    if err := s.ruleRepo.Update(ctx, rule); err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return nil, ErrAlertRuleNotFound
+        }
+        return nil, err
+    }
+    return rule, nil
+}
+
+func (s *AlertService) DeleteRule(ctx context.Context, projectID, ruleID string) error {
+    if _, err := uuid.Parse(ruleID); err != nil {
+        return ErrAlertRuleNotFound
+    }
+    if err := s.ruleRepo.Delete(ctx, projectID, ruleID); err != nil {
+        if errors.Is(err, sql.ErrNoRows) {
+            return ErrAlertRuleNotFound
+        }
+        return err
+    }
+    return nil
+}
+
+// normalizeRule fills defaults and rejects rules the evaluator cannot run
+func normalizeRule(rule *models.AlertRule) error {
+    rule.Name = strings.TrimSpace(rule.Name)
+    if rule.Name == "" {
+        return ErrInvalidAlertRule
+    }
+    if rule.Severity == "" {
+        rule.Severity = "warning"
+    }
+    if rule.WindowSeconds < 0 || rule.Threshold < 0 {
+        return ErrInvalidAlertRule
+    }
+    if rule.WindowSeconds == 0 {
+        rule.WindowSeconds = int(defaultRuleWindow.Seconds())
+    }
+
+    switch rule.ConditionType {
+    case models.ConditionNewFingerprint, models.ConditionErrorCount:
+        rule.Query = ""
+    case models.ConditionSessionErrorRate:
+        if rule.Threshold > 1 {
+            return ErrInvalidAlertRule
+        }
+        rule.Query = ""
+    case models.ConditionPromQL:
+        if strings.TrimSpace(rule.Query) == "" {
+            return ErrInvalidAlertRule
+        }
+        // The evaluator scopes the query again each time it runs it
+        if _, err := promql.Scope(rule.Query, rule.ProjectID); err != nil {
+            return fmt.Errorf("%w: %w", ErrInvalidAlertRule, err)
+        }
+    default:
+        return ErrInvalidAlertRule
+    }
+    return nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/promql"
+)
+
+func TestNormalizePromQLRule(t *testing.T) {
+	tests := []struct {
+		query string
+		want  error
+	}{
+		{`sum(rate(http_errors_total[5m])) > 1`, nil},
+		{`up{project_id="project-1"}`, nil},
+		{`up{project_id="project-2"}`, promql.ErrUnscoped},
+		{`up{project_id=~".*"}`, promql.ErrUnscoped},
+		{`sum(rate(up[5m])`, promql.ErrInvalidQuery},
+	}
+	for _, tt := range tests {
+		rule := &models.AlertRule{ProjectID: "project-1", Name: "rule", ConditionType: models.ConditionPromQL, Query: tt.query}
+		err := normalizeRule(rule)
+		if tt.want == nil && err != nil {
+			t.Errorf("normalizeRule(%q) error = %v", tt.query, err)
+		}
+		if tt.want != nil && (!errors.Is(err, tt.want) || !errors.Is(err, ErrInvalidAlertRule)) {
+			t.Errorf("normalizeRule(%q) error = %v, want %v", tt.query, err, tt.want)
+		}
+	}
+}
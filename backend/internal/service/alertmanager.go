@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/notifier"
+
+	"github.com/google/uuid"
+)
+
+// Labels that select the PulseGuard project of an Alertmanager alert, by ID or slug
+const (
+	alertmanagerProjectIDLabel = "project_id"
+	alertmanagerProjectLabel   = "project"
+)
+
+// AlertmanagerPayload is the body Alertmanager posts to webhook receivers (version 4)
+type AlertmanagerPayload struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertmanagerImport summarises what a webhook call changed
+type AlertmanagerImport struct {
+	Received int `json:"received"`
+	Firing   int `json:"firing"`
+	Resolved int `json:"resolved"`
+	Skipped  int `json:"skipped"`
+}
+
+// ImportAlertmanager upserts the alerts of an Alertmanager notification.
+// Each alert is mapped to a project by its project_id or project label,
+// falling back to defaultProject. Alerts that match no project are skipped.
+func (s *AlertService) ImportAlertmanager(ctx context.Context, payload *AlertmanagerPayload, defaultProject string) (AlertmanagerImport, error) {
+	result := AlertmanagerImport{Received: len(payload.Alerts)}
+	projects := map[string]string{}
+
+	for _, am := range payload.Alerts {
+		projectID, err := s.alertmanagerProject(ctx, am.Labels, defaultProject, projects)
+		if err != nil {
+			return result, err
+		}
+		if projectID == "" {
+			result.Skipped++
+			continue
+		}
+
+		fingerprint := am.Fingerprint
+		if fingerprint == "" {
+			fingerprint = labelsFingerprint(am.Labels)
+		}
+
+		if am.Status == models.AlertStatusResolved {
+			resolvedAt := am.EndsAt
+			if resolvedAt.IsZero() {
+				resolvedAt = time.Now()
+			}
+			alert, err := s.alertRepo.ResolveByFingerprint(ctx, projectID, models.AlertSourceAlertmanager, fingerprint, resolvedAt)
+			if errors.Is(err, sql.ErrNoRows) {
+				result.Skipped++
+				continue
+			}
+			if err != nil {
+				return result, err
+			}
+			result.Resolved++
+			s.notifications.NotifyAlert(ctx, alert, notifier.EventAlertResolved)
+			continue
+		}
+
+		now := time.Now()
+		startedAt := am.StartsAt
+		if startedAt.IsZero() {
+			startedAt = now
+		}
+		labels := am.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		alert := &models.Alert{
+			ID:          uuid.NewString(),
+			ProjectID:   projectID,
+			Message:     alertmanagerMessage(am),
+			Severity:    alertmanagerSeverity(am.Labels),
+			Status:      models.AlertStatusFiring,
+			Fingerprint: fingerprint,
+			Source:      models.AlertSourceAlertmanager,
+			Labels:      labels,
+			StartedAt:   startedAt,
+			CreatedAt:   now,
+		}
+		created, err := s.alertRepo.UpsertFiring(ctx, alert)
+		if err != nil {
+			return result, err
+		}
+		result.Firing++
+		if created {
+			s.notifications.NotifyAlert(ctx, alert, notifier.EventAlertFiring)
+		}
+	}
+
+	return result, nil
+}
+
+// alertmanagerProject resolves the project of an alert, caching lookups for
+// the rest of the payload. It returns "" when no project matches.
+func (s *AlertService) alertmanagerProject(ctx context.Context, labels map[string]string, defaultProject string, cache map[string]string) (string, error) {
+	ref := labels[alertmanagerProjectIDLabel]
+	if ref == "" {
+		ref = labels[alertmanagerProjectLabel]
+	}
+	if ref == "" {
+		ref = defaultProject
+	}
+	if ref == "" {
+		return "", nil
+	}
+
+	if id, ok := cache[ref]; ok {
+		return id, nil
+	}
+	id, err := s.projectRepo.LookupID(ctx, ref)
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, postgres.ErrAmbiguousProject) {
+		id, err = "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	cache[ref] = id
+	return id, nil
+}
+
+func alertmanagerMessage(am AlertmanagerAlert) string {
+	for _, key := range []string{"summary", "description", "message"} {
+		if text := strings.TrimSpace(am.Annotations[key]); text != "" {
+			if name := am.Labels["alertname"]; name != "" {
+				return name + ": " + text
+			}
+			return text
+		}
+	}
+	if name := am.Labels["alertname"]; name != "" {
+		return name
+	}
+	return "Alertmanager alert " + formatLabels(am.Labels)
+}
+
+func alertmanagerSeverity(labels map[string]string) string {
+	if severity := strings.ToLower(labels["severity"]); severity != "" {
+		return severity
+	}
+	return "warning"
+}
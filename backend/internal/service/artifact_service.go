@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/internal/sourcemap"
+	"pulseguard/internal/stacktrace"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrArtifactNotFound = errors.New("artifact not found")
+	ErrInvalidArtifact  = errors.New("invalid artifact")
+)
+
+const (
+	// sourceContextLines is how many source lines are kept around a symbolicated frame
+	sourceContextLines = 5
+	// sourceMapCacheSize is how many parsed source maps are kept in memory
+	sourceMapCacheSize = 64
+)
+
+// ArtifactService stores release artifacts and uses uploaded source maps to
+// symbolicate minified JavaScript stack traces
+type ArtifactService struct {
+	repo   *postgres.ArtifactRepository
+	store  storage.Store
+	logger *logger.Logger
+
+	mu    sync.Mutex
+	cache map[string]*sourcemap.Map
+	order []string
+}
+
+func NewArtifactService(repo *postgres.ArtifactRepository, store storage.Store, logger *logger.Logger) *ArtifactService {
+	return &ArtifactService{
+		repo:   repo,
+		store:  store,
+		logger: logger,
+		cache:  make(map[string]*sourcemap.Map),
+	}
+}
+
+// UploadArtifact stores a source map for a release of the project,
+// replacing the artifact with the same name
+func (s *ArtifactService) UploadArtifact(ctx context.Context, projectID, release, name string, data []byte, userID string) (*models.ReleaseArtifact, error) {
+	release = strings.TrimSpace(release)
+	name = strings.TrimSpace(name)
+	switch {
+	case release == "" || len(release) > 250:
+		return nil, fmt.Errorf("%w: release must be 1-250 characters", ErrInvalidArtifact)
+	case name == "" || len(name) > 1024:
+		return nil, fmt.Errorf("%w: name must be 1-1024 characters", ErrInvalidArtifact)
+	case !strings.HasSuffix(name, ".map"):
+		return nil, fmt.Errorf("%w: only source maps (.map) are supported", ErrInvalidArtifact)
+	}
+	if _, err := sourcemap.Parse(data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArtifact, err)
+	}
+
+	checksum := sha1.Sum(data)
+	artifact := &models.ReleaseArtifact{
+		ID:         uuid.NewString(),
+		ProjectID:  projectID,
+		Release:    release,
+		Name:       name,
+		Size:       int64(len(data)),
+		Checksum:   hex.EncodeToString(checksum[:]),
+		UploadedBy: userID,
+		CreatedAt:  time.Now(),
+	}
+	// Every upload gets its own key, so a replaced map is never served from cache
+	artifact.StorageKey = fmt.Sprintf("artifacts/%s/%s", projectID, artifact.ID)
+
+	if err := s.store.Put(ctx, artifact.StorageKey, data); err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+	replacedKey, err := s.repo.Upsert(ctx, artifact)
+	if err != nil {
+		s.removeContent(ctx, artifact.StorageKey)
+		return nil, err
+	}
+	if replacedKey != "" && replacedKey != artifact.StorageKey {
+		s.removeContent(ctx, replacedKey)
+	}
+	return artifact, nil
+}
+
+// ListArtifacts returns the artifacts uploaded for a release of the project
+func (s *ArtifactService) ListArtifacts(ctx context.Context, projectID, release string) ([]*models.ReleaseArtifact, error) {
+	return s.repo.ListByRelease(ctx, projectID, release)
+}
+
+// DeleteArtifact removes an artifact of the project and its content
+func (s *ArtifactService) DeleteArtifact(ctx context.Context, projectID, release, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return ErrArtifactNotFound
+	}
+	artifact, err := s.repo.GetByID(ctx, projectID, id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && artifact.Release != release) {
+		return ErrArtifactNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, projectID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrArtifactNotFound
+		}
+		return err
+	}
+	s.removeContent(ctx, artifact.StorageKey)
+	return nil
+}
+
+// removeContent deletes stored content that no artifact refers to anymore.
+// Failing to do so only leaves an orphaned object, so it is logged.
+func (s *ArtifactService) removeContent(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		s.logger.Error(ctx, "Failed to delete artifact content", err, "storage_key", key)
+	}
+}
+
+// Symbolicate maps the minified JavaScript frames of an error back to their
+// original source, using the source maps uploaded for the error's release.
+// It rewrites Frames, StackTrace and ComponentStack. Frames without a
+// matching source map are kept as they are, and failures are logged rather
+// than returned so an event is never lost to symbolication.
+func (s *ArtifactService) Symbolicate(ctx context.Context, e *models.Error) {
+	if e.Release == "" {
+		return
+	}
+
+	componentFrames := stacktrace.Parse(e.ComponentStack)
+	maps, err := s.loadMaps(ctx, e.ProjectID, e.Release, append(append([]models.StackFrame{}, e.Frames...), componentFrames...))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to load source maps", err, "release", e.Release)
+		return
+	}
+	if len(maps) == 0 {
+		return
+	}
+
+	mapFrame := func(f models.StackFrame) (models.StackFrame, bool) {
+		return symbolicateFrame(f, maps)
+	}
+	for i, f := range e.Frames {
+		if mapped, ok := mapFrame(f); ok {
+			e.Frames[i] = mapped
+		}
+	}
+	e.StackTrace = stacktrace.Rewrite(e.StackTrace, mapFrame)
+	e.ComponentStack = stacktrace.Rewrite(e.ComponentStack, mapFrame)
+}
+
+// loadMaps returns the release's source maps for the files of the frames,
+// keyed by frame file
+func (s *ArtifactService) loadMaps(ctx context.Context, projectID, release string, frames []models.StackFrame) (map[string]*sourcemap.Map, error) {
+	// Several files, e.g. the same path on different origins, may share a name
+	candidates := make(map[string][]string)
+	for _, f := range frames {
+		if f.Line == 0 || !isJavaScriptFile(f.File) {
+			continue
+		}
+		for _, name := range sourceMapNames(f.File) {
+			candidates[name] = append(candidates[name], f.File)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	artifacts, err := s.repo.FindByNames(ctx, projectID, release, names)
+	if err != nil {
+		return nil, err
+	}
+
+	maps := make(map[string]*sourcemap.Map)
+	exact := make(map[string]bool)
+	for _, artifact := range artifacts {
+		m, err := s.sourceMap(ctx, artifact)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", artifact.Name, err)
+		}
+		// A map uploaded for the full URL wins over one for the path below any origin
+		pathMatch := strings.HasPrefix(artifact.Name, "~/")
+		for _, file := range candidates[artifact.Name] {
+			if pathMatch && exact[file] {
+				continue
+			}
+			maps[file] = m
+			exact[file] = !pathMatch
+		}
+	}
+	return maps, nil
+}
+
+// sourceMap returns the parsed content of an artifact, from cache if possible
+func (s *ArtifactService) sourceMap(ctx context.Context, artifact *models.ReleaseArtifact) (*sourcemap.Map, error) {
+	s.mu.Lock()
+	m, ok := s.cache[artifact.StorageKey]
+	s.mu.Unlock()
+	if ok {
+		return m, nil
+	}
+
+	data, err := s.store.Get(ctx, artifact.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	m, err = sourcemap.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.cache[artifact.StorageKey]; !ok {
+		if len(s.order) >= sourceMapCacheSize {
+			delete(s.cache, s.order[0])
+			s.order = s.order[1:]
+		}
+		s.cache[artifact.StorageKey] = m
+		s.order = append(s.order, artifact.StorageKey)
+	}
+	return m, nil
+}
+
+func symbolicateFrame(f models.StackFrame, maps map[string]*sourcemap.Map) (models.StackFrame, bool) {
+	m, ok := maps[f.File]
+	if !ok {
+		return f, false
+	}
+	pos, ok := m.Lookup(f.Line, f.Column)
+	if !ok {
+		return f, false
+	}
+
+	mapped := models.StackFrame{
+		Function: f.Function,
+		File:     pos.Source,
+		Line:     pos.Line,
+		Column:   pos.Column,
+	}
+	if pos.Name != "" {
+		mapped.Function = pos.Name
+	}
+	mapped.InApp = stacktrace.InApp(mapped)
+	if pre, line, post, ok := m.Context(pos.Source, pos.Line, sourceContextLines); ok {
+		mapped.PreContext, mapped.ContextLine, mapped.PostContext = pre, line, post
+	}
+	return mapped, true
+}
+
+// sourceMapNames returns the artifact names a frame's source map may be
+// uploaded as: the full URL of the file, or its path below any origin
+func sourceMapNames(file string) []string {
+	url := file
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	names := []string{url + ".map"}
+	if p := stacktrace.Path(file); p != "" {
+		names = append(names, "~/"+p+".map")
+	}
+	return names
+}
+
+func isJavaScriptFile(file string) bool {
+	if i := strings.IndexAny(file, "?#"); i >= 0 {
+		file = file[:i]
+	}
+	for _, ext := range []string{".js", ".mjs", ".cjs", ".jsx"} {
+		if strings.HasSuffix(file, ext) {
+			return true
+		}
+	}
+	return false
+}
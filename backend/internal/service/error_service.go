@@ -2,59 +2,401 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/google/uuid"
+
+	"pulseguard/internal/breadcrumbs"
 	"pulseguard/internal/models"
 	"pulseguard/internal/repository/postgres"
+	"pulseguard/internal/search"
+	"pulseguard/internal/stacktrace"
+)
+
+var (
+	ErrErrorNotFound = errors.New("error not found")
+	ErrMergeNotFound = errors.New("merge not found")
+	ErrInvalidMerge  = errors.New("invalid merge")
+	ErrInvalidStatus = errors.New("invalid status")
+
+	ErrInvalidSearch      = errors.New("invalid search")
+	ErrOccurrenceNotFound = errors.New("occurrence not found")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+
+	ErrInvalidAssignment = errors.New("invalid assignment")
+	ErrInvalidComment    = errors.New("invalid comment")
+
+	ErrInvalidBulkAction = errors.New("invalid bulk action")
+)
+
+const (
+	// maxCommentLength caps the length of a comment body, in bytes
+	maxCommentLength = 10000
+	// maxBulkIDs caps how many errors a bulk action can list by id
+	maxBulkIDs = 1000
 )
 
 type ErrorService struct {
-	errorRepo *postgres.ErrorRepository
+	errorRepo       *postgres.ErrorRepository
+	groupingService *GroupingService
+	artifacts       *ArtifactService
+	views           *SavedViewService
 }
 
-func NewErrorService(errorRepo *postgres.ErrorRepository) *ErrorService {
-	return &ErrorService{errorRepo: errorRepo}
+func NewErrorService(errorRepo *postgres.ErrorRepository, groupingService *GroupingService, artifacts *ArtifactService, views *SavedViewService) *ErrorService {
+	return &ErrorService{errorRepo: errorRepo, groupingService: groupingService, artifacts: artifacts, views: views}
 }
 
-func (s *ErrorService) Track(ctx context.Context, errorData *models.Error, metadata map[string]interface{}) (*models.Error, error) {
+// Track parses the error's stack trace, unless the client sent structured
+// frames, symbolicates it with the release's source maps, fingerprints the
+// error and records it in its group along with its breadcrumbs, capped in
+// size, and tags.
+// fingerprint is the optional fingerprint sent by the client.
+func (s *ErrorService) Track(ctx context.Context, errorData *models.Error, fingerprint []string, metadata map[string]interface{}) (*models.Error, error) {
+	if err := s.prepare(ctx, errorData, fingerprint); err != nil {
+		return nil, err
+	}
 	return s.errorRepo.Track(ctx, errorData, metadata)
 }
 
+// prepare does the work of Track that precedes storing the error, which
+// leaves it with its frames and fingerprint set
+func (s *ErrorService) prepare(ctx context.Context, errorData *models.Error, fingerprint []string) error {
+	if errorData.Frames == nil {
+		errorData.Frames = stacktrace.Parse(errorData.StackTrace)
+	}
+	errorData.Breadcrumbs = breadcrumbs.Normalize(errorData.Breadcrumbs, errorData.OccurredAt)
+	s.artifacts.Symbolicate(ctx, errorData)
+	fp, err := s.groupingService.Fingerprint(ctx, errorData, fingerprint)
+	if err != nil {
+		return err
+	}
+	errorData.Fingerprint = fp
+	return nil
+}
+
+// trackBatch stores prepared events that share a project, environment and
+// fingerprint, see postgres.ErrorRepository.TrackBatch
+func (s *ErrorService) trackBatch(ctx context.Context, events []postgres.TrackedEvent) (*models.Error, error) {
+	return s.errorRepo.TrackBatch(ctx, events)
+}
+
 type ErrorFilters struct {
 	ProjectID   string
 	Environment string
 	Status      string
-	Search      string
+	Search      string // a query in the syntax of package search
+	ViewID      string // a saved view whose search and filters apply
+	ViewerID    string // the user searching, who assigned:me refers to
 	UserID      string
 	SessionID   string
 	StartDate   time.Time
 	EndDate     time.Time
+	Sort        string // see postgres.IsErrorSort; last_seen by default
 	Page        int
 	Limit       int
 }
 
+// GetErrors returns a page of the errors matching the filters and the total
+// number of matches. With a saved view, the view's query must match as well
+// as the search, and its environment, status and sort apply unless the
+// filters set them. An invalid search fails with ErrInvalidSearch wrapping
+// a *search.ParseError, and a view the viewer cannot see with
+// ErrSavedViewNotFound.
 func (s *ErrorService) GetErrors(ctx context.Context, filters ErrorFilters) ([]*models.Error, int, error) {
-	repoFilters := postgres.ErrorFilters{
+	repoFilters, err := s.resolveFilters(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.errorRepo.GetErrors(ctx, repoFilters)
+}
+
+// resolveFilters applies the saved view and parses the search of filters
+func (s *ErrorService) resolveFilters(ctx context.Context, filters ErrorFilters) (postgres.ErrorFilters, error) {
+	var query []search.Term
+	if filters.ViewID != "" {
+		view, err := s.views.GetView(ctx, filters.ProjectID, filters.ViewID, filters.ViewerID)
+		if err != nil {
+			return postgres.ErrorFilters{}, err
+		}
+		query, err = parseSearch(view.Query, filters.ViewerID)
+		if err != nil {
+			return postgres.ErrorFilters{}, err
+		}
+		if filters.Environment == "" {
+			filters.Environment = view.Environment
+		}
+		if filters.Status == "" {
+			filters.Status = view.Status
+		}
+		if filters.Sort == "" {
+			filters.Sort = view.Sort
+		}
+	}
+	terms, err := parseSearch(filters.Search, filters.ViewerID)
+	if err != nil {
+		return postgres.ErrorFilters{}, err
+	}
+	return postgres.ErrorFilters{
 		ProjectID:   filters.ProjectID,
 		Environment: filters.Environment,
 		Status:      filters.Status,
-		Search:      filters.Search,
+		Query:       append(query, terms...),
 		UserID:      filters.UserID,
 		SessionID:   filters.SessionID,
 		StartDate:   filters.StartDate,
 		EndDate:     filters.EndDate,
+		Sort:        filters.Sort,
 		Page:        filters.Page,
 		Limit:       filters.Limit,
+	}, nil
+}
+
+// parseSearch parses a search query, resolving assigned:me to the viewer
+func parseSearch(query, viewerID string) ([]search.Term, error) {
+	terms, err := search.Parse(query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSearch, err)
 	}
-	return s.errorRepo.GetErrors(ctx, repoFilters)
+	for i, term := range terms {
+		if term.Key != "assigned" {
+			continue
+		}
+		for j, value := range term.Values {
+			if value == "me" {
+				terms[i].Values[j] = viewerID
+			}
+		}
+	}
+	return terms, nil
 }
 
-func (s *ErrorService) GetErrorByID(ctx context.Context, id string) (*models.Error, error) {
-	return s.errorRepo.GetErrorByID(ctx, id)
+// GetErrorByID returns the error with the given ID within a project, or nil if
+// the project has no such error.
+func (s *ErrorService) GetErrorByID(ctx context.Context, projectID, id string) (*models.Error, error) {
+	e, err := s.errorRepo.GetErrorByID(ctx, projectID, id)
+	if err != nil || e == nil {
+		return e, err
+	}
+	// Errors tracked before frames were stored only have the raw stack trace
+	if e.Frames == nil {
+		e.Frames = stacktrace.Parse(e.StackTrace)
+	}
+	return e, nil
 }
 
-func (s *ErrorService) UpdateErrorStatus(ctx context.Context, id, status string) (*models.Error, error) {
-	return s.errorRepo.UpdateErrorStatus(ctx, id, status)
+// UpdateErrorStatus changes the status of an error within a project on behalf
+// of userID, returning nil if the error does not exist. details holds the
+// optional conditions:
+// resolving in the next release for RESOLVED, and ignoring until a time,
+// a number of occurrences or a number of affected users for IGNORED.
+func (s *ErrorService) UpdateErrorStatus(ctx context.Context, projectID, id, status string, details *models.StatusDetails, userID string) (*models.Error, error) {
+	details, err := normalizeStatusDetails(status, details)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := s.errorRepo.UpdateErrorStatus(ctx, projectID, id, status, details, userID)
+	if errors.Is(err, postgres.ErrNoRelease) {
+		return nil, fmt.Errorf("%w: resolving in the next release needs a release to be created first", ErrInvalidStatus)
+	}
+	return e, err
+}
+
+// normalizeStatusDetails checks that the conditions fit the status and
+// returns nil when there are none
+func normalizeStatusDetails(status string, details *models.StatusDetails) (*models.StatusDetails, error) {
+	if details == nil {
+		return nil, nil
+	}
+	ignoring := details.IgnoreUntil != nil || details.IgnoreCount != 0 || details.IgnoreUserCount != 0
+	switch {
+	case details.InNextRelease && status != "RESOLVED":
+		return nil, fmt.Errorf("%w: inNextRelease only applies to RESOLVED", ErrInvalidStatus)
+	case ignoring && status != "IGNORED":
+		return nil, fmt.Errorf("%w: ignore conditions only apply to IGNORED", ErrInvalidStatus)
+	case details.IgnoreCount < 0 || details.IgnoreUserCount < 0:
+		return nil, fmt.Errorf("%w: ignore counts must be positive", ErrInvalidStatus)
+	case details.IgnoreUntil != nil && !details.IgnoreUntil.After(time.Now()):
+		return nil, fmt.Errorf("%w: ignoreUntil must be in the future", ErrInvalidStatus)
+	case !details.InNextRelease && !ignoring:
+		return nil, nil
+	}
+	return &models.StatusDetails{
+		InNextRelease:   details.InNextRelease,
+		IgnoreUntil:     details.IgnoreUntil,
+		IgnoreCount:     details.IgnoreCount,
+		IgnoreUserCount: details.IgnoreUserCount,
+	}, nil
+}
+
+// BulkUpdate applies an action on behalf of userID to the project's errors
+// with the given ids or, when there are none, to all errors matching the
+// filters, in one transaction. It returns how many errors the action changed.
+func (s *ErrorService) BulkUpdate(ctx context.Context, filters ErrorFilters, ids []string, action *models.BulkAction, userID string) (int, error) {
+	if err := validateBulkAction(action); err != nil {
+		return 0, err
+	}
+	if len(ids) > maxBulkIDs {
+		return 0, fmt.Errorf("%w: at most %d ids can be listed, use a search instead", ErrInvalidBulkAction, maxBulkIDs)
+	}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return 0, fmt.Errorf("%w: invalid id %q", ErrInvalidBulkAction, id)
+		}
+	}
+
+	repoFilters, err := s.resolveFilters(ctx, filters)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		ids = nil
+	}
+
+	affected, err := s.errorRepo.BulkUpdate(ctx, repoFilters, ids, action, userID)
+	switch {
+	case errors.Is(err, postgres.ErrNoRelease):
+		return 0, fmt.Errorf("%w: resolving in the next release needs a release to be created first", ErrInvalidStatus)
+	case errors.Is(err, postgres.ErrInvalidAssignee):
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	}
+	return affected, err
+}
+
+// validateBulkAction checks the action's type and that it has what the type needs
+func validateBulkAction(action *models.BulkAction) error {
+	switch action.Type {
+	case models.BulkResolve, models.BulkIgnore:
+		details, err := normalizeStatusDetails(action.Status(), action.StatusDetails)
+		if err != nil {
+			return err
+		}
+		action.StatusDetails = details
+	case models.BulkAssign:
+		return validateAssignee(action.AssigneeUserID, action.AssigneeTeamID)
+	case models.BulkTag:
+		if len(action.Tags) == 0 {
+			return fmt.Errorf("%w: tag needs at least one tag", ErrInvalidBulkAction)
+		}
+		for i := range action.Tags {
+			tag := &action.Tags[i]
+			tag.Key, tag.Value = strings.TrimSpace(tag.Key), strings.TrimSpace(tag.Value)
+			if tag.Key == "" || tag.Value == "" {
+				return fmt.Errorf("%w: tags need a key and a value", ErrInvalidBulkAction)
+			}
+		}
+	case models.BulkDelete:
+	default:
+		return fmt.Errorf("%w: unknown action %q, expected one of resolve, ignore, assign, tag, delete", ErrInvalidBulkAction, action.Type)
+	}
+	return nil
+}
+
+// OccurrenceFilters narrows the occurrences of an error group. Cursor is the
+// NextCursor of the previous page, empty for the first page.
+type OccurrenceFilters struct {
+	ProjectID   string
+	ErrorID     string
+	UserID      string
+	SessionID   string
+	Release     string
+	Environment string
+	StartDate   time.Time
+	EndDate     time.Time
+	Cursor      string
+	Limit       int
+}
+
+// ListOccurrences returns a page of an error group's occurrences, newest
+// first, and the cursor of the next page, empty on the last page
+func (s *ErrorService) ListOccurrences(ctx context.Context, filters OccurrenceFilters) ([]models.ErrorOccurrence, string, error) {
+	if _, err := uuid.Parse(filters.ErrorID); err != nil {
+		return nil, "", ErrErrorNotFound
+	}
+	repoFilters := postgres.OccurrenceFilters{
+		ProjectID:   filters.ProjectID,
+		ErrorID:     filters.ErrorID,
+		UserID:      filters.UserID,
+		SessionID:   filters.SessionID,
+		Release:     filters.Release,
+		Environment: filters.Environment,
+		StartDate:   filters.StartDate,
+		EndDate:     filters.EndDate,
+		// One more than the page, to know whether there is a next page
+		Limit: filters.Limit + 1,
+	}
+	if filters.Cursor != "" {
+		before, id, err := decodeOccurrenceCursor(filters.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		repoFilters.BeforeTime = before
+		repoFilters.BeforeID = id
+	}
+
+	occurrences, err := s.errorRepo.ListOccurrences(ctx, repoFilters)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrErrorNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(occurrences) > filters.Limit {
+		occurrences = occurrences[:filters.Limit]
+		last := occurrences[len(occurrences)-1]
+		next = encodeOccurrenceCursor(last.Timestamp, last.ID)
+	}
+	return occurrences, next, nil
+}
+
+// GetOccurrence returns one occurrence of an error group with its frames and breadcrumbs
+func (s *ErrorService) GetOccurrence(ctx context.Context, projectID, errorID, occurrenceID string) (*models.ErrorOccurrence, error) {
+	if _, err := uuid.Parse(errorID); err != nil {
+		return nil, ErrOccurrenceNotFound
+	}
+	if _, err := uuid.Parse(occurrenceID); err != nil {
+		return nil, ErrOccurrenceNotFound
+	}
+	o, err := s.errorRepo.GetOccurrence(ctx, projectID, errorID, occurrenceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOccurrenceNotFound
+	}
+	return o, err
+}
+
+// encodeOccurrenceCursor encodes the position of an occurrence as an opaque cursor
+func encodeOccurrenceCursor(timestamp time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(timestamp.UnixNano(), 10) + ":" + id))
+}
+
+func decodeOccurrenceCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return time.Unix(0, n), id, nil
 }
 
 // gets recent errors...type 3
@@ -65,4 +407,283 @@ func (s *ErrorService) ListRecentByProject(ctx context.Context, projectID string
 // counts total errors...type 3
 func (s *ErrorService) CountByProject(ctx context.Context, projectID string) (int64, error) {
     return s.errorRepo.CountByProject(ctx, projectID)
-}
\ No newline at end of file
+}
+
+// CountOccurrencesSince counts the project's error occurrences since the given time
+func (s *ErrorService) CountOccurrencesSince(ctx context.Context, projectID, environment string, since time.Time) (int64, error) {
+	return s.errorRepo.CountOccurrencesSince(ctx, projectID, environment, since)
+}
+
+// ListFirstSeenSince returns the project's error groups first seen since the given time
+func (s *ErrorService) ListFirstSeenSince(ctx context.Context, projectID, environment string, since time.Time) ([]*models.Error, error) {
+	return s.errorRepo.ListFirstSeenSince(ctx, projectID, environment, since)
+}
+
+// MergeErrors folds the source groups into the target group. Future events
+// with any of the merged fingerprints are tracked in the target.
+func (s *ErrorService) MergeErrors(ctx context.Context, projectID, targetID string, sourceIDs []string, userID string) (*models.Error, []*models.ErrorMerge, error) {
+	if _, err := uuid.Parse(targetID); err != nil {
+		return nil, nil, ErrErrorNotFound
+	}
+	if len(sourceIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one source error is required", ErrInvalidMerge)
+	}
+	seen := make(map[string]bool, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, nil, ErrErrorNotFound
+		}
+		if id == targetID {
+			return nil, nil, fmt.Errorf("%w: an error cannot be merged into itself", ErrInvalidMerge)
+		}
+		if seen[id] {
+			return nil, nil, fmt.Errorf("%w: duplicate source error %s", ErrInvalidMerge, id)
+		}
+		seen[id] = true
+	}
+
+	target, merges, err := s.errorRepo.Merge(ctx, projectID, targetID, sourceIDs, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil, ErrErrorNotFound
+	case errors.Is(err, postgres.ErrMergeEnvironmentMismatch):
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+	case err != nil:
+		return nil, nil, err
+	}
+	return target, merges, nil
+}
+
+// UnmergeError splits a merged group out of its target again, returning the
+// restored group
+func (s *ErrorService) UnmergeError(ctx context.Context, projectID, mergeID, userID string) (*models.Error, error) {
+	if _, err := uuid.Parse(mergeID); err != nil {
+		return nil, ErrMergeNotFound
+	}
+
+	restored, err := s.errorRepo.Unmerge(ctx, projectID, mergeID, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrMergeNotFound
+	case errors.Is(err, postgres.ErrMergeTargetGone):
+		return nil, fmt.Errorf("%w: %v; unmerge its target first", ErrInvalidMerge, err)
+	case err != nil:
+		return nil, err
+	}
+	return restored, nil
+}
+
+// ListErrorMerges returns the groups merged into an error
+func (s *ErrorService) ListErrorMerges(ctx context.Context, projectID, errorID string) ([]*models.ErrorMerge, error) {
+	if _, err := uuid.Parse(errorID); err != nil {
+		return nil, ErrErrorNotFound
+	}
+	return s.errorRepo.ListMerges(ctx, projectID, errorID)
+}
+
+// AssignError assigns the error to a user or a team on behalf of userID, or
+// unassigns it when both are empty. Along with the new assignee it returns
+// the users to notify: the assigned user or the members of the assigned
+// team, except userID. Nobody is notified when the assignee did not change.
+func (s *ErrorService) AssignError(ctx context.Context, projectID, errorID, assigneeUserID, assigneeTeamID, userID string) (*models.Assignee, []models.UserSummary, error) {
+	if _, err := uuid.Parse(errorID); err != nil {
+		return nil, nil, ErrErrorNotFound
+	}
+	if err := validateAssignee(assigneeUserID, assigneeTeamID); err != nil {
+		return nil, nil, err
+	}
+
+	assignee, changed, err := s.errorRepo.Assign(ctx, projectID, errorID, assigneeUserID, assigneeTeamID, userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil, ErrErrorNotFound
+	case errors.Is(err, postgres.ErrInvalidAssignee):
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidAssignment, err)
+	case err != nil:
+		return nil, nil, err
+	}
+	if !changed || assignee == nil {
+		return assignee, nil, nil
+	}
+
+	var users []models.UserSummary
+	if assignee.Type == "team" {
+		if users, err = s.errorRepo.TeamUsers(ctx, assignee.ID); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		users = []models.UserSummary{{ID: assignee.ID, Name: assignee.Name, Email: assignee.Email}}
+	}
+	notify := make([]models.UserSummary, 0, len(users))
+	for _, u := range users {
+		if u.ID != userID {
+			notify = append(notify, u)
+		}
+	}
+	return assignee, notify, nil
+}
+
+// validateAssignee checks that at most one of a user and a team is assigned
+func validateAssignee(userID, teamID string) error {
+	if userID != "" && teamID != "" {
+		return fmt.Errorf("%w: assign either a user or a team", ErrInvalidAssignment)
+	}
+	for _, id := range []string{userID, teamID} {
+		if _, err := uuid.Parse(id); id != "" && err != nil {
+			return fmt.Errorf("%w: invalid id %q", ErrInvalidAssignment, id)
+		}
+	}
+	return nil
+}
+
+// AddComment adds a comment by userID to the error, as a reply when parentID
+// is set. Users with access to the project are mentioned with @name or
+// @email, see mentionedUsers; along with the comment it returns the mentioned users to notify,
+// except the author.
+func (s *ErrorService) AddComment(ctx context.Context, projectID, errorID, parentID, body, userID string) (*models.ErrorComment, []models.UserSummary, error) {
+	if _, err := uuid.Parse(errorID); err != nil {
+		return nil, nil, ErrErrorNotFound
+	}
+	body = strings.TrimSpace(body)
+	switch {
+	case body == "":
+		return nil, nil, fmt.Errorf("%w: body is required", ErrInvalidComment)
+	case len(body) > maxCommentLength:
+		return nil, nil, fmt.Errorf("%w: body is longer than %d characters", ErrInvalidComment, maxCommentLength)
+	}
+	if _, err := uuid.Parse(parentID); parentID != "" && err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidComment, postgres.ErrCommentParentNotFound)
+	}
+
+	users, err := s.errorRepo.ProjectUsers(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	mentioned := mentionedUsers(body, users)
+
+	now := time.Now()
+	comment := &models.ErrorComment{
+		ID:        uuid.NewString(),
+		ProjectID: projectID,
+		ErrorID:   errorID,
+		ParentID:  parentID,
+		AuthorID:  userID,
+		Body:      body,
+		Mentions:  make([]string, 0, len(mentioned)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, u := range users {
+		if u.ID == userID {
+			comment.AuthorName = u.Name
+		}
+	}
+	notify := make([]models.UserSummary, 0, len(mentioned))
+	for _, u := range mentioned {
+		comment.Mentions = append(comment.Mentions, u.ID)
+		if u.ID != userID {
+			notify = append(notify, u)
+		}
+	}
+
+	err = s.errorRepo.CreateComment(ctx, comment)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil, ErrErrorNotFound
+	case errors.Is(err, postgres.ErrCommentParentNotFound):
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidComment, err)
+	case err != nil:
+		return nil, nil, err
+	}
+	return comment, notify, nil
+}
+
+// GetErrorActivity returns the error's assignee, its comments as threads and
+// its activity log, oldest first
+func (s *ErrorService) GetErrorActivity(ctx context.Context, projectID, errorID string) (*models.ErrorTimeline, error) {
+	if _, err := uuid.Parse(errorID); err != nil {
+		return nil, ErrErrorNotFound
+	}
+	timeline, err := s.errorRepo.Timeline(ctx, projectID, errorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrErrorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	timeline.Comments = threadComments(timeline.Comments)
+	return timeline, nil
+}
+
+// mentionedUsers returns the users mentioned in body as @ followed by their
+// name or email, compared case-insensitively. Names are matched against the
+// users as they are, so they may hold spaces or any other character. An @
+// inside a word or an email address mentions no one, nor does a name
+// followed by more of a word. Where several users match at one @, the
+// longest name or email wins, so that "@Ann Lee" is not read as "@Ann".
+func mentionedUsers(body string, users []models.UserSummary) []models.UserSummary {
+	var mentioned []models.UserSummary
+	seen := make(map[string]bool)
+	for i := 0; i < len(body); i++ {
+		if body[i] != '@' {
+			continue
+		}
+		if before, _ := utf8.DecodeLastRuneInString(body[:i]); i > 0 && (isHandleRune(before) || before == '.') {
+			continue
+		}
+		rest := body[i+1:]
+		var best *models.UserSummary
+		length := 0
+		for j, u := range users {
+			for _, handle := range []string{u.Name, u.Email} {
+				if len(handle) > length && len(handle) <= len(rest) &&
+					strings.EqualFold(rest[:len(handle)], handle) && mentionEnds(rest[len(handle):]) {
+					best, length = &users[j], len(handle)
+				}
+			}
+		}
+		if best == nil {
+			continue
+		}
+		if !seen[best.ID] {
+			seen[best.ID] = true
+			mentioned = append(mentioned, *best)
+		}
+		i += length
+	}
+	return mentioned
+}
+
+// mentionEnds reports whether a mention may end before rest: at the end of
+// the body, or before anything but more of a word or an email address. A
+// full stop ends it when it ends the sentence.
+func mentionEnds(rest string) bool {
+	r, size := utf8.DecodeRuneInString(rest)
+	if r == '.' {
+		r, _ = utf8.DecodeRuneInString(rest[size:])
+	}
+	return rest == "" || !isHandleRune(r)
+}
+
+// isHandleRune reports whether r may continue a name or an email address
+func isHandleRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-+@", r)
+}
+
+// threadComments nests replies under the comment they answer, keeping the
+// order of comments
+func threadComments(comments []*models.ErrorComment) []*models.ErrorComment {
+	byID := make(map[string]*models.ErrorComment, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+	}
+	threads := []*models.ErrorComment{}
+	for _, c := range comments {
+		if parent, ok := byID[c.ParentID]; ok {
+			parent.Replies = append(parent.Replies, c)
+		} else {
+			threads = append(threads, c)
+		}
+	}
+	return threads
+}
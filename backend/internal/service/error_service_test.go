@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"pulseguard/internal/models"
+)
+
+func TestMentionedUsers(t *testing.T) {
+	users := []models.UserSummary{
+		{ID: "1", Name: "ann", Email: "ann@example.com"},
+		{ID: "2", Name: "Ann Lee", Email: "lee@example.com"},
+		{ID: "3", Name: "bob.smith", Email: "bob@example.com"},
+		{ID: "4", Name: "Zoë", Email: "zoe@example.com"},
+		{ID: "5", Name: "", Email: "noname@example.com"},
+	}
+
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{"@ann please look", []string{"1"}},
+		{"thanks @ANN.", []string{"1"}},
+		{"@ann Lee has it", []string{"2"}},
+		{"@Ann Leeway", []string{"1"}},
+		{"@bob.smith, @bob@example.com", []string{"3"}},
+		{"@bob.smithers", nil},
+		{"(@zoë) and @lee@example.com", []string{"4", "2"}},
+		{"@noname@example.com", []string{"5"}},
+		{"mail ann@example.com or x.@ann", nil},
+		{"@annie", nil},
+		{"@ann @ann @ann", []string{"1"}},
+		{"@", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, u := range mentionedUsers(tt.body, users) {
+			got = append(got, u.ID)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("mentionedUsers(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"pulseguard/internal/grouping"
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrGroupingRuleNotFound = errors.New("grouping rule not found")
+	ErrInvalidGroupingRule  = errors.New("invalid grouping rule")
+)
+
+// GroupingService fingerprints tracked errors and manages the per-project
+// rules that override the default fingerprint
+type GroupingService struct {
+	repo     *postgres.GroupingRuleRepository
+	patterns sync.Map // pattern -> *regexp.Regexp
+}
+
+func NewGroupingService(repo *postgres.GroupingRuleRepository) *GroupingService {
+	return &GroupingService{repo: repo}
+}
+
+// Fingerprint decides the group of an error. The first enabled grouping rule
+// that matches wins; otherwise the fingerprint sent by the client is used,
+// and without one the default fingerprint of type and stack (or message).
+// A default fingerprint comes with e.LegacyFingerprint set, which leads
+// events to the groups opened before stack-aware grouping.
+func (s *GroupingService) Fingerprint(ctx context.Context, e *models.Error, clientFingerprint []string) (string, error) {
+	event := grouping.Event{
+		Type:    e.Type,
+		Message: e.Message,
+		Source:  e.Source,
+		Frames:  e.Frames,
+	}
+
+	rules, err := s.repo.ListEnabled(ctx, e.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load grouping rules: %w", err)
+	}
+
+	var frames []grouping.Frame
+	for _, rule := range rules {
+		if rule.MatchFrame != "" && frames == nil {
+			frames = grouping.GroupingFrames(e.Frames)
+		}
+		if s.matches(rule, e, frames) {
+			return grouping.Fingerprint(grouping.Expand(rule.Fingerprint, event)), nil
+		}
+	}
+
+	if len(clientFingerprint) > 0 {
+		return grouping.Fingerprint(grouping.Expand(clientFingerprint, event)), nil
+	}
+	e.LegacyFingerprint = grouping.LegacyFingerprint(event)
+	return grouping.Fingerprint(grouping.DefaultComponents(event)), nil
+}
+
+// CreateRule validates and stores a new grouping rule for the project
+func (s *GroupingService) CreateRule(ctx context.Context, rule *models.GroupingRule) (*models.GroupingRule, error) {
+	if err := normalizeGroupingRule(rule); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	rule.ID = uuid.NewString()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *GroupingService) ListRules(ctx context.Context, projectID string) ([]*models.GroupingRule, error) {
+	return s.repo.ListByProject(ctx, projectID)
+}
+
+// GetRule returns one of the project's grouping rules
+func (s *GroupingService) GetRule(ctx context.Context, projectID, ruleID string) (*models.GroupingRule, error) {
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return nil, ErrGroupingRuleNotFound
+	}
+	rule, err := s.repo.GetByID(ctx, projectID, ruleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGroupingRuleNotFound
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule validates and saves changes to an existing grouping rule
+func (s *GroupingService) UpdateRule(ctx context.Context, rule *models.GroupingRule) (*models.GroupingRule, error) {
+	if err := normalizeGroupingRule(rule); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGroupingRuleNotFound
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *GroupingService) DeleteRule(ctx context.Context, projectID, ruleID string) error {
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return ErrGroupingRuleNotFound
+	}
+	if err := s.repo.Delete(ctx, projectID, ruleID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrGroupingRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *GroupingService) matches(rule *models.GroupingRule, e *models.Error, frames []grouping.Frame) bool {
+	if rule.MatchType != "" && rule.MatchType != e.Type {
+		return false
+	}
+	if rule.MatchMessage != "" && !s.pattern(rule.MatchMessage).MatchString(e.Message) {
+		return false
+	}
+	if rule.MatchFrame != "" {
+		re := s.pattern(rule.MatchFrame)
+		for _, f := range frames {
+			if re.MatchString(f.String()) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// pattern compiles a validated rule pattern once. Patterns that no longer
+// compile match nothing.
+func (s *GroupingService) pattern(expr string) *regexp.Regexp {
+	if re, ok := s.patterns.Load(expr); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		re = regexp.MustCompile(`[^\s\S]`)
+	}
+	s.patterns.Store(expr, re)
+	return re
+}
+
+// normalizeGroupingRule trims the rule and rejects rules that cannot match
+// or would produce an empty fingerprint
+func normalizeGroupingRule(rule *models.GroupingRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.MatchType = strings.TrimSpace(rule.MatchType)
+	if rule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidGroupingRule)
+	}
+	if rule.MatchType == "" && rule.MatchMessage == "" && rule.MatchFrame == "" {
+		return fmt.Errorf("%w: at least one of match_type, match_message or match_frame is required", ErrInvalidGroupingRule)
+	}
+	for _, expr := range []string{rule.MatchMessage, rule.MatchFrame} {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("%w: invalid regular expression %q", ErrInvalidGroupingRule, expr)
+		}
+	}
+
+	fingerprint := make([]string, 0, len(rule.Fingerprint))
+	for _, part := range rule.Fingerprint {
+		if part = strings.TrimSpace(part); part != "" {
+			fingerprint = append(fingerprint, part)
+		}
+	}
+	if len(fingerprint) == 0 {
+		return fmt.Errorf("%w: fingerprint needs at least one value", ErrInvalidGroupingRule)
+	}
+	rule.Fingerprint = fingerprint
+	return nil
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+)
+
+var ErrInvalidIngestLimits = errors.New("invalid ingest limits")
+
+const (
+	// ingestLimitsTTL is how long a project's limits and quota usage are
+	// cached before they are read again
+	ingestLimitsTTL = time.Minute
+	// usageFlushInterval is how often usage counts are written
+	usageFlushInterval = 10 * time.Second
+
+	// A project is spiking when it sends more than spikeFactor times its
+	// usual rate in a minute, and at least spikeMinimum events. Its usual
+	// rate is a moving average of its events per minute, where the last
+	// minute weighs spikeSmoothing.
+	spikeMinimum   = 600
+	spikeFactor    = 10
+	spikeSmoothing = 0.1
+)
+
+// IngestDecision is the outcome of an event checked by IngestLimiter.Admit,
+// one of models.IngestAccepted, IngestRateLimited, IngestOverQuota and
+// IngestSpike
+type IngestDecision struct {
+	Outcome string
+	// RetryAfter is how long the client should wait before sending again
+	// when the event was not accepted
+	RetryAfter time.Duration
+}
+
+// Accepted reports whether the event may be ingested
+func (d IngestDecision) Accepted() bool {
+	return d.Outcome == models.IngestAccepted
+}
+
+// IngestLimiter enforces the ingest limits of projects and keys and counts
+// the events they send. Each project and each key with a rate limit has a
+// token bucket that holds a minute's worth of events. Accepted events count
+// towards the project's monthly quota. With spike protection, events beyond
+// a threshold that follows the project's usual rate are sampled.
+//
+// Limits are enforced per server instance; usage counts are written every
+// usageFlushInterval and quota usage is read back every ingestLimitsTTL.
+type IngestLimiter struct {
+	repo             *postgres.UsageRepository
+	logger           *logger.Logger
+	defaultRateLimit int
+
+	mu       sync.Mutex
+	projects map[string]*projectLimitState
+	keys     map[string]*tokenBucket
+	pending  map[usageKey]*models.UsageDay
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type projectLimitState struct {
+	limits   models.IngestLimits
+	loadedAt time.Time
+	bucket   tokenBucket
+
+	// period is the start of the month used counts the accepted events of
+	period time.Time
+	used   int64
+
+	// minute is the start of the minute received counts the events of
+	minute    time.Time
+	received  int
+	usualRate float64
+}
+
+type usageKey struct {
+	projectID string
+	day       time.Time
+}
+
+// NewIngestLimiter returns a limiter that gives projects without their own
+// rate limit defaultRateLimit events per minute; 0 leaves them unlimited.
+func NewIngestLimiter(repo *postgres.UsageRepository, defaultRateLimit int, logger *logger.Logger) *IngestLimiter {
+	return &IngestLimiter{
+		repo:             repo,
+		logger:           logger,
+		defaultRateLimit: defaultRateLimit,
+		projects:         make(map[string]*projectLimitState),
+		keys:             make(map[string]*tokenBucket),
+		pending:          make(map[usageKey]*models.UsageDay),
+	}
+}
+
+// Start writes usage counts every usageFlushInterval until Stop is called
+// or ctx ends.
+func (l *IngestLimiter) Start(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cancel != nil {
+		return
+	}
+
+	ctx, l.cancel = context.WithCancel(ctx)
+	l.done = make(chan struct{})
+
+	go func() {
+		defer close(l.done)
+		ticker := time.NewTicker(usageFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				// The last counts are written even though ctx has ended
+				l.flush(context.WithoutCancel(ctx))
+				return
+			case <-ticker.C:
+				l.flush(ctx)
+			}
+		}
+	}()
+}
+
+// Stop ends the flush loop after writing the pending usage counts.
+func (l *IngestLimiter) Stop() {
+	l.mu.Lock()
+	cancel, done := l.cancel, l.done
+	l.cancel = nil
+	l.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Admit checks one event sent with key against the limits of the key and
+// its project, and counts it in the project's usage
+func (l *IngestLimiter) Admit(ctx context.Context, key *models.ProjectKey) IngestDecision {
+	now := time.Now().UTC()
+	state := l.state(ctx, key.ProjectID, now)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	decision := l.decide(state, key, now)
+	l.count(key.ProjectID, decision.Outcome, now)
+	return decision
+}
+
+// Refund takes back an event Admit accepted but that could not be queued or
+// stored.
+// The event no longer counts towards the key's rate limits and the
+// project's quota, and its usage moves from accepted to dropped for the
+// given outcome.
+func (l *IngestLimiter) Refund(key *models.ProjectKey, outcome string) {
+	now := time.Now().UTC()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if st := l.projects[key.ProjectID]; st != nil {
+		st.bucket.give()
+		if st.used > 0 {
+			st.used--
+		}
+	}
+	l.keys[key.ID].give()
+
+	l.count(key.ProjectID, outcome, now)
+	l.pending[usageKey{projectID: key.ProjectID, day: now.Truncate(24 * time.Hour)}].Accepted--
+}
+
+func (l *IngestLimiter) decide(st *projectLimitState, key *models.ProjectKey, now time.Time) IngestDecision {
+	if period := monthStart(now); !period.Equal(st.period) {
+		st.period = period
+		st.used = 0
+	}
+
+	rate := st.limits.RateLimit
+	if rate == 0 {
+		rate = l.defaultRateLimit
+	}
+	st.bucket.refill(rate, now)
+	var keyBucket *tokenBucket
+	if key.RateLimit > 0 {
+		if keyBucket = l.keys[key.ID]; keyBucket == nil {
+			keyBucket = &tokenBucket{}
+			l.keys[key.ID] = keyBucket
+		}
+		keyBucket.refill(key.RateLimit, now)
+	}
+	if wait := max(st.bucket.wait(rate), keyBucket.wait(key.RateLimit)); wait > 0 {
+		return IngestDecision{Outcome: models.IngestRateLimited, RetryAfter: wait}
+	}
+
+	if st.limits.MonthlyQuota > 0 && st.used >= st.limits.MonthlyQuota {
+		return IngestDecision{Outcome: models.IngestOverQuota, RetryAfter: st.period.AddDate(0, 1, 0).Sub(now)}
+	}
+	if st.spiking(now) && st.limits.SpikeProtection {
+		return IngestDecision{Outcome: models.IngestSpike, RetryAfter: now.Truncate(time.Minute).Add(time.Minute).Sub(now)}
+	}
+
+	st.bucket.take(rate)
+	keyBucket.take(key.RateLimit)
+	st.used++
+	return IngestDecision{Outcome: models.IngestAccepted}
+}
+
+// spiking counts an event in the current minute and reports whether it
+// falls beyond the spike threshold. Above the threshold, events are sampled
+// so that fewer get through the further the minute's count goes past it.
+func (st *projectLimitState) spiking(now time.Time) bool {
+	minute := now.Truncate(time.Minute)
+	if !minute.Equal(st.minute) {
+		if !st.minute.IsZero() {
+			st.usualRate += spikeSmoothing * (float64(st.received) - st.usualRate)
+			// Idle minutes since then count as minutes without events
+			for idle := int(minute.Sub(st.minute)/time.Minute) - 1; idle > 0 && st.usualRate > 0.01; idle-- {
+				st.usualRate -= spikeSmoothing * st.usualRate
+			}
+		}
+		st.minute = minute
+		st.received = 0
+	}
+	st.received++
+
+	threshold := math.Max(spikeMinimum, spikeFactor*st.usualRate)
+	if float64(st.received) <= threshold {
+		return false
+	}
+	return rand.Float64() >= threshold/float64(st.received)
+}
+
+// state returns the limits of a project, reading them again when the cached
+// ones are older than ingestLimitsTTL. When they cannot be read, the last
+// known limits apply, or the defaults.
+func (l *IngestLimiter) state(ctx context.Context, projectID string, now time.Time) *projectLimitState {
+	l.mu.Lock()
+	st := l.projects[projectID]
+	fresh := st != nil && now.Sub(st.loadedAt) < ingestLimitsTTL
+	l.mu.Unlock()
+	if fresh {
+		return st
+	}
+
+	period := monthStart(now)
+	limits, err := l.repo.GetLimits(ctx, projectID)
+	var used int64
+	if err == nil {
+		used, err = l.repo.CountAccepted(ctx, projectID, period)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if st = l.projects[projectID]; st == nil {
+		st = &projectLimitState{limits: models.IngestLimits{ProjectID: projectID, SpikeProtection: true}}
+		l.projects[projectID] = st
+	}
+	st.loadedAt = now
+	if err != nil {
+		l.logger.Error(ctx, "Failed to load ingest limits", err)
+		return st
+	}
+	st.limits = *limits
+	st.period = period
+	st.used = used + l.pendingAccepted(projectID, period)
+	return st
+}
+
+// count adds an event's outcome to the project's pending usage
+func (l *IngestLimiter) count(projectID, outcome string, now time.Time) {
+	key := usageKey{projectID: projectID, day: now.Truncate(24 * time.Hour)}
+	usage := l.pending[key]
+	if usage == nil {
+		usage = &models.UsageDay{Day: key.day}
+		l.pending[key] = usage
+	}
+	switch outcome {
+	case models.IngestAccepted:
+		usage.Accepted++
+	case models.IngestRateLimited:
+		usage.RateLimited++
+	default:
+		usage.Dropped++
+	}
+}
+
+func (l *IngestLimiter) pendingAccepted(projectID string, since time.Time) int64 {
+	var n int64
+	for key, usage := range l.pending {
+		if key.projectID == projectID && !key.day.Before(since) {
+			n += usage.Accepted
+		}
+	}
+	return n
+}
+
+// flush writes the pending usage counts. Counts that fail to be written
+// are logged and dropped: usage is informational, and the quota is
+// enforced from the in-memory count until the next read.
+func (l *IngestLimiter) flush(ctx context.Context) {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = make(map[usageKey]*models.UsageDay)
+	l.mu.Unlock()
+
+	for key, usage := range pending {
+		if err := l.repo.AddUsage(ctx, key.projectID, *usage); err != nil {
+			l.logger.Error(logger.WithProjectID(ctx, key.projectID), "Failed to record ingest usage", err)
+		}
+	}
+}
+
+// Usage returns the project's limits and its usage in the current month
+func (l *IngestLimiter) Usage(ctx context.Context, projectID string) (*models.Usage, error) {
+	limits, err := l.Limits(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	// The counts include events not written yet
+	l.flush(ctx)
+
+	now := time.Now().UTC()
+	usage := &models.Usage{
+		PeriodStart: monthStart(now),
+		PeriodEnd:   monthStart(now).AddDate(0, 1, 0),
+		Limits:      limits,
+	}
+	usage.Days, err = l.repo.ListUsage(ctx, projectID, usage.PeriodStart, usage.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range usage.Days {
+		usage.Accepted += day.Accepted
+		usage.RateLimited += day.RateLimited
+		usage.Dropped += day.Dropped
+	}
+	if limits.MonthlyQuota > 0 {
+		remaining := max(limits.MonthlyQuota-usage.Accepted, 0)
+		usage.Remaining = &remaining
+	}
+	return usage, nil
+}
+
+// Limits returns the ingest limits of a project
+func (l *IngestLimiter) Limits(ctx context.Context, projectID string) (*models.IngestLimits, error) {
+	limits, err := l.repo.GetLimits(ctx, projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingest limits: %w", err)
+	}
+	return limits, nil
+}
+
+// UpdateLimits sets the ingest limits of a project, which apply from the
+// next event on
+func (l *IngestLimiter) UpdateLimits(ctx context.Context, limits *models.IngestLimits) error {
+	if limits.RateLimit < 0 || limits.MonthlyQuota < 0 {
+		return fmt.Errorf("%w: rate_limit and monthly_quota cannot be negative", ErrInvalidIngestLimits)
+	}
+	err := l.repo.UpdateLimits(ctx, limits)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrProjectNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	if st := l.projects[limits.ProjectID]; st != nil {
+		st.loadedAt = time.Time{}
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// tokenBucket holds up to a minute's worth of tokens at its rate, in events
+// per minute, and earns them back continuously
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(rate int, now time.Time) {
+	if rate <= 0 {
+		return
+	}
+	capacity := float64(rate)
+	if b.last.IsZero() {
+		b.tokens = capacity
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Minutes()*capacity)
+	}
+	b.last = now
+}
+
+// wait is how long until the bucket has a token, 0 when it has one. A nil
+// bucket or a rate of 0 is unlimited.
+func (b *tokenBucket) wait(rate int) time.Duration {
+	if b == nil || rate <= 0 || b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / float64(rate) * float64(time.Minute))
+}
+
+func (b *tokenBucket) take(rate int) {
+	if b != nil && rate > 0 {
+		b.tokens--
+	}
+}
+
+// give returns a token taken; refill caps the bucket again
+func (b *tokenBucket) give() {
+	if b != nil && !b.last.IsZero() {
+		b.tokens++
+	}
+}
+
+// monthStart is the start of the calendar month of t, in UTC
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/pkg/logger"
+)
+
+// testLimiter returns a limiter holding limits for a project as though they
+// were just read, so that it does not read them from the database
+func testLimiter(limits models.IngestLimits) *IngestLimiter {
+	l := NewIngestLimiter(nil, 0, logger.NewLogger())
+	l.projects[limits.ProjectID] = &projectLimitState{limits: limits, loadedAt: time.Now().UTC()}
+	return l
+}
+
+// pendingUsage sums the usage of a project not written yet
+func pendingUsage(l *IngestLimiter, projectID string) models.UsageDay {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var sum models.UsageDay
+	for key, usage := range l.pending {
+		if key.projectID == projectID {
+			sum.Accepted += usage.Accepted
+			sum.RateLimited += usage.RateLimited
+			sum.Dropped += usage.Dropped
+		}
+	}
+	return sum
+}
+
+func TestIngestLimiterQuotaRollover(t *testing.T) {
+	l := testLimiter(models.IngestLimits{ProjectID: "project-1", MonthlyQuota: 2})
+	st := l.projects["project-1"]
+	key := &models.ProjectKey{ID: "k1", ProjectID: "project-1"}
+
+	endOfJanuary := time.Date(2026, time.January, 31, 23, 59, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if d := l.decide(st, key, endOfJanuary); !d.Accepted() {
+			t.Fatalf("event %d in January = %s, want accepted", i+1, d.Outcome)
+		}
+	}
+	d := l.decide(st, key, endOfJanuary)
+	if d.Outcome != models.IngestOverQuota {
+		t.Fatalf("event over the quota = %s, want %s", d.Outcome, models.IngestOverQuota)
+	}
+	if d.RetryAfter != time.Minute {
+		t.Errorf("RetryAfter = %s, want the minute left in January", d.RetryAfter)
+	}
+
+	february := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	if d := l.decide(st, key, february); !d.Accepted() {
+		t.Fatalf("first event in February = %s, want accepted", d.Outcome)
+	}
+	if !st.period.Equal(february) || st.used != 1 {
+		t.Errorf("period %s used %d, want %s used 1", st.period, st.used, february)
+	}
+}
+
+func TestIngestLimiterRefund(t *testing.T) {
+	tests := []struct {
+		name        string
+		queue       func(q *IngestQueue)
+		wantErr     error
+		wantOutcome string
+	}{
+		{
+			name:        "queue closed",
+			queue:       func(q *IngestQueue) { q.Shutdown(context.Background()) },
+			wantErr:     ErrIngestQueueClosed,
+			wantOutcome: models.IngestQueueClosed,
+		},
+		{
+			name: "queue full",
+			queue: func(q *IngestQueue) {
+				q.Enqueue(nil, &models.Error{ProjectID: "project-2"}, nil, nil)
+			},
+			wantErr:     ErrIngestQueueFull,
+			wantOutcome: models.IngestQueueFull,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The project and its key allow one event, so that another is
+			// only accepted once the first is refunded
+			l := testLimiter(models.IngestLimits{ProjectID: "project-1", RateLimit: 1, MonthlyQuota: 1})
+			key := &models.ProjectKey{ID: "k1", ProjectID: "project-1", RateLimit: 1}
+			q := testQueue(t, l, IngestQueueConfig{Capacity: 1})
+			tt.queue(q)
+
+			if d := l.Admit(context.Background(), key); !d.Accepted() {
+				t.Fatalf("Admit() = %s, want accepted", d.Outcome)
+			}
+			err := q.Enqueue(key, &models.Error{ProjectID: "project-1"}, nil, nil)
+			if err != tt.wantErr {
+				t.Fatalf("Enqueue() error = %v, want %v", err, tt.wantErr)
+			}
+			if outcome := IngestQueueOutcome(err); outcome != tt.wantOutcome {
+				t.Fatalf("IngestQueueOutcome() = %s, want %s", outcome, tt.wantOutcome)
+			}
+			l.Refund(key, IngestQueueOutcome(err))
+
+			if usage := pendingUsage(l, "project-1"); usage.Accepted != 0 || usage.Dropped != 1 {
+				t.Errorf("usage = %d accepted %d dropped, want 0 accepted 1 dropped", usage.Accepted, usage.Dropped)
+			}
+			if d := l.Admit(context.Background(), key); !d.Accepted() {
+				t.Errorf("Admit() after the refund = %s, want accepted", d.Outcome)
+			}
+			if d := l.Admit(context.Background(), key); d.Accepted() {
+				t.Error("Admit() accepted more events than the limits allow")
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	ErrIngestQueueFull   = errors.New("ingest queue is full")
+	ErrIngestQueueClosed = errors.New("ingest queue is closed")
+)
+
+// ingestFlushTimeout bounds how long one group's batch may take to store
+const ingestFlushTimeout = 30 * time.Second
+
+// IngestQueueConfig sizes an IngestQueue. Zero values take the defaults of
+// DefaultIngestQueueConfig.
+type IngestQueueConfig struct {
+	Capacity      int           // events accepted but not yet prepared
+	Workers       int           // goroutines preparing events, and as many storing them
+	BatchSize     int           // events a writer holds before it flushes
+	FlushInterval time.Duration // longest an event waits in a writer's batch
+}
+
+// DefaultIngestQueueConfig is the configuration used for unset fields
+var DefaultIngestQueueConfig = IngestQueueConfig{
+	Capacity:      10000,
+	Workers:       4,
+	BatchSize:     500,
+	FlushInterval: time.Second,
+}
+
+// IngestQueue tracks errors in the background, so that ingest requests do
+// not wait on the database. Workers parse, symbolicate and fingerprint the
+// queued events and hand them to writers by fingerprint, so that every
+// event of a group goes to the same writer. A writer coalesces the events
+// of each group in its batch and stores them with one update of the group.
+// Events are sampled by the sampling configuration of their project, see
+// SamplingService.
+//
+// Memory is bounded: Enqueue fails with ErrIngestQueueFull rather than
+// wait when Capacity events are queued, and workers block while writers
+// are behind.
+//
+// Events that fail to be prepared or stored are dropped after the ingest
+// limits admitted them, so they are refunded to the limits and counted as
+// dropped.
+type IngestQueue struct {
+	ingestLimiter *IngestLimiter
+	// prepare, sample and store are the steps of ErrorService and
+	// SamplingService an event goes through
+	prepare func(ctx context.Context, errorData *models.Error, fingerprint []string) error
+	sample  func(ctx context.Context, event *postgres.TrackedEvent)
+	store   func(ctx context.Context, events []postgres.TrackedEvent) error
+	// rand rounds the weights of client-sampled events
+	rand func() float64
+
+	metrics *otel.Metrics
+	logger  *logger.Logger
+	cfg     IngestQueueConfig
+
+	events  chan queuedEvent
+	writers []chan preparedEvent
+
+	mu       sync.RWMutex
+	started  bool
+	closed   bool
+	workerWG sync.WaitGroup
+	writerWG sync.WaitGroup
+	done     chan struct{}
+}
+
+type queuedEvent struct {
+	// key is the project key the event was admitted for, nil when it was
+	// not sent with one
+	key         *models.ProjectKey
+	errorData   *models.Error
+	fingerprint []string
+	metadata    map[string]interface{}
+	// clientRate is the rate the client sampled the event at, 0 when it
+	// did not sample
+	clientRate float64
+}
+
+// preparedEvent is a queued event ready to be stored
+type preparedEvent struct {
+	key     *models.ProjectKey
+	tracked postgres.TrackedEvent
+}
+
+func NewIngestQueue(errorService *ErrorService, samplingService *SamplingService, ingestLimiter *IngestLimiter, metrics *otel.Metrics, cfg IngestQueueConfig, logger *logger.Logger) *IngestQueue {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = DefaultIngestQueueConfig.Capacity
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultIngestQueueConfig.Workers
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultIngestQueueConfig.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultIngestQueueConfig.FlushInterval
+	}
+
+	q := &IngestQueue{
+		ingestLimiter: ingestLimiter,
+		prepare:       errorService.prepare,
+		sample:        samplingService.Sample,
+		store: func(ctx context.Context, events []postgres.TrackedEvent) error {
+			_, err := errorService.trackBatch(ctx, events)
+			return err
+		},
+		rand:    rand.Float64,
+		metrics: metrics,
+		logger:  logger,
+		cfg:     cfg,
+		events:  make(chan queuedEvent, cfg.Capacity),
+		writers: make([]chan preparedEvent, cfg.Workers),
+		done:    make(chan struct{}),
+	}
+	for i := range q.writers {
+		q.writers[i] = make(chan preparedEvent, cfg.BatchSize)
+	}
+	return q
+}
+
+// Start runs the workers and writers until Shutdown is called.
+func (q *IngestQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started || q.closed {
+		return
+	}
+	q.started = true
+
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.workerWG.Add(1)
+		go q.work()
+	}
+	for _, events := range q.writers {
+		q.writerWG.Add(1)
+		go q.write(events)
+	}
+
+	// Writers stop once the workers are done feeding them
+	go func() {
+		q.workerWG.Wait()
+		for _, events := range q.writers {
+			close(events)
+		}
+		q.writerWG.Wait()
+		close(q.done)
+	}()
+}
+
+// Enqueue queues an error to be tracked as ErrorService.Track would. key
+// is the project key the ingest limits admitted the error for; it is
+// refunded when the error is dropped later on. Enqueue fails with
+// ErrIngestQueueFull when the queue is at capacity, and with
+// ErrIngestQueueClosed once Shutdown was called.
+func (q *IngestQueue) Enqueue(key *models.ProjectKey, errorData *models.Error, fingerprint []string, metadata map[string]interface{}) error {
+	return q.EnqueueSampled(key, errorData, fingerprint, metadata, 0)
+}
+
+// EnqueueSampled queues an error the client sampled at clientRate, as
+// Enqueue does. The event is not sampled again, and counts for as many
+// events as clientRate stands for; a clientRate of 0 or 1 is Enqueue.
+func (q *IngestQueue) EnqueueSampled(key *models.ProjectKey, errorData *models.Error, fingerprint []string, metadata map[string]interface{}, clientRate float64) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrIngestQueueClosed
+	}
+	select {
+	case q.events <- queuedEvent{key: key, errorData: errorData, fingerprint: fingerprint, metadata: metadata, clientRate: clientRate}:
+		return nil
+	default:
+		return ErrIngestQueueFull
+	}
+}
+
+// RetryAfter is how long clients turned away by a full queue should wait,
+// about the time writers take to flush their batches
+func (q *IngestQueue) RetryAfter() time.Duration {
+	if q.cfg.FlushInterval < time.Second {
+		return time.Second
+	}
+	return q.cfg.FlushInterval.Round(time.Second)
+}
+
+// IngestQueueOutcome is the ingest outcome of an event Enqueue turned away
+func IngestQueueOutcome(err error) string {
+	if errors.Is(err, ErrIngestQueueFull) {
+		return models.IngestQueueFull
+	}
+	return models.IngestQueueClosed
+}
+
+// Shutdown stops accepting events and waits until the queued ones are
+// stored, or ctx ends.
+func (q *IngestQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.events)
+	started := q.started
+	q.mu.Unlock()
+
+	if !started {
+		if n := len(q.events); n > 0 {
+			return fmt.Errorf("ingest queue was never started, %d events dropped", n)
+		}
+		return nil
+	}
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("ingest queue did not drain: %w", ctx.Err())
+	}
+}
+
+// work prepares and samples queued events and hands each to the writer of
+// its group
+func (q *IngestQueue) work() {
+	defer q.workerWG.Done()
+	for event := range q.events {
+		ctx, cancel := context.WithTimeout(logger.WithProjectID(context.Background(), event.errorData.ProjectID), ingestFlushTimeout)
+		err := q.prepare(ctx, event.errorData, event.fingerprint)
+		if err != nil {
+			cancel()
+			q.logger.Error(ctx, "Failed to prepare queued error, dropping it", err)
+			q.drop(event.errorData.ProjectID, models.IngestPrepareFailed, event.key)
+			continue
+		}
+
+		tracked := postgres.TrackedEvent{Error: event.errorData, Metadata: event.metadata}
+		if event.clientRate > 0 && event.clientRate < 1 {
+			tracked.Weight = ClientSampleWeight(event.clientRate, q.rand)
+		} else {
+			q.sample(ctx, &tracked)
+		}
+		cancel()
+		q.writers[q.writerFor(event.errorData)] <- preparedEvent{key: event.key, tracked: tracked}
+	}
+}
+
+func (q *IngestQueue) writerFor(e *models.Error) int {
+	h := fnv.New32a()
+	h.Write([]byte(e.ProjectID + "\x00" + e.Environment + "\x00" + e.Fingerprint))
+	return int(h.Sum32() % uint32(len(q.writers)))
+}
+
+// groupKey identifies the group an event is stored in
+type groupKey struct {
+	projectID, environment, fingerprint string
+}
+
+// write batches the events it is handed and stores them every
+// FlushInterval, or as soon as BatchSize events are waiting
+func (q *IngestQueue) write(events <-chan preparedEvent) {
+	defer q.writerWG.Done()
+	ticker := time.NewTicker(q.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make(map[groupKey][]preparedEvent)
+	var order []groupKey
+	size := 0
+	flush := func() {
+		for _, key := range order {
+			q.flush(key, batch[key])
+		}
+		clear(batch)
+		order = order[:0]
+		size = 0
+	}
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				flush()
+				return
+			}
+			e := event.tracked.Error
+			key := groupKey{e.ProjectID, e.Environment, e.Fingerprint}
+			if _, ok := batch[key]; !ok {
+				order = append(order, key)
+			}
+			batch[key] = append(batch[key], event)
+			if size++; size >= q.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// flush stores the events of one group. A failed batch is logged and
+// dropped; the events are not retried.
+func (q *IngestQueue) flush(key groupKey, events []preparedEvent) {
+	ctx, cancel := context.WithTimeout(logger.WithProjectID(context.Background(), key.projectID), ingestFlushTimeout)
+	defer cancel()
+
+	tracked := make([]postgres.TrackedEvent, len(events))
+	keys := make([]*models.ProjectKey, len(events))
+	for i, event := range events {
+		tracked[i] = event.tracked
+		keys[i] = event.key
+	}
+	if err := q.store(ctx, tracked); err != nil {
+		q.logger.Error(ctx, "Failed to store queued errors", err,
+			"fingerprint", key.fingerprint, "environment", key.environment, "dropped", len(events))
+		q.drop(key.projectID, models.IngestStoreFailed, keys...)
+	}
+}
+
+// drop counts events of a project dropped for outcome after they were
+// admitted, and refunds them to the ingest limits of their keys
+func (q *IngestQueue) drop(projectID, outcome string, keys ...*models.ProjectKey) {
+	for _, key := range keys {
+		if key != nil {
+			q.ingestLimiter.Refund(key, outcome)
+		}
+	}
+	q.metrics.IngestDroppedTotal.Add(context.Background(), int64(len(keys)), metric.WithAttributes(
+		attribute.String("project_id", projectID),
+		attribute.String("reason", outcome),
+	))
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/otel"
+
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// testQueue returns a queue whose events are prepared and sampled as they
+// are, and stored nowhere until the test sets store
+func testQueue(t *testing.T, limiter *IngestLimiter, cfg IngestQueueConfig) *IngestQueue {
+	t.Helper()
+	dropped, err := noop.NewMeterProvider().Meter("test").Int64Counter("ingest_dropped_total")
+	if err != nil {
+		t.Fatalf("Int64Counter() error = %v", err)
+	}
+	q := NewIngestQueue(nil, nil, limiter, &otel.Metrics{IngestDroppedTotal: dropped}, cfg, logger.NewLogger())
+	q.prepare = func(context.Context, *models.Error, []string) error { return nil }
+	q.sample = func(context.Context, *postgres.TrackedEvent) {}
+	q.store = func(context.Context, []postgres.TrackedEvent) error { return nil }
+	return q
+}
+
+// storedEvents records the batches a queue stores
+type storedEvents struct {
+	mu      sync.Mutex
+	batches [][]postgres.TrackedEvent
+}
+
+func (s *storedEvents) store(_ context.Context, events []postgres.TrackedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, events)
+	return nil
+}
+
+// byGroup lists the messages stored per fingerprint, in the order they were
+// stored
+func (s *storedEvents) byGroup() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := make(map[string][]string)
+	for _, batch := range s.batches {
+		for _, event := range batch {
+			groups[event.Error.Fingerprint] = append(groups[event.Error.Fingerprint], event.Error.Message)
+		}
+	}
+	return groups
+}
+
+func TestIngestQueueShardOrder(t *testing.T) {
+	q := testQueue(t, nil, IngestQueueConfig{Workers: 4, BatchSize: 3, FlushInterval: time.Hour})
+	stored := &storedEvents{}
+	q.store = stored.store
+
+	event := func(group string, i int) preparedEvent {
+		return preparedEvent{tracked: postgres.TrackedEvent{Error: &models.Error{
+			ProjectID:   "project-1",
+			Environment: "production",
+			Fingerprint: group,
+			Message:     fmt.Sprintf("%s %d", group, i),
+		}}}
+	}
+	for i := 0; i < 10; i++ {
+		if a, b := q.writerFor(event("a", 0).tracked.Error), q.writerFor(event("a", i).tracked.Error); a != b {
+			t.Fatalf("events of a group went to writers %d and %d", a, b)
+		}
+	}
+
+	// Events of two groups interleave on one writer and are flushed in
+	// several batches
+	events := make(chan preparedEvent)
+	q.writerWG.Add(1)
+	go q.write(events)
+	want := map[string][]string{}
+	for i := 0; i < 10; i++ {
+		for _, group := range []string{"a", "b"} {
+			e := event(group, i)
+			events <- e
+			want[group] = append(want[group], e.tracked.Error.Message)
+		}
+	}
+	close(events)
+	q.writerWG.Wait()
+
+	for _, batch := range stored.batches {
+		for _, event := range batch[1:] {
+			if event.Error.Fingerprint != batch[0].Error.Fingerprint {
+				t.Errorf("a batch holds events of groups %s and %s", batch[0].Error.Fingerprint, event.Error.Fingerprint)
+				break
+			}
+		}
+	}
+	got := stored.byGroup()
+	for group, messages := range want {
+		if fmt.Sprint(got[group]) != fmt.Sprint(messages) {
+			t.Errorf("group %s stored %v, want %v", group, got[group], messages)
+		}
+	}
+}
+
+func TestIngestQueueDrainsOnShutdown(t *testing.T) {
+	const events = 200
+	l := testLimiter(models.IngestLimits{ProjectID: "project-1"})
+	key := &models.ProjectKey{ID: "k1", ProjectID: "project-1"}
+	// Nothing is flushed before Shutdown, by size or by time
+	q := testQueue(t, l, IngestQueueConfig{Capacity: events, Workers: 4, BatchSize: 10 * events, FlushInterval: time.Hour})
+	stored := &storedEvents{}
+	q.store = stored.store
+	q.Start()
+
+	for i := 0; i < events; i++ {
+		if d := l.Admit(context.Background(), key); !d.Accepted() {
+			t.Fatalf("Admit() = %s, want accepted", d.Outcome)
+		}
+		e := &models.Error{ProjectID: "project-1", Fingerprint: fmt.Sprint(i % 7), Message: fmt.Sprint(i)}
+		if err := q.Enqueue(key, e, nil, nil); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := q.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	n := 0
+	for _, messages := range stored.byGroup() {
+		n += len(messages)
+	}
+	if n != events {
+		t.Errorf("stored %d events, want %d", n, events)
+	}
+	if usage := pendingUsage(l, "project-1"); usage.Accepted != events || usage.Dropped != 0 {
+		t.Errorf("usage = %d accepted %d dropped, want %d accepted 0 dropped", usage.Accepted, usage.Dropped, events)
+	}
+	if err := q.Enqueue(key, &models.Error{ProjectID: "project-1"}, nil, nil); err != ErrIngestQueueClosed {
+		t.Errorf("Enqueue() after Shutdown error = %v, want %v", err, ErrIngestQueueClosed)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrInvitationExpired       = errors.New("invitation expired or already accepted")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email")
+)
+
+type InvitationService struct {
+	invitationRepo *postgres.InvitationRepository
+	membershipRepo *postgres.MembershipRepository
+	userRepo       *postgres.UserRepository
+}
+
+func NewInvitationService(invitationRepo *postgres.InvitationRepository, membershipRepo *postgres.MembershipRepository, userRepo *postgres.UserRepository) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		membershipRepo: membershipRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// InviteToOrganization creates an invitation to join the organization.
+func (s *InvitationService) InviteToOrganization(ctx context.Context, organizationID, email string, role models.Role, invitedBy string) (*models.Invitation, error) {
+	return s.create(ctx, &models.Invitation{OrganizationID: organizationID, Email: email, Role: role, InvitedBy: invitedBy})
+}
+
+// InviteToProject creates an invitation to join the project.
+func (s *InvitationService) InviteToProject(ctx context.Context, projectID, email string, role models.Role, invitedBy string) (*models.Invitation, error) {
+	return s.create(ctx, &models.Invitation{ProjectID: projectID, Email: email, Role: role, InvitedBy: invitedBy})
+}
+
+func (s *InvitationService) create(ctx context.Context, inv *models.Invitation) (*models.Invitation, error) {
+	if !inv.Role.IsValid() || inv.Role == models.RoleOwner {
+		return nil, ErrInvalidRole
+	}
+
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	inv.ID = uuid.NewString()
+	inv.Email = strings.ToLower(strings.TrimSpace(inv.Email))
+	inv.Token = token
+	inv.CreatedAt = now
+	inv.ExpiresAt = now.Add(invitationTTL)
+
+	if err := s.invitationRepo.Create(ctx, inv); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Withdraw removes an invitation that was never sent, so that it cannot be
+// accepted.
+func (s *InvitationService) Withdraw(ctx context.Context, id string) error {
+	return s.invitationRepo.Delete(ctx, id)
+}
+
+// ListPendingForOrganization returns the organization's open invitations.
+func (s *InvitationService) ListPendingForOrganization(ctx context.Context, organizationID string) ([]*models.Invitation, error) {
+	return s.invitationRepo.ListPendingForOrganization(ctx, organizationID)
+}
+
+// ListPendingForProject returns the project's open invitations.
+func (s *InvitationService) ListPendingForProject(ctx context.Context, projectID string) ([]*models.Invitation, error) {
+	return s.invitationRepo.ListPendingForProject(ctx, projectID)
+}
+
+// Accept adds the user to the invitation's organization or project. The
+// user's email must match the invited address. An existing stronger role is
+// never downgraded.
+func (s *InvitationService) Accept(ctx context.Context, token, userID string) (*models.Invitation, error) {
+	inv, err := s.invitationRepo.GetByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if inv.AcceptedAt != nil || time.Now().After(inv.ExpiresAt) {
+		return nil, ErrInvitationExpired
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, ErrInvitationEmailMismatch
+	}
+	user, err := s.userRepo.GetByID(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, inv.Email) {
+		return nil, ErrInvitationEmailMismatch
+	}
+
+	if inv.OrganizationID != "" {
+		current, err := s.membershipRepo.OrganizationRole(ctx, inv.OrganizationID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !current.AtLeast(inv.Role) {
+			if err := s.membershipRepo.AddOrganizationMember(ctx, inv.OrganizationID, userID, inv.Role); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		current, err := s.membershipRepo.ProjectMemberRole(ctx, inv.ProjectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !current.AtLeast(inv.Role) {
+			if err := s.membershipRepo.AddProjectMember(ctx, inv.ProjectID, userID, inv.Role); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	now := time.Now()
+	if err := s.invitationRepo.MarkAccepted(ctx, inv.ID, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvitationExpired
+		}
+		return nil, err
+	}
+	inv.AcceptedAt = &now
+
+	return inv, nil
+}
+
+func generateInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/notifier"
+)
+
+var (
+	ErrIssueMailNotConfigured = errors.New("email is not configured (RESEND_API_KEY and EMAIL_FROM)")
+	ErrIssueMailQueueFull     = errors.New("issue email queue is full")
+	ErrIssueMailQueueClosed   = errors.New("issue email queue is closed")
+)
+
+const (
+	// issueMailQueueSize is how many issue emails may wait to be sent
+	issueMailQueueSize = 256
+	// issueMailWorkers is how many issue emails are sent at once
+	issueMailWorkers = 2
+)
+
+// IssueMailer sends the emails of issue activity, such as assignments and
+// mentions, in the background so that requests do not wait on the mail
+// provider. Emails wait in a bounded queue; when it is full they are
+// dropped rather than holding up the request.
+type IssueMailer struct {
+	sender notifier.Sender
+	logger *logger.Logger
+
+	emails chan issueEmail
+	wg     sync.WaitGroup
+
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+}
+
+type issueEmail struct {
+	to      string
+	subject string
+	body    string
+}
+
+// NewIssueMailer returns a mailer that sends with sender; sender may be nil
+// when email is not configured, and then Send fails.
+func NewIssueMailer(sender notifier.Sender, logger *logger.Logger) *IssueMailer {
+	return &IssueMailer{
+		sender: sender,
+		logger: logger,
+		emails: make(chan issueEmail, issueMailQueueSize),
+	}
+}
+
+// Start runs the workers that send queued emails until Stop is called.
+func (m *IssueMailer) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started || m.closed {
+		return
+	}
+	m.started = true
+
+	for range issueMailWorkers {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			for email := range m.emails {
+				if _, err := m.sender.Send([]string{email.to}, email.subject, email.body); err != nil {
+					m.logger.Error(context.Background(), "Failed to send issue email", err, "subject", email.subject)
+				}
+			}
+		}()
+	}
+}
+
+// Send queues an email to one recipient. It fails with
+// ErrIssueMailNotConfigured without a sender, with ErrIssueMailQueueFull
+// when too many emails are waiting, and with ErrIssueMailQueueClosed once
+// Stop was called.
+func (m *IssueMailer) Send(to, subject, body string) error {
+	if m.sender == nil {
+		return ErrIssueMailNotConfigured
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return ErrIssueMailQueueClosed
+	}
+	select {
+	case m.emails <- issueEmail{to: to, subject: subject, body: body}:
+		return nil
+	default:
+		return ErrIssueMailQueueFull
+	}
+}
+
+// Stop stops accepting emails and waits until the queued ones are sent.
+func (m *IssueMailer) Stop() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.emails)
+	started := m.started
+	m.mu.Unlock()
+
+	if started {
+		m.wg.Wait()
+	}
+}
@@ -16,4 +16,9 @@ func NewMetricsService(promRepo *telemetry.PrometheusRepository) *MetricsService
 
 func (s *MetricsService) GetMetrics(ctx context.Context, projectID string) ([]*models.Metric, error) {
     return s.promRepo.QueryMetrics(ctx, projectID)
-}
\ No newline at end of file
+}
+
+// Query runs a PromQL instant query
+func (s *MetricsService) Query(ctx context.Context, query string) ([]models.MetricSample, error) {
+    return s.promRepo.Query(ctx, query)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+)
+
+const (
+	deliveryBatchSize    = 50
+	deliveryTimeout      = 30 * time.Second
+	deliveryStaleSending = 5 * time.Minute
+)
+
+// NotificationDispatcher sends queued notification deliveries in the
+// background, retrying failed ones with backoff.
+type NotificationDispatcher struct {
+	repo          *postgres.NotificationRepository
+	notifications *NotificationService
+	logger        *logger.Logger
+	interval      time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+func NewNotificationDispatcher(repo *postgres.NotificationRepository, notifications *NotificationService, logger *logger.Logger, interval time.Duration) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		repo:          repo,
+		notifications: notifications,
+		logger:        logger,
+		interval:      interval,
+	}
+}
+
+// Start polls for due deliveries every interval until Stop is called or ctx ends.
+func (d *NotificationDispatcher) Start(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil {
+		return
+	}
+
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			d.RunOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the dispatch loop and waits for in-flight deliveries.
+func (d *NotificationDispatcher) Stop() {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel = nil
+	d.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// RunOnce sends every delivery that is due, one batch at a time.
+func (d *NotificationDispatcher) RunOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.repo.ClaimDueDeliveries(ctx, deliveryBatchSize, deliveryStaleSending)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.logger.Error(ctx, "Failed to load due notification deliveries", err)
+			}
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// Deliveries are recorded even when shutdown interrupts the loop
+				sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deliveryTimeout)
+				defer cancel()
+				d.notifications.deliver(sendCtx, delivery)
+			}()
+		}
+		wg.Wait()
+
+		if len(deliveries) < deliveryBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/notifier"
+	"pulseguard/pkg/validator"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrChannelNotFound = errors.New("notification channel not found")
+	ErrInvalidChannel  = errors.New("invalid notification channel")
+)
+
+// maxDeliveryAttempts is how often a notification is tried before it is marked failed
+const maxDeliveryAttempts = 6
+
+type NotificationService struct {
+	repo        *postgres.NotificationRepository
+	httpClient  *http.Client
+	emailSender notifier.Sender
+	silences    *SilenceService
+	logger      *logger.Logger
+}
+
+// NewNotificationService creates the service. httpClient may be nil to use
+// notifier.DefaultClient; emailSender may be nil when email is not configured.
+func NewNotificationService(repo *postgres.NotificationRepository, httpClient *http.Client, emailSender notifier.Sender, silences *SilenceService, logger *logger.Logger) *NotificationService {
+	return &NotificationService{
+		repo:        repo,
+		httpClient:  httpClient,
+		emailSender: emailSender,
+		silences:    silences,
+		logger:      logger,
+	}
+}
+
+// CreateChannel validates and stores a new channel. Webhook channels get a
+// signing secret when none is supplied.
+func (s *NotificationService) CreateChannel(ctx context.Context, ch *models.NotificationChannel) (*models.NotificationChannel, error) {
+	if err := validateChannel(ch); err != nil {
+		return nil, err
+	}
+	if ch.Type == models.ChannelWebhook && ch.Secret == "" {
+		secret, err := generateChannelSecret()
+		if err != nil {
+			return nil, err
+		}
+		ch.Secret = secret
+	}
+
+	now := time.Now()
+	ch.ID = uuid.NewString()
+	ch.CreatedAt = now
+	ch.UpdatedAt = now
+
+	if err := s.repo.CreateChannel(ctx, ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *NotificationService) ListChannels(ctx context.Context, projectID string) ([]*models.NotificationChannel, error) {
+	return s.repo.ListChannels(ctx, projectID)
+}
+
+// GetChannel returns one of the project's channels
+func (s *NotificationService) GetChannel(ctx context.Context, projectID, channelID string) (*models.NotificationChannel, error) {
+	if _, err := uuid.Parse(channelID); err != nil {
+		return nil, ErrChannelNotFound
+	}
+	ch, err := s.repo.GetChannel(ctx, projectID, channelID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrChannelNotFound
+		}
+		return nil, err
+	}
+	return ch, nil
+}
+
+// UpdateChannel validates and saves changes to a channel. The type of a
+// channel cannot change.
+func (s *NotificationService) UpdateChannel(ctx context.Context, ch *models.NotificationChannel) (*models.NotificationChannel, error) {
+	if err := validateChannel(ch); err != nil {
+		return nil, err
+	}
+	ch.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateChannel(ctx, ch); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrChannelNotFound
+		}
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *NotificationService) DeleteChannel(ctx context.Context, projectID, channelID string) error {
+	if _, err := uuid.Parse(channelID); err != nil {
+		return ErrChannelNotFound
+	}
+	if err := s.repo.DeleteChannel(ctx, projectID, channelID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChannelNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// TestChannel sends a test notification right away, without retries
+func (s *NotificationService) TestChannel(ctx context.Context, ch *models.NotificationChannel) error {
+	n, err := s.notifierFor(ch)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return n.Notify(ctx, notifier.Message{
+		Event:     notifier.EventTest,
+		ProjectID: ch.ProjectID,
+		Title:     "PulseGuard test notification",
+		Text:      fmt.Sprintf("Channel %q is set up correctly.", ch.Name),
+		Severity:  "info",
+		Status:    "test",
+		StartedAt: now,
+		SentAt:    now,
+	})
+}
+
+// NotifyAlert queues a notification about the alert for every enabled channel
+// of its project, unless a silence or maintenance window suppresses it.
+// Failures are logged rather than returned so that raising the alert itself
+// never fails because of notifications.
+func (s *NotificationService) NotifyAlert(ctx context.Context, alert *models.Alert, event string) {
+	reason, err := s.silences.Suppression(ctx, alert, time.Now())
+	if err != nil {
+		s.logger.Error(ctx, "Failed to check alert silences", err, "alert_id", alert.ID)
+	}
+	if reason != "" {
+		s.logger.Info(ctx, "Alert notification suppressed", "alert_id", alert.ID, "event", event, "reason", reason)
+		return
+	}
+
+	channels, err := s.repo.ListEnabledChannels(ctx, alert.ProjectID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to load notification channels", err, "alert_id", alert.ID)
+		return
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(alertMessage(alert, event))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to encode alert notification", err, "alert_id", alert.ID)
+		return
+	}
+
+	now := time.Now()
+	for _, ch := range channels {
+		delivery := &models.NotificationDelivery{
+			ID:            uuid.NewString(),
+			ProjectID:     alert.ProjectID,
+			ChannelID:     ch.ID,
+			AlertID:       alert.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			s.logger.Error(ctx, "Failed to queue alert notification", err, "alert_id", alert.ID, "channel_id", ch.ID)
+		}
+	}
+}
+
+// ListDeliveries returns the project's delivery log
+func (s *NotificationService) ListDeliveries(ctx context.Context, filters postgres.DeliveryFilters) ([]*models.NotificationDelivery, error) {
+	if filters.ChannelID != "" {
+		if _, err := uuid.Parse(filters.ChannelID); err != nil {
+			return []*models.NotificationDelivery{}, nil
+		}
+	}
+	return s.repo.ListDeliveries(ctx, filters)
+}
+
+// deliver makes one attempt at sending a claimed delivery and records the outcome
+func (s *NotificationService) deliver(ctx context.Context, d *models.NotificationDelivery) {
+	ctx = logger.WithProjectID(ctx, d.ProjectID)
+
+	err := s.attempt(ctx, d)
+	if err == nil {
+		if err := s.repo.MarkDelivered(ctx, d.ID, time.Now()); err != nil {
+			s.logger.Error(ctx, "Failed to record notification delivery", err, "delivery_id", d.ID)
+		}
+		return
+	}
+
+	responseCode, next := deliveryFailure(d, err, time.Now())
+	s.logger.Error(ctx, "Notification delivery attempt failed", err, "delivery_id", d.ID, "attempt", d.Attempts)
+	if err := s.repo.MarkAttemptFailed(ctx, d.ID, notifier.Describe(err), responseCode, next); err != nil {
+		s.logger.Error(ctx, "Failed to record notification delivery", err, "delivery_id", d.ID)
+	}
+}
+
+// deliveryFailure returns the response code of a failed attempt, when the
+// receiver answered, and when to try again, nil once the delivery has had
+// maxDeliveryAttempts
+func deliveryFailure(d *models.NotificationDelivery, err error, now time.Time) (*int, *time.Time) {
+	var responseCode *int
+	var statusErr *notifier.StatusError
+	if errors.As(err, &statusErr) {
+		responseCode = &statusErr.StatusCode
+	}
+
+	var next *time.Time
+	if d.Attempts < maxDeliveryAttempts {
+		at := now.Add(notifier.Backoff(d.Attempts))
+		next = &at
+	}
+	return responseCode, next
+}
+
+func (s *NotificationService) attempt(ctx context.Context, d *models.NotificationDelivery) error {
+	ch, err := s.repo.GetChannel(ctx, d.ProjectID, d.ChannelID)
+	if err != nil {
+		return fmt.Errorf("load channel: %w", err)
+	}
+
+	var msg notifier.Message
+	if err := json.Unmarshal(d.Payload, &msg); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	msg.SentAt = time.Now()
+
+	n, err := s.notifierFor(ch)
+	if err != nil {
+		return err
+	}
+	return n.Notify(ctx, msg)
+}
+
+func (s *NotificationService) notifierFor(ch *models.NotificationChannel) (notifier.Notifier, error) {
+	switch ch.Type {
+	case models.ChannelWebhook:
+		return notifier.NewWebhookNotifier(ch.URL, ch.Secret, s.httpClient), nil
+	case models.ChannelSlack:
+		return notifier.NewChatNotifier(ch.URL, notifier.FormatSlack, s.httpClient), nil
+	case models.ChannelDiscord:
+		return notifier.NewChatNotifier(ch.URL, notifier.FormatDiscord, s.httpClient), nil
+	case models.ChannelEmail:
+		return notifier.NewEmailNotifier(s.emailSender, ch.Recipients), nil
+	}
+	return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidChannel, ch.Type)
+}
+
+func alertMessage(alert *models.Alert, event string) notifier.Message {
+	title := alert.Message
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = title[:i]
+	}
+	if len(title) > 120 {
+		title = title[:117] + "..."
+	}
+
+	return notifier.Message{
+		Event:      event,
+		AlertID:    alert.ID,
+		ProjectID:  alert.ProjectID,
+		Title:      title,
+		Text:       alert.Message,
+		Severity:   alert.Severity,
+		Status:     alert.Status,
+		Labels:     alert.Labels,
+		Value:      alert.Value,
+		StartedAt:  alert.StartedAt,
+		ResolvedAt: alert.ResolvedAt,
+	}
+}
+
+func validateChannel(ch *models.NotificationChannel) error {
+	ch.Name = strings.TrimSpace(ch.Name)
+	if ch.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidChannel)
+	}
+
+	switch ch.Type {
+	case models.ChannelWebhook, models.ChannelSlack, models.ChannelDiscord:
+		u, err := url.Parse(ch.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: a valid http(s) url is required", ErrInvalidChannel)
+		}
+		// Names are checked again when the notifier connects, once resolved
+		host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		if addr, err := netip.ParseAddr(host); (err == nil && !notifier.IsPublicAddr(addr)) ||
+			host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return fmt.Errorf("%w: the url must point to a public address", ErrInvalidChannel)
+		}
+		ch.Recipients = nil
+	case models.ChannelEmail:
+		if len(ch.Recipients) == 0 {
+			return fmt.Errorf("%w: at least one recipient is required", ErrInvalidChannel)
+		}
+		for _, to := range ch.Recipients {
+			if !validator.IsValidEmail(to) {
+				return fmt.Errorf("%w: invalid recipient %q", ErrInvalidChannel, to)
+			}
+		}
+		ch.URL = ""
+		ch.Secret = ""
+	default:
+		return fmt.Errorf("%w: type must be webhook, email, slack or discord", ErrInvalidChannel)
+	}
+	return nil
+}
+
+func generateChannelSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/pkg/logger"
+	"pulseguard/pkg/notifier"
+)
+
+type sentEmail struct {
+	to      []string
+	subject string
+	body    string
+}
+
+type fakeSender struct {
+	sent []sentEmail
+	err  error
+}
+
+func (f *fakeSender) Send(to []string, subject, htmlBody string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.sent = append(f.sent, sentEmail{to: to, subject: subject, body: htmlBody})
+	return "email-1", nil
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver answers every request with status and hands it to the test
+func receiver(t *testing.T, status int) (*httptest.Server, <-chan receivedRequest) {
+	t.Helper()
+	requests := make(chan receivedRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- receivedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, requests
+}
+
+func testMessage() notifier.Message {
+	return notifier.Message{
+		Event:     notifier.EventAlertFiring,
+		ProjectID: "project-1",
+		Title:     "High error rate",
+		Text:      "Error rate above 5%",
+		Severity:  "critical",
+		Status:    "firing",
+		Labels:    map[string]string{"service": "api"},
+		StartedAt: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNotifierDelivers(t *testing.T) {
+	t.Run("webhook", func(t *testing.T) {
+		srv, requests := receiver(t, http.StatusOK)
+		s := NewNotificationService(nil, srv.Client(), nil, nil, logger.NewLogger())
+		ch := &models.NotificationChannel{Type: models.ChannelWebhook, URL: srv.URL, Secret: "s3cret"}
+
+		n, err := s.notifierFor(ch)
+		if err != nil {
+			t.Fatalf("notifierFor: %v", err)
+		}
+		if err := n.Notify(context.Background(), testMessage()); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+
+		req := <-requests
+		var got notifier.Message
+		if err := json.Unmarshal(req.body, &got); err != nil {
+			t.Fatalf("webhook body is not a message: %v", err)
+		}
+		if got.Title != "High error rate" || got.Event != notifier.EventAlertFiring {
+			t.Errorf("webhook message = %+v", got)
+		}
+		timestamp := req.header.Get(notifier.TimestampHeader)
+		if !notifier.Verify("s3cret", timestamp, req.body, req.header.Get(notifier.SignatureHeader)) {
+			t.Errorf("webhook signature %q does not verify", req.header.Get(notifier.SignatureHeader))
+		}
+	})
+
+	t.Run("slack", func(t *testing.T) {
+		srv, requests := receiver(t, http.StatusOK)
+		s := NewNotificationService(nil, srv.Client(), nil, nil, logger.NewLogger())
+		ch := &models.NotificationChannel{Type: models.ChannelSlack, URL: srv.URL}
+
+		n, err := s.notifierFor(ch)
+		if err != nil {
+			t.Fatalf("notifierFor: %v", err)
+		}
+		if err := n.Notify(context.Background(), testMessage()); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+
+		req := <-requests
+		var got struct {
+			Text        string `json:"text"`
+			Attachments []struct {
+				Text string `json:"text"`
+			} `json:"attachments"`
+		}
+		if err := json.Unmarshal(req.body, &got); err != nil {
+			t.Fatalf("slack body: %v", err)
+		}
+		if !strings.Contains(got.Text, "*High error rate*") {
+			t.Errorf("slack text = %q", got.Text)
+		}
+		if len(got.Attachments) != 1 || got.Attachments[0].Text != "Error rate above 5%" {
+			t.Errorf("slack attachments = %+v", got.Attachments)
+		}
+	})
+
+	t.Run("email", func(t *testing.T) {
+		sender := &fakeSender{}
+		s := NewNotificationService(nil, nil, sender, nil, logger.NewLogger())
+		ch := &models.NotificationChannel{Type: models.ChannelEmail, Recipients: []string{"oncall@example.com"}}
+
+		n, err := s.notifierFor(ch)
+		if err != nil {
+			t.Fatalf("notifierFor: %v", err)
+		}
+		if err := n.Notify(context.Background(), testMessage()); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+
+		if len(sender.sent) != 1 {
+			t.Fatalf("sent %d emails, want 1", len(sender.sent))
+		}
+		email := sender.sent[0]
+		if len(email.to) != 1 || email.to[0] != "oncall@example.com" {
+			t.Errorf("email to = %v", email.to)
+		}
+		if email.subject != "[PulseGuard] [FIRING] High error rate" {
+			t.Errorf("email subject = %q", email.subject)
+		}
+	})
+}
+
+func TestNotifierFailures(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name       string
+		channel    func(t *testing.T) (*models.NotificationChannel, *NotificationService)
+		statusCode int
+	}{
+		{
+			name: "webhook answers 500",
+			channel: func(t *testing.T) (*models.NotificationChannel, *NotificationService) {
+				srv, _ := receiver(t, http.StatusInternalServerError)
+				return &models.NotificationChannel{Type: models.ChannelWebhook, URL: srv.URL},
+					NewNotificationService(nil, srv.Client(), nil, nil, logger.NewLogger())
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "slack answers 404",
+			channel: func(t *testing.T) (*models.NotificationChannel, *NotificationService) {
+				srv, _ := receiver(t, http.StatusNotFound)
+				return &models.NotificationChannel{Type: models.ChannelSlack, URL: srv.URL},
+					NewNotificationService(nil, srv.Client(), nil, nil, logger.NewLogger())
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "webhook unreachable",
+			channel: func(t *testing.T) (*models.NotificationChannel, *NotificationService) {
+				return &models.NotificationChannel{Type: models.ChannelWebhook, URL: closed.URL},
+					NewNotificationService(nil, &http.Client{Timeout: time.Second}, nil, nil, logger.NewLogger())
+			},
+		},
+		{
+			name: "email not configured",
+			channel: func(t *testing.T) (*models.NotificationChannel, *NotificationService) {
+				return &models.NotificationChannel{Type: models.ChannelEmail, Recipients: []string{"oncall@example.com"}},
+					NewNotificationService(nil, nil, nil, nil, logger.NewLogger())
+			},
+		},
+		{
+			name: "email provider fails",
+			channel: func(t *testing.T) (*models.NotificationChannel, *NotificationService) {
+				return &models.NotificationChannel{Type: models.ChannelEmail, Recipients: []string{"oncall@example.com"}},
+					NewNotificationService(nil, nil, &fakeSender{err: errors.New("provider down")}, nil, logger.NewLogger())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch, s := tt.channel(t)
+			n, err := s.notifierFor(ch)
+			if err != nil {
+				t.Fatalf("notifierFor: %v", err)
+			}
+			err = n.Notify(context.Background(), testMessage())
+			if err == nil {
+				t.Fatal("Notify succeeded, want an error")
+			}
+
+			var statusErr *notifier.StatusError
+			switch {
+			case tt.statusCode != 0 && !errors.As(err, &statusErr):
+				t.Fatalf("error %v is not a status error", err)
+			case tt.statusCode != 0 && statusErr.StatusCode != tt.statusCode:
+				t.Errorf("status code = %d, want %d", statusErr.StatusCode, tt.statusCode)
+			case tt.statusCode == 0 && errors.As(err, &statusErr):
+				t.Errorf("error %v is a status error, want a transport error", err)
+			}
+		})
+	}
+}
+
+func TestNotifierForUnknownType(t *testing.T) {
+	s := NewNotificationService(nil, nil, nil, nil, logger.NewLogger())
+	if _, err := s.notifierFor(&models.NotificationChannel{Type: "pager"}); !errors.Is(err, ErrInvalidChannel) {
+		t.Errorf("err = %v, want ErrInvalidChannel", err)
+	}
+}
+
+func TestDeliveryFailure(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	statusErr := &notifier.StatusError{StatusCode: http.StatusBadGateway}
+
+	tests := []struct {
+		name         string
+		attempts     int
+		err          error
+		responseCode int
+		retryIn      time.Duration
+	}{
+		{"first attempt", 1, statusErr, http.StatusBadGateway, 30 * time.Second},
+		{"second attempt", 2, statusErr, http.StatusBadGateway, time.Minute},
+		{"transport error", 3, errors.New("connection refused"), 0, 2 * time.Minute},
+		{"wrapped status error", 4, errors.Join(errors.New("deliver"), statusErr), http.StatusBadGateway, 4 * time.Minute},
+		{"last attempt", maxDeliveryAttempts - 1, statusErr, http.StatusBadGateway, notifier.Backoff(maxDeliveryAttempts - 1)},
+		{"attempts exhausted", maxDeliveryAttempts, statusErr, http.StatusBadGateway, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, next := deliveryFailure(&models.NotificationDelivery{Attempts: tt.attempts}, tt.err, now)
+
+			switch {
+			case tt.responseCode == 0 && code != nil:
+				t.Errorf("response code = %d, want none", *code)
+			case tt.responseCode != 0 && (code == nil || *code != tt.responseCode):
+				t.Errorf("response code = %v, want %d", code, tt.responseCode)
+			}
+
+			switch {
+			case tt.retryIn == 0 && next != nil:
+				t.Errorf("next attempt at %v, want none", next)
+			case tt.retryIn != 0 && (next == nil || !next.Equal(now.Add(tt.retryIn))):
+				t.Errorf("next attempt at %v, want %v", next, now.Add(tt.retryIn))
+			}
+		})
+	}
+}
+
+func TestDefaultClientRefusesInternalAddresses(t *testing.T) {
+	srv, requests := receiver(t, http.StatusOK)
+	s := NewNotificationService(nil, nil, nil, nil, logger.NewLogger())
+	// The literal address skips validateChannel, as a name that resolves
+	// or rebinds to it would
+	ch := &models.NotificationChannel{Type: models.ChannelWebhook, URL: srv.URL}
+
+	n, err := s.notifierFor(ch)
+	if err != nil {
+		t.Fatalf("notifierFor: %v", err)
+	}
+	err = n.Notify(context.Background(), testMessage())
+	if !errors.Is(err, notifier.ErrForbiddenAddress) {
+		t.Fatalf("Notify() error = %v, want ErrForbiddenAddress", err)
+	}
+	if got := notifier.Describe(err); got != "receiver address is not allowed" || strings.Contains(got, "127.0.0.1") {
+		t.Errorf("Describe() = %q", got)
+	}
+	select {
+	case <-requests:
+		t.Error("the receiver got the notification")
+	default:
+	}
+}
+
+func TestValidateChannelURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://hooks.slack.com/services/T0/B0/x", true},
+		{"http://203.0.113.10:8080/hook", true},
+		{"ftp://example.com/hook", false},
+		{"https://", false},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://localhost/hook", false},
+		{"http://api.localhost./hook", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://10.0.0.5/hook", false},
+		{"http://192.168.1.1/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+	}
+	for _, tt := range tests {
+		ch := &models.NotificationChannel{Name: "hook", Type: models.ChannelWebhook, URL: tt.url}
+		err := validateChannel(ch)
+		if tt.ok && err != nil {
+			t.Errorf("validateChannel(%q) error = %v", tt.url, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidChannel) {
+			t.Errorf("validateChannel(%q) error = %v, want ErrInvalidChannel", tt.url, err)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrOrganizationNotFound     = errors.New("organization not found")
+	ErrOrganizationAccessDenied = errors.New("organization access denied")
+	ErrDuplicateOrganization    = errors.New("organization slug already exists")
+	ErrDuplicateTeam            = errors.New("team slug already exists")
+	ErrTeamNotFound             = errors.New("team not found")
+	ErrMemberNotFound           = errors.New("member not found")
+	ErrInvalidRole              = errors.New("invalid role")
+	ErrInsufficientRole         = errors.New("insufficient role")
+	ErrLastOwner                = errors.New("cannot remove the last owner")
+)
+
+type OrganizationService struct {
+	orgRepo        *postgres.OrganizationRepository
+	membershipRepo *postgres.MembershipRepository
+}
+
+func NewOrganizationService(orgRepo *postgres.OrganizationRepository, membershipRepo *postgres.MembershipRepository) *OrganizationService {
+	return &OrganizationService{orgRepo: orgRepo, membershipRepo: membershipRepo}
+}
+
+// Create creates a new organization owned by the given user.
+func (s *OrganizationService) Create(ctx context.Context, name, userID string) (*models.Organization, error) {
+	org := &models.Organization{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Slug:      slugify(name),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Role:      models.RoleOwner,
+	}
+
+	if err := s.orgRepo.Create(ctx, org); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateOrganization
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+// ListForUser returns the organizations the user belongs to.
+func (s *OrganizationService) ListForUser(ctx context.Context, userID string) ([]*models.Organization, error) {
+	return s.orgRepo.ListForUser(ctx, userID)
+}
+
+// Authorize returns the organization with the given slug and the user's role
+// in it. It returns ErrOrganizationNotFound when the organization does not
+// exist and ErrOrganizationAccessDenied when the user is not a member.
+func (s *OrganizationService) Authorize(ctx context.Context, userID, slug string) (*models.Organization, error) {
+	org, err := s.orgRepo.GetBySlugForUser(ctx, slug, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch organization: %w", err)
+	}
+	if org.Role == "" {
+		return nil, ErrOrganizationAccessDenied
+	}
+	return org, nil
+}
+
+// Delete removes the organization.
+func (s *OrganizationService) Delete(ctx context.Context, organizationID string) error {
+	if err := s.orgRepo.Delete(ctx, organizationID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListMembers returns the organization's members.
+func (s *OrganizationService) ListMembers(ctx context.Context, organizationID string) ([]models.Member, error) {
+	return s.membershipRepo.ListOrganizationMembers(ctx, organizationID)
+}
+
+// UpdateMemberRole changes a member's role. Only owners may grant or revoke
+// the owner role, and the last owner cannot be demoted.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, org *models.Organization, userID string, role models.Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	current, err := s.membershipRepo.OrganizationRole(ctx, org.ID, userID)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return ErrMemberNotFound
+	}
+	if err := s.checkOwnerChange(ctx, org, current, role); err != nil {
+		return err
+	}
+
+	return s.membershipRepo.UpdateOrganizationMemberRole(ctx, org.ID, userID, role)
+}
+
+// RemoveMember removes a member from the organization and its teams.
+func (s *OrganizationService) RemoveMember(ctx context.Context, org *models.Organization, userID string) error {
+	current, err := s.membershipRepo.OrganizationRole(ctx, org.ID, userID)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return ErrMemberNotFound
+	}
+	if err := s.checkOwnerChange(ctx, org, current, ""); err != nil {
+		return err
+	}
+
+	return s.membershipRepo.RemoveOrganizationMember(ctx, org.ID, userID)
+}
+
+// checkOwnerChange guards changes that touch the owner role. org.Role is the
+// role of the member making the change.
+func (s *OrganizationService) checkOwnerChange(ctx context.Context, org *models.Organization, current, next models.Role) error {
+	if current != models.RoleOwner && next != models.RoleOwner {
+		return nil
+	}
+	if org.Role != models.RoleOwner {
+		return ErrInsufficientRole
+	}
+	if current == models.RoleOwner && next != models.RoleOwner {
+		owners, err := s.membershipRepo.CountOrganizationOwners(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+		if owners <= 1 {
+			return ErrLastOwner
+		}
+	}
+	return nil
+}
+
+// CreateTeam creates a new team in the organization.
+func (s *OrganizationService) CreateTeam(ctx context.Context, organizationID, name string) (*models.Team, error) {
+	team := &models.Team{
+		ID:             uuid.NewString(),
+		OrganizationID: organizationID,
+		Name:           name,
+		Slug:           slugify(name),
+		CreatedAt:      time.Now(),
+		Members:        []models.Member{},
+	}
+
+	if err := s.orgRepo.CreateTeam(ctx, team); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateTeam
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+// ListTeams returns the organization's teams with their members.
+func (s *OrganizationService) ListTeams(ctx context.Context, organizationID string) ([]*models.Team, error) {
+	return s.orgRepo.ListTeams(ctx, organizationID)
+}
+
+// GetTeam returns the organization's team with the given slug.
+func (s *OrganizationService) GetTeam(ctx context.Context, organizationID, slug string) (*models.Team, error) {
+	team, err := s.orgRepo.GetTeamBySlug(ctx, organizationID, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+// DeleteTeam removes a team.
+func (s *OrganizationService) DeleteTeam(ctx context.Context, teamID string) error {
+	if err := s.orgRepo.DeleteTeam(ctx, teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AddTeamMember adds an organization member to a team.
+func (s *OrganizationService) AddTeamMember(ctx context.Context, team *models.Team, userID string) error {
+	role, err := s.membershipRepo.OrganizationRole(ctx, team.OrganizationID, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return ErrMemberNotFound
+	}
+	return s.orgRepo.AddTeamMember(ctx, team.ID, userID)
+}
+
+// RemoveTeamMember removes a user from a team.
+func (s *OrganizationService) RemoveTeamMember(ctx context.Context, team *models.Team, userID string) error {
+	if err := s.orgRepo.RemoveTeamMember(ctx, team.ID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func slugify(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
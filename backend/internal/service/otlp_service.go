@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/otlp"
+	"pulseguard/internal/repository/telemetry"
+	"pulseguard/pkg/logger"
+)
+
+var ErrTelemetryUnavailable = errors.New("telemetry backend unavailable")
+
+// OTLPService receives OTLP telemetry sent with a project key, scopes it to
+// the key's project and forwards it. Exceptions recorded on spans are
+// queued to be tracked as errors of the project, within its ingest limits.
+type OTLPService struct {
+	forwarder     *telemetry.OTLPRepository
+	ingestQueue   *IngestQueue
+	ingestLimiter *IngestLimiter
+	logger        *logger.Logger
+}
+
+func NewOTLPService(forwarder *telemetry.OTLPRepository, ingestQueue *IngestQueue, ingestLimiter *IngestLimiter, logger *logger.Logger) *OTLPService {
+	return &OTLPService{forwarder: forwarder, ingestQueue: ingestQueue, ingestLimiter: ingestLimiter, logger: logger}
+}
+
+// Export decodes an export request of the signal, stamps it with the
+// project_id resource attribute of the key's project and forwards it. For
+// traces it returns how many span exceptions had each ingest outcome.
+// mediaType is otlp.ContentTypeProtobuf or otlp.ContentTypeJSON.
+func (s *OTLPService) Export(ctx context.Context, key *models.ProjectKey, signal otlp.Signal, mediaType string, body []byte) (map[string]int, error) {
+	req := otlp.NewRequest(signal)
+	if err := otlp.Unmarshal(mediaType, body, req); err != nil {
+		return nil, err
+	}
+
+	otlp.Stamp(req, key.ProjectID)
+	if err := s.forwarder.Export(ctx, signal, req); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTelemetryUnavailable, err)
+	}
+
+	outcomes := make(map[string]int)
+	traces, ok := req.(*coltracepb.ExportTraceServiceRequest)
+	if !ok {
+		return outcomes, nil
+	}
+	// The spans are stored by now, so exceptions the limits or the queue turn
+	// away are counted rather than failing the export, which the exporter
+	// would retry
+	now := time.Now()
+	exceptions := otlp.Exceptions(traces)
+	for i, exc := range exceptions {
+		decision := s.ingestLimiter.Admit(ctx, key)
+		if !decision.Accepted() {
+			outcomes[decision.Outcome]++
+			continue
+		}
+		if err := s.ingestQueue.Enqueue(key, exc.ToError(key.ProjectID, now), nil, exc.Metadata()); err != nil {
+			s.logger.Error(ctx, "Failed to queue span exceptions", err, "dropped", len(exceptions)-i)
+			s.ingestLimiter.Refund(key, IngestQueueOutcome(err))
+			outcomes[IngestQueueOutcome(err)] += len(exceptions) - i
+			break
+		}
+		outcomes[models.IngestAccepted]++
+	}
+	return outcomes, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProjectKeyNotFound = errors.New("project key not found")
+	ErrInvalidProjectKey  = errors.New("invalid or revoked project key")
+	ErrInvalidRateLimit   = errors.New("rate limit cannot be negative")
+)
+
+// lastUsedInterval is how often a key's last_used_at is written at most,
+// however many events it sends
+const lastUsedInterval = time.Minute
+
+type ProjectKeyService struct {
+	keyRepo *postgres.ProjectKeyRepository
+
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func NewProjectKeyService(keyRepo *postgres.ProjectKeyRepository) *ProjectKeyService {
+	return &ProjectKeyService{keyRepo: keyRepo, lastUsed: make(map[string]time.Time)}
+}
+
+// Create issues a new ingest key for the project.
+func (s *ProjectKeyService) Create(ctx context.Context, projectID, name, createdBy string) (*models.ProjectKey, error) {
+	publicKey, err := generatePublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = "Default"
+	}
+
+	key := &models.ProjectKey{
+		ID:        uuid.NewString(),
+		ProjectID: projectID,
+		Name:      name,
+		PublicKey: publicKey,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListByProject returns all keys of the project, newest first.
+func (s *ProjectKeyService) ListByProject(ctx context.Context, projectID string) ([]*models.ProjectKey, error) {
+	return s.keyRepo.ListByProject(ctx, projectID)
+}
+
+// Rotate replaces the secret of an active key, invalidating the old DSN.
+func (s *ProjectKeyService) Rotate(ctx context.Context, projectID, keyID string) (*models.ProjectKey, error) {
+	publicKey, err := generatePublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keyRepo.Rotate(ctx, projectID, keyID, publicKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProjectKeyNotFound
+	}
+	return key, err
+}
+
+// Revoke permanently disables a key.
+func (s *ProjectKeyService) Revoke(ctx context.Context, projectID, keyID string) (*models.ProjectKey, error) {
+	key, err := s.keyRepo.Revoke(ctx, projectID, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProjectKeyNotFound
+	}
+	return key, err
+}
+
+// UpdateRateLimit sets the events per minute a key may send, 0 for no
+// limit beyond the project's own.
+func (s *ProjectKeyService) UpdateRateLimit(ctx context.Context, projectID, keyID string, rateLimit int) (*models.ProjectKey, error) {
+	if rateLimit < 0 {
+		return nil, ErrInvalidRateLimit
+	}
+
+	key, err := s.keyRepo.UpdateRateLimit(ctx, projectID, keyID, rateLimit)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProjectKeyNotFound
+	}
+	return key, err
+}
+
+// Authenticate resolves an ingest key presented by a client.
+func (s *ProjectKeyService) Authenticate(ctx context.Context, publicKey string) (*models.ProjectKey, error) {
+	if publicKey == "" {
+		return nil, ErrInvalidProjectKey
+	}
+
+	key, err := s.keyRepo.GetActiveByPublicKey(ctx, publicKey)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidProjectKey
+		}
+		return nil, fmt.Errorf("failed to look up project key: %w", err)
+	}
+
+	s.touchLastUsed(ctx, key.ID, time.Now())
+
+	return key, nil
+}
+
+// touchLastUsed writes when a key was used, at most once per
+// lastUsedInterval per key. last_used_at is informational only, a failed
+// write must not reject the event.
+func (s *ProjectKeyService) touchLastUsed(ctx context.Context, keyID string, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastUsed[keyID]) < lastUsedInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastUsed[keyID] = now
+	s.mu.Unlock()
+
+	_ = s.keyRepo.TouchLastUsed(ctx, keyID, now)
+}
+
+// BuildDSN returns the DSN clients use to report events, in the form
+// scheme://<public_key>@host/<project_id>.
+func BuildDSN(baseURL string, key *models.ProjectKey) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.User = url.User(key.PublicKey)
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + key.ProjectID
+	return u.String()
+}
+
+// BuildSentryDSN returns the DSN Sentry SDKs report events with. Sentry
+// requires a numeric project ID, so the DSN carries a placeholder and the
+// public key identifies the project.
+func BuildSentryDSN(baseURL string, key *models.ProjectKey) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	u.User = url.User(key.PublicKey)
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/sentry/0"
+	return u.String()
+}
+
+// ParseDSN extracts the public key and project ID from a DSN.
+func ParseDSN(dsn string) (publicKey, projectID string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid dsn: missing public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	projectID = path[strings.LastIndex(path, "/")+1:]
+	if projectID == "" {
+		return "", "", fmt.Errorf("invalid dsn: missing project id")
+	}
+	return u.User.Username(), projectID, nil
+}
+
+func generatePublicKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate project key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
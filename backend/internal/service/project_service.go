@@ -16,28 +16,49 @@ import (
 )
 
 type ProjectService struct {
-	projectRepo *postgres.ProjectRepository
+	projectRepo    *postgres.ProjectRepository
+	membershipRepo *postgres.MembershipRepository
+	orgRepo        *postgres.OrganizationRepository
 }
 
-var ErrDuplicateSlug = errors.New("duplicate project slug")
+var (
+	ErrDuplicateSlug       = errors.New("duplicate project slug")
+	ErrProjectNotFound     = errors.New("project not found")
+	ErrProjectAccessDenied = errors.New("project access denied")
+)
 
-func NewProjectService(projectRepo *postgres.ProjectRepository) *ProjectService {
-	return &ProjectService{projectRepo: projectRepo}
+func NewProjectService(projectRepo *postgres.ProjectRepository, membershipRepo *postgres.MembershipRepository, orgRepo *postgres.OrganizationRepository) *ProjectService {
+	return &ProjectService{projectRepo: projectRepo, membershipRepo: membershipRepo, orgRepo: orgRepo}
 }
 
 // Create creates a new project with the given name, description, and owner ID.
-func (s *ProjectService) Create(ctx context.Context, name, description, ownerID string) (*models.Project, error) {
+// When organizationID is set the owner must be an admin of that organization.
+func (s *ProjectService) Create(ctx context.Context, name, description, ownerID, organizationID string) (*models.Project, error) {
 	slug := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
 
-	
+	if organizationID != "" {
+		if _, err := uuid.Parse(organizationID); err != nil {
+			return nil, ErrOrganizationNotFound
+		}
+		role, err := s.membershipRepo.OrganizationRole(ctx, organizationID, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		if !role.AtLeast(models.RoleAdmin) {
+			return nil, ErrOrganizationAccessDenied
+		}
+	}
+
 	p := &models.Project{
-		ID:          uuid.NewString(),
-		Name:        name,
-		Slug:        slug,
-		Description: description,
-		OwnerID:     ownerID,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:             uuid.NewString(),
+		Name:           name,
+		Slug:           slug,
+		Description:    description,
+		OwnerID:        ownerID,
+		OrganizationID: organizationID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		Role:           models.RoleOwner,
 	}
 
 	err := s.projectRepo.Create(ctx, p)
@@ -57,23 +78,22 @@ func (s *ProjectService) Create(ctx context.Context, name, description, ownerID
 	return p, nil
 }
 
-// ListByOwner retrieves all projects owned by the specified owner ID.
-func (s *ProjectService) ListByOwner(ctx context.Context, ownerID string) ([]*models.Project, error) {
-	projects, err := s.projectRepo.ListByOwner(ctx, ownerID)
+// ListForUser retrieves all projects the user is a member of, directly,
+// through an organization or through a team.
+func (s *ProjectService) ListForUser(ctx context.Context, userID string) ([]*models.Project, error) {
+	projects, err := s.projectRepo.ListForUser(ctx, userID)
 	if err != nil {
-		// fmt.Printf("❌ Error retrieving projects for owner %s: %v\n", ownerID, err)
 		return nil, err
 	}
-
-	// if len(projects) == 0 {
-	// 	fmt.Printf("ℹ️ No projects found for owner %s\n", ownerID)
-	// } else {
-	// 	fmt.Printf("✅ Found %d projects for owner %s\n", len(projects), ownerID)
-	// }
-
 	return projects, nil
 }
 
+// ErrorCount returns the number of error groups of the project. The
+// project Authorize returns does not carry it.
+func (s *ProjectService) ErrorCount(ctx context.Context, projectID string) (int, error) {
+	return s.projectRepo.CountErrors(ctx, projectID)
+}
+
 // GetBySlug retrieves projects of specified slug.
 func (s *ProjectService) GetBySlug(ctx context.Context, slug string) (*models.Project, error) {
 	project, err := s.projectRepo.GetBySlug(ctx, slug)
@@ -91,9 +111,9 @@ func (s *ProjectService) GetBySlug(ctx context.Context, slug string) (*models.Pr
 	return project, nil
 }
 
-// DeleteBySlug deletes project by specified slug
-func (s *ProjectService) DeleteBySlug(ctx context.Context, slug string) (*models.Project, error) {
-	project, err := s.projectRepo.DeleteBySlug(ctx, slug)
+// Delete deletes the project with the given ID
+func (s *ProjectService) Delete(ctx context.Context, projectID string) (*models.Project, error) {
+	project, err := s.projectRepo.DeleteByID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,18 +121,14 @@ func (s *ProjectService) DeleteBySlug(ctx context.Context, slug string) (*models
 	return project, nil
 }
 
-// UpdateProject updates an existing project with the given slug, name, and description.
-func (s *ProjectService) UpdateProject(ctx context.Context, oldSlug, name, description, slug string) (*models.Project, error) {
-	// Fetch the existing project
-	project, err := s.projectRepo.GetBySlug(ctx, oldSlug)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch project: %w", err)
-	}
-	if project == nil {
-		return nil, fmt.Errorf("project with slug %s not found", oldSlug)
+// UpdateProject updates an existing project with the given name, description and slug.
+func (s *ProjectService) UpdateProject(ctx context.Context, project *models.Project, name, description, slug string) (*models.Project, error) {
+	if slug == "" {
+		slug = project.Slug
 	}
 
 	p := &models.Project{
+		ID:          project.ID,
 		Name:        name,
 		Slug:        slug,
 		Description: description,
@@ -122,12 +138,13 @@ func (s *ProjectService) UpdateProject(ctx context.Context, oldSlug, name, descr
 	}
 
 	// Save updated project
-	project, err = s.projectRepo.UpdateProject(ctx, oldSlug, p)
+	updated, err := s.projectRepo.UpdateProject(ctx, p)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update project: %w", err)
 	}
+	updated.Role = project.Role
 
-	return project, nil
+	return updated, nil
 }
 
 // Delete all projects owned by a specific user.
@@ -147,4 +164,145 @@ func (s *ProjectService) DeleteAllByOwner(ctx context.Context, ownerID string) e
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// Authorize checks that the user may access the project with the given ID
+// and sets the user's role on the returned project. It returns
+// ErrProjectNotFound when the project does not exist and
+// ErrProjectAccessDenied when the user holds no role on it.
+func (s *ProjectService) Authorize(ctx context.Context, userID, projectID string) (*models.Project, error) {
+	if _, err := uuid.Parse(projectID); err != nil {
+		return nil, ErrProjectNotFound
+	}
+
+	project, err := s.projectRepo.GetByIDForUser(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch project: %w", err)
+	}
+
+	return authorizeMember(project)
+}
+
+// AuthorizeSlug is Authorize for routes that address a project by slug.
+func (s *ProjectService) AuthorizeSlug(ctx context.Context, userID, slug string) (*models.Project, error) {
+	project, err := s.projectRepo.GetBySlugForUser(ctx, slug, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch project: %w", err)
+	}
+
+	return authorizeMember(project)
+}
+
+func authorizeMember(project *models.Project) (*models.Project, error) {
+	if project.Role == "" {
+		return nil, ErrProjectAccessDenied
+	}
+	return project, nil
+}
+
+// ListMembers returns the project's direct members and the teams granted access.
+func (s *ProjectService) ListMembers(ctx context.Context, projectID string) ([]models.Member, []models.ProjectTeam, error) {
+	members, err := s.membershipRepo.ListProjectMembers(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	teams, err := s.membershipRepo.ListProjectTeams(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return members, teams, nil
+}
+
+// UpdateMemberRole changes a direct member's role. project.Role is the role of
+// the member making the change; only owners may grant or revoke ownership and
+// the project's creator always stays an owner.
+func (s *ProjectService) UpdateMemberRole(ctx context.Context, project *models.Project, userID string, role models.Role) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+
+	current, err := s.membershipRepo.ProjectMemberRole(ctx, project.ID, userID)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return ErrMemberNotFound
+	}
+	if err := checkProjectOwnerChange(project, userID, current, role); err != nil {
+		return err
+	}
+
+	return s.membershipRepo.UpdateProjectMemberRole(ctx, project.ID, userID, role)
+}
+
+// RemoveMember removes a user's direct membership of the project.
+func (s *ProjectService) RemoveMember(ctx context.Context, project *models.Project, userID string) error {
+	current, err := s.membershipRepo.ProjectMemberRole(ctx, project.ID, userID)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return ErrMemberNotFound
+	}
+	if err := checkProjectOwnerChange(project, userID, current, ""); err != nil {
+		return err
+	}
+
+	return s.membershipRepo.RemoveProjectMember(ctx, project.ID, userID)
+}
+
+func checkProjectOwnerChange(project *models.Project, userID string, current, next models.Role) error {
+	if current != models.RoleOwner && next != models.RoleOwner {
+		return nil
+	}
+	if project.Role != models.RoleOwner {
+		return ErrInsufficientRole
+	}
+	if userID == project.OwnerID && next != models.RoleOwner {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// GrantTeam gives a team of the project's organization a role on the project.
+func (s *ProjectService) GrantTeam(ctx context.Context, project *models.Project, teamID string, role models.Role) error {
+	if !role.IsValid() || role == models.RoleOwner {
+		return ErrInvalidRole
+	}
+	if _, err := uuid.Parse(teamID); err != nil {
+		return ErrTeamNotFound
+	}
+
+	team, err := s.orgRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+	if project.OrganizationID == "" || team.OrganizationID != project.OrganizationID {
+		return ErrTeamNotFound
+	}
+
+	return s.membershipRepo.GrantTeam(ctx, project.ID, team.ID, role)
+}
+
+// RevokeTeam removes a team's access to the project.
+func (s *ProjectService) RevokeTeam(ctx context.Context, projectID, teamID string) error {
+	if _, err := uuid.Parse(teamID); err != nil {
+		return ErrTeamNotFound
+	}
+	if err := s.membershipRepo.RevokeTeam(ctx, projectID, teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+	return nil
+}
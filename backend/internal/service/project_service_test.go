@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pulseguard/internal/models"
+)
+
+func TestAuthorizeRejectsMalformedProjectIDs(t *testing.T) {
+	// No repository: a malformed ID must be turned away before any query
+	s := &ProjectService{}
+	for _, id := range []string{"", "shop", "1 OR 1=1", "00000000-0000-0000-0000"} {
+		if _, err := s.Authorize(context.Background(), "user-1", id); !errors.Is(err, ErrProjectNotFound) {
+			t.Errorf("Authorize(%q) error = %v, want ErrProjectNotFound", id, err)
+		}
+	}
+}
+
+func TestAuthorizeMember(t *testing.T) {
+	tests := []struct {
+		role    models.Role
+		wantErr error
+	}{
+		{models.RoleOwner, nil},
+		{models.RoleAdmin, nil},
+		{models.RoleMember, nil},
+		{models.RoleViewer, nil},
+		{"", ErrProjectAccessDenied},
+	}
+	for _, tt := range tests {
+		project, err := authorizeMember(&models.Project{ID: "project-1", Role: tt.role})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("authorizeMember(%q) error = %v, want %v", tt.role, err, tt.wantErr)
+		}
+		if err == nil && project.Role != tt.role {
+			t.Errorf("authorizeMember(%q) role = %q", tt.role, project.Role)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReleaseNotFound = errors.New("release not found")
+	ErrInvalidRelease  = errors.New("invalid release")
+	ErrInvalidDeploy   = errors.New("invalid deploy")
+)
+
+// releaseListLimit caps how many releases are listed
+const releaseListLimit = 100
+
+type ReleaseService struct {
+	repo *postgres.ReleaseRepository
+}
+
+func NewReleaseService(repo *postgres.ReleaseRepository) *ReleaseService {
+	return &ReleaseService{repo: repo}
+}
+
+// CreateRelease creates a release of the project, or updates the details of
+// an existing one with the same version. It reports whether it was created.
+func (s *ReleaseService) CreateRelease(ctx context.Context, release *models.Release) (*models.Release, bool, error) {
+	release.Version = strings.TrimSpace(release.Version)
+	if err := validateReleaseVersion(release.Version); err != nil {
+		return nil, false, err
+	}
+
+	release.ID = uuid.NewString()
+	release.CreatedAt = time.Now()
+	created, err := s.repo.Upsert(ctx, release)
+	if err != nil {
+		return nil, false, err
+	}
+
+	stored, err := s.GetRelease(ctx, release.ProjectID, release.Version)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, created, nil
+}
+
+// ListReleases returns the project's most recent releases, newest first
+func (s *ReleaseService) ListReleases(ctx context.Context, projectID string) ([]*models.Release, error) {
+	return s.repo.List(ctx, projectID, releaseListLimit)
+}
+
+// GetRelease returns a release of the project by version
+func (s *ReleaseService) GetRelease(ctx context.Context, projectID, version string) (*models.Release, error) {
+	release, err := s.repo.GetByVersion(ctx, projectID, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReleaseNotFound
+	}
+	return release, err
+}
+
+// CreateDeploy records that a release was deployed to an environment
+func (s *ReleaseService) CreateDeploy(ctx context.Context, projectID, version string, deploy *models.Deploy) (*models.Deploy, error) {
+	deploy.Environment = strings.TrimSpace(deploy.Environment)
+	if deploy.Environment == "" {
+		return nil, fmt.Errorf("%w: environment is required", ErrInvalidDeploy)
+	}
+
+	release, err := s.GetRelease(ctx, projectID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deploy.ID = uuid.NewString()
+	deploy.ProjectID = projectID
+	deploy.ReleaseID = release.ID
+	deploy.CreatedAt = now
+	if deploy.DateFinished.IsZero() {
+		deploy.DateFinished = now
+	}
+	if deploy.DateStarted != nil && deploy.DateStarted.After(deploy.DateFinished) {
+		return nil, fmt.Errorf("%w: date_started is after date_finished", ErrInvalidDeploy)
+	}
+
+	if err := s.repo.CreateDeploy(ctx, deploy); err != nil {
+		return nil, err
+	}
+	return deploy, nil
+}
+
+// ListDeploys returns the deploys of a release, most recent first
+func (s *ReleaseService) ListDeploys(ctx context.Context, projectID, version string) ([]*models.Deploy, error) {
+	release, err := s.GetRelease(ctx, projectID, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeploys(ctx, projectID, release.ID)
+}
+
+// validateReleaseVersion accepts the versions that can be used in URLs, such
+// as "1.4.0", "web@1.4.0" or a commit hash
+func validateReleaseVersion(version string) error {
+	switch {
+	case version == "" || len(version) > 250:
+		return fmt.Errorf("%w: version must be 1-250 characters", ErrInvalidRelease)
+	case version == "." || version == "..":
+		return fmt.Errorf("%w: version %q is reserved", ErrInvalidRelease, version)
+	case strings.ContainsAny(version, "/\\\n\r\t"):
+		return fmt.Errorf("%w: version must not contain slashes or whitespace other than spaces", ErrInvalidRelease)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+)
+
+var ErrInvalidSamplingConfig = errors.New("invalid sampling configuration")
+
+// samplingConfigTTL is how long a project's sampling configuration is cached
+// before it is read again
+const samplingConfigTTL = time.Minute
+
+// SamplingService manages the sampling configuration of projects and
+// applies it to ingested events. Server-side, the events a project sends
+// are sampled by its configuration as they are stored. Client-side, SDKs
+// sample with the same configuration and send the rate they sampled at;
+// such events are not sampled again, and count for as many events as the
+// rate stands for.
+type SamplingService struct {
+	repo   *postgres.SamplingRepository
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	configs map[string]cachedSamplingConfig
+}
+
+type cachedSamplingConfig struct {
+	config   *models.SamplingConfig
+	loadedAt time.Time
+}
+
+func NewSamplingService(repo *postgres.SamplingRepository, logger *logger.Logger) *SamplingService {
+	return &SamplingService{
+		repo:    repo,
+		logger:  logger,
+		configs: make(map[string]cachedSamplingConfig),
+	}
+}
+
+// Config returns the sampling configuration of a project
+func (s *SamplingService) Config(ctx context.Context, projectID string) (*models.SamplingConfig, error) {
+	cfg, err := s.repo.GetConfig(ctx, projectID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sampling configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// UpdateConfig replaces the sampling configuration of a project, which
+// applies to the events stored from then on
+func (s *SamplingService) UpdateConfig(ctx context.Context, cfg *models.SamplingConfig) error {
+	if err := validateSamplingConfig(cfg); err != nil {
+		return err
+	}
+	err := s.repo.UpdateConfig(ctx, cfg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrProjectNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.configs, cfg.ProjectID)
+	s.mu.Unlock()
+	return nil
+}
+
+// Sample sets how a server-side sampled event is stored, from the
+// configuration of its project. When the configuration cannot be read the
+// event is stored unsampled.
+func (s *SamplingService) Sample(ctx context.Context, event *postgres.TrackedEvent) {
+	cfg, err := s.cachedConfig(ctx, event.Error.ProjectID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to load sampling configuration", err)
+		return
+	}
+	event.SampleRate = cfg.RateFor(event.Error.Environment, event.Error.Type)
+	event.KeepFirst = cfg.KeepFirst
+}
+
+func (s *SamplingService) cachedConfig(ctx context.Context, projectID string) (*models.SamplingConfig, error) {
+	s.mu.Lock()
+	cached, ok := s.configs[projectID]
+	s.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < samplingConfigTTL {
+		return cached.config, nil
+	}
+
+	cfg, err := s.repo.GetConfig(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.configs[projectID] = cachedSamplingConfig{config: cfg, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return cfg, nil
+}
+
+// ClientSampleWeight is how many events an event sampled by its client at
+// rate stands for. The fraction of 1/rate is rounded up when draw falls
+// under it, so that counts are right on average.
+func ClientSampleWeight(rate float64, draw func() float64) int {
+	if rate <= 0 || rate >= 1 {
+		return 1
+	}
+	whole, frac := math.Modf(1 / rate)
+	if draw() < frac {
+		whole++
+	}
+	return int(whole)
+}
+
+func validateSamplingConfig(cfg *models.SamplingConfig) error {
+	if !validSampleRate(cfg.SampleRate) {
+		return fmt.Errorf("%w: sample_rate must be greater than 0 and at most 1", ErrInvalidSamplingConfig)
+	}
+	for environment, rate := range cfg.EnvironmentRates {
+		if !validSampleRate(rate) {
+			return fmt.Errorf("%w: rate of environment %q must be greater than 0 and at most 1", ErrInvalidSamplingConfig, environment)
+		}
+	}
+	for errorType, rate := range cfg.TypeRates {
+		if !validSampleRate(rate) {
+			return fmt.Errorf("%w: rate of type %q must be greater than 0 and at most 1", ErrInvalidSamplingConfig, errorType)
+		}
+	}
+	if cfg.KeepFirst < 0 {
+		return fmt.Errorf("%w: keep_first cannot be negative", ErrInvalidSamplingConfig)
+	}
+	return nil
+}
+
+// validSampleRate accepts rates in (0, 1]; a rate of 0 would leave counts
+// that cannot be extrapolated
+func validSampleRate(rate float64) bool {
+	return rate > 0 && rate <= 1
+}
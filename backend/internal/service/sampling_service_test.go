@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/pkg/logger"
+)
+
+func TestSample(t *testing.T) {
+	s := NewSamplingService(nil, logger.NewLogger())
+	s.configs["project-1"] = cachedSamplingConfig{loadedAt: time.Now(), config: &models.SamplingConfig{
+		ProjectID:        "project-1",
+		SampleRate:       0.5,
+		EnvironmentRates: map[string]float64{"staging": 0.1},
+		TypeRates:        map[string]float64{"TypeError": 0.2},
+		KeepFirst:        25,
+	}}
+
+	tests := []struct {
+		environment, errorType string
+		want                   float64
+	}{
+		{"production", "Error", 0.5},
+		{"staging", "Error", 0.1},
+		{"staging", "TypeError", 0.2},
+		{"production", "TypeError", 0.2},
+	}
+	for _, tt := range tests {
+		event := postgres.TrackedEvent{Error: &models.Error{ProjectID: "project-1", Environment: tt.environment, Type: tt.errorType}}
+		s.Sample(context.Background(), &event)
+		if event.SampleRate != tt.want || event.KeepFirst != 25 {
+			t.Errorf("%s %s sampled at %v keeping %d, want %v keeping 25", tt.environment, tt.errorType, event.SampleRate, event.KeepFirst, tt.want)
+		}
+	}
+}
+
+func TestClientSampleWeight(t *testing.T) {
+	tests := []struct {
+		rate float64
+		draw float64
+		want int
+	}{
+		{1, 0, 1},
+		{0, 0, 1},
+		{1.5, 0, 1},
+		{0.5, 0.99, 2},
+		{0.25, 0, 4},
+		// 1/0.3 is 3.33: a third of the draws round up
+		{0.3, 0.2, 4},
+		{0.3, 0.5, 3},
+	}
+	for _, tt := range tests {
+		draw := func() float64 { return tt.draw }
+		if got := ClientSampleWeight(tt.rate, draw); got != tt.want {
+			t.Errorf("ClientSampleWeight(%v) with draw %v = %d, want %d", tt.rate, tt.draw, got, tt.want)
+		}
+	}
+
+	// Weights add up to the events the client saw
+	draw := rand.New(rand.NewPCG(1, 2)).Float64
+	for _, rate := range []float64{0.3, 0.07, 0.9} {
+		const sent = 10000
+		total := 0
+		for i := 0; i < sent; i++ {
+			total += ClientSampleWeight(rate, draw)
+		}
+		if want := sent / rate; float64(total) < want*0.99 || float64(total) > want*1.01 {
+			t.Errorf("rate %v: %d events weigh %d, want about %.0f", rate, sent, total, want)
+		}
+	}
+}
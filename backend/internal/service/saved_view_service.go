@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+	"pulseguard/internal/search"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSavedViewNotFound  = errors.New("saved view not found")
+	ErrInvalidSavedView   = errors.New("invalid saved view")
+	ErrSavedViewForbidden = errors.New("not allowed to change this saved view")
+)
+
+// maxSavedViewName caps the length of a view's name, as the column does
+const maxSavedViewName = 255
+
+// SavedViewService manages the named error searches of a project. Anyone
+// with access to the project can keep private views; sharing one takes at
+// least the member role. A view is changed by its owner, or by an admin
+// once it is shared.
+type SavedViewService struct {
+	repo *postgres.SavedViewRepository
+}
+
+func NewSavedViewService(repo *postgres.SavedViewRepository) *SavedViewService {
+	return &SavedViewService{repo: repo}
+}
+
+// CreateView validates and stores a new view owned by view.OwnerID, whose
+// role on the project is role
+func (s *SavedViewService) CreateView(ctx context.Context, view *models.SavedView, role models.Role) (*models.SavedView, error) {
+	if err := normalizeSavedView(view); err != nil {
+		return nil, err
+	}
+	if view.Shared && !role.AtLeast(models.RoleMember) {
+		return nil, ErrSavedViewForbidden
+	}
+
+	now := time.Now()
+	view.ID = uuid.NewString()
+	view.CreatedAt = now
+	view.UpdatedAt = now
+
+	if err := s.repo.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// ListViews returns the user's own views and those shared with the project
+func (s *SavedViewService) ListViews(ctx context.Context, projectID, userID string) ([]*models.SavedView, error) {
+	return s.repo.ListVisible(ctx, projectID, userID)
+}
+
+// GetView returns a view of the project the user owns or that is shared
+func (s *SavedViewService) GetView(ctx context.Context, projectID, viewID, userID string) (*models.SavedView, error) {
+	if _, err := uuid.Parse(viewID); err != nil {
+		return nil, ErrSavedViewNotFound
+	}
+	view, err := s.repo.GetVisible(ctx, projectID, viewID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSavedViewNotFound
+		}
+		return nil, err
+	}
+	return view, nil
+}
+
+// UpdateView replaces the name, search and sharing of the view with ID
+// view.ID on behalf of userID, whose role on the project is role
+func (s *SavedViewService) UpdateView(ctx context.Context, view *models.SavedView, userID string, role models.Role) (*models.SavedView, error) {
+	stored, err := s.GetView(ctx, view.ProjectID, view.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canChangeView(stored, userID, role) || view.Shared && !role.AtLeast(models.RoleMember) {
+		return nil, ErrSavedViewForbidden
+	}
+	if err := normalizeSavedView(view); err != nil {
+		return nil, err
+	}
+	view.OwnerID = stored.OwnerID
+	view.OwnerName = stored.OwnerName
+	view.CreatedAt = stored.CreatedAt
+	view.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(ctx, view); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSavedViewNotFound
+		}
+		return nil, err
+	}
+	return view, nil
+}
+
+// DeleteView removes a view on behalf of userID, whose role on the project is role
+func (s *SavedViewService) DeleteView(ctx context.Context, projectID, viewID, userID string, role models.Role) error {
+	view, err := s.GetView(ctx, projectID, viewID, userID)
+	if err != nil {
+		return err
+	}
+	if !canChangeView(view, userID, role) {
+		return ErrSavedViewForbidden
+	}
+	if err := s.repo.Delete(ctx, projectID, viewID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSavedViewNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func canChangeView(view *models.SavedView, userID string, role models.Role) bool {
+	return view.OwnerID == userID || view.Shared && role.AtLeast(models.RoleAdmin)
+}
+
+// normalizeSavedView trims the view's fields and checks its name, query and sort
+func normalizeSavedView(view *models.SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+	view.Query = strings.TrimSpace(view.Query)
+	view.Environment = strings.TrimSpace(view.Environment)
+	view.Status = strings.ToUpper(strings.TrimSpace(view.Status))
+	view.Sort = strings.TrimSpace(view.Sort)
+
+	switch {
+	case view.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidSavedView)
+	case len(view.Name) > maxSavedViewName:
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidSavedView, maxSavedViewName)
+	case view.Sort != "" && !postgres.IsErrorSort(view.Sort):
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidSavedView, view.Sort)
+	}
+	if _, err := search.Parse(view.Query, time.Now()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSavedView, err)
+	}
+	return nil
+}
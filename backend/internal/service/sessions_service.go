@@ -20,12 +20,15 @@ func (s *SessionService) CreateSession(ctx context.Context, session *models.Sess
 	return s.repo.CreateSession(ctx, session)
 }
 
-func (s *SessionService) EndSession(ctx context.Context, sessionID string, endTime time.Time) error {
-	return s.repo.UpdateSessionEnd(ctx, sessionID, endTime)
+// EndSession closes a session. A non-empty projectID restricts the update to
+// sessions of that project.
+func (s *SessionService) EndSession(ctx context.Context, projectID, sessionID string, endTime time.Time) error {
+	return s.repo.UpdateSessionEnd(ctx, projectID, sessionID, endTime)
 }
 
-func (s *SessionService) IncrementErrorCount(ctx context.Context, sessionID string) error {
-	return s.repo.IncrementErrorCount(ctx, sessionID)
+// IncrementErrorCount counts an error against a session of the project
+func (s *SessionService) IncrementErrorCount(ctx context.Context, projectID, sessionID string) error {
+	return s.repo.IncrementErrorCount(ctx, projectID, sessionID)
 }
 
 func (s *SessionService) GetSessions(ctx context.Context, projectID string, start, end time.Time) ([]*models.Session, error) {
@@ -35,3 +38,9 @@ func (s *SessionService) GetSessions(ctx context.Context, projectID string, star
 func (s *SessionService) CountSessions(ctx context.Context, projectID string) (int64, error) {
 	return s.repo.CountSessions(ctx, projectID)
 }
+
+// CountSessionsWithErrorsSince returns the number of sessions started since the
+// given time and how many of them recorded errors
+func (s *SessionService) CountSessionsWithErrorsSince(ctx context.Context, projectID string, since time.Time) (int64, int64, error) {
+	return s.repo.CountSessionsWithErrorsSince(ctx, projectID, since)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"pulseguard/internal/models"
+	"pulseguard/internal/repository/postgres"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSilenceNotFound           = errors.New("silence not found")
+	ErrInvalidSilence            = errors.New("invalid silence")
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+	ErrInvalidMaintenanceWindow  = errors.New("invalid maintenance window")
+)
+
+// SilenceService manages silences and maintenance windows, and decides
+// whether an alert's notifications are suppressed by them
+type SilenceService struct {
+	repo *postgres.SilenceRepository
+}
+
+func NewSilenceService(repo *postgres.SilenceRepository) *SilenceService {
+	return &SilenceService{repo: repo}
+}
+
+// CreateSilence validates and stores a silence. It starts now unless a start
+// time is given.
+func (s *SilenceService) CreateSilence(ctx context.Context, silence *models.Silence) (*models.Silence, error) {
+	now := time.Now()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if !silence.EndsAt.After(silence.StartsAt) || !silence.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: ends_at must be in the future and after starts_at", ErrInvalidSilence)
+	}
+	if len(silence.Matchers) == 0 {
+		return nil, fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+	for i := range silence.Matchers {
+		m := &silence.Matchers[i]
+		m.Name = strings.TrimSpace(m.Name)
+		if m.Name == "" {
+			return nil, fmt.Errorf("%w: matcher names are required", ErrInvalidSilence)
+		}
+		if m.IsRegex {
+			if _, err := regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+				return nil, fmt.Errorf("%w: matcher %q has an invalid regular expression", ErrInvalidSilence, m.Name)
+			}
+		}
+	}
+
+	silence.ID = uuid.NewString()
+	silence.CreatedAt = now
+	if err := s.repo.CreateSilence(ctx, silence); err != nil {
+		return nil, err
+	}
+	return silence, nil
+}
+
+func (s *SilenceService) ListSilences(ctx context.Context, projectID string, includeExpired bool) ([]*models.Silence, error) {
+	return s.repo.ListSilences(ctx, projectID, includeExpired, time.Now())
+}
+
+// ExpireSilence ends one of the project's silences now
+func (s *SilenceService) ExpireSilence(ctx context.Context, projectID, silenceID string) error {
+	if _, err := uuid.Parse(silenceID); err != nil {
+		return ErrSilenceNotFound
+	}
+	if err := s.repo.ExpireSilence(ctx, projectID, silenceID, time.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSilenceNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateMaintenanceWindow validates and stores a maintenance window
+func (s *SilenceService) CreateMaintenanceWindow(ctx context.Context, window *models.MaintenanceWindow) (*models.MaintenanceWindow, error) {
+	window.Name = strings.TrimSpace(window.Name)
+	if window.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidMaintenanceWindow)
+	}
+	if window.StartsAt.IsZero() || !window.EndsAt.After(window.StartsAt) {
+		return nil, fmt.Errorf("%w: starts_at and a later ends_at are required", ErrInvalidMaintenanceWindow)
+	}
+
+	window.ID = uuid.NewString()
+	window.CreatedAt = time.Now()
+	if err := s.repo.CreateMaintenanceWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+func (s *SilenceService) ListMaintenanceWindows(ctx context.Context, projectID string, includePast bool) ([]*models.MaintenanceWindow, error) {
+	return s.repo.ListMaintenanceWindows(ctx, projectID, includePast, time.Now())
+}
+
+func (s *SilenceService) DeleteMaintenanceWindow(ctx context.Context, projectID, windowID string) error {
+	if _, err := uuid.Parse(windowID); err != nil {
+		return ErrMaintenanceWindowNotFound
+	}
+	if err := s.repo.DeleteMaintenanceWindow(ctx, projectID, windowID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMaintenanceWindowNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Suppression explains why notifications for the alert are suppressed at the
+// given time, or returns "" when they are not
+func (s *SilenceService) Suppression(ctx context.Context, alert *models.Alert, at time.Time) (string, error) {
+	windows, err := s.repo.ListActiveMaintenanceWindows(ctx, alert.ProjectID, at)
+	if err != nil {
+		return "", err
+	}
+	if len(windows) > 0 {
+		return fmt.Sprintf("maintenance window %q", windows[0].Name), nil
+	}
+
+	silences, err := s.repo.ListActiveSilences(ctx, alert.ProjectID, at)
+	if err != nil {
+		return "", err
+	}
+	labels := silenceLabels(alert)
+	for _, silence := range silences {
+		if silence.Matches(labels) {
+			return "silence " + silence.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// silenceLabels is the label set silences match against: the alert's own
+// labels plus its severity, source and rule_id unless the labels set them
+func silenceLabels(alert *models.Alert) map[string]string {
+	labels := make(map[string]string, len(alert.Labels)+3)
+	for k, v := range alert.Labels {
+		labels[k] = v
+	}
+	for k, v := range map[string]string{"severity": alert.Severity, "source": alert.Source, "rule_id": alert.RuleID} {
+		if _, ok := labels[k]; !ok {
+			labels[k] = v
+		}
+	}
+	return labels
+}
@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"pulseguard/internal/models"
 	"pulseguard/internal/repository/telemetry"
 	"time"
 )
 
+var ErrTraceNotFound = errors.New("trace not found")
+
 type TracesService struct {
 	TempoClient *telemetry.TempoClient
 }
@@ -24,3 +27,25 @@ func (s *TracesService) ListTracesByProject(ctx context.Context, projectID strin
 func (s *TracesService) GetTrace(ctx context.Context, traceID string) (*models.Trace, error) {
 	return s.TempoClient.GetTrace(ctx, traceID)
 }
+
+// GetProjectTrace fetches a trace with only the spans reported for the given
+// project, so one tenant cannot read another tenant's spans by trace ID, even
+// of a trace that crosses both their services.
+func (s *TracesService) GetProjectTrace(ctx context.Context, projectID, traceID string) (*models.Trace, error) {
+	trace, err := s.TempoClient.GetTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make([]*models.Span, 0, len(trace.Spans))
+	for _, span := range trace.Spans {
+		if span.Attributes["project_id"] == projectID || span.Resources["project_id"] == projectID {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) == 0 {
+		return nil, ErrTraceNotFound
+	}
+	trace.Spans = spans
+	return trace, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"pulseguard/internal/repository/telemetry"
+)
+
+// tempoServer answers trace lookups like the Tempo HTTP API with one trace
+// that crosses the services of two projects. shop labels its resource with
+// its project_id, blog its spans.
+func tempoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	attribute := func(key, value string) map[string]any {
+		return map[string]any{"key": key, "value": map[string]any{"stringValue": value}}
+	}
+	span := func(id, parent string, attributes ...map[string]any) map[string]any {
+		return map[string]any{
+			"spanId":            id,
+			"parentSpanId":      parent,
+			"name":              id,
+			"startTimeUnixNano": "1000000",
+			"endTimeUnixNano":   "3000000",
+			"attributes":        attributes,
+		}
+	}
+	trace := map[string]any{"batches": []any{
+		map[string]any{
+			"resource": map[string]any{"attributes": []any{attribute("service.name", "shop"), attribute("project_id", "shop")}},
+			"scopeSpans": []any{map[string]any{"spans": []any{
+				span("checkout", ""),
+				span("charge", "checkout"),
+			}}},
+		},
+		map[string]any{
+			"resource": map[string]any{"attributes": []any{attribute("service.name", "blog")}},
+			"scopeSpans": []any{map[string]any{"spans": []any{
+				span("render", "charge", attribute("project_id", "blog")),
+				span("unlabelled", "render"),
+			}}},
+		},
+	}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/traces/trace-1" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(trace)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetProjectTrace(t *testing.T) {
+	s := NewTracesService(telemetry.NewTempoRepository(tempoServer(t).URL))
+
+	tests := []struct {
+		projectID string
+		wantSpans []string
+	}{
+		{"shop", []string{"charge", "checkout"}},
+		{"blog", []string{"render"}},
+	}
+	for _, tt := range tests {
+		trace, err := s.GetProjectTrace(context.Background(), tt.projectID, "trace-1")
+		if err != nil {
+			t.Fatalf("GetProjectTrace(%s) error = %v", tt.projectID, err)
+		}
+		var spans []string
+		for _, span := range trace.Spans {
+			spans = append(spans, span.SpanID)
+		}
+		sort.Strings(spans)
+		if len(spans) != len(tt.wantSpans) {
+			t.Errorf("GetProjectTrace(%s) spans = %v, want %v", tt.projectID, spans, tt.wantSpans)
+			continue
+		}
+		for i := range spans {
+			if spans[i] != tt.wantSpans[i] {
+				t.Errorf("GetProjectTrace(%s) spans = %v, want %v", tt.projectID, spans, tt.wantSpans)
+				break
+			}
+		}
+	}
+
+	if _, err := s.GetProjectTrace(context.Background(), "other", "trace-1"); !errors.Is(err, ErrTraceNotFound) {
+		t.Errorf("GetProjectTrace() of a project without spans error = %v, want %v", err, ErrTraceNotFound)
+	}
+}
@@ -0,0 +1,253 @@
+// Package sourcemap decodes JavaScript source maps (revision 3) and maps
+// positions in minified code back to the original source.
+package sourcemap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxGeneratedLines bounds the generated lines a map may describe, so that
+// a section offset cannot make it allocate without bound
+const maxGeneratedLines = 1 << 22
+
+// Map is a decoded source map
+type Map struct {
+	File    string
+	Sources []string
+	Names   []string
+
+	contents []string
+	// lines[i] holds the segments of generated line i, sorted by column
+	lines [][]segment
+}
+
+// segment maps a generated column to a position in a source. source is -1
+// for segments that map to nothing.
+type segment struct {
+	column       int
+	source       int
+	sourceLine   int
+	sourceColumn int
+	name         int
+}
+
+// Position is an original source position, with 1-based line and column
+type Position struct {
+	Source string
+	Line   int
+	Column int
+	// Name is the original identifier at the position, if the map has one
+	Name string
+}
+
+type rawMap struct {
+	Version        int       `json:"version"`
+	File           string    `json:"file"`
+	SourceRoot     string    `json:"sourceRoot"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+	Names          []string  `json:"names"`
+	Mappings       string    `json:"mappings"`
+	Sections       []struct {
+		Offset struct {
+			Line   int `json:"line"`
+			Column int `json:"column"`
+		} `json:"offset"`
+		Map *rawMap `json:"map"`
+	} `json:"sections"`
+}
+
+// Parse decodes a source map, including index maps made of sections
+func Parse(data []byte) (*Map, error) {
+	// Maps served to browsers may start with an XSSI guard line
+	if s := string(data); strings.HasPrefix(s, ")]}") {
+		if i := strings.IndexByte(s, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+
+	var raw rawMap
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid source map: %w", err)
+	}
+	if raw.Version != 3 {
+		return nil, fmt.Errorf("unsupported source map version %d", raw.Version)
+	}
+
+	m := &Map{File: raw.File}
+	if len(raw.Sections) == 0 {
+		if err := m.add(&raw, 0, 0); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	for _, section := range raw.Sections {
+		if section.Map == nil {
+			return nil, errors.New("invalid source map: section without map")
+		}
+		if section.Offset.Line < 0 || section.Offset.Column < 0 {
+			return nil, errors.New("invalid source map: negative section offset")
+		}
+		if err := m.add(section.Map, section.Offset.Line, section.Offset.Column); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// add decodes raw into the map, shifting its generated positions by the
+// section offset
+func (m *Map) add(raw *rawMap, lineOffset, columnOffset int) error {
+	sourceBase, nameBase := len(m.Sources), len(m.Names)
+	for i, source := range raw.Sources {
+		if raw.SourceRoot != "" && !strings.Contains(source, "://") && !strings.HasPrefix(source, "/") {
+			source = strings.TrimSuffix(raw.SourceRoot, "/") + "/" + source
+		}
+		m.Sources = append(m.Sources, source)
+		content := ""
+		if i < len(raw.SourcesContent) && raw.SourcesContent[i] != nil {
+			content = *raw.SourcesContent[i]
+		}
+		m.contents = append(m.contents, content)
+	}
+	m.Names = append(m.Names, raw.Names...)
+
+	var source, sourceLine, sourceColumn, name int
+	for i, line := range strings.Split(raw.Mappings, ";") {
+		genLine := lineOffset + i
+		if genLine >= maxGeneratedLines {
+			return fmt.Errorf("invalid source map: more than %d generated lines", maxGeneratedLines)
+		}
+		column := 0
+		if i == 0 {
+			column = columnOffset
+		}
+
+		var segments []segment
+		for _, field := range strings.Split(line, ",") {
+			if field == "" {
+				continue
+			}
+			values, err := decodeVLQ(field)
+			if err != nil {
+				return err
+			}
+
+			column += values[0]
+			seg := segment{column: column, source: -1, name: -1}
+			switch len(values) {
+			case 1:
+			case 4, 5:
+				source += values[1]
+				sourceLine += values[2]
+				sourceColumn += values[3]
+				seg.source = sourceBase + source
+				seg.sourceLine = sourceLine
+				seg.sourceColumn = sourceColumn
+				if len(values) == 5 {
+					name += values[4]
+					seg.name = nameBase + name
+				}
+			default:
+				return fmt.Errorf("invalid source map: segment %q has %d fields", field, len(values))
+			}
+			segments = append(segments, seg)
+		}
+
+		sort.SliceStable(segments, func(a, b int) bool { return segments[a].column < segments[b].column })
+		for len(m.lines) <= genLine {
+			m.lines = append(m.lines, nil)
+		}
+		m.lines[genLine] = append(m.lines[genLine], segments...)
+	}
+	return nil
+}
+
+// Lookup returns the original position of a 1-based line and column in the
+// generated code, as reported in JavaScript stack traces
+func (m *Map) Lookup(line, column int) (Position, bool) {
+	if line < 1 || line > len(m.lines) {
+		return Position{}, false
+	}
+	segments := m.lines[line-1]
+	col := column - 1
+	if col < 0 {
+		col = 0
+	}
+
+	// The last segment starting at or before the column covers it
+	i := sort.Search(len(segments), func(i int) bool { return segments[i].column > col }) - 1
+	if i < 0 {
+		return Position{}, false
+	}
+	seg := segments[i]
+	if seg.source < 0 || seg.source >= len(m.Sources) {
+		return Position{}, false
+	}
+
+	pos := Position{
+		Source: m.Sources[seg.source],
+		Line:   seg.sourceLine + 1,
+		Column: seg.sourceColumn + 1,
+	}
+	if seg.name >= 0 && seg.name < len(m.Names) {
+		pos.Name = m.Names[seg.name]
+	}
+	return pos, true
+}
+
+// Context returns up to n lines of the original source before and after a
+// 1-based line, and the line itself. ok is false when the map does not
+// embed the source.
+func (m *Map) Context(source string, line, n int) (pre []string, current string, post []string, ok bool) {
+	for i, s := range m.Sources {
+		if s != source || m.contents[i] == "" {
+			continue
+		}
+		lines := strings.Split(strings.ReplaceAll(m.contents[i], "\r\n", "\n"), "\n")
+		if line < 1 || line > len(lines) {
+			return nil, "", nil, false
+		}
+		start := max(line-1-n, 0)
+		end := min(line+n, len(lines))
+		return lines[start : line-1], lines[line-1], lines[line:end], true
+	}
+	return nil, "", nil, false
+}
+
+const base64Chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// decodeVLQ decodes the base64 VLQ values of one mapping segment
+func decodeVLQ(field string) ([]int, error) {
+	var values []int
+	value, shift := 0, 0
+	for i := 0; i < len(field); i++ {
+		digit := strings.IndexByte(base64Chars, field[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid source map: bad mapping character %q", field[i])
+		}
+		value += (digit & 31) << shift
+		if digit&32 != 0 {
+			shift += 5
+			// Values are 32-bit signed integers
+			if shift > 30 {
+				return nil, errors.New("invalid source map: mapping value too large")
+			}
+			continue
+		}
+		if value&1 != 0 {
+			values = append(values, -(value >> 1))
+		} else {
+			values = append(values, value>>1)
+		}
+		value, shift = 0, 0
+	}
+	if shift != 0 {
+		return nil, errors.New("invalid source map: truncated mapping")
+	}
+	return values, nil
+}
@@ -0,0 +1,213 @@
+package sourcemap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testMap maps generated line 1 columns 1 and 2 to src/app.ts 1:1 and 1:2,
+// the latter named render, and generated line 2 column 1 to 2:1
+const testMap = `{
+	"version": 3,
+	"file": "app.min.js",
+	"sourceRoot": "webpack:///",
+	"sources": ["src/app.ts"],
+	"sourcesContent": ["const a = 1;\nrender(a);\nexport {};"],
+	"names": ["render"],
+	"mappings": "AAAA,CAACA;AACD"
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "map", data: testMap},
+		{name: "xssi guard", data: ")]}'\n" + testMap},
+		{name: "empty mappings", data: `{"version":3,"sources":[],"mappings":""}`},
+		{name: "empty lines and segments", data: `{"version":3,"sources":["a.js"],"mappings":";;,AAAA,;"}`},
+		{
+			name: "index map",
+			data: `{"version":3,"sections":[
+				{"offset":{"line":0,"column":0},"map":{"version":3,"sources":["a.js"],"mappings":"AAAA"}},
+				{"offset":{"line":10,"column":5},"map":{"version":3,"sources":["b.js"],"mappings":"AAAA"}}]}`,
+		},
+
+		// Malformed maps are rejected with an error, never a panic
+		{name: "not json", data: "var x = 1;", wantErr: "invalid source map"},
+		{name: "truncated json", data: testMap[:40], wantErr: "invalid source map"},
+		{name: "empty", data: "", wantErr: "invalid source map"},
+		{name: "xssi guard only", data: ")]}'", wantErr: "invalid source map"},
+		{name: "version 2", data: `{"version":2,"mappings":""}`, wantErr: "unsupported source map version 2"},
+		{name: "no version", data: `{"mappings":""}`, wantErr: "unsupported source map version 0"},
+		{name: "bad mapping character", data: `{"version":3,"sources":["a.js"],"mappings":"AA!A"}`, wantErr: "bad mapping character"},
+		{name: "truncated value", data: `{"version":3,"sources":["a.js"],"mappings":"AAAg"}`, wantErr: "truncated mapping"},
+		{name: "value too large", data: `{"version":3,"sources":["a.js"],"mappings":"ggggggggA"}`, wantErr: "mapping value too large"},
+		{name: "two fields", data: `{"version":3,"sources":["a.js"],"mappings":"AA"}`, wantErr: "has 2 fields"},
+		{name: "six fields", data: `{"version":3,"sources":["a.js"],"mappings":"AAAAAA"}`, wantErr: "has 6 fields"},
+		{name: "section without map", data: `{"version":3,"sections":[{"offset":{"line":0,"column":0}}]}`, wantErr: "section without map"},
+		{
+			name:    "negative section offset",
+			data:    `{"version":3,"sections":[{"offset":{"line":-1,"column":0},"map":{"version":3,"sources":["a.js"],"mappings":"AAAA"}}]}`,
+			wantErr: "negative section offset",
+		},
+		{
+			name:    "huge section offset",
+			data:    `{"version":3,"sections":[{"offset":{"line":1000000000,"column":0},"map":{"version":3,"sources":["a.js"],"mappings":"AAAA"}}]}`,
+			wantErr: "generated lines",
+		},
+		{name: "sources of the wrong type", data: `{"version":3,"sources":"a.js","mappings":""}`, wantErr: "invalid source map"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if m == nil {
+					t.Fatal("Parse() returned no map")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	m, err := Parse([]byte(testMap))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		line, column int
+		want         Position
+		ok           bool
+	}{
+		{"first segment", 1, 1, Position{Source: "webpack:///src/app.ts", Line: 1, Column: 1}, true},
+		{"named segment", 1, 2, Position{Source: "webpack:///src/app.ts", Line: 1, Column: 2, Name: "render"}, true},
+		{"column past the last segment", 1, 500, Position{Source: "webpack:///src/app.ts", Line: 1, Column: 2, Name: "render"}, true},
+		{"column 0 is the first column", 2, 0, Position{Source: "webpack:///src/app.ts", Line: 2, Column: 1}, true},
+		{"line 0", 0, 1, Position{}, false},
+		{"negative line", -1, 1, Position{}, false},
+		{"line past the end", 3, 1, Position{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Lookup(tt.line, tt.column)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Lookup(%d, %d) = %+v, %v, want %+v, %v", tt.line, tt.column, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLookupMalformedSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings string
+	}{
+		// A source index before the first source
+		{"negative source", "ADAA"},
+		// A source index past the last source
+		{"source out of range", "ACAA"},
+		// A segment without a source
+		{"unmapped segment", "A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse([]byte(`{"version":3,"sources":["a.js"],"names":[],"mappings":"` + tt.mappings + `"}`))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if pos, ok := m.Lookup(1, 1); ok {
+				t.Errorf("Lookup() = %+v, want no position", pos)
+			}
+		})
+	}
+
+	// A name index out of range leaves the name out
+	m, err := Parse([]byte(`{"version":3,"sources":["a.js"],"names":[],"mappings":"AAAAC"}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pos, ok := m.Lookup(1, 1); !ok || pos.Name != "" {
+		t.Errorf("Lookup() = %+v, %v, want a position without name", pos, ok)
+	}
+}
+
+func TestLookupIndexMap(t *testing.T) {
+	m, err := Parse([]byte(`{"version":3,"sections":[
+		{"offset":{"line":0,"column":0},"map":{"version":3,"sources":["a.js"],"mappings":"AAAA"}},
+		{"offset":{"line":10,"column":5},"map":{"version":3,"sources":["b.js"],"mappings":"AAAA"}}]}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if pos, ok := m.Lookup(1, 1); !ok || pos.Source != "a.js" {
+		t.Errorf("Lookup(1, 1) = %+v, %v, want a.js", pos, ok)
+	}
+	if pos, ok := m.Lookup(11, 6); !ok || pos.Source != "b.js" {
+		t.Errorf("Lookup(11, 6) = %+v, %v, want b.js", pos, ok)
+	}
+	if pos, ok := m.Lookup(11, 1); ok {
+		t.Errorf("Lookup(11, 1) = %+v, want no position before the section's column", pos)
+	}
+}
+
+func TestContext(t *testing.T) {
+	m, err := Parse([]byte(testMap))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	pre, current, post, ok := m.Context("webpack:///src/app.ts", 2, 5)
+	if !ok || current != "render(a);" ||
+		!reflect.DeepEqual(pre, []string{"const a = 1;"}) || !reflect.DeepEqual(post, []string{"export {};"}) {
+		t.Errorf("Context() = %q, %q, %q, %v", pre, current, post, ok)
+	}
+
+	for _, tt := range []struct {
+		source string
+		line   int
+	}{
+		{"webpack:///src/app.ts", 0},
+		{"webpack:///src/app.ts", 4},
+		{"src/other.ts", 1},
+	} {
+		if _, _, _, ok := m.Context(tt.source, tt.line, 5); ok {
+			t.Errorf("Context(%q, %d) found a context", tt.source, tt.line)
+		}
+	}
+}
+
+func TestDecodeVLQ(t *testing.T) {
+	tests := []struct {
+		field   string
+		want    []int
+		wantErr bool
+	}{
+		{"A", []int{0}, false},
+		{"C", []int{1}, false},
+		{"D", []int{-1}, false},
+		{"gB", []int{16}, false},
+		{"AACD", []int{0, 0, 1, -1}, false},
+		{"+/", nil, true},
+		{"g", nil, true},
+		{"=", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeVLQ(tt.field)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("decodeVLQ(%q) = %v, %v, want %v, error %v", tt.field, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
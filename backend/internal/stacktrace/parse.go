@@ -0,0 +1,124 @@
+// Package stacktrace parses stack traces into structured frames.
+package stacktrace
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"pulseguard/internal/models"
+)
+
+var (
+	// at fn (file:1:2) / at file:1:2 (V8)
+	v8Frame = regexp.MustCompile(`^\s*at (?:(?:async )?(.+?) \()?(.+?)(?::(\d+))?(?::(\d+))?\)?$`)
+	// fn@file:1:2 or fn@[native code] (Firefox, Safari); without a position
+	// the line is a message that happens to contain an @
+	geckoFrame = regexp.MustCompile(`^\s*(.*?)@(?:(.+?):(\d+)(?::(\d+))?|(\[native code\]))$`)
+	// File "file", line 1, in fn (Python)
+	pythonFrame = regexp.MustCompile(`^\s*File "(.+?)", line (\d+), in (.+)$`)
+	// at pkg.Class.method(File.java:1) (JVM)
+	jvmFrame = regexp.MustCompile(`^\s*at ([\w$.<>/]+)\((.*?)(?::(\d+))?\)$`)
+	// pkg.fn(...) followed by a tab-indented "file.go:1 +0x1" line (Go)
+	goFunc = regexp.MustCompile(`^([\w./*()-]+?)(?:\(.*\))?$`)
+	goFile = regexp.MustCompile(`^\s+(.+\.go):(\d+)`)
+)
+
+// Paths and modules that belong to dependencies or the runtime, not the application
+var notInApp = []string{
+	"node_modules/", "/vendor/", "site-packages/", "dist-packages/", "/lib/python",
+	"/usr/lib/", "/usr/local/go/", "/go/pkg/mod/", "runtime/", "<anonymous>", "native code",
+	"webpack/bootstrap", "java.", "javax.", "jdk.", "sun.", "kotlin.", "scala.",
+}
+
+// Parse turns a stack trace in any of the common JavaScript (V8, Firefox,
+// Safari), Go panic, Python traceback or JVM formats into frames, innermost
+// frame first. Lines that are not frames are ignored; nil is returned when
+// there are none.
+func Parse(stack string) []models.StackFrame {
+	lines := strings.Split(strings.ReplaceAll(stack, "\r\n", "\n"), "\n")
+	var frames []models.StackFrame
+	python := false
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		var f models.StackFrame
+		switch {
+		case pythonFrame.MatchString(line):
+			m := pythonFrame.FindStringSubmatch(line)
+			f = models.StackFrame{Function: m[3], File: m[1], Line: atoi(m[2])}
+			python = true
+		case jvmFrame.MatchString(line):
+			m := jvmFrame.FindStringSubmatch(line)
+			f = models.StackFrame{Function: m[1], File: m[2], Line: atoi(m[3])}
+		case v8Frame.MatchString(line):
+			m := v8Frame.FindStringSubmatch(line)
+			f = models.StackFrame{Function: m[1], File: m[2], Line: atoi(m[3]), Column: atoi(m[4])}
+		case i+1 < len(lines) && goFile.MatchString(lines[i+1]) && goFunc.MatchString(line):
+			m := goFile.FindStringSubmatch(lines[i+1])
+			f = models.StackFrame{Function: goFunc.FindStringSubmatch(line)[1], File: m[1], Line: atoi(m[2])}
+			i++
+		case strings.Contains(line, "@") && geckoFrame.MatchString(line):
+			m := geckoFrame.FindStringSubmatch(line)
+			f = models.StackFrame{Function: m[1], File: m[2] + m[5], Line: atoi(m[3]), Column: atoi(m[4])}
+		default:
+			continue
+		}
+
+		f.InApp = InApp(f)
+		frames = append(frames, f)
+	}
+
+	// Python tracebacks list the innermost call last
+	if python {
+		for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+			frames[i], frames[j] = frames[j], frames[i]
+		}
+	}
+	return frames
+}
+
+// Path drops the origin, query string and hash of a frame's file, keeping
+// the path. Files that are not URLs are only cleaned.
+func Path(file string) string {
+	if i := strings.Index(file, "://"); i >= 0 {
+		file = file[i+3:]
+		j := strings.Index(file, "/")
+		if j < 0 {
+			// Only an origin
+			return ""
+		}
+		file = file[j:]
+	}
+	if i := strings.IndexAny(file, "?#"); i >= 0 {
+		file = file[:i]
+	}
+	return path.Clean("/" + strings.TrimPrefix(file, "/"))[1:]
+}
+
+// InApp reports whether a frame belongs to the application rather than to a
+// dependency or the runtime
+func InApp(f models.StackFrame) bool {
+	file := Path(f.File)
+	s := f.Function + " /" + file
+	for _, marker := range notInApp {
+		if strings.Contains(s, marker) {
+			return false
+		}
+	}
+	return file != ""
+}
+
+// atoi reads a line or column number; numbers that do not fit are unknown
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,187 @@
+package stacktrace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"pulseguard/internal/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		stack string
+		want  []models.StackFrame
+	}{
+		{
+			name: "v8",
+			stack: "TypeError: x is undefined\n" +
+				"    at render (https://app.example.com/static/app.js:10:5)\n" +
+				"    at async load (https://app.example.com/static/app.js?v=2:20:7)\n" +
+				"    at https://app.example.com/node_modules/react/index.js:1:100",
+			want: []models.StackFrame{
+				{Function: "render", File: "https://app.example.com/static/app.js", Line: 10, Column: 5, InApp: true},
+				{Function: "load", File: "https://app.example.com/static/app.js?v=2", Line: 20, Column: 7, InApp: true},
+				{File: "https://app.example.com/node_modules/react/index.js", Line: 1, Column: 100},
+			},
+		},
+		{
+			name: "gecko",
+			stack: "render@https://app.example.com/app.js:10:5\n" +
+				"@https://app.example.com/app.js:3:1",
+			want: []models.StackFrame{
+				{Function: "render", File: "https://app.example.com/app.js", Line: 10, Column: 5, InApp: true},
+				{File: "https://app.example.com/app.js", Line: 3, Column: 1, InApp: true},
+			},
+		},
+		{
+			name: "python, innermost last",
+			stack: "Traceback (most recent call last):\n" +
+				"  File \"/srv/app/main.py\", line 12, in handler\n" +
+				"    process()\n" +
+				"  File \"/usr/lib/python3.12/json/decoder.py\", line 337, in decode\n" +
+				"ValueError: bad json",
+			want: []models.StackFrame{
+				{Function: "decode", File: "/usr/lib/python3.12/json/decoder.py", Line: 337},
+				{Function: "handler", File: "/srv/app/main.py", Line: 12, InApp: true},
+			},
+		},
+		{
+			name: "jvm",
+			stack: "java.lang.IllegalStateException: boom\n" +
+				"\tat com.example.Service.run(Service.java:42)\n" +
+				"\tat java.base/java.lang.Thread.run(Thread.java:833)\n" +
+				"\tat com.example.Native.call(Native Method)",
+			want: []models.StackFrame{
+				{Function: "com.example.Service.run", File: "Service.java", Line: 42, InApp: true},
+				{Function: "java.base/java.lang.Thread.run", File: "Thread.java", Line: 833},
+				{Function: "com.example.Native.call", File: "Native Method", InApp: true},
+			},
+		},
+		{
+			name: "go panic",
+			stack: "panic: runtime error\n\n" +
+				"goroutine 1 [running]:\n" +
+				"main.handler(0x1)\n" +
+				"\t/srv/app/main.go:17 +0x1d\n" +
+				"runtime.goexit()\n" +
+				"\t/usr/local/go/src/runtime/asm_amd64.s:1695 +0x1",
+			want: []models.StackFrame{
+				{Function: "main.handler", File: "/srv/app/main.go", Line: 17, InApp: true},
+			},
+		},
+		{
+			name:  "windows line endings",
+			stack: "Error\r\n    at run (app.js:1:2)\r\n",
+			want: []models.StackFrame{
+				{Function: "run", File: "app.js", Line: 1, Column: 2, InApp: true},
+			},
+		},
+
+		// Malformed input yields the frames that can be read, or none
+		{name: "empty", stack: "", want: nil},
+		{name: "blank lines", stack: "\n \n\t\n", want: nil},
+		{name: "message only", stack: "Error: something failed", want: nil},
+		{
+			name:  "v8 frame without position",
+			stack: "    at render (app.js)",
+			want:  []models.StackFrame{{Function: "render", File: "app.js", InApp: true}},
+		},
+		{
+			name:  "v8 frame with non-numeric position",
+			stack: "    at render (app.js:x:y)",
+			want:  []models.StackFrame{{Function: "render", File: "app.js:x:y", InApp: true}},
+		},
+		{
+			name:  "line number overflowing int",
+			stack: "    at render (app.js:99999999999999999999:1)",
+			want:  []models.StackFrame{{Function: "render", File: "app.js", Column: 1, InApp: true}},
+		},
+		{
+			name:  "go function without file line",
+			stack: "main.handler(0x1)\nmain.other()",
+			want:  nil,
+		},
+		{
+			name:  "go file line without function",
+			stack: "\t/srv/app/main.go:17 +0x1d",
+			want:  nil,
+		},
+		{
+			name:  "truncated python frame",
+			stack: "  File \"/srv/app/main.py\", line",
+			want:  nil,
+		},
+		{name: "at sign in a message is not a frame", stack: "Error: invalid email user@example.com", want: nil},
+		{
+			name:  "gecko native code",
+			stack: "forEach@[native code]\nrun@app.js:2:3",
+			want: []models.StackFrame{
+				{Function: "forEach", File: "[native code]"},
+				{Function: "run", File: "app.js", Line: 2, Column: 3, InApp: true},
+			},
+		},
+		{
+			name:  "gecko eval code",
+			stack: "@debugger eval code:1:1",
+			want:  []models.StackFrame{{File: "debugger eval code", Line: 1, Column: 1, InApp: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.stack)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLongInput(t *testing.T) {
+	stack := "Error\n" + strings.Repeat("    at f (app.js:1:1)\n", 10000) + strings.Repeat("x", 1<<16)
+	if got := len(Parse(stack)); got != 10000 {
+		t.Errorf("Parse() returned %d frames, want 10000", got)
+	}
+}
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"https://app.example.com/static/app.js?v=2#x", "static/app.js"},
+		{"webpack:///./src/App.tsx", "src/App.tsx"},
+		{"/srv/app/../app/main.py", "srv/app/main.py"},
+		{"app.js", "app.js"},
+		{"https://app.example.com", ""},
+		{"", ""},
+		{"?#", ""},
+		{"../../etc/passwd", "etc/passwd"},
+	}
+	for _, tt := range tests {
+		if got := Path(tt.file); got != tt.want {
+			t.Errorf("Path(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestInApp(t *testing.T) {
+	tests := []struct {
+		frame models.StackFrame
+		want  bool
+	}{
+		{models.StackFrame{Function: "render", File: "https://app.example.com/app.js"}, true},
+		{models.StackFrame{File: "https://app.example.com/node_modules/react/index.js"}, false},
+		{models.StackFrame{Function: "java.util.ArrayList.get", File: "ArrayList.java"}, false},
+		{models.StackFrame{Function: "runtime.gopanic", File: "/usr/local/go/src/runtime/panic.go"}, false},
+		{models.StackFrame{Function: "eval", File: "<anonymous>"}, false},
+		{models.StackFrame{Function: "render"}, false},
+	}
+	for _, tt := range tests {
+		if got := InApp(tt.frame); got != tt.want {
+			t.Errorf("InApp(%+v) = %v, want %v", tt.frame, got, tt.want)
+		}
+	}
+}
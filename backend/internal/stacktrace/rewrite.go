@@ -0,0 +1,50 @@
+package stacktrace
+
+import (
+	"fmt"
+	"strings"
+
+	"pulseguard/internal/models"
+)
+
+// Rewrite replaces the JavaScript frames (V8, Firefox and Safari formats) of
+// a stack trace or React component stack with the frames mapFrame returns,
+// keeping every other line and each frame's format as it was
+func Rewrite(stack string, mapFrame func(models.StackFrame) (models.StackFrame, bool)) string {
+	lines := strings.Split(stack, "\n")
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		switch {
+		case v8Frame.MatchString(line):
+			m := v8Frame.FindStringSubmatch(line)
+			f, ok := mapFrame(models.StackFrame{Function: m[1], File: m[2], Line: atoi(m[3]), Column: atoi(m[4])})
+			if !ok {
+				continue
+			}
+			if f.Function == "" {
+				lines[i] = fmt.Sprintf("%sat %s", indent, location(f))
+			} else {
+				lines[i] = fmt.Sprintf("%sat %s (%s)", indent, f.Function, location(f))
+			}
+		case strings.Contains(line, "@") && geckoFrame.MatchString(line):
+			m := geckoFrame.FindStringSubmatch(line)
+			f, ok := mapFrame(models.StackFrame{Function: m[1], File: m[2], Line: atoi(m[3]), Column: atoi(m[4])})
+			if !ok {
+				continue
+			}
+			lines[i] = fmt.Sprintf("%s%s@%s", indent, f.Function, location(f))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func location(f models.StackFrame) string {
+	switch {
+	case f.Line == 0:
+		return f.File
+	case f.Column == 0:
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	default:
+		return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+	}
+}
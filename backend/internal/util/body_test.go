@@ -0,0 +1,115 @@
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func deflated(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestReadBody(t *testing.T) {
+	const limit = 1 << 10
+	payload := []byte(`{"message":"boom"}`)
+	full := bytes.Repeat([]byte("a"), limit)
+	// A few kilobytes that decompress to 16 MiB
+	bomb := gzipped(t, make([]byte, 16<<20))
+	compressed := gzipped(t, payload)
+
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		want     []byte
+		wantErr  error
+		errText  string
+	}{
+		{name: "plain", body: payload, want: payload},
+		{name: "identity", body: payload, encoding: "identity", want: payload},
+		{name: "gzip", body: compressed, encoding: "gzip", want: payload},
+		{name: "encoding is case-insensitive", body: compressed, encoding: " GZIP ", want: payload},
+		{name: "x-gzip", body: compressed, encoding: "x-gzip", want: payload},
+		{name: "deflate", body: deflated(t, payload), encoding: "deflate", want: payload},
+		{name: "empty", body: nil, want: []byte{}},
+		{name: "exactly the limit", body: full, want: full},
+		{name: "gzip of exactly the limit", body: gzipped(t, full), encoding: "gzip", want: full},
+		{
+			name:     "concatenated gzip members",
+			body:     append(gzipped(t, []byte(`{"a":`)), gzipped(t, []byte(`1}`))...),
+			encoding: "gzip",
+			want:     []byte(`{"a":1}`),
+		},
+
+		{name: "over the limit", body: append(full, 'a'), wantErr: ErrBodyTooLarge},
+		{name: "gzip over the limit", body: gzipped(t, append(full, 'a')), encoding: "gzip", wantErr: ErrBodyTooLarge},
+		{name: "gzip bomb", body: bomb, encoding: "gzip", wantErr: ErrBodyTooLarge},
+		{name: "deflate bomb", body: deflated(t, make([]byte, 16<<20)), encoding: "deflate", wantErr: ErrBodyTooLarge},
+		{
+			name:     "gzip bomb in a later member",
+			body:     append(gzipped(t, payload), bomb...),
+			encoding: "gzip",
+			wantErr:  ErrBodyTooLarge,
+		},
+		{name: "unsupported encoding", body: payload, encoding: "br", wantErr: ErrUnsupportedEncoding},
+		{name: "gzip header on plain body", body: payload, encoding: "gzip", errText: "invalid gzip body"},
+		{name: "empty gzip body", body: nil, encoding: "gzip", errText: "invalid gzip body"},
+		{name: "deflate header on plain body", body: payload, encoding: "deflate", errText: "invalid deflate body"},
+		{name: "truncated gzip", body: compressed[:len(compressed)-6], encoding: "gzip", errText: "failed to read body"},
+		{name: "corrupt gzip checksum", body: corruptTrailer(compressed), encoding: "gzip", errText: "failed to read body"},
+		{name: "garbage after gzip", body: append(append([]byte{}, compressed...), "junk"...), encoding: "gzip", errText: "failed to read body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadBody(bytes.NewReader(tt.body), tt.encoding, limit)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReadBody() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("ReadBody() error = %v, want one containing %q", err, tt.errText)
+				}
+			case err != nil:
+				t.Fatalf("ReadBody() error = %v", err)
+			case !bytes.Equal(got, tt.want):
+				t.Errorf("ReadBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// corruptTrailer flips a bit of the CRC-32 at the end of a gzip stream
+func corruptTrailer(b []byte) []byte {
+	c := append([]byte{}, b...)
+	c[len(c)-8] ^= 1
+	return c
+}
@@ -3,12 +3,21 @@ package util
 import (
 	"context"
 
+	"pulseguard/internal/models"
 	"pulseguard/pkg/otel"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
+type contextKey string
+
+const (
+	projectKeyCtxKey contextKey = "project_key"
+	projectCtxKey    contextKey = "project"
+	orgCtxKey        contextKey = "organization"
+)
+
 // GetUserIDFromContext retrieves the user_id from the context
 func GetUserIDFromContext(ctx context.Context, metrics *otel.Metrics) (string, bool) {
 	userID, ok := ctx.Value("user_id").(string)
@@ -17,3 +26,36 @@ func GetUserIDFromContext(ctx context.Context, metrics *otel.Metrics) (string, b
 	}
 	return userID, ok
 }
+
+// WithProjectKey stores the ingest key that authenticated the request
+func WithProjectKey(ctx context.Context, key *models.ProjectKey) context.Context {
+	return context.WithValue(ctx, projectKeyCtxKey, key)
+}
+
+// GetProjectKeyFromContext retrieves the ingest key set by the ingest middleware
+func GetProjectKeyFromContext(ctx context.Context) (*models.ProjectKey, bool) {
+	key, ok := ctx.Value(projectKeyCtxKey).(*models.ProjectKey)
+	return key, ok && key != nil
+}
+
+// WithProject stores the project the caller has been authorized for
+func WithProject(ctx context.Context, project *models.Project) context.Context {
+	return context.WithValue(ctx, projectCtxKey, project)
+}
+
+// GetProjectFromContext retrieves the project set by the project access middleware
+func GetProjectFromContext(ctx context.Context) (*models.Project, bool) {
+	project, ok := ctx.Value(projectCtxKey).(*models.Project)
+	return project, ok && project != nil
+}
+
+// WithOrganization stores the organization the caller has been authorized for
+func WithOrganization(ctx context.Context, org *models.Organization) context.Context {
+	return context.WithValue(ctx, orgCtxKey, org)
+}
+
+// GetOrganizationFromContext retrieves the organization set by the organization access middleware
+func GetOrganizationFromContext(ctx context.Context) (*models.Organization, bool) {
+	org, ok := ctx.Value(orgCtxKey).(*models.Organization)
+	return org, ok && org != nil
+}
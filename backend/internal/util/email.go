@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"html"
 	"log"
 	"pulseguard/pkg/mailer"
 )
@@ -23,3 +24,37 @@ func SendPasswordResetEmail(to string, resetLink string) error {
 	log.Printf("✅ Password reset email sent: %s", emailID)
 	return nil
 }
+
+func SendInvitationEmail(to string, target string, inviteLink string) error {
+	m, err := mailer.NewMailer()
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("You're invited to join %s on Pulseguard", target)
+	body := fmt.Sprintf(`<p>You have been invited to join <strong>%s</strong> on Pulseguard.</p><p><a href="%s">Accept the invitation</a></p><p>This link expires in 7 days.</p>`, html.EscapeString(target), inviteLink)
+
+	emailID, err := m.Send([]string{to}, subject, body)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✅ Invitation email sent: %s", emailID)
+	return nil
+}
+
+// IssueAssignedEmail returns the subject and body of the email telling a
+// user an issue was assigned
+func IssueAssignedEmail(assignee string, issueTitle string, issueLink string) (string, string) {
+	subject := fmt.Sprintf("Issue assigned to %s: %s", assignee, issueTitle)
+	body := fmt.Sprintf(`<p>An issue has been assigned to <strong>%s</strong>:</p><p><a href="%s">%s</a></p>`, html.EscapeString(assignee), issueLink, html.EscapeString(issueTitle))
+	return subject, body
+}
+
+// MentionEmail returns the subject and body of the email telling a user
+// they were mentioned in a comment on an issue
+func MentionEmail(author string, issueTitle string, comment string, issueLink string) (string, string) {
+	subject := fmt.Sprintf("%s mentioned you on %s", author, issueTitle)
+	body := fmt.Sprintf(`<p><strong>%s</strong> mentioned you on <a href="%s">%s</a>:</p><blockquote>%s</blockquote>`, html.EscapeString(author), issueLink, html.EscapeString(issueTitle), html.EscapeString(comment))
+	return subject, body
+}
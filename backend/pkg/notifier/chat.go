@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Chat webhook formats
+const (
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+)
+
+// ChatNotifier posts to a Slack or Discord compatible incoming webhook
+type ChatNotifier struct {
+	URL    string
+	Format string
+	Client *http.Client
+}
+
+func NewChatNotifier(url, format string, client *http.Client) *ChatNotifier {
+	return &ChatNotifier{URL: url, Format: format, Client: client}
+}
+
+func (n *ChatNotifier) Notify(ctx context.Context, msg Message) error {
+	var payload any
+	switch n.Format {
+	case FormatSlack:
+		payload = slackPayload(msg)
+	case FormatDiscord:
+		payload = discordPayload(msg)
+	default:
+		return fmt.Errorf("unknown chat format %q", n.Format)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, n.Client, n.URL, body, nil)
+}
+
+func slackPayload(msg Message) map[string]any {
+	return map[string]any{
+		"text": fmt.Sprintf("%s *%s*", statusEmoji(msg), msg.Title),
+		"attachments": []map[string]any{{
+			"color":  severityColorHex(msg),
+			"text":   msg.Text,
+			"fields": chatFields(msg, "title", "value"),
+		}},
+	}
+}
+
+func discordPayload(msg Message) map[string]any {
+	return map[string]any{
+		"content": fmt.Sprintf("%s **%s**", statusEmoji(msg), msg.Title),
+		"embeds": []map[string]any{{
+			"description": msg.Text,
+			"color":       severityColor(msg),
+			"fields":      chatFields(msg, "name", "value"),
+			"timestamp":   msg.StartedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}},
+	}
+}
+
+func chatFields(msg Message, nameKey, valueKey string) []map[string]any {
+	fields := []map[string]any{
+		{nameKey: "Status", valueKey: msg.Status, "inline": true, "short": true},
+		{nameKey: "Severity", valueKey: msg.Severity, "inline": true, "short": true},
+	}
+	if len(msg.Labels) > 0 {
+		parts := make([]string, 0, len(msg.Labels))
+		for key, value := range msg.Labels {
+			parts = append(parts, key+"="+value)
+		}
+		fields = append(fields, map[string]any{nameKey: "Labels", valueKey: strings.Join(parts, ", ")})
+	}
+	return fields
+}
+
+func statusEmoji(msg Message) string {
+	switch msg.Event {
+	case EventAlertResolved:
+		return "✅"
+	case EventAlertAcknowledged:
+		return "👀"
+	case EventTest:
+		return "🔔"
+	}
+	return "🚨"
+}
+
+func severityColor(msg Message) int {
+	if msg.Event == EventAlertResolved {
+		return 0x2eb67d
+	}
+	switch strings.ToLower(msg.Severity) {
+	case "critical", "error", "high":
+		return 0xe01e5a
+	case "info", "low":
+		return 0x36c5f0
+	}
+	return 0xecb22e
+}
+
+func severityColorHex(msg Message) string {
+	return fmt.Sprintf("#%06x", severityColor(msg))
+}
@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Sender sends an HTML email; *mailer.Mailer satisfies it
+type Sender interface {
+	Send(to []string, subject, htmlBody string) (string, error)
+}
+
+// EmailNotifier emails the message to a fixed list of recipients
+type EmailNotifier struct {
+	Sender     Sender
+	Recipients []string
+}
+
+func NewEmailNotifier(sender Sender, recipients []string) *EmailNotifier {
+	return &EmailNotifier{Sender: sender, Recipients: recipients}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, msg Message) error {
+	if n.Sender == nil {
+		return errors.New("email is not configured (RESEND_API_KEY and EMAIL_FROM)")
+	}
+	if len(n.Recipients) == 0 {
+		return errors.New("email channel has no recipients")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("[PulseGuard] [%s] %s", strings.ToUpper(msg.Status), msg.Title)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "<h2>%s</h2>", html.EscapeString(msg.Title))
+	fmt.Fprintf(&body, "<p>%s</p>", html.EscapeString(msg.Text))
+	fmt.Fprintf(&body, "<p><strong>Status:</strong> %s<br><strong>Severity:</strong> %s<br><strong>Started:</strong> %s</p>",
+		html.EscapeString(msg.Status), html.EscapeString(msg.Severity), msg.StartedAt.Format("2006-01-02 15:04:05 MST"))
+	if len(msg.Labels) > 0 {
+		body.WriteString("<ul>")
+		for key, value := range msg.Labels {
+			fmt.Fprintf(&body, "<li><code>%s</code> = %s</li>", html.EscapeString(key), html.EscapeString(value))
+		}
+		body.WriteString("</ul>")
+	}
+
+	_, err := n.Sender.Send(n.Recipients, subject, body.String())
+	return err
+}
@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Event names carried in Message.Event
+const (
+	EventAlertFiring       = "alert.firing"
+	EventAlertAcknowledged = "alert.acknowledged"
+	EventAlertResolved     = "alert.resolved"
+	EventTest              = "test"
+)
+
+// Message is the alert notification handed to every channel
+type Message struct {
+	Event      string            `json:"event"`
+	AlertID    string            `json:"alert_id,omitempty"`
+	ProjectID  string            `json:"project_id"`
+	Title      string            `json:"title"`
+	Text       string            `json:"message"`
+	Severity   string            `json:"severity"`
+	Status     string            `json:"status"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Value      *float64          `json:"value,omitempty"`
+	StartedAt  time.Time         `json:"started_at"`
+	ResolvedAt *time.Time        `json:"resolved_at,omitempty"`
+	SentAt     time.Time         `json:"sent_at"`
+}
+
+// Notifier delivers a message to one destination
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// StatusError is returned when the receiving endpoint answers with a
+// non-2xx status. It carries only the status: the response body may come
+// from a service the sender should not be able to read.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("receiver responded with status %d", e.StatusCode)
+}
+
+// DefaultClient is used by notifiers created without an explicit HTTP
+// client. It only connects to public addresses; see ErrForbiddenAddress.
+var DefaultClient = &http.Client{Timeout: 10 * time.Second, Transport: publicTransport()}
+
+// Describe returns what a project may be told about a failed notification:
+// the status the receiver answered with, or why it could not be reached,
+// without the response or the addresses involved
+func Describe(err error) string {
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.Error()
+	case errors.Is(err, ErrForbiddenAddress):
+		return ErrForbiddenAddress.Error()
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "receiver did not respond in time"
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return "receiver could not be reached"
+	}
+	return err.Error()
+}
+
+// Backoff returns how long to wait before retry number attempt (1-based):
+// 30s, 1m, 2m, 4m ... capped at one hour.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := time.Duration(float64(30*time.Second) * math.Pow(2, float64(attempt-1)))
+	if delay > time.Hour || delay <= 0 {
+		return time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,99 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.1.2.3", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.0.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"fc00::1", false},
+		{"fe80::1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultClientRefusesLoopback(t *testing.T) {
+	reached := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(srv.URL, "", nil).Notify(context.Background(), Message{Title: "x"})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Notify() error = %v, want ErrForbiddenAddress", err)
+	}
+	if reached {
+		t.Error("the loopback receiver was reached")
+	}
+}
+
+func TestStatusErrorLeavesOutTheResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"AccessKeyId":"secret"}`)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(srv.URL, "", srv.Client()).Notify(context.Background(), Message{Title: "x"})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Notify() error = %v, want a 403 StatusError", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %q includes the response body", err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "http://internal", Err: errors.New("dial tcp 10.0.0.5:80: connection refused")}
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&StatusError{StatusCode: 500}, "receiver responded with status 500"},
+		{fmt.Errorf("deliver: %w", &StatusError{StatusCode: 404}), "receiver responded with status 404"},
+		{&url.Error{Op: "Post", URL: "http://internal", Err: ErrForbiddenAddress}, "receiver address is not allowed"},
+		{&url.Error{Op: "Post", URL: "http://slow", Err: context.DeadlineExceeded}, "receiver did not respond in time"},
+		{dialErr, "receiver could not be reached"},
+		{errors.New("email channel has no recipients"), "email channel has no recipients"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.err); got != tt.want {
+			t.Errorf("Describe(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "sha256=<hex hmac>" of "<timestamp>.<body>"
+	SignatureHeader = "X-PulseGuard-Signature"
+	// TimestampHeader carries the unix time the payload was signed at
+	TimestampHeader = "X-PulseGuard-Timestamp"
+)
+
+// WebhookNotifier posts the message as JSON to a URL. When Secret is set the
+// request is signed with HMAC-SHA256 so receivers can verify it.
+type WebhookNotifier struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url, secret string, client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Secret: secret, Client: client}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	if n.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = "sha256=" + Sign(n.Secret, timestamp, body)
+	}
+
+	return postJSON(ctx, n.Client, n.URL, body, headers)
+}
+
+// Sign computes the hex HMAC-SHA256 of "<timestamp>.<body>" with secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature (with or without the "sha256=" prefix)
+// matches the payload
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	if len(signature) > 7 && signature[:7] == "sha256=" {
+		signature = signature[7:]
+	}
+	expected := Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	if client == nil {
+		client = DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "PulseGuard-Notifier/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return nil
+}
@@ -13,6 +13,10 @@ type Metrics struct {
 	UserActivityTotal     metric.Int64Counter
 	ActiveSessions        metric.Int64UpDownCounter
 	PageViewsTotal        metric.Int64Counter
+
+	IngestAcceptedTotal    metric.Int64Counter
+	IngestRateLimitedTotal metric.Int64Counter
+	IngestDroppedTotal     metric.Int64Counter
 }
 
 // InitMetrics initializes all application metrics.
@@ -76,6 +80,30 @@ func InitMetrics(client *Client) (*Metrics, error) {
 		return nil, err
 	}
 
+	ingestAcceptedTotal, err := meter.Int64Counter(
+		"ingest_events_accepted_total",
+		metric.WithDescription("Total ingested events accepted for storage"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ingestRateLimitedTotal, err := meter.Int64Counter(
+		"ingest_events_rate_limited_total",
+		metric.WithDescription("Total ingested events rejected by a project or key rate limit"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ingestDroppedTotal, err := meter.Int64Counter(
+		"ingest_events_dropped_total",
+		metric.WithDescription("Total ingested events dropped by quota, spike protection or a full queue"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Metrics{
 		HTTPRequestsTotal:     httpRequestsTotal,
 		HTTPRequestDurationMs: httpRequestDurationMs,
@@ -84,5 +112,9 @@ func InitMetrics(client *Client) (*Metrics, error) {
 		UserActivityTotal:     userActivityTotal,
 		ActiveSessions:        activeSessions,
 		PageViewsTotal:        pageViewsTotal,
+
+		IngestAcceptedTotal:    ingestAcceptedTotal,
+		IngestRateLimitedTotal: ingestRateLimitedTotal,
+		IngestDroppedTotal:     ingestDroppedTotal,
 	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("object not found")
+
+// Store keeps binary objects, such as uploaded artifacts, under string keys
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStoreFromEnv returns the store selected by ARTIFACT_STORAGE: "s3" for an
+// S3-compatible bucket configured by the S3_* variables, otherwise a
+// directory on local disk (ARTIFACT_DIR, ./data/artifacts by default)
+func NewStoreFromEnv() (Store, error) {
+	switch os.Getenv("ARTIFACT_STORAGE") {
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	case "", "local":
+		dir := os.Getenv("ARTIFACT_DIR")
+		if dir == "" {
+			dir = "data/artifacts"
+		}
+		return NewLocalStore(dir)
+	default:
+		return nil, fmt.Errorf("unknown ARTIFACT_STORAGE %q", os.Getenv("ARTIFACT_STORAGE"))
+	}
+}